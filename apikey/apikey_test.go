@@ -0,0 +1,75 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	prefix, key, err := Generate("")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.HasPrefix(prefix, "iam_") || !strings.HasPrefix(key, prefix+"_") {
+		t.Errorf("prefix %q, key %q", prefix, key)
+	}
+	if got, ok := PrefixOf(key); !ok || got != prefix {
+		t.Errorf("PrefixOf = %q, %v; want %q", got, ok, prefix)
+	}
+
+	_, other, _ := Generate("")
+	if other == key || Hash(other) == Hash(key) {
+		t.Error("expected distinct keys")
+	}
+
+	if _, _, err := Generate("my_app"); err == nil {
+		t.Error("expected error for label containing '_'")
+	}
+}
+
+func TestPrefixOf_Malformed(t *testing.T) {
+	for _, key := range []string{"", "iam", "iam_", "iam_abc", "_abc_def", "iam_abc_"} {
+		if p, ok := PrefixOf(key); ok {
+			t.Errorf("PrefixOf(%q) = %q, want malformed", key, p)
+		}
+	}
+}
+
+func TestFromHeaders(t *testing.T) {
+	tests := []struct {
+		apiKey, authorization, want string
+	}{
+		{"iam_a_b", "", "iam_a_b"},
+		{"", "ApiKey iam_a_b", "iam_a_b"},
+		{"", "apikey iam_a_b", "iam_a_b"},
+		{"iam_a_b", "Bearer token", "iam_a_b"},
+		{"", "Bearer token", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := FromHeaders(tt.apiKey, tt.authorization); got != tt.want {
+			t.Errorf("FromHeaders(%q, %q) = %q, want %q", tt.apiKey, tt.authorization, got, tt.want)
+		}
+	}
+}
+
+func TestAllows(t *testing.T) {
+	scopes := []string{"orders:read", "billing:*"}
+	tests := []struct {
+		permission string
+		want       bool
+	}{
+		{"orders:read", true},
+		{"orders:write", false},
+		{"billing:refund", true},
+		{"users:read", false},
+	}
+	for _, tt := range tests {
+		if got := Allows(scopes, tt.permission); got != tt.want {
+			t.Errorf("Allows(%s) = %v, want %v", tt.permission, got, tt.want)
+		}
+	}
+	if !Allows(nil, "anything") {
+		t.Error("empty scopes should allow everything")
+	}
+}
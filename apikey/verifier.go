@@ -0,0 +1,157 @@
+package apikey
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/cache"
+	"github.com/chimerakang/iam-go/internal/flight"
+)
+
+// Issuer is the iam.Claims issuer of API key credentials.
+const Issuer = "apikey"
+
+// ErrInvalidKey is returned by Verifier.Verify for malformed, unknown,
+// revoked and expired keys.
+var ErrInvalidKey = errors.New("iam/apikey: invalid API key")
+
+// Default cache settings.
+const (
+	DefaultCacheTTL    = 30 * time.Second
+	DefaultNegativeTTL = 5 * time.Second
+	DefaultMaxEntries  = 10000
+)
+
+// Verifier implements iam.TokenVerifier for API keys. Key records are cached
+// by prefix, so a revocation takes effect within the cache TTL (or at once
+// after Invalidate).
+type Verifier struct {
+	keys        iam.APIKeyService
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxEntries  int
+
+	cache *cache.LRU[string, *iam.APIKey] // prefix → key; nil for unknown prefixes
+	sf    flight.Group
+	now   func() time.Time
+}
+
+// compile-time check
+var _ iam.TokenVerifier = (*Verifier)(nil)
+
+// Option configures the Verifier.
+type Option func(*Verifier)
+
+// WithCacheTTL sets how long key records are cached (default: 30 seconds).
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(v *Verifier) {
+		v.ttl = ttl
+	}
+}
+
+// WithNegativeTTL sets how long unknown prefixes are cached (default: 5
+// seconds), so that garbage keys do not reach the backend on every request.
+// Zero disables negative caching.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(v *Verifier) {
+		v.negativeTTL = ttl
+	}
+}
+
+// WithMaxEntries bounds the cache size (default: 10000). Zero or negative
+// means unbounded.
+func WithMaxEntries(n int) Option {
+	return func(v *Verifier) {
+		v.maxEntries = n
+	}
+}
+
+// NewVerifier creates a Verifier that looks keys up in keys.
+func NewVerifier(keys iam.APIKeyService, opts ...Option) *Verifier {
+	v := &Verifier{
+		keys:        keys,
+		ttl:         DefaultCacheTTL,
+		negativeTTL: DefaultNegativeTTL,
+		maxEntries:  DefaultMaxEntries,
+		now:         time.Now,
+	}
+	for _, o := range opts {
+		o(v)
+	}
+	v.cache = cache.New[string, *iam.APIKey](v.maxEntries)
+	return v
+}
+
+// Verify checks key and returns claims for the key's user and tenant, limited
+// to the key's scopes. Claims.Extra["api_key_id"] holds the key ID.
+func (v *Verifier) Verify(ctx context.Context, key string) (*iam.Claims, error) {
+	prefix, ok := PrefixOf(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidKey)
+	}
+
+	rec, err := v.lookup(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil || subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(rec.SecretHash)) != 1 {
+		return nil, ErrInvalidKey
+	}
+	now := v.now()
+	if !rec.RevokedAt.IsZero() {
+		return nil, fmt.Errorf("%w: revoked", ErrInvalidKey)
+	}
+	if !rec.Active(now) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidKey)
+	}
+
+	claims := &iam.Claims{
+		Subject:       rec.UserID,
+		TenantID:      rec.TenantID,
+		Issuer:        Issuer,
+		IssuedAt:      rec.CreatedAt,
+		ExpiresAt:     rec.ExpiresAt,
+		PrincipalType: iam.PrincipalAPIKey,
+		Extra:         map[string]any{"api_key_id": rec.ID},
+	}
+	if len(rec.Scopes) > 0 {
+		claims.Scopes = append([]string(nil), rec.Scopes...)
+	}
+	return claims, nil
+}
+
+// Invalidate drops the cached record for prefix, e.g. right after revoking
+// or rotating the key.
+func (v *Verifier) Invalidate(prefix string) {
+	v.cache.Delete(prefix)
+}
+
+// lookup returns the key record for prefix, or nil if there is none.
+func (v *Verifier) lookup(ctx context.Context, prefix string) (*iam.APIKey, error) {
+	if rec, ok := v.cache.Get(prefix); ok {
+		return rec, nil
+	}
+
+	res, err := v.sf.Do(ctx, prefix, func(ctx context.Context) (interface{}, error) {
+		rec, err := v.keys.Lookup(ctx, prefix)
+		if errors.Is(err, iam.ErrNotFound) {
+			if v.negativeTTL > 0 {
+				v.cache.Set(prefix, nil, v.negativeTTL)
+			}
+			return (*iam.APIKey)(nil), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iam/apikey: looking up key: %w", err)
+		}
+		v.cache.Set(prefix, rec, v.ttl)
+		return rec, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*iam.APIKey), nil
+}
@@ -0,0 +1,149 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// countingKeys is an APIKeyService holding one key, counting lookups.
+type countingKeys struct {
+	iam.APIKeyService
+	key     iam.APIKey
+	lookups atomic.Int32
+	fail    error
+}
+
+func (c *countingKeys) Lookup(_ context.Context, prefix string) (*iam.APIKey, error) {
+	c.lookups.Add(1)
+	if c.fail != nil {
+		return nil, c.fail
+	}
+	if prefix != c.key.Prefix {
+		return nil, iam.ErrNotFound
+	}
+	k := c.key
+	return &k, nil
+}
+
+func newKeys(t *testing.T) (*countingKeys, string) {
+	t.Helper()
+	prefix, key, err := Generate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &countingKeys{key: iam.APIKey{
+		ID:         "key-1",
+		Prefix:     prefix,
+		UserID:     "u1",
+		TenantID:   "t1",
+		Scopes:     []string{"orders:read"},
+		SecretHash: Hash(key),
+	}}, key
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	keys, key := newKeys(t)
+	v := NewVerifier(keys)
+
+	claims, err := v.Verify(context.Background(), key)
+
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.Subject != "u1" || claims.TenantID != "t1" || claims.Issuer != Issuer || claims.Principal() != iam.PrincipalAPIKey || claims.Extra["api_key_id"] != "key-1" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	if len(claims.Scopes) != 1 || claims.Scopes[0] != "orders:read" {
+		t.Errorf("Scopes = %v", claims.Scopes)
+	}
+
+	// Served from the cache
+	for range 5 {
+		if _, err := v.Verify(context.Background(), key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := keys.lookups.Load(); n != 1 {
+		t.Errorf("expected 1 lookup, got %d", n)
+	}
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	keys, key := newKeys(t)
+	v := NewVerifier(keys)
+	wrongSecret := keys.key.Prefix + "_" + "00"
+
+	for _, k := range []string{"garbage", wrongSecret, "iam_ffffffff_00"} {
+		if _, err := v.Verify(context.Background(), k); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Verify(%q): expected ErrInvalidKey, got %v", k, err)
+		}
+	}
+
+	// Unknown prefixes are cached too
+	_, _ = v.Verify(context.Background(), "iam_ffffffff_00")
+	if n := keys.lookups.Load(); n != 2 {
+		t.Errorf("expected 2 lookups, got %d", n)
+	}
+
+	keys.key.ExpiresAt = time.Now().Add(-time.Minute)
+	v = NewVerifier(keys)
+	if _, err := v.Verify(context.Background(), key); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expired key: expected ErrInvalidKey, got %v", err)
+	}
+}
+
+func TestVerifier_RevocationAfterInvalidate(t *testing.T) {
+	keys, key := newKeys(t)
+	v := NewVerifier(keys, WithCacheTTL(time.Hour))
+	if _, err := v.Verify(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+
+	keys.key.RevokedAt = time.Now()
+	if _, err := v.Verify(context.Background(), key); err != nil {
+		t.Fatalf("expected cached key to be accepted until invalidated, got %v", err)
+	}
+	v.Invalidate(keys.key.Prefix)
+	if _, err := v.Verify(context.Background(), key); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey after revocation, got %v", err)
+	}
+}
+
+func TestVerifier_BackendErrorNotCached(t *testing.T) {
+	keys, key := newKeys(t)
+	keys.fail = errors.New("unavailable")
+	v := NewVerifier(keys)
+
+	if _, err := v.Verify(context.Background(), key); err == nil || errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected backend error, got %v", err)
+	}
+	keys.fail = nil
+	if _, err := v.Verify(context.Background(), key); err != nil {
+		t.Errorf("expected success after backend recovered, got %v", err)
+	}
+}
+
+func TestVerifier_CoalescesLookups(t *testing.T) {
+	keys, key := newKeys(t)
+	v := NewVerifier(keys)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = v.Verify(context.Background(), key)
+		}()
+	}
+	wg.Wait()
+
+	if n := keys.lookups.Load(); n > 2 {
+		t.Errorf("expected concurrent lookups to be coalesced, got %d", n)
+	}
+}
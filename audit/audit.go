@@ -12,17 +12,19 @@ import (
 
 // Event represents an IAM audit event.
 type Event struct {
-	Timestamp  time.Time `json:"timestamp"`
-	RequestID  string    `json:"request_id,omitempty"`
-	UserID     string    `json:"user_id,omitempty"`
-	TenantID   string    `json:"tenant_id,omitempty"`
-	Action     string    `json:"action"` // auth, permission_check, token_revoke, etc.
-	Resource   string    `json:"resource,omitempty"`
-	Result     string    `json:"result"` // success, failure, denied
-	Details    string    `json:"details,omitempty"`
-	IP         string    `json:"ip,omitempty"`
-	UserAgent  string    `json:"user_agent,omitempty"`
-	Error      string    `json:"error,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+	RequestID     string    `json:"request_id,omitempty"`
+	UserID        string    `json:"user_id,omitempty"`
+	ActorID       string    `json:"actor_id,omitempty"`       // set when UserID is being impersonated
+	PrincipalType string    `json:"principal_type,omitempty"` // user, service_account, api_key
+	TenantID      string    `json:"tenant_id,omitempty"`
+	Action        string    `json:"action"` // auth, permission_check, token_revoke, etc.
+	Resource      string    `json:"resource,omitempty"`
+	Result        string    `json:"result"` // success, failure, denied
+	Details       string    `json:"details,omitempty"`
+	IP            string    `json:"ip,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+	Error         string    `json:"error,omitempty"`
 }
 
 // Handler processes audit events. Implementations should not block.
@@ -33,6 +35,7 @@ type Logger struct {
 	handlers []Handler
 	queue    chan Event
 	done     chan struct{}
+	closed   sync.Once
 	wg       sync.WaitGroup
 }
 
@@ -124,9 +127,10 @@ func (l *Logger) process() {
 	}
 }
 
-// Close flushes pending events and stops the logger.
+// Close flushes pending events and stops the logger. Further calls are
+// no-ops.
 func (l *Logger) Close() error {
-	close(l.done)
+	l.closed.Do(func() { close(l.done) })
 	l.wg.Wait()
 	return nil
 }
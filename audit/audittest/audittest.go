@@ -0,0 +1,36 @@
+// Package audittest provides an in-memory audit.Logger for tests of code
+// that writes audit events.
+package audittest
+
+import (
+	"sync"
+
+	"github.com/chimerakang/iam-go/audit"
+)
+
+// Recorder is an audit.Logger that keeps the events it receives in memory.
+type Recorder struct {
+	*audit.Logger
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+// NewRecorder creates a Recorder.
+func NewRecorder() *Recorder {
+	r := &Recorder{}
+	r.Logger = audit.New(0, audit.WithHandler(func(e audit.Event) {
+		r.mu.Lock()
+		r.events = append(r.events, e)
+		r.mu.Unlock()
+	}))
+	return r
+}
+
+// Events closes the logger, so that every event logged so far is delivered,
+// and returns the events in the order they were logged.
+func (r *Recorder) Events() []audit.Event {
+	_ = r.Close()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]audit.Event(nil), r.events...)
+}
@@ -0,0 +1,21 @@
+package audittest
+
+import (
+	"testing"
+
+	"github.com/chimerakang/iam-go/audit"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	r.Log(audit.Event{Action: "auth", Result: "success"})
+	r.Log(audit.Event{Action: "auth", Result: "denied"})
+
+	got := r.Events()
+	if len(got) != 2 || got[0].Result != "success" || got[1].Result != "denied" {
+		t.Fatalf("Events() = %+v, want both events in order", got)
+	}
+	if again := r.Events(); len(again) != 2 {
+		t.Errorf("second Events() = %+v, want the same events", again)
+	}
+}
@@ -29,8 +29,11 @@ type Client struct {
 	verifier  TokenVerifier
 	authz     Authorizer
 	users     UserService
+	userAdmin UserAdminService
 	tenants   TenantService
 	sessions  SessionService
+	apiKeys   APIKeyService
+	accounts  ServiceAccountService
 	oauth2    OAuth2TokenExchanger
 }
 
@@ -92,6 +95,21 @@ func WithUserService(u UserService) Option {
 	return func(c *Client) { c.users = u }
 }
 
+// WithUserAdminService sets the user administration implementation.
+func WithUserAdminService(a UserAdminService) Option {
+	return func(c *Client) { c.userAdmin = a }
+}
+
+// WithAPIKeyService sets the API key management implementation.
+func WithAPIKeyService(k APIKeyService) Option {
+	return func(c *Client) { c.apiKeys = k }
+}
+
+// WithServiceAccountService sets the service account management implementation.
+func WithServiceAccountService(s ServiceAccountService) Option {
+	return func(c *Client) { c.accounts = s }
+}
+
 // WithTenantService sets the tenant management implementation.
 func WithTenantService(t TenantService) Option {
 	return func(c *Client) { c.tenants = t }
@@ -138,12 +156,21 @@ func (c *Client) Authz() Authorizer { return c.authz }
 // Users returns the user service, or nil if not configured.
 func (c *Client) Users() UserService { return c.users }
 
+// UserAdmin returns the user administration service, or nil if not configured.
+func (c *Client) UserAdmin() UserAdminService { return c.userAdmin }
+
 // Tenants returns the tenant service, or nil if not configured.
 func (c *Client) Tenants() TenantService { return c.tenants }
 
 // Sessions returns the session service, or nil if not configured.
 func (c *Client) Sessions() SessionService { return c.sessions }
 
+// APIKeys returns the API key service, or nil if not configured.
+func (c *Client) APIKeys() APIKeyService { return c.apiKeys }
+
+// ServiceAccounts returns the service account service, or nil if not configured.
+func (c *Client) ServiceAccounts() ServiceAccountService { return c.accounts }
+
 // OAuth2 returns the OAuth2 token exchanger, or nil if not configured.
 func (c *Client) OAuth2() OAuth2TokenExchanger { return c.oauth2 }
 
@@ -151,8 +178,8 @@ func (c *Client) OAuth2() OAuth2TokenExchanger { return c.oauth2 }
 // It attempts to verify a dummy context without a token to check if the system is responsive.
 // Returns nil if healthy, or an error if the client is not properly configured or unreachable.
 func (c *Client) HealthCheck(ctx context.Context) error {
-	if c.verifier == nil && c.authz == nil && c.users == nil &&
-		c.tenants == nil && c.sessions == nil && c.oauth2 == nil {
+	if c.verifier == nil && c.authz == nil && c.users == nil && c.userAdmin == nil &&
+		c.tenants == nil && c.sessions == nil && c.apiKeys == nil && c.accounts == nil && c.oauth2 == nil {
 		return fmt.Errorf("iam: no services configured — at least one service is required for health check")
 	}
 
@@ -165,8 +192,8 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 // Any injected service that implements io.Closer will be closed.
 func (c *Client) Close() error {
 	closers := []interface{}{
-		c.verifier, c.authz, c.users,
-		c.tenants, c.sessions, c.oauth2,
+		c.verifier, c.authz, c.users, c.userAdmin,
+		c.tenants, c.sessions, c.apiKeys, c.accounts, c.oauth2,
 	}
 	var firstErr error
 	for _, svc := range closers {
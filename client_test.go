@@ -113,9 +113,12 @@ func (m *mockUserService) Get(ctx context.Context, userID string) (*iam.User, er
 func (m *mockUserService) GetCurrent(ctx context.Context) (*iam.User, error) {
 	return nil, nil
 }
-func (m *mockUserService) List(ctx context.Context, opts iam.ListOptions) ([]*iam.User, int, error) {
-	return nil, 0, nil
+func (m *mockUserService) List(ctx context.Context, opts iam.ListOptions) (*iam.UserList, error) {
+	return &iam.UserList{}, nil
 }
 func (m *mockUserService) GetRoles(ctx context.Context, userID string) ([]iam.Role, error) {
 	return nil, nil
 }
+func (m *mockUserService) GetMany(ctx context.Context, userIDs []string) (map[string]*iam.User, error) {
+	return nil, nil
+}
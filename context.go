@@ -9,6 +9,9 @@ const (
 	ctxKeyTenantID ctxKey = "iam_tenant_id"
 	ctxKeyRoles    ctxKey = "iam_roles"
 	ctxKeyClaims   ctxKey = "iam_claims"
+	ctxKeySession  ctxKey = "iam_session_id"
+	ctxKeyActorID  ctxKey = "iam_actor_id"
+	ctxKeyToken    ctxKey = "iam_access_token"
 )
 
 // WithUserID stores the authenticated user ID in the context.
@@ -17,6 +20,8 @@ func WithUserID(ctx context.Context, userID string) context.Context {
 }
 
 // UserIDFromContext extracts the authenticated user ID from the context.
+// During impersonation this is the effective user, the one being impersonated;
+// see ActorIDFromContext for the person acting.
 func UserIDFromContext(ctx context.Context) string {
 	v, _ := ctx.Value(ctxKeyUserID).(string)
 	return v
@@ -44,6 +49,17 @@ func RolesFromContext(ctx context.Context) []string {
 	return v
 }
 
+// WithSessionID stores the current session ID (the token's "sid" claim) in the context.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, ctxKeySession, sessionID)
+}
+
+// SessionIDFromContext extracts the current session ID from the context.
+func SessionIDFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(ctxKeySession).(string)
+	return v
+}
+
 // WithClaims stores the full token claims in the context.
 func WithClaims(ctx context.Context, claims *Claims) context.Context {
 	return context.WithValue(ctx, ctxKeyClaims, claims)
@@ -54,3 +70,44 @@ func ClaimsFromContext(ctx context.Context) *Claims {
 	v, _ := ctx.Value(ctxKeyClaims).(*Claims)
 	return v
 }
+
+// WithAccessToken stores the bearer token the request was authenticated
+// with, e.g. to exchange it for a downstream token on the caller's behalf.
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, ctxKeyToken, token)
+}
+
+// AccessTokenFromContext extracts the request's bearer token from the
+// context. It is empty for API keys and unauthenticated requests.
+func AccessTokenFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(ctxKeyToken).(string)
+	return v
+}
+
+// PrincipalTypeFromContext returns the kind of the authenticated principal
+// (see Claims.Principal), or "" if the context carries no claims.
+func PrincipalTypeFromContext(ctx context.Context) PrincipalType {
+	return ClaimsFromContext(ctx).Principal()
+}
+
+// WithActorID stores the ID of the user acting on behalf of the context's
+// user, for impersonated requests.
+func WithActorID(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, ctxKeyActorID, actorID)
+}
+
+// ActorIDFromContext extracts the acting user ID from the context. It is empty
+// unless the request is impersonated.
+func ActorIDFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(ctxKeyActorID).(string)
+	return v
+}
+
+// ActingUserIDFromContext returns the user accountable for the request: the
+// actor when impersonating, otherwise the authenticated user.
+func ActingUserIDFromContext(ctx context.Context) string {
+	if actor := ActorIDFromContext(ctx); actor != "" {
+		return actor
+	}
+	return UserIDFromContext(ctx)
+}
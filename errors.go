@@ -0,0 +1,91 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned (possibly wrapped) by service implementations when
+	// the requested entity does not exist. Callers and caches use errors.Is to
+	// tell a definitive "not found" apart from transient backend failures.
+	ErrNotFound = errors.New("iam: not found")
+
+	// ErrSessionInvalid is returned (possibly wrapped) when a session has been
+	// revoked, has expired, or violates a session policy.
+	ErrSessionInvalid = errors.New("iam: session invalid")
+
+	// ErrInvalidArgument is returned (possibly wrapped) when a request is
+	// malformed, e.g. an unknown sort field or a page token that does not
+	// belong to the query.
+	ErrInvalidArgument = errors.New("iam: invalid argument")
+
+	// ErrAlreadyExists is returned (possibly wrapped) when creating something
+	// that already exists, e.g. a user with a taken email address.
+	ErrAlreadyExists = errors.New("iam: already exists")
+)
+
+// ErrStepUpRequired is matched (via errors.Is) by errors reporting that the
+// caller must re-authenticate, more strongly or more recently, before the
+// request can proceed.
+var ErrStepUpRequired = errors.New("iam: step-up authentication required")
+
+// StepUpError reports that a token's authentication context does not meet
+// an operation's requirements. It matches ErrStepUpRequired.
+type StepUpError struct {
+	// ACRValues lists the acceptable "acr" values, if a class was required.
+	ACRValues []string
+	// MaxAge is the maximum time since authentication, if freshness was required.
+	MaxAge time.Duration
+}
+
+func (e *StepUpError) Error() string {
+	switch {
+	case len(e.ACRValues) > 0:
+		return fmt.Sprintf("iam: step-up authentication required: acr must be one of %q", e.ACRValues)
+	case e.MaxAge > 0:
+		return fmt.Sprintf("iam: step-up authentication required: authentication older than %s", e.MaxAge)
+	}
+	return ErrStepUpRequired.Error()
+}
+
+// Is reports whether target is ErrStepUpRequired.
+func (e *StepUpError) Is(target error) bool {
+	return target == ErrStepUpRequired
+}
+
+// Metadata returns the requirements as "acr_values" (space-separated) and
+// "max_age" (seconds) entries, for error details in non-HTTP transports.
+func (e *StepUpError) Metadata() map[string]string {
+	md := make(map[string]string, 2)
+	if len(e.ACRValues) > 0 {
+		md["acr_values"] = strings.Join(e.ACRValues, " ")
+	}
+	if e.MaxAge > 0 {
+		md["max_age"] = strconv.Itoa(int(e.MaxAge / time.Second))
+	}
+	return md
+}
+
+// Challenge returns the RFC 9470 WWW-Authenticate value asking the client to
+// obtain a token that satisfies the requirements.
+func (e *StepUpError) Challenge() string {
+	desc := "Step-up authentication is required"
+	switch {
+	case len(e.ACRValues) > 0:
+		desc = "A different authentication level is required"
+	case e.MaxAge > 0:
+		desc = "More recent authentication is required"
+	}
+	challenge := `Bearer error="insufficient_user_authentication", error_description="` + desc + `"`
+	md := e.Metadata()
+	for _, k := range []string{"acr_values", "max_age"} {
+		if v, ok := md[k]; ok {
+			challenge += `, ` + k + `="` + v + `"`
+		}
+	}
+	return challenge
+}
@@ -5,12 +5,18 @@ package fake
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/mail"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
+	"github.com/chimerakang/iam-go/session"
 )
 
 // Option configures the fake client.
@@ -18,12 +24,39 @@ type Option func(*state)
 
 type state struct {
 	mu          sync.RWMutex
-	users       map[string]*iam.User       // userID → User
-	tenants     map[string]*iam.Tenant     // tenantID → Tenant
-	tenantSlugs map[string]string          // slug → tenantID
-	permissions map[string]map[string]bool // userID → permission → allowed
-	sessions    map[string][]*iam.Session  // userID → sessions
-	oauth2App   *oauth2AppEntry            // OAuth2 application credentials
+	users       map[string]*iam.User           // userID → User
+	tenants     map[string]*iam.Tenant         // tenantID → Tenant
+	tenantSlugs map[string]string              // slug → tenantID
+	permissions map[string]map[string]bool     // userID → permission → allowed
+	sessions    map[string][]*iam.Session      // userID → sessions
+	memberships map[string]map[string]string   // userID → tenantID → role name (besides User.TenantID)
+	roles       map[string]map[string]bool     // tenantID → IDs of the roles defined there
+	switched    map[string]switchedToken       // token → tenant-switched identity
+	devices     map[string]*deviceSettings     // deviceID → user-chosen settings
+	authCtx     map[string]authContext         // token → authentication context
+	oauth2App   *oauth2AppEntry                // OAuth2 application credentials
+	nextUserID  int                            // counter for users created through UserAdminService
+	apiKeys     map[string]*iam.APIKey         // keyID → key, with SecretHash
+	nextKeyID   int                            // counter for keys created through APIKeyService
+	accounts    map[string]*iam.ServiceAccount // accountID → service account
+	nextSAID    int                            // counter for accounts created through ServiceAccountService
+}
+
+type switchedToken struct {
+	userID   string
+	tenantID string
+	actorID  string // set for impersonation tokens
+}
+
+type authContext struct {
+	acr      string
+	amr      []string
+	authTime time.Time
+}
+
+type deviceSettings struct {
+	name    string
+	trusted bool
 }
 
 type oauth2AppEntry struct {
@@ -38,6 +71,7 @@ func WithUser(id, tenantID, email string, roleNames []string) Option {
 		roles := make([]iam.Role, len(roleNames))
 		for i, name := range roleNames {
 			roles[i] = iam.Role{ID: name, Name: name}
+			s.defineRole(tenantID, name)
 		}
 		s.users[id] = &iam.User{
 			ID:       id,
@@ -45,10 +79,20 @@ func WithUser(id, tenantID, email string, roleNames []string) Option {
 			Name:     email,
 			TenantID: tenantID,
 			Roles:    roles,
+			Status:   iam.UserStatusActive,
 		}
 	}
 }
 
+// WithRole defines a role in tenantID that no user needs to hold. Roles
+// given to users are defined in their tenant automatically. The fake uses
+// the role name as its ID.
+func WithRole(tenantID, name string) Option {
+	return func(s *state) {
+		s.defineRole(tenantID, name)
+	}
+}
+
 // WithTenant adds a fake tenant.
 func WithTenant(id, slug, status string) Option {
 	return func(s *state) {
@@ -62,6 +106,113 @@ func WithTenant(id, slug, status string) Option {
 	}
 }
 
+// WithMembership adds the user to an additional tenant with the given role.
+// The tenant from WithUser is always a membership; use this for users that
+// belong to several tenants.
+func WithMembership(userID, tenantID, roleName string) Option {
+	return func(s *state) {
+		if s.memberships[userID] == nil {
+			s.memberships[userID] = make(map[string]string)
+		}
+		s.memberships[userID][tenantID] = roleName
+	}
+}
+
+// WithSession adds an active session for the user. The session ID also works
+// as a bearer token: the fake verifier returns the user's claims with SessionID set.
+func WithSession(userID, sessionID string) Option {
+	return WithSessionDetails(iam.Session{ID: sessionID, UserID: userID})
+}
+
+// WithSessionDetails adds a session with explicit device, location and
+// timestamps. Zero timestamps default to now (expiry to now + 24h), and
+// device/OS/browser are parsed from UserAgent when left empty.
+func WithSessionDetails(sess iam.Session) Option {
+	return func(s *state) {
+		now := time.Now()
+		if sess.CreatedAt.IsZero() {
+			sess.CreatedAt = now
+		}
+		if sess.ExpiresAt.IsZero() {
+			sess.ExpiresAt = now.Add(24 * time.Hour)
+		}
+		if sess.LastActiveAt.IsZero() {
+			sess.LastActiveAt = now
+		}
+		if sess.Device == "" && sess.OS == "" && sess.Browser == "" {
+			ua := session.ParseUserAgent(sess.UserAgent)
+			sess.Device, sess.OS, sess.Browser = ua.Device, ua.OS, ua.Browser
+		}
+		s.sessions[sess.UserID] = append(s.sessions[sess.UserID], &sess)
+	}
+}
+
+// WithImpersonation adds an impersonation token: the fake verifier resolves
+// token to userID's claims with an "act" claim naming actorID.
+func WithImpersonation(token, actorID, userID string) Option {
+	return func(s *state) {
+		s.switched[token] = switchedToken{userID: userID, actorID: actorID}
+	}
+}
+
+// WithAuthContext sets how the user behind token authenticated: the acr and
+// amr claims and auth_time. Without it, tokens have no acr or amr and an
+// auth_time of now.
+func WithAuthContext(token, acr string, amr []string, authTime time.Time) Option {
+	return func(s *state) {
+		s.authCtx[token] = authContext{acr: acr, amr: amr, authTime: authTime}
+	}
+}
+
+// WithAPIKey adds an API key for userID in tenantID. key must be well-formed,
+// like "iam_test_secret"; its prefix doubles as the key ID.
+func WithAPIKey(key, userID, tenantID string, scopes []string) Option {
+	return func(s *state) {
+		prefix, ok := apikey.PrefixOf(key)
+		if !ok {
+			panic(fmt.Sprintf("iam/fake: malformed API key %q", key))
+		}
+		s.apiKeys[prefix] = &iam.APIKey{
+			ID:         prefix,
+			Prefix:     prefix,
+			Name:       prefix,
+			UserID:     userID,
+			TenantID:   tenantID,
+			Scopes:     scopes,
+			CreatedAt:  time.Now(),
+			SecretHash: apikey.Hash(key),
+		}
+	}
+}
+
+// WithServiceAccount adds a service account. Its ID works as a bearer token,
+// as does "fake_access_token_<clientID>", the token the fake OAuth2 exchanger
+// issues for WithOAuth2App(clientID, ...); both verify to service-account
+// claims without tenant or roles. Grant permissions with WithPermissions(id, ...).
+func WithServiceAccount(id, clientID string) Option {
+	return func(s *state) {
+		s.accounts[id] = &iam.ServiceAccount{
+			ID:        id,
+			Name:      id,
+			ClientID:  clientID,
+			CreatedAt: time.Now(),
+		}
+	}
+}
+
+// WithServiceAccountRole binds a service account added with
+// WithServiceAccount to roleName in tenantID.
+func WithServiceAccountRole(id, tenantID, roleName string) Option {
+	return func(s *state) {
+		if sa := s.accounts[id]; sa != nil {
+			sa.Bindings = append(sa.Bindings, iam.RoleBinding{
+				TenantID: tenantID,
+				Role:     iam.Role{ID: roleName, Name: roleName},
+			})
+		}
+	}
+}
+
 // WithPermissions sets the allowed permissions for a user.
 func WithPermissions(userID string, perms []string) Option {
 	return func(s *state) {
@@ -92,6 +243,13 @@ func NewClient(opts ...Option) *iam.Client {
 		tenantSlugs: make(map[string]string),
 		permissions: make(map[string]map[string]bool),
 		sessions:    make(map[string][]*iam.Session),
+		memberships: make(map[string]map[string]string),
+		roles:       make(map[string]map[string]bool),
+		switched:    make(map[string]switchedToken),
+		devices:     make(map[string]*deviceSettings),
+		authCtx:     make(map[string]authContext),
+		apiKeys:     make(map[string]*iam.APIKey),
+		accounts:    make(map[string]*iam.ServiceAccount),
 	}
 	for _, o := range opts {
 		o(s)
@@ -100,15 +258,21 @@ func NewClient(opts ...Option) *iam.Client {
 	v := &fakeVerifier{s: s}
 	a := &fakeAuthorizer{s: s}
 	u := &fakeUserService{s: s}
+	ua := &fakeUserAdminService{s: s}
 	t := &fakeTenantService{s: s}
 	ss := &fakeSessionService{s: s}
+	k := &fakeAPIKeyService{s: s}
+	sa := &fakeServiceAccountService{s: s}
 
 	clientOpts := []iam.Option{
 		iam.WithTokenVerifier(v),
 		iam.WithAuthorizer(a),
 		iam.WithUserService(u),
+		iam.WithUserAdminService(ua),
 		iam.WithTenantService(t),
 		iam.WithSessionService(ss),
+		iam.WithAPIKeyService(k),
+		iam.WithServiceAccountService(sa),
 	}
 
 	if s.oauth2App != nil {
@@ -130,26 +294,61 @@ func (f *fakeVerifier) Verify(_ context.Context, token string) (*iam.Claims, err
 	f.s.mu.RLock()
 	defer f.s.mu.RUnlock()
 
-	// Treat the token string as a userID for simplicity
-	user, ok := f.s.users[token]
+	if sa := f.s.accountForToken(token); sa != nil {
+		return &iam.Claims{
+			Subject:       sa.ID,
+			PrincipalType: iam.PrincipalServiceAccount,
+			ExpiresAt:     time.Now().Add(1 * time.Hour),
+			IssuedAt:      time.Now(),
+			Issuer:        "fake",
+			Extra:         map[string]any{"client_id": sa.ClientID},
+		}, nil
+	}
+
+	// Tokens issued by SwitchTenant carry the switched tenant
+	userID, tenantID, sessionID, actorID := token, "", "", ""
+	if sw, ok := f.s.switched[token]; ok {
+		userID, tenantID, actorID = sw.userID, sw.tenantID, sw.actorID
+	} else if owner := f.s.sessionOwner(token); owner != "" {
+		// Session IDs double as tokens bound to that session
+		userID, sessionID = owner, token
+	}
+
+	// Otherwise treat the token string as a userID for simplicity
+	user, ok := f.s.users[userID]
 	if !ok {
 		return nil, fmt.Errorf("iam/fake: unknown token %q", token)
 	}
+	if user.Status == iam.UserStatusDisabled {
+		return nil, fmt.Errorf("iam/fake: user %q is disabled", userID)
+	}
+	if tenantID == "" {
+		tenantID = user.TenantID
+	}
 
 	roleNames := make([]string, len(user.Roles))
 	for i, r := range user.Roles {
 		roleNames[i] = r.Name
 	}
 
-	return &iam.Claims{
+	claims := &iam.Claims{
 		Subject:   user.ID,
-		TenantID:  user.TenantID,
+		TenantID:  tenantID,
 		Roles:     roleNames,
 		Email:     user.Email,
+		SessionID: sessionID,
 		ExpiresAt: time.Now().Add(1 * time.Hour),
 		IssuedAt:  time.Now(),
 		Issuer:    "fake",
-	}, nil
+		AuthTime:  time.Now(),
+	}
+	if ac, ok := f.s.authCtx[token]; ok {
+		claims.ACR, claims.AMR, claims.AuthTime = ac.acr, ac.amr, ac.authTime
+	}
+	if actorID != "" {
+		claims.Actor = &iam.Actor{Subject: actorID, Issuer: "fake"}
+	}
+	return claims, nil
 }
 
 // --- Authorizer ---
@@ -202,39 +401,62 @@ func (f *fakeUserService) Get(_ context.Context, userID string) (*iam.User, erro
 
 	user, ok := f.s.users[userID]
 	if !ok {
-		return nil, fmt.Errorf("iam/fake: user %q not found", userID)
+		return nil, fmt.Errorf("iam/fake: user %q: %w", userID, iam.ErrNotFound)
 	}
 	return user, nil
 }
 
-func (f *fakeUserService) List(_ context.Context, opts iam.ListOptions) ([]*iam.User, int, error) {
+// List filters and sorts users, then pages through them with keyset cursors:
+// a page token records the sort key and ID of the last user returned, so
+// inserts and deletes between calls never shift later pages.
+func (f *fakeUserService) List(_ context.Context, opts iam.ListOptions) (*iam.UserList, error) {
+	field, desc, err := parseOrderBy(opts.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("%+v|%s|%t", opts.Filter, field, desc)
+
 	f.s.mu.RLock()
 	defer f.s.mu.RUnlock()
 
-	all := make([]*iam.User, 0, len(f.s.users))
+	matched := make([]*iam.User, 0, len(f.s.users))
 	for _, u := range f.s.users {
-		all = append(all, u)
+		if matchesFilter(u, opts.Filter) {
+			matched = append(matched, u)
+		}
 	}
+	sort.Slice(matched, func(i, j int) bool {
+		return cursorLess(userSortKey(matched[i], field), matched[i].ID, userSortKey(matched[j], field), matched[j].ID, desc)
+	})
 
-	total := len(all)
-	page := opts.Page
-	if page < 1 {
-		page = 1
-	}
 	size := opts.PageSize
 	if size < 1 {
 		size = 20
 	}
+	start := 0
+	switch {
+	case opts.PageToken != "":
+		c, err := decodeCursor(opts.PageToken)
+		if err != nil || c.Query != query {
+			return nil, fmt.Errorf("iam/fake: page token does not match query: %w", iam.ErrInvalidArgument)
+		}
+		start = sort.Search(len(matched), func(i int) bool {
+			return cursorLess(c.Key, c.ID, userSortKey(matched[i], field), matched[i].ID, desc)
+		})
+	case opts.Page > 1:
+		start = min((opts.Page-1)*size, len(matched))
+	}
+	end := min(start+size, len(matched))
 
-	start := (page - 1) * size
-	if start >= total {
-		return nil, total, nil
+	list := &iam.UserList{
+		Users: matched[start:end],
+		Total: len(matched),
 	}
-	end := start + size
-	if end > total {
-		end = total
+	if end < len(matched) {
+		last := matched[end-1]
+		list.NextPageToken = encodeCursor(listCursor{Query: query, Key: userSortKey(last, field), ID: last.ID})
 	}
-	return all[start:end], total, nil
+	return list, nil
 }
 
 func (f *fakeUserService) GetRoles(_ context.Context, userID string) ([]iam.Role, error) {
@@ -243,11 +465,592 @@ func (f *fakeUserService) GetRoles(_ context.Context, userID string) ([]iam.Role
 
 	user, ok := f.s.users[userID]
 	if !ok {
-		return nil, fmt.Errorf("iam/fake: user %q not found", userID)
+		return nil, fmt.Errorf("iam/fake: user %q: %w", userID, iam.ErrNotFound)
 	}
 	return user.Roles, nil
 }
 
+func (f *fakeUserService) GetMany(_ context.Context, userIDs []string) (map[string]*iam.User, error) {
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	users := make(map[string]*iam.User, len(userIDs))
+	for _, id := range userIDs {
+		if user, ok := f.s.users[id]; ok {
+			users[id] = user
+		}
+	}
+	return users, nil
+}
+
+// listCursor is the decoded form of a fake page token.
+type listCursor struct {
+	Query string `json:"q"`
+	Key   string `json:"k"`
+	ID    string `json:"id"`
+}
+
+func encodeCursor(c listCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(token string) (listCursor, error) {
+	var c listCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
+
+func parseOrderBy(orderBy string) (field string, desc bool, err error) {
+	parts := strings.Fields(orderBy)
+	if len(parts) == 0 {
+		return "id", false, nil
+	}
+	switch parts[0] {
+	case "id", "email", "name":
+	default:
+		return "", false, fmt.Errorf("iam/fake: unsupported order_by field %q: %w", parts[0], iam.ErrInvalidArgument)
+	}
+	switch {
+	case len(parts) == 1:
+	case len(parts) == 2 && strings.EqualFold(parts[1], "desc"):
+		desc = true
+	case len(parts) == 2 && strings.EqualFold(parts[1], "asc"):
+	default:
+		return "", false, fmt.Errorf("iam/fake: invalid order_by %q: %w", orderBy, iam.ErrInvalidArgument)
+	}
+	return parts[0], desc, nil
+}
+
+func userSortKey(u *iam.User, field string) string {
+	switch field {
+	case "email":
+		return strings.ToLower(u.Email)
+	case "name":
+		return u.Name
+	}
+	return u.ID
+}
+
+// cursorLess orders users by sort key, then ID, reversing both when desc.
+func cursorLess(keyA, idA, keyB, idB string, desc bool) bool {
+	if keyA != keyB {
+		return (keyA < keyB) != desc
+	}
+	if idA != idB {
+		return (idA < idB) != desc
+	}
+	return false
+}
+
+func matchesFilter(u *iam.User, f iam.UserFilter) bool {
+	if f.TenantID != "" && u.TenantID != f.TenantID {
+		return false
+	}
+	if f.Status != "" && u.Status != f.Status {
+		return false
+	}
+	if f.EmailPrefix != "" && !strings.HasPrefix(strings.ToLower(u.Email), strings.ToLower(f.EmailPrefix)) {
+		return false
+	}
+	if f.Role != "" {
+		for _, r := range u.Roles {
+			if r.ID == f.Role || r.Name == f.Role {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// --- UserAdminService ---
+
+// fakeUserAdminService validates like a real server: malformed input wraps
+// iam.ErrInvalidArgument, taken emails and duplicate role assignments
+// iam.ErrAlreadyExists, unknown users, tenants and roles iam.ErrNotFound.
+// Users are replaced rather than modified, so values returned earlier never
+// change underneath the caller.
+type fakeUserAdminService struct{ s *state }
+
+func (f *fakeUserAdminService) Create(_ context.Context, input iam.CreateUserInput) (*iam.User, error) {
+	if err := fakeValidateEmail(input.Email); err != nil {
+		return nil, err
+	}
+
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	if f.s.emailTaken(input.Email, "") {
+		return nil, fmt.Errorf("iam/fake: email %q: %w", input.Email, iam.ErrAlreadyExists)
+	}
+	if input.TenantID != "" && f.s.tenants[input.TenantID] == nil {
+		return nil, fmt.Errorf("iam/fake: tenant %q: %w", input.TenantID, iam.ErrNotFound)
+	}
+
+	var id string
+	for id == "" || f.s.users[id] != nil {
+		f.s.nextUserID++
+		id = fmt.Sprintf("user-%d", f.s.nextUserID)
+	}
+	user := &iam.User{
+		ID:       id,
+		Email:    input.Email,
+		Name:     input.Name,
+		TenantID: input.TenantID,
+		Metadata: metadataToAny(input.Metadata),
+		Status:   iam.UserStatusActive,
+	}
+	for _, roleID := range input.RoleIDs {
+		user.Roles = append(user.Roles, iam.Role{ID: roleID, Name: roleID})
+		f.s.defineRole(input.TenantID, roleID)
+	}
+	f.s.users[id] = user
+	return user, nil
+}
+
+func (f *fakeUserAdminService) Update(_ context.Context, userID string, update iam.UserUpdate) (*iam.User, error) {
+	if update.Email != nil {
+		if err := fakeValidateEmail(*update.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	user, err := f.s.cloneUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Email != nil {
+		if f.s.emailTaken(*update.Email, userID) {
+			return nil, fmt.Errorf("iam/fake: email %q: %w", *update.Email, iam.ErrAlreadyExists)
+		}
+		user.Email = *update.Email
+	}
+	if update.Name != nil {
+		user.Name = *update.Name
+	}
+	if update.Metadata != nil {
+		user.Metadata = metadataToAny(update.Metadata)
+	}
+	f.s.users[userID] = user
+	return user, nil
+}
+
+func (f *fakeUserAdminService) Disable(_ context.Context, userID, _ string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	user, err := f.s.cloneUser(userID)
+	if err != nil {
+		return err
+	}
+	user.Status = iam.UserStatusDisabled
+	f.s.users[userID] = user
+	delete(f.s.sessions, userID)
+	return nil
+}
+
+func (f *fakeUserAdminService) Enable(_ context.Context, userID string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	user, err := f.s.cloneUser(userID)
+	if err != nil {
+		return err
+	}
+	user.Status = iam.UserStatusActive
+	f.s.users[userID] = user
+	return nil
+}
+
+func (f *fakeUserAdminService) Delete(_ context.Context, userID string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	if f.s.users[userID] == nil {
+		return fmt.Errorf("iam/fake: user %q: %w", userID, iam.ErrNotFound)
+	}
+	delete(f.s.users, userID)
+	delete(f.s.sessions, userID)
+	delete(f.s.permissions, userID)
+	delete(f.s.memberships, userID)
+	for id, k := range f.s.apiKeys {
+		if k.UserID == userID {
+			delete(f.s.apiKeys, id)
+		}
+	}
+	return nil
+}
+
+func (f *fakeUserAdminService) AssignRole(_ context.Context, userID, roleID string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	user, err := f.s.cloneUser(userID)
+	if err != nil {
+		return err
+	}
+	for _, r := range user.Roles {
+		if r.ID == roleID {
+			return fmt.Errorf("iam/fake: user %q already has role %q: %w", userID, roleID, iam.ErrAlreadyExists)
+		}
+	}
+	user.Roles = append(user.Roles, iam.Role{ID: roleID, Name: roleID})
+	f.s.users[userID] = user
+	f.s.defineRole(user.TenantID, roleID)
+	return nil
+}
+
+func (f *fakeUserAdminService) RemoveRole(_ context.Context, userID, roleID string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	user, err := f.s.cloneUser(userID)
+	if err != nil {
+		return err
+	}
+	for i, r := range user.Roles {
+		if r.ID == roleID {
+			user.Roles = append(user.Roles[:i], user.Roles[i+1:]...)
+			f.s.users[userID] = user
+			return nil
+		}
+	}
+	return fmt.Errorf("iam/fake: user %q has no role %q: %w", userID, roleID, iam.ErrNotFound)
+}
+
+// ListRoles returns the roles defined in opts.TenantID, 100 per page by
+// default.
+func (f *fakeUserAdminService) ListRoles(_ context.Context, opts iam.ListRolesOptions) (*iam.RoleList, error) {
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	var ids []string
+	for id := range f.s.roles[opts.TenantID] {
+		if opts.Role == "" || opts.Role == id {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	list := &iam.RoleList{Roles: []iam.Role{}, Total: len(ids)}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	for _, id := range ids[min(max(opts.Offset, 0), len(ids)):] {
+		if len(list.Roles) == limit {
+			break
+		}
+		list.Roles = append(list.Roles, iam.Role{ID: id, Name: id})
+	}
+	return list, nil
+}
+
+// defineRole records that roleID exists in tenantID. Caller must hold s.mu.
+func (s *state) defineRole(tenantID, roleID string) {
+	if s.roles[tenantID] == nil {
+		s.roles[tenantID] = make(map[string]bool)
+	}
+	s.roles[tenantID][roleID] = true
+}
+
+// cloneUser returns a copy of the user safe to modify. Caller must hold s.mu.
+func (s *state) cloneUser(userID string) (*iam.User, error) {
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("iam/fake: user %q: %w", userID, iam.ErrNotFound)
+	}
+	clone := *user
+	clone.Roles = append([]iam.Role(nil), user.Roles...)
+	return &clone, nil
+}
+
+// emailTaken reports whether another user than exceptID uses email. Caller
+// must hold s.mu.
+func (s *state) emailTaken(email, exceptID string) bool {
+	for id, u := range s.users {
+		if id != exceptID && strings.EqualFold(u.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func fakeValidateEmail(email string) error {
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return fmt.Errorf("iam/fake: invalid email %q: %w", email, iam.ErrInvalidArgument)
+	}
+	return nil
+}
+
+func metadataToAny(m map[string]string) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// --- APIKeyService ---
+
+// fakeAPIKeyService generates real keys with apikey.Generate and stores their
+// hashes, so apikey.Verifier works against it unchanged.
+type fakeAPIKeyService struct{ s *state }
+
+func (f *fakeAPIKeyService) Create(_ context.Context, input iam.CreateAPIKeyInput) (*iam.NewAPIKey, error) {
+	prefix, key, err := apikey.Generate("")
+	if err != nil {
+		return nil, err
+	}
+
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	if f.s.users[input.UserID] == nil {
+		return nil, fmt.Errorf("iam/fake: user %q: %w", input.UserID, iam.ErrNotFound)
+	}
+	if !input.ExpiresAt.IsZero() && !input.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("iam/fake: expiry in the past: %w", iam.ErrInvalidArgument)
+	}
+
+	f.s.nextKeyID++
+	k := &iam.APIKey{
+		ID:         fmt.Sprintf("key-%d", f.s.nextKeyID),
+		Prefix:     prefix,
+		Name:       input.Name,
+		UserID:     input.UserID,
+		TenantID:   input.TenantID,
+		Scopes:     append([]string(nil), input.Scopes...),
+		CreatedAt:  time.Now(),
+		ExpiresAt:  input.ExpiresAt,
+		SecretHash: apikey.Hash(key),
+	}
+	f.s.apiKeys[k.ID] = k
+	return newAPIKey(k, key), nil
+}
+
+func (f *fakeAPIKeyService) List(_ context.Context, userID string) ([]iam.APIKey, error) {
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	var keys []iam.APIKey
+	for _, k := range f.s.apiKeys {
+		if k.UserID == userID {
+			c := *k
+			c.SecretHash = ""
+			keys = append(keys, c)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+func (f *fakeAPIKeyService) Revoke(_ context.Context, keyID string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	k, ok := f.s.apiKeys[keyID]
+	if !ok {
+		return fmt.Errorf("iam/fake: API key %q: %w", keyID, iam.ErrNotFound)
+	}
+	if k.RevokedAt.IsZero() {
+		c := *k
+		c.RevokedAt = time.Now()
+		f.s.apiKeys[keyID] = &c
+	}
+	return nil
+}
+
+func (f *fakeAPIKeyService) Rotate(_ context.Context, keyID string) (*iam.NewAPIKey, error) {
+	prefix, key, err := apikey.Generate("")
+	if err != nil {
+		return nil, err
+	}
+
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	k, ok := f.s.apiKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("iam/fake: API key %q: %w", keyID, iam.ErrNotFound)
+	}
+	if !k.RevokedAt.IsZero() {
+		return nil, fmt.Errorf("iam/fake: API key %q is revoked: %w", keyID, iam.ErrInvalidArgument)
+	}
+	c := *k
+	c.Prefix, c.SecretHash = prefix, apikey.Hash(key)
+	f.s.apiKeys[keyID] = &c
+	return newAPIKey(&c, key), nil
+}
+
+func (f *fakeAPIKeyService) Lookup(_ context.Context, prefix string) (*iam.APIKey, error) {
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	for _, k := range f.s.apiKeys {
+		if k.Prefix == prefix {
+			c := *k
+			return &c, nil
+		}
+	}
+	return nil, fmt.Errorf("iam/fake: API key prefix %q: %w", prefix, iam.ErrNotFound)
+}
+
+func newAPIKey(k *iam.APIKey, key string) *iam.NewAPIKey {
+	n := &iam.NewAPIKey{APIKey: *k, Key: key}
+	n.SecretHash = ""
+	return n
+}
+
+// --- ServiceAccountService ---
+
+type fakeServiceAccountService struct{ s *state }
+
+func (f *fakeServiceAccountService) Create(_ context.Context, input iam.CreateServiceAccountInput) (*iam.ServiceAccount, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, fmt.Errorf("iam/fake: service account name is required: %w", iam.ErrInvalidArgument)
+	}
+
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	f.s.nextSAID++
+	sa := &iam.ServiceAccount{
+		ID:          fmt.Sprintf("sa-%d", f.s.nextSAID),
+		Name:        input.Name,
+		Description: input.Description,
+		ClientID:    input.ClientID,
+		CreatedAt:   time.Now(),
+	}
+	if sa.ClientID == "" {
+		sa.ClientID = sa.ID
+	}
+	for _, other := range f.s.accounts {
+		if other.ClientID == sa.ClientID {
+			return nil, fmt.Errorf("iam/fake: client %q already has a service account: %w", sa.ClientID, iam.ErrAlreadyExists)
+		}
+	}
+	f.s.accounts[sa.ID] = sa
+	return cloneAccount(sa), nil
+}
+
+func (f *fakeServiceAccountService) Get(_ context.Context, id string) (*iam.ServiceAccount, error) {
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	sa, ok := f.s.accounts[id]
+	if !ok {
+		return nil, fmt.Errorf("iam/fake: service account %q: %w", id, iam.ErrNotFound)
+	}
+	return cloneAccount(sa), nil
+}
+
+func (f *fakeServiceAccountService) List(_ context.Context, tenantID string) ([]*iam.ServiceAccount, error) {
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	var accounts []*iam.ServiceAccount
+	for _, sa := range f.s.accounts {
+		if tenantID == "" || len(sa.RolesIn(tenantID)) > 0 {
+			accounts = append(accounts, cloneAccount(sa))
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	return accounts, nil
+}
+
+func (f *fakeServiceAccountService) Delete(_ context.Context, id string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	if f.s.accounts[id] == nil {
+		return fmt.Errorf("iam/fake: service account %q: %w", id, iam.ErrNotFound)
+	}
+	delete(f.s.accounts, id)
+	delete(f.s.permissions, id)
+	return nil
+}
+
+func (f *fakeServiceAccountService) BindRole(_ context.Context, id, tenantID, roleID string) error {
+	if tenantID == "" || roleID == "" {
+		return fmt.Errorf("iam/fake: tenant and role are required: %w", iam.ErrInvalidArgument)
+	}
+
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	sa, ok := f.s.accounts[id]
+	if !ok {
+		return fmt.Errorf("iam/fake: service account %q: %w", id, iam.ErrNotFound)
+	}
+	for _, b := range sa.Bindings {
+		if b.TenantID == tenantID && b.Role.ID == roleID {
+			return nil
+		}
+	}
+	c := cloneAccount(sa)
+	c.Bindings = append(c.Bindings, iam.RoleBinding{TenantID: tenantID, Role: iam.Role{ID: roleID, Name: roleID}})
+	f.s.accounts[id] = c
+	return nil
+}
+
+func (f *fakeServiceAccountService) UnbindRole(_ context.Context, id, tenantID, roleID string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	sa, ok := f.s.accounts[id]
+	if !ok {
+		return fmt.Errorf("iam/fake: service account %q: %w", id, iam.ErrNotFound)
+	}
+	c := cloneAccount(sa)
+	c.Bindings = c.Bindings[:0]
+	for _, b := range sa.Bindings {
+		if b.TenantID != tenantID || b.Role.ID != roleID {
+			c.Bindings = append(c.Bindings, b)
+		}
+	}
+	f.s.accounts[id] = c
+	return nil
+}
+
+// accountForToken returns the service account a token was issued to, or nil.
+// Caller must hold s.mu.
+func (s *state) accountForToken(token string) *iam.ServiceAccount {
+	if sa, ok := s.accounts[token]; ok {
+		return sa
+	}
+	if clientID, ok := strings.CutPrefix(token, "fake_access_token_"); ok {
+		for _, sa := range s.accounts {
+			if sa.ClientID == clientID {
+				return sa
+			}
+		}
+	}
+	return nil
+}
+
+func cloneAccount(sa *iam.ServiceAccount) *iam.ServiceAccount {
+	c := *sa
+	c.Bindings = append([]iam.RoleBinding(nil), sa.Bindings...)
+	return &c
+}
+
 // --- TenantService ---
 
 type fakeTenantService struct{ s *state }
@@ -263,7 +1066,7 @@ func (f *fakeTenantService) Resolve(_ context.Context, identifier string) (*iam.
 	if id, ok := f.s.tenantSlugs[identifier]; ok {
 		return f.s.tenants[id], nil
 	}
-	return nil, fmt.Errorf("iam/fake: tenant %q not found", identifier)
+	return nil, fmt.Errorf("iam/fake: tenant %q: %w", identifier, iam.ErrNotFound)
 }
 
 func (f *fakeTenantService) ValidateMembership(_ context.Context, userID, tenantID string) (bool, error) {
@@ -274,7 +1077,53 @@ func (f *fakeTenantService) ValidateMembership(_ context.Context, userID, tenant
 	if !ok {
 		return false, nil
 	}
-	return user.TenantID == tenantID, nil
+	if user.TenantID == tenantID {
+		return true, nil
+	}
+	_, ok = f.s.memberships[userID][tenantID]
+	return ok, nil
+}
+
+func (f *fakeTenantService) ListMemberships(_ context.Context, userID string) ([]iam.Membership, error) {
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	user, ok := f.s.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("iam/fake: user %q: %w", userID, iam.ErrNotFound)
+	}
+
+	var result []iam.Membership
+	if user.TenantID != "" {
+		var role iam.Role
+		if len(user.Roles) > 0 {
+			role = user.Roles[0]
+		}
+		result = append(result, f.s.membership(user.TenantID, role))
+	}
+
+	extra := make([]string, 0, len(f.s.memberships[userID]))
+	for tenantID := range f.s.memberships[userID] {
+		if tenantID != user.TenantID {
+			extra = append(extra, tenantID)
+		}
+	}
+	sort.Strings(extra)
+	for _, tenantID := range extra {
+		name := f.s.memberships[userID][tenantID]
+		result = append(result, f.s.membership(tenantID, iam.Role{ID: name, Name: name}))
+	}
+	return result, nil
+}
+
+// membership builds a Membership, using the configured tenant when known.
+// Caller must hold s.mu.
+func (s *state) membership(tenantID string, role iam.Role) iam.Membership {
+	t := iam.Tenant{ID: tenantID, Status: "active"}
+	if known, ok := s.tenants[tenantID]; ok {
+		t = *known
+	}
+	return iam.Membership{Tenant: t, Role: role, Status: "active"}
 }
 
 // --- SessionService ---
@@ -286,10 +1135,12 @@ func (f *fakeSessionService) List(ctx context.Context) ([]iam.Session, error) {
 	f.s.mu.RLock()
 	defer f.s.mu.RUnlock()
 
+	current := iam.SessionIDFromContext(ctx)
 	sessions := f.s.sessions[userID]
 	result := make([]iam.Session, len(sessions))
 	for i, s := range sessions {
 		result[i] = *s
+		result[i].Current = current != "" && s.ID == current
 	}
 	return result, nil
 }
@@ -310,10 +1161,139 @@ func (f *fakeSessionService) Revoke(_ context.Context, sessionID string) error {
 }
 
 func (f *fakeSessionService) RevokeAllOthers(ctx context.Context) error {
-	// No-op in fake: no concept of "current session"
+	current := iam.SessionIDFromContext(ctx)
+	if current == "" {
+		return fmt.Errorf("iam/fake: no current session in context")
+	}
+
+	userID := userIDFromCtx(ctx)
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	var kept []*iam.Session
+	for _, s := range f.s.sessions[userID] {
+		if s.ID == current {
+			kept = append(kept, s)
+		}
+	}
+	f.s.sessions[userID] = kept
 	return nil
 }
 
+func (f *fakeSessionService) Validate(_ context.Context, sessionID string) (*iam.Session, error) {
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	sess := f.s.session(sessionID)
+	if sess == nil {
+		return nil, fmt.Errorf("iam/fake: session %q: %w", sessionID, iam.ErrSessionInvalid)
+	}
+	if !sess.ExpiresAt.IsZero() && time.Now().After(sess.ExpiresAt) {
+		return nil, fmt.Errorf("iam/fake: session %q expired: %w", sessionID, iam.ErrSessionInvalid)
+	}
+	cp := *sess
+	return &cp, nil
+}
+
+func (f *fakeSessionService) Touch(_ context.Context, sessionID string) error {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	sess := f.s.session(sessionID)
+	if sess == nil {
+		return fmt.Errorf("iam/fake: session %q: %w", sessionID, iam.ErrSessionInvalid)
+	}
+	sess.LastActiveAt = time.Now()
+	return nil
+}
+
+func (f *fakeSessionService) ListDevices(ctx context.Context) ([]iam.Device, error) {
+	userID := userIDFromCtx(ctx)
+	f.s.mu.RLock()
+	defer f.s.mu.RUnlock()
+
+	var devices []iam.Device
+	index := make(map[string]int)
+	for _, sess := range f.s.sessions[userID] {
+		if sess.DeviceID == "" {
+			continue
+		}
+		if i, ok := index[sess.DeviceID]; ok {
+			d := &devices[i]
+			if sess.CreatedAt.Before(d.FirstSeenAt) {
+				d.FirstSeenAt = sess.CreatedAt
+			}
+			if sess.LastActiveAt.After(d.LastSeenAt) {
+				d.LastSeenAt = sess.LastActiveAt
+			}
+			continue
+		}
+		d := iam.Device{
+			ID:          sess.DeviceID,
+			UserID:      userID,
+			Device:      sess.Device,
+			OS:          sess.OS,
+			Browser:     sess.Browser,
+			FirstSeenAt: sess.CreatedAt,
+			LastSeenAt:  sess.LastActiveAt,
+		}
+		if ds, ok := f.s.devices[sess.DeviceID]; ok {
+			d.Name, d.Trusted = ds.name, ds.trusted
+		}
+		index[sess.DeviceID] = len(devices)
+		devices = append(devices, d)
+	}
+	return devices, nil
+}
+
+func (f *fakeSessionService) RenameDevice(ctx context.Context, deviceID, name string) error {
+	return f.updateDevice(ctx, deviceID, func(ds *deviceSettings) { ds.name = name })
+}
+
+func (f *fakeSessionService) TrustDevice(ctx context.Context, deviceID string, trusted bool) error {
+	return f.updateDevice(ctx, deviceID, func(ds *deviceSettings) { ds.trusted = trusted })
+}
+
+// updateDevice applies fn to the settings of one of the current user's devices.
+func (f *fakeSessionService) updateDevice(ctx context.Context, deviceID string, fn func(*deviceSettings)) error {
+	userID := userIDFromCtx(ctx)
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+
+	for _, sess := range f.s.sessions[userID] {
+		if sess.DeviceID == deviceID {
+			ds, ok := f.s.devices[deviceID]
+			if !ok {
+				ds = &deviceSettings{}
+				f.s.devices[deviceID] = ds
+			}
+			fn(ds)
+			return nil
+		}
+	}
+	return fmt.Errorf("iam/fake: device %q: %w", deviceID, iam.ErrNotFound)
+}
+
+// session returns the session with the given ID, or nil. Caller must hold s.mu.
+func (s *state) session(sessionID string) *iam.Session {
+	for _, sessions := range s.sessions {
+		for _, sess := range sessions {
+			if sess.ID == sessionID {
+				return sess
+			}
+		}
+	}
+	return nil
+}
+
+// sessionOwner returns the user owning sessionID, or "" if none. Caller must hold s.mu.
+func (s *state) sessionOwner(sessionID string) string {
+	if sess := s.session(sessionID); sess != nil {
+		return sess.UserID
+	}
+	return ""
+}
+
 // --- OAuth2TokenExchanger ---
 
 type fakeOAuth2Exchanger struct{ s *state }
@@ -343,12 +1323,88 @@ func (f *fakeOAuth2Exchanger) GetCachedToken(ctx context.Context) (string, error
 	return token.AccessToken, nil
 }
 
+// GetToken returns the app's token with req's scopes; the fake does not
+// distinguish audiences or resources.
+func (f *fakeOAuth2Exchanger) GetToken(ctx context.Context, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	return f.ExchangeToken(ctx, req.Scopes)
+}
+
+// ExchangeOnBehalfOf issues a token that the fake verifier resolves to the
+// subject and tenant of subjectToken, with an "act" claim naming the OAuth2
+// app's client ID.
+func (f *fakeOAuth2Exchanger) ExchangeOnBehalfOf(ctx context.Context, subjectToken string, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	claims, err := (&fakeVerifier{s: f.s}).Verify(ctx, subjectToken)
+	if err != nil {
+		return nil, err
+	}
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+	if f.s.oauth2App == nil {
+		return nil, fmt.Errorf("iam/fake: no oauth2 app configured")
+	}
+
+	actorID := f.s.oauth2App.clientID
+	token := actorID + ">" + claims.Subject + "@" + claims.TenantID + "/" + req.Audience
+	f.s.switched[token] = switchedToken{userID: claims.Subject, tenantID: claims.TenantID, actorID: actorID}
+
+	return &iam.OAuth2Token{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   3600,
+		ExpiresAt:   time.Now().Add(1 * time.Hour),
+		Scope:       strings.Join(req.Scopes, " "),
+	}, nil
+}
+
+// SwitchTenant issues a token that the fake verifier resolves to the subject
+// of subjectToken with tenantID as its tenant.
+func (f *fakeOAuth2Exchanger) SwitchTenant(ctx context.Context, subjectToken, tenantID string) (*iam.OAuth2Token, error) {
+	claims, err := (&fakeVerifier{s: f.s}).Verify(ctx, subjectToken)
+	if err != nil {
+		return nil, err
+	}
+	member, _ := (&fakeTenantService{s: f.s}).ValidateMembership(ctx, claims.Subject, tenantID)
+	if !member {
+		return nil, fmt.Errorf("iam/fake: user %q is not a member of tenant %q", claims.Subject, tenantID)
+	}
+
+	token := claims.Subject + "@" + tenantID
+	sw := switchedToken{userID: claims.Subject, tenantID: tenantID}
+	if claims.Actor != nil {
+		// Switching tenants keeps the impersonation going
+		sw.actorID = claims.Actor.Subject
+		token = sw.actorID + ">" + token
+	}
+	f.s.mu.Lock()
+	f.s.switched[token] = sw
+	f.s.mu.Unlock()
+
+	return &iam.OAuth2Token{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   3600,
+		ExpiresAt:   time.Now().Add(1 * time.Hour),
+	}, nil
+}
+
 // ContextWithUserID returns a context with the user ID set.
 // Use this in tests to simulate an authenticated user.
 func ContextWithUserID(ctx context.Context, userID string) context.Context {
 	return iam.WithUserID(ctx, userID)
 }
 
+// ContextWithSession returns a context with the user and current session ID set,
+// as the Auth middleware would for a token carrying a "sid" claim.
+func ContextWithSession(ctx context.Context, userID, sessionID string) context.Context {
+	return iam.WithSessionID(iam.WithUserID(ctx, userID), sessionID)
+}
+
+// ContextWithImpersonation returns a context in which actorID is impersonating
+// userID, as the Auth middleware would for an admitted impersonation token.
+func ContextWithImpersonation(ctx context.Context, actorID, userID string) context.Context {
+	return iam.WithActorID(iam.WithUserID(ctx, userID), actorID)
+}
+
 func userIDFromCtx(ctx context.Context) string {
 	return iam.UserIDFromContext(ctx)
 }
@@ -2,10 +2,13 @@ package fake_test
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
 	"github.com/chimerakang/iam-go/fake"
 )
 
@@ -146,15 +149,89 @@ func TestUserService_GetCurrent(t *testing.T) {
 func TestUserService_List(t *testing.T) {
 	c := setup()
 
-	users, total, err := c.Users().List(context.Background(), iam.ListOptions{Page: 1, PageSize: 2})
+	list, err := c.Users().List(context.Background(), iam.ListOptions{Page: 1, PageSize: 2})
 	if err != nil {
 		t.Fatalf("List() error: %v", err)
 	}
-	if total != 3 {
-		t.Errorf("total = %d, want 3", total)
+	if list.Total != 3 {
+		t.Errorf("total = %d, want 3", list.Total)
 	}
-	if len(users) != 2 {
-		t.Errorf("len(users) = %d, want 2 (page size)", len(users))
+	if len(list.Users) != 2 {
+		t.Errorf("len(users) = %d, want 2 (page size)", len(list.Users))
+	}
+}
+
+func TestUserService_ListCursor(t *testing.T) {
+	c := setup()
+	opts := iam.ListOptions{PageSize: 2, OrderBy: "email desc"}
+
+	first, err := c.Users().List(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(first.Users) != 2 || first.Users[0].ID != "u3" || first.Users[1].ID != "u2" {
+		t.Fatalf("first page = %v, want u3, u2", first.Users)
+	}
+	if first.NextPageToken == "" {
+		t.Fatal("expected NextPageToken")
+	}
+
+	// A user inserted before the cursor must not shift the next page.
+	if _, err := c.UserAdmin().Create(context.Background(), iam.CreateUserInput{Email: "dave@example.com"}); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	opts.PageToken = first.NextPageToken
+	second, err := c.Users().List(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(second.Users) != 1 || second.Users[0].ID != "u1" {
+		t.Errorf("second page = %v, want u1", second.Users)
+	}
+	if second.NextPageToken != "" {
+		t.Errorf("NextPageToken = %q, want empty on last page", second.NextPageToken)
+	}
+}
+
+func TestUserService_ListFilter(t *testing.T) {
+	c := setup()
+
+	tests := []struct {
+		name   string
+		filter iam.UserFilter
+		want   int
+	}{
+		{"role", iam.UserFilter{Role: "admin"}, 2},
+		{"tenant", iam.UserFilter{TenantID: "t1"}, 2},
+		{"status", iam.UserFilter{Status: iam.UserStatusActive}, 3},
+		{"email prefix", iam.UserFilter{EmailPrefix: "BOB"}, 1},
+		{"combined", iam.UserFilter{Role: "admin", TenantID: "t2"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := c.Users().List(context.Background(), iam.ListOptions{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("List() error: %v", err)
+			}
+			if list.Total != tt.want || len(list.Users) != tt.want {
+				t.Errorf("got %d users (total %d), want %d", len(list.Users), list.Total, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserService_ListInvalidArgument(t *testing.T) {
+	c := setup()
+
+	first, _ := c.Users().List(context.Background(), iam.ListOptions{PageSize: 1})
+	for _, opts := range []iam.ListOptions{
+		{OrderBy: "created_at"},
+		{PageToken: "garbage"},
+		{PageToken: first.NextPageToken, Filter: iam.UserFilter{TenantID: "t1"}},
+	} {
+		if _, err := c.Users().List(context.Background(), opts); !errors.Is(err, iam.ErrInvalidArgument) {
+			t.Errorf("List(%+v) error = %v, want iam.ErrInvalidArgument", opts, err)
+		}
 	}
 }
 
@@ -170,6 +247,164 @@ func TestUserService_GetRoles(t *testing.T) {
 	}
 }
 
+func TestUserService_GetMany(t *testing.T) {
+	c := setup()
+
+	users, err := c.Users().GetMany(context.Background(), []string{"u1", "u3", "nonexistent"})
+	if err != nil {
+		t.Fatalf("GetMany() error: %v", err)
+	}
+	if len(users) != 2 || users["u1"] == nil || users["u3"] == nil {
+		t.Errorf("GetMany() = %v, want u1 and u3", users)
+	}
+}
+
+// --- UserAdminService ---
+
+func TestUserAdminService_CreateAndUpdate(t *testing.T) {
+	c := setup()
+	ctx := context.Background()
+
+	user, err := c.UserAdmin().Create(ctx, iam.CreateUserInput{
+		Email: "dave@example.com", Name: "Dave", TenantID: "t1", RoleIDs: []string{"viewer"},
+	})
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if user.ID == "" || user.Status != iam.UserStatusActive || len(user.Roles) != 1 {
+		t.Errorf("Create() = %+v", user)
+	}
+
+	email := "david@example.com"
+	updated, err := c.UserAdmin().Update(ctx, user.ID, iam.UserUpdate{Email: &email})
+	if err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if updated.Email != email || updated.Name != "Dave" {
+		t.Errorf("Update() = %+v", updated)
+	}
+	if user.Email != "dave@example.com" {
+		t.Error("Update() modified a previously returned user")
+	}
+}
+
+func TestUserAdminService_ValidationErrors(t *testing.T) {
+	c := setup()
+	ctx := context.Background()
+	taken := "ALICE@example.com"
+
+	tests := []struct {
+		name string
+		err  error
+		call func() error
+	}{
+		{"invalid email", iam.ErrInvalidArgument, func() error {
+			_, err := c.UserAdmin().Create(ctx, iam.CreateUserInput{Email: "not-an-email"})
+			return err
+		}},
+		{"duplicate email", iam.ErrAlreadyExists, func() error {
+			_, err := c.UserAdmin().Create(ctx, iam.CreateUserInput{Email: "bob@example.com"})
+			return err
+		}},
+		{"unknown tenant", iam.ErrNotFound, func() error {
+			_, err := c.UserAdmin().Create(ctx, iam.CreateUserInput{Email: "x@example.com", TenantID: "t9"})
+			return err
+		}},
+		{"email taken on update", iam.ErrAlreadyExists, func() error {
+			_, err := c.UserAdmin().Update(ctx, "u2", iam.UserUpdate{Email: &taken})
+			return err
+		}},
+		{"unknown user", iam.ErrNotFound, func() error { return c.UserAdmin().Enable(ctx, "nonexistent") }},
+		{"duplicate role", iam.ErrAlreadyExists, func() error { return c.UserAdmin().AssignRole(ctx, "u1", "admin") }},
+		{"missing role", iam.ErrNotFound, func() error { return c.UserAdmin().RemoveRole(ctx, "u2", "admin") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.err) {
+				t.Errorf("error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestUserAdminService_DisableBlocksSignIn(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", nil),
+		fake.WithSession("u1", "s1"),
+	)
+	ctx := context.Background()
+
+	if err := c.UserAdmin().Disable(ctx, "u1", "compromised"); err != nil {
+		t.Fatalf("Disable() error: %v", err)
+	}
+	if _, err := c.Verifier().Verify(ctx, "u1"); err == nil {
+		t.Error("Verify() expected error for disabled user")
+	}
+	if sessions, _ := c.Sessions().List(ctxAs("u1")); len(sessions) != 0 {
+		t.Errorf("expected sessions revoked, got %d", len(sessions))
+	}
+
+	if err := c.UserAdmin().Enable(ctx, "u1"); err != nil {
+		t.Fatalf("Enable() error: %v", err)
+	}
+	if _, err := c.Verifier().Verify(ctx, "u1"); err != nil {
+		t.Errorf("Verify() after Enable error: %v", err)
+	}
+}
+
+func TestUserAdminService_RolesAndDelete(t *testing.T) {
+	c := setup()
+	ctx := context.Background()
+
+	if err := c.UserAdmin().AssignRole(ctx, "u2", "editor"); err != nil {
+		t.Fatalf("AssignRole() error: %v", err)
+	}
+	if err := c.UserAdmin().RemoveRole(ctx, "u2", "viewer"); err != nil {
+		t.Fatalf("RemoveRole() error: %v", err)
+	}
+	roles, _ := c.Users().GetRoles(ctx, "u2")
+	if len(roles) != 1 || roles[0].ID != "editor" {
+		t.Errorf("roles = %v, want [editor]", roles)
+	}
+
+	if err := c.UserAdmin().Delete(ctx, "u2"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if _, err := c.Users().Get(ctx, "u2"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v, want iam.ErrNotFound", err)
+	}
+}
+
+func TestUserAdminService_ListRoles(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", []string{"editor"}),
+		fake.WithUser("u2", "t2", "bob@example.com", []string{"viewer"}),
+		fake.WithRole("t1", "auditor"),
+	)
+	ctx := context.Background()
+
+	// Removing the last holder keeps the role
+	if err := c.UserAdmin().RemoveRole(ctx, "u1", "editor"); err != nil {
+		t.Fatalf("RemoveRole() error: %v", err)
+	}
+	list, err := c.UserAdmin().ListRoles(ctx, iam.ListRolesOptions{TenantID: "t1"})
+	if err != nil {
+		t.Fatalf("ListRoles() error: %v", err)
+	}
+	if list.Total != 2 || len(list.Roles) != 2 || list.Roles[0].ID != "auditor" || list.Roles[1].ID != "editor" {
+		t.Errorf("ListRoles() = %+v, want [auditor editor]", list)
+	}
+
+	page, _ := c.UserAdmin().ListRoles(ctx, iam.ListRolesOptions{TenantID: "t1", Offset: 1, Limit: 5})
+	if page.Total != 2 || len(page.Roles) != 1 || page.Roles[0].ID != "editor" {
+		t.Errorf("second page = %+v, want [editor] of 2", page)
+	}
+	one, _ := c.UserAdmin().ListRoles(ctx, iam.ListRolesOptions{TenantID: "t1", Role: "auditor"})
+	if one.Total != 1 || one.Roles[0].ID != "auditor" {
+		t.Errorf("Role filter = %+v, want [auditor]", one)
+	}
+}
+
 // --- TenantService ---
 
 func TestTenantService_ResolveByID(t *testing.T) {
@@ -230,6 +465,181 @@ func TestTenantService_ValidateMembership(t *testing.T) {
 	}
 }
 
+func TestTenantService_ListMemberships(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", []string{"admin"}),
+		fake.WithTenant("t1", "acme", "active"),
+		fake.WithTenant("t2", "globex", "active"),
+		fake.WithMembership("u1", "t2", "viewer"),
+	)
+
+	memberships, err := c.Tenants().ListMemberships(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("ListMemberships() error: %v", err)
+	}
+	if len(memberships) != 2 {
+		t.Fatalf("ListMemberships() = %d memberships, want 2", len(memberships))
+	}
+	if memberships[0].Tenant.Slug != "acme" || memberships[0].Role.Name != "admin" {
+		t.Errorf("memberships[0] = %+v, want acme/admin", memberships[0])
+	}
+	if memberships[1].Tenant.Slug != "globex" || memberships[1].Role.Name != "viewer" {
+		t.Errorf("memberships[1] = %+v, want globex/viewer", memberships[1])
+	}
+
+	ok, _ := c.Tenants().ValidateMembership(context.Background(), "u1", "t2")
+	if !ok {
+		t.Error("ValidateMembership(u1, t2) = false, want true for extra membership")
+	}
+}
+
+// --- SessionService ---
+
+func sessionClient() *iam.Client {
+	return fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", []string{"admin"}),
+		fake.WithSession("u1", "s1"),
+		fake.WithSession("u1", "s2"),
+		fake.WithSession("u1", "s3"),
+	)
+}
+
+func TestVerifier_ImpersonationToken(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", nil),
+		fake.WithUser("support", "t1", "support@example.com", nil),
+		fake.WithImpersonation("imp", "support", "u1"),
+	)
+
+	claims, err := c.Verifier().Verify(context.Background(), "imp")
+
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if claims.Subject != "u1" || claims.TenantID != "t1" || claims.Actor == nil || claims.Actor.Subject != "support" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	if claims, _ := c.Verifier().Verify(context.Background(), "u1"); claims.Actor != nil {
+		t.Errorf("plain token should have no actor, got %+v", claims.Actor)
+	}
+}
+
+func TestVerifier_SessionToken(t *testing.T) {
+	c := sessionClient()
+	claims, err := c.Verifier().Verify(context.Background(), "s2")
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if claims.Subject != "u1" || claims.SessionID != "s2" {
+		t.Errorf("claims = %s/%s, want u1/s2", claims.Subject, claims.SessionID)
+	}
+}
+
+func TestSessionService_ListMarksCurrent(t *testing.T) {
+	c := sessionClient()
+	sessions, err := c.Sessions().List(fake.ContextWithSession(context.Background(), "u1", "s2"))
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	for _, s := range sessions {
+		if s.Current != (s.ID == "s2") {
+			t.Errorf("session %s Current = %v", s.ID, s.Current)
+		}
+	}
+}
+
+func TestSessionService_RevokeAllOthers(t *testing.T) {
+	c := sessionClient()
+	ctx := fake.ContextWithSession(context.Background(), "u1", "s2")
+
+	if err := c.Sessions().RevokeAllOthers(ctx); err != nil {
+		t.Fatalf("RevokeAllOthers() error: %v", err)
+	}
+	sessions, _ := c.Sessions().List(ctx)
+	if len(sessions) != 1 || sessions[0].ID != "s2" || !sessions[0].Current {
+		t.Errorf("sessions = %+v, want only current s2", sessions)
+	}
+}
+
+func TestSessionService_RevokeAllOthers_NoCurrentSession(t *testing.T) {
+	c := sessionClient()
+
+	if err := c.Sessions().RevokeAllOthers(ctxAs("u1")); err == nil {
+		t.Fatal("RevokeAllOthers() expected error without current session")
+	}
+	sessions, _ := c.Sessions().List(ctxAs("u1"))
+	if len(sessions) != 3 {
+		t.Errorf("expected 3 sessions kept, got %d", len(sessions))
+	}
+}
+
+func TestSessionService_ValidateAndTouch(t *testing.T) {
+	c := sessionClient()
+	ctx := fake.ContextWithSession(context.Background(), "u1", "s1")
+
+	before, err := c.Sessions().Validate(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := c.Sessions().Touch(ctx, "s1"); err != nil {
+		t.Fatalf("Touch() error: %v", err)
+	}
+	after, _ := c.Sessions().Validate(ctx, "s1")
+	if !after.LastActiveAt.After(before.LastActiveAt) {
+		t.Errorf("LastActiveAt not advanced: %v -> %v", before.LastActiveAt, after.LastActiveAt)
+	}
+
+	_ = c.Sessions().Revoke(ctx, "s2")
+	if _, err := c.Sessions().Validate(ctx, "s2"); !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("Validate() revoked session error = %v, want ErrSessionInvalid", err)
+	}
+}
+
+func TestSessionService_Devices(t *testing.T) {
+	const chromeOnMac = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	c := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", []string{"admin"}),
+		fake.WithSessionDetails(iam.Session{ID: "s1", UserID: "u1", DeviceID: "d1", UserAgent: chromeOnMac}),
+		fake.WithSessionDetails(iam.Session{ID: "s2", UserID: "u1", DeviceID: "d1", UserAgent: chromeOnMac}),
+		fake.WithSessionDetails(iam.Session{ID: "s3", UserID: "u1", DeviceID: "d2", Device: "mobile", OS: "iOS"}),
+	)
+	ctx := ctxAs("u1")
+
+	sessions, _ := c.Sessions().List(ctx)
+	if sessions[0].OS != "macOS" || sessions[0].Browser != "Chrome" || sessions[0].Device != "desktop" {
+		t.Errorf("session not parsed from User-Agent: %+v", sessions[0])
+	}
+
+	dm, ok := c.Sessions().(iam.DeviceManager)
+	if !ok {
+		t.Fatal("fake SessionService should implement iam.DeviceManager")
+	}
+	if err := dm.RenameDevice(ctx, "d1", "Work laptop"); err != nil {
+		t.Fatalf("RenameDevice() error: %v", err)
+	}
+	if err := dm.TrustDevice(ctx, "d1", true); err != nil {
+		t.Fatalf("TrustDevice() error: %v", err)
+	}
+	if err := dm.TrustDevice(ctxAs("u2"), "d1", true); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("TrustDevice() on another user's device error = %v, want ErrNotFound", err)
+	}
+
+	devices, err := dm.ListDevices(ctx)
+	if err != nil {
+		t.Fatalf("ListDevices() error: %v", err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("ListDevices() = %d devices, want 2", len(devices))
+	}
+	if devices[0].ID != "d1" || devices[0].Name != "Work laptop" || !devices[0].Trusted {
+		t.Errorf("devices[0] = %+v", devices[0])
+	}
+	if devices[1].ID != "d2" || devices[1].Trusted || devices[1].OS != "iOS" {
+		t.Errorf("devices[1] = %+v", devices[1])
+	}
+}
+
 // --- OAuth2TokenExchanger ---
 
 func TestOAuth2_ExchangeToken(t *testing.T) {
@@ -275,3 +685,172 @@ func TestOAuth2_NotConfigured(t *testing.T) {
 		t.Error("OAuth2() should be nil when no OAuth2App is configured")
 	}
 }
+
+func TestOAuth2_SwitchTenant(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", []string{"admin"}),
+		fake.WithMembership("u1", "t2", "viewer"),
+		fake.WithOAuth2App("app_test", "secret_test", nil),
+	)
+	switcher, ok := c.OAuth2().(iam.TenantSwitcher)
+	if !ok {
+		t.Fatal("fake OAuth2 exchanger should implement iam.TenantSwitcher")
+	}
+
+	token, err := switcher.SwitchTenant(context.Background(), "u1", "t2")
+	if err != nil {
+		t.Fatalf("SwitchTenant() error: %v", err)
+	}
+	claims, err := c.Verifier().Verify(context.Background(), token.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if claims.Subject != "u1" || claims.TenantID != "t2" {
+		t.Errorf("claims = %s/%s, want u1/t2", claims.Subject, claims.TenantID)
+	}
+
+	if _, err := switcher.SwitchTenant(context.Background(), "u1", "t3"); err == nil {
+		t.Error("SwitchTenant() expected error for non-member tenant")
+	}
+}
+
+func TestAPIKeys_Lifecycle(t *testing.T) {
+	c := setup()
+	ctx := context.Background()
+	keys := c.APIKeys()
+	verifier := apikey.NewVerifier(keys, apikey.WithCacheTTL(0))
+
+	created, err := keys.Create(ctx, iam.CreateAPIKeyInput{Name: "ci", UserID: "u1", TenantID: "t1", Scopes: []string{"records:read"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.Key == "" || created.SecretHash != "" || created.Prefix == "" {
+		t.Errorf("unexpected key: %+v", created)
+	}
+	claims, err := verifier.Verify(ctx, created.Key)
+	if err != nil || claims.Subject != "u1" || claims.Scopes[0] != "records:read" {
+		t.Fatalf("Verify = %+v, %v", claims, err)
+	}
+
+	rotated, err := keys.Rotate(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if rotated.ID != created.ID || rotated.Key == created.Key {
+		t.Errorf("unexpected rotated key: %+v", rotated)
+	}
+	if _, err := verifier.Verify(ctx, created.Key); !errors.Is(err, apikey.ErrInvalidKey) {
+		t.Errorf("old key after rotation: expected ErrInvalidKey, got %v", err)
+	}
+
+	if err := keys.Revoke(ctx, created.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := verifier.Verify(ctx, rotated.Key); !errors.Is(err, apikey.ErrInvalidKey) {
+		t.Errorf("revoked key: expected ErrInvalidKey, got %v", err)
+	}
+	if _, err := keys.Rotate(ctx, created.ID); !errors.Is(err, iam.ErrInvalidArgument) {
+		t.Errorf("rotating revoked key: expected ErrInvalidArgument, got %v", err)
+	}
+
+	list, err := keys.List(ctx, "u1")
+	if err != nil || len(list) != 1 || list[0].RevokedAt.IsZero() || list[0].SecretHash != "" {
+		t.Errorf("List = %+v, %v", list, err)
+	}
+}
+
+func TestAPIKeys_Errors(t *testing.T) {
+	c := setup()
+	ctx := context.Background()
+
+	if _, err := c.APIKeys().Create(ctx, iam.CreateAPIKeyInput{UserID: "nobody"}); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("unknown user: expected ErrNotFound, got %v", err)
+	}
+	past := iam.CreateAPIKeyInput{UserID: "u1", ExpiresAt: time.Now().Add(-time.Hour)}
+	if _, err := c.APIKeys().Create(ctx, past); !errors.Is(err, iam.ErrInvalidArgument) {
+		t.Errorf("past expiry: expected ErrInvalidArgument, got %v", err)
+	}
+	if err := c.APIKeys().Revoke(ctx, "key-404"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("unknown key: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestWithAPIKey(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", nil),
+		fake.WithAPIKey("iam_test_secret", "u1", "t1", nil),
+	)
+
+	claims, err := apikey.NewVerifier(c.APIKeys()).Verify(context.Background(), "iam_test_secret")
+
+	if err != nil || claims.Subject != "u1" || claims.Scopes != nil {
+		t.Errorf("Verify = %+v, %v", claims, err)
+	}
+}
+
+func TestServiceAccounts_Lifecycle(t *testing.T) {
+	c := setup()
+	ctx := context.Background()
+	accounts := c.ServiceAccounts()
+
+	sa, err := accounts.Create(ctx, iam.CreateServiceAccountInput{Name: "billing-worker", ClientID: "billing"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if sa.ID == "" || sa.ClientID != "billing" {
+		t.Errorf("unexpected account: %+v", sa)
+	}
+	if _, err := accounts.Create(ctx, iam.CreateServiceAccountInput{Name: "dup", ClientID: "billing"}); !errors.Is(err, iam.ErrAlreadyExists) {
+		t.Errorf("duplicate client: expected ErrAlreadyExists, got %v", err)
+	}
+
+	for _, b := range [][2]string{{"t1", "reader"}, {"t1", "writer"}, {"t2", "reader"}, {"t1", "reader"}} {
+		if err := accounts.BindRole(ctx, sa.ID, b[0], b[1]); err != nil {
+			t.Fatalf("BindRole(%v): %v", b, err)
+		}
+	}
+	got, err := accounts.Get(ctx, sa.ID)
+	if err != nil || len(got.Bindings) != 3 || len(got.RolesIn("t1")) != 2 {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+
+	if err := accounts.UnbindRole(ctx, sa.ID, "t2", "reader"); err != nil {
+		t.Fatalf("UnbindRole: %v", err)
+	}
+	if list, _ := accounts.List(ctx, "t2"); len(list) != 0 {
+		t.Errorf("List(t2) = %+v, want none", list)
+	}
+	if list, _ := accounts.List(ctx, ""); len(list) != 1 {
+		t.Errorf("List() = %+v, want one", list)
+	}
+
+	if err := accounts.Delete(ctx, sa.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := accounts.Get(ctx, sa.ID); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("deleted account: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestVerifier_ServiceAccountToken(t *testing.T) {
+	c := fake.NewClient(
+		fake.WithServiceAccount("sa-sync", "sync"),
+		fake.WithServiceAccountRole("sa-sync", "t1", "reader"),
+		fake.WithOAuth2App("sync", "secret", nil),
+	)
+	ctx := context.Background()
+
+	token, err := c.OAuth2().GetCachedToken(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tok := range []string{"sa-sync", token} {
+		claims, err := c.Verifier().Verify(ctx, tok)
+		if err != nil {
+			t.Fatalf("Verify(%q): %v", tok, err)
+		}
+		if claims.Subject != "sa-sync" || claims.Principal() != iam.PrincipalServiceAccount || claims.TenantID != "" {
+			t.Errorf("Verify(%q) = %+v", tok, claims)
+		}
+	}
+}
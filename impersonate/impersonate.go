@@ -0,0 +1,182 @@
+// Package impersonate lets support staff act as another user ("view as user").
+//
+// An impersonation token is an ordinary token for the impersonated user that
+// carries an RFC 8693 "act" claim naming the staff member:
+//
+//	{"sub": "customer-42", "act": {"sub": "support-7"}}
+//
+// The auth middleware rejects such tokens unless it is given a Policy
+// (kratosmw.WithImpersonation, grpcmw.WithImpersonation). The Policy checks
+// that the actor holds the impersonation permission, records both identities
+// in the context (iam.UserIDFromContext is the impersonated user,
+// iam.ActorIDFromContext the actor) and writes an audit event per request.
+package impersonate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/audit"
+)
+
+// DefaultPermission is the permission an actor needs to impersonate users.
+const DefaultPermission = "iam:impersonate"
+
+// AuditAction is the audit.Event action for impersonated requests.
+const AuditAction = "impersonation"
+
+// ErrNotPermitted is returned by Policy.Authorize when the actor may not
+// impersonate the token's subject.
+var ErrNotPermitted = errors.New("iam/impersonate: impersonation not permitted")
+
+// Policy decides whether impersonation tokens are accepted and which
+// permissions are checked against the actor instead of the impersonated user.
+type Policy struct {
+	authz            iam.Authorizer
+	permission       string
+	actorPermissions []string
+	logger           *audit.Logger
+}
+
+// Option configures Policy behavior.
+type Option func(*Policy)
+
+// WithPermission sets the permission the actor must hold in the token's
+// tenant. Default: DefaultPermission.
+func WithPermission(permission string) Option {
+	return func(p *Policy) {
+		p.permission = permission
+	}
+}
+
+// WithActorPermissions lists permissions that are always evaluated against
+// the actor, so that impersonating a user never grants more than the actor
+// could do themselves (e.g. "billing:refund", "users:delete"). A trailing
+// "*" matches any permission with that prefix ("billing:*").
+func WithActorPermissions(permissions ...string) Option {
+	return func(p *Policy) {
+		p.actorPermissions = append(p.actorPermissions, permissions...)
+	}
+}
+
+// WithAuditLogger sets the audit logger. Defaults to audit.FromContext.
+func WithAuditLogger(l *audit.Logger) Option {
+	return func(p *Policy) {
+		p.logger = l
+	}
+}
+
+// NewPolicy creates a Policy that checks actors with authz.
+func NewPolicy(authz iam.Authorizer, opts ...Option) *Policy {
+	p := &Policy{authz: authz, permission: DefaultPermission}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Authorize admits an impersonated request for operation. ctx must already
+// carry the token's user and tenant. If claims has no actor, ctx is returned
+// unchanged. Otherwise the actor must hold the impersonation permission; on
+// success the returned context carries the actor ID and the Policy (see Check).
+//
+// Every impersonated request is audited, whether admitted or not.
+func (p *Policy) Authorize(ctx context.Context, claims *iam.Claims, operation string) (context.Context, error) {
+	if claims == nil || claims.Actor == nil {
+		return ctx, nil
+	}
+	actor := claims.Actor.Subject
+
+	ok, err := p.authz.Check(iam.WithUserID(ctx, actor), p.permission)
+	switch {
+	case err != nil:
+		err = fmt.Errorf("iam/impersonate: checking actor %q: %w", actor, err)
+		p.audit(ctx, claims, operation, "failure", err)
+		return ctx, err
+	case !ok:
+		p.audit(ctx, claims, operation, "denied", nil)
+		return ctx, ErrNotPermitted
+	}
+
+	ctx = iam.WithActorID(ctx, actor)
+	ctx = context.WithValue(ctx, contextKeyPolicy, p)
+	p.audit(ctx, claims, operation, "success", nil)
+	return ctx, nil
+}
+
+// Check checks permission with authz like authz.Check, except that during
+// impersonation permissions listed in WithActorPermissions are checked
+// against the actor. Middleware uses it for Require and RequireAny.
+func Check(ctx context.Context, authz iam.Authorizer, permission string) (bool, error) {
+	if p, ok := ctx.Value(contextKeyPolicy).(*Policy); ok && p.actorScoped(permission) {
+		ctx = AsActor(ctx)
+	}
+	return authz.Check(ctx, permission)
+}
+
+// AsActor returns a context whose user is the actor, for calls that must be
+// made with the actor's own rights. Outside impersonation ctx is returned
+// unchanged.
+func AsActor(ctx context.Context) context.Context {
+	if actor := iam.ActorIDFromContext(ctx); actor != "" {
+		return iam.WithUserID(ctx, actor)
+	}
+	return ctx
+}
+
+func (p *Policy) actorScoped(permission string) bool {
+	for _, pattern := range p.actorPermissions {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(permission, prefix) {
+				return true
+			}
+		} else if pattern == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) audit(ctx context.Context, claims *iam.Claims, operation, result string, err error) {
+	logger := p.logger
+	if logger == nil {
+		logger = audit.FromContext(ctx)
+	}
+	if logger == nil {
+		return
+	}
+
+	event := audit.Event{
+		RequestID:     audit.RequestID(ctx),
+		UserID:        claims.Subject,
+		ActorID:       claims.Actor.Subject,
+		PrincipalType: string(claims.Principal()),
+		TenantID:      claims.TenantID,
+		Action:        AuditAction,
+		Resource:      operation,
+		Result:        result,
+	}
+	if chain := actorChain(claims.Actor.Actor); chain != "" {
+		event.Details = "delegated_by=" + chain
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	logger.Log(event)
+}
+
+// actorChain lists the earlier actors of a delegation chain, most recent first.
+func actorChain(a *iam.Actor) string {
+	var subjects []string
+	for ; a != nil; a = a.Actor {
+		subjects = append(subjects, a.Subject)
+	}
+	return strings.Join(subjects, ",")
+}
+
+type contextKey string
+
+const contextKeyPolicy contextKey = "impersonate.policy"
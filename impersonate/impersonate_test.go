@@ -0,0 +1,118 @@
+package impersonate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/audit/audittest"
+	"github.com/chimerakang/iam-go/fake"
+)
+
+func newClient() *iam.Client {
+	return fake.NewClient(
+		fake.WithUser("support", "t1", "support@example.com", []string{"support"}),
+		fake.WithUser("customer", "t1", "customer@example.com", nil),
+		fake.WithPermissions("support", []string{DefaultPermission, "tickets:read"}),
+		fake.WithPermissions("customer", []string{"billing:refund", "billing:view", "orders:read"}),
+	)
+}
+
+func impersonated(actor string) (context.Context, *iam.Claims) {
+	claims := &iam.Claims{Subject: "customer", TenantID: "t1", Actor: &iam.Actor{Subject: actor}}
+	ctx := iam.WithTenantID(iam.WithUserID(context.Background(), claims.Subject), claims.TenantID)
+	return ctx, claims
+}
+
+func TestAuthorize(t *testing.T) {
+	rec := audittest.NewRecorder()
+	p := NewPolicy(newClient().Authz(), WithAuditLogger(rec.Logger))
+	ctx, claims := impersonated("support")
+
+	ctx, err := p.Authorize(ctx, claims, "/orders.v1.Orders/List")
+
+	if err != nil {
+		t.Fatalf("Authorize returned error: %v", err)
+	}
+	if iam.UserIDFromContext(ctx) != "customer" || iam.ActorIDFromContext(ctx) != "support" {
+		t.Errorf("user = %q, actor = %q", iam.UserIDFromContext(ctx), iam.ActorIDFromContext(ctx))
+	}
+	if got := iam.ActingUserIDFromContext(ctx); got != "support" {
+		t.Errorf("ActingUserIDFromContext = %q, want support", got)
+	}
+	got := rec.Events()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 audit event, got %d", len(got))
+	}
+	if e := got[0]; e.Action != AuditAction || e.Result != "success" || e.UserID != "customer" ||
+		e.ActorID != "support" || e.Resource != "/orders.v1.Orders/List" {
+		t.Errorf("unexpected audit event: %+v", e)
+	}
+}
+
+func TestAuthorize_Denied(t *testing.T) {
+	rec := audittest.NewRecorder()
+	p := NewPolicy(newClient().Authz(), WithAuditLogger(rec.Logger))
+	// The customer may not impersonate anyone, not even via a delegation chain.
+	ctx, claims := impersonated("customer")
+	claims.Actor.Actor = &iam.Actor{Subject: "support"}
+
+	ctx, err := p.Authorize(ctx, claims, "op")
+
+	if !errors.Is(err, ErrNotPermitted) {
+		t.Fatalf("expected ErrNotPermitted, got %v", err)
+	}
+	if iam.ActorIDFromContext(ctx) != "" {
+		t.Error("denied request should not carry an actor")
+	}
+	if got := rec.Events(); len(got) != 1 || got[0].Result != "denied" || got[0].Details != "delegated_by=support" {
+		t.Errorf("unexpected audit events: %+v", got)
+	}
+}
+
+func TestAuthorize_NoActor(t *testing.T) {
+	rec := audittest.NewRecorder()
+	p := NewPolicy(newClient().Authz(), WithAuditLogger(rec.Logger))
+	ctx := context.Background()
+
+	got, err := p.Authorize(ctx, &iam.Claims{Subject: "customer"}, "op")
+
+	if err != nil || got != ctx {
+		t.Errorf("expected unchanged context, got err %v", err)
+	}
+	if n := len(rec.Events()); n != 0 {
+		t.Errorf("expected no audit events, got %d", n)
+	}
+}
+
+func TestCheck_ActorPermissions(t *testing.T) {
+	authz := newClient().Authz()
+	p := NewPolicy(authz, WithActorPermissions("billing:*", "tickets:read"))
+	ctx, claims := impersonated("support")
+	ctx, err := p.Authorize(ctx, claims, "op")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		permission string
+		want       bool
+	}{
+		{"orders:read", true},     // the customer's permission
+		{"billing:refund", false}, // actor-scoped: support may not refund
+		{"billing:view", false},
+		{"tickets:read", true}, // actor-scoped and held by support
+	}
+	for _, tt := range tests {
+		ok, err := Check(ctx, authz, tt.permission)
+		if err != nil || ok != tt.want {
+			t.Errorf("Check(%s) = %v, %v; want %v", tt.permission, ok, err, tt.want)
+		}
+	}
+
+	// Without impersonation, actor-scoped permissions are the user's own.
+	if ok, _ := Check(fake.ContextWithUserID(context.Background(), "customer"), authz, "billing:refund"); !ok {
+		t.Error("expected customer to refund outside impersonation")
+	}
+}
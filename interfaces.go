@@ -30,11 +30,103 @@ type UserService interface {
 	// Get returns a user by ID.
 	Get(ctx context.Context, userID string) (*User, error)
 
-	// List returns users with pagination.
-	List(ctx context.Context, opts ListOptions) ([]*User, int, error)
+	// List returns one page of users matching opts.Filter.
+	List(ctx context.Context, opts ListOptions) (*UserList, error)
 
 	// GetRoles returns the roles assigned to a user.
 	GetRoles(ctx context.Context, userID string) ([]Role, error)
+
+	// GetMany returns the users with the given IDs, keyed by ID. Unknown IDs
+	// are absent from the result rather than an error.
+	GetMany(ctx context.Context, userIDs []string) (map[string]*User, error)
+}
+
+// UserAdminService creates, changes and removes user accounts.
+//
+// Implementations report validation failures with errors wrapping
+// ErrInvalidArgument, duplicates with ErrAlreadyExists and unknown users or
+// roles with ErrNotFound.
+type UserAdminService interface {
+	// Create creates a user.
+	Create(ctx context.Context, input CreateUserInput) (*User, error)
+
+	// Update changes the non-nil fields of update.
+	Update(ctx context.Context, userID string, update UserUpdate) (*User, error)
+
+	// Disable blocks the user from signing in and revokes their sessions.
+	// Disabling a disabled user is not an error.
+	Disable(ctx context.Context, userID, reason string) error
+
+	// Enable re-enables a disabled user. Enabling an active user is not an error.
+	Enable(ctx context.Context, userID string) error
+
+	// Delete permanently deletes a user.
+	Delete(ctx context.Context, userID string) error
+
+	// AssignRole grants a role to a user.
+	AssignRole(ctx context.Context, userID, roleID string) error
+
+	// RemoveRole revokes a role from a user.
+	RemoveRole(ctx context.Context, userID, roleID string) error
+
+	// ListRoles returns one page of the roles defined in opts.TenantID,
+	// ordered by ID, whether or not any user holds them.
+	ListRoles(ctx context.Context, opts ListRolesOptions) (*RoleList, error)
+}
+
+// APIKeyService manages long-lived API keys.
+//
+// A key is shown to its owner once, at creation or rotation; the server keeps
+// only its prefix and a hash of the full key (see the apikey package).
+// Unknown keys are reported with errors wrapping ErrNotFound.
+type APIKeyService interface {
+	// Create issues a key for input.UserID in input.TenantID.
+	Create(ctx context.Context, input CreateAPIKeyInput) (*NewAPIKey, error)
+
+	// List returns the user's keys, including revoked and expired ones.
+	// SecretHash is not set.
+	List(ctx context.Context, userID string) ([]APIKey, error)
+
+	// Revoke disables a key. Revoking a revoked key is not an error.
+	Revoke(ctx context.Context, keyID string) error
+
+	// Rotate replaces a key's prefix and secret, keeping its ID, name,
+	// scopes and expiry. The old key stops working immediately.
+	Rotate(ctx context.Context, keyID string) (*NewAPIKey, error)
+
+	// Lookup returns the key with the given prefix, with SecretHash set, for
+	// verifying presented keys locally.
+	Lookup(ctx context.Context, prefix string) (*APIKey, error)
+}
+
+// ServiceAccountService manages service accounts and their role bindings.
+// Unlike users, service accounts belong to no tenant: they are bound to roles
+// per tenant, and may be bound in several.
+//
+// Implementations report validation failures with errors wrapping
+// ErrInvalidArgument, duplicates with ErrAlreadyExists and unknown accounts,
+// tenants or roles with ErrNotFound.
+type ServiceAccountService interface {
+	// Create creates a service account.
+	Create(ctx context.Context, input CreateServiceAccountInput) (*ServiceAccount, error)
+
+	// Get returns a service account by ID, with its bindings.
+	Get(ctx context.Context, id string) (*ServiceAccount, error)
+
+	// List returns the service accounts bound to a role in tenantID, or all
+	// service accounts if tenantID is empty.
+	List(ctx context.Context, tenantID string) ([]*ServiceAccount, error)
+
+	// Delete deletes a service account and its bindings.
+	Delete(ctx context.Context, id string) error
+
+	// BindRole grants the account a role in a tenant. Binding a bound role
+	// is not an error.
+	BindRole(ctx context.Context, id, tenantID, roleID string) error
+
+	// UnbindRole revokes a role binding. Unbinding an unbound role is not an
+	// error.
+	UnbindRole(ctx context.Context, id, tenantID, roleID string) error
 }
 
 // TenantService manages tenant resolution and membership.
@@ -44,6 +136,9 @@ type TenantService interface {
 
 	// ValidateMembership returns true if the user belongs to the tenant.
 	ValidateMembership(ctx context.Context, userID, tenantID string) (bool, error)
+
+	// ListMemberships returns all tenants the user belongs to.
+	ListMemberships(ctx context.Context, userID string) ([]Membership, error)
 }
 
 // SessionService manages user sessions.
@@ -56,6 +151,26 @@ type SessionService interface {
 
 	// RevokeAllOthers terminates all sessions except the current one.
 	RevokeAllOthers(ctx context.Context) error
+
+	// Validate returns the session if it is still active. Revoked, expired or
+	// unknown sessions yield an error wrapping ErrSessionInvalid.
+	Validate(ctx context.Context, sessionID string) (*Session, error)
+
+	// Touch records activity on the session, updating LastActiveAt.
+	Touch(ctx context.Context, sessionID string) error
+}
+
+// DeviceManager manages the devices a user has signed in from.
+// SessionService implementations may implement it; callers use a type assertion.
+type DeviceManager interface {
+	// ListDevices returns the current user's devices.
+	ListDevices(ctx context.Context) ([]Device, error)
+
+	// RenameDevice sets a display name for one of the current user's devices.
+	RenameDevice(ctx context.Context, deviceID, name string) error
+
+	// TrustDevice marks one of the current user's devices as trusted or untrusted.
+	TrustDevice(ctx context.Context, deviceID string, trusted bool) error
 }
 
 // OAuth2TokenExchanger exchanges OAuth2 client credentials for access tokens.
@@ -67,3 +182,30 @@ type OAuth2TokenExchanger interface {
 	// GetCachedToken returns a valid cached token, or fetches a new one if expired.
 	GetCachedToken(ctx context.Context) (string, error)
 }
+
+// TokenProvider returns cached access tokens per target, so a service calling
+// several downstream APIs gets a token with the scopes and audience of each.
+// Implementations: oauth2/ (Exchanger), fake/ (testing).
+type TokenProvider interface {
+	// GetToken returns a valid cached token for req, or fetches a new one.
+	GetToken(ctx context.Context, req TokenRequest) (*OAuth2Token, error)
+}
+
+// OnBehalfOfExchanger exchanges the token of a service's caller for a
+// token to call a downstream service on the caller's behalf (RFC 8693
+// delegation): restricted to the target's audience and scopes, and naming
+// the calling service in its "act" claim.
+// Implementations: oauth2/ (Exchanger), fake/ (testing).
+type OnBehalfOfExchanger interface {
+	// ExchangeOnBehalfOf returns a cached or new token for req on behalf of
+	// the subject of subjectToken.
+	ExchangeOnBehalfOf(ctx context.Context, subjectToken string, req TokenRequest) (*OAuth2Token, error)
+}
+
+// TenantSwitcher exchanges a user's token for one scoped to another tenant,
+// so the active tenant can change without re-authentication.
+// Implementations: oauth2/ (RFC 8693 token exchange), fake/ (testing).
+type TenantSwitcher interface {
+	// SwitchTenant returns a new token for the subject of subjectToken, scoped to tenantID.
+	SwitchTenant(ctx context.Context, subjectToken, tenantID string) (*OAuth2Token, error)
+}
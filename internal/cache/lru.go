@@ -0,0 +1,117 @@
+// Package cache provides a bounded, TTL-aware LRU cache shared by the
+// service implementations in this module.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a fixed-capacity cache with per-entry expiry. When full, the least
+// recently used entry is evicted. Safe for concurrent use.
+type LRU[K comparable, V any] struct {
+	mu    sync.Mutex
+	max   int
+	ll    *list.List
+	items map[K]*list.Element
+	now   func() time.Time
+}
+
+type item[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// New creates an LRU holding at most maxEntries entries. maxEntries <= 0 means unbounded.
+func New[K comparable, V any](maxEntries int) *LRU[K, V] {
+	return &LRU[K, V]{
+		max:   maxEntries,
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+		now:   time.Now,
+	}
+}
+
+// Get returns the value for key if present and not expired.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	it := el.Value.(*item[K, V])
+	if !c.now().Before(it.expiresAt) {
+		c.removeElement(el)
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	return it.value, true
+}
+
+// Set stores value under key for ttl, evicting the least recently used entry if full.
+func (c *LRU[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		it := el.Value.(*item[K, V])
+		it.value = value
+		it.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&item[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.max > 0 && c.ll.Len() > c.max {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Delete removes key from the cache.
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// DeleteFunc removes every entry whose key matches fn.
+func (c *LRU[K, V]) DeleteFunc(fn func(K) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.items {
+		if fn(key) {
+			c.removeElement(el)
+		}
+	}
+}
+
+// Clear removes all entries.
+func (c *LRU[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = make(map[K]*list.Element)
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRU[K, V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*item[K, V]).key)
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU_GetSet(t *testing.T) {
+	c := New[string, int](10)
+	c.Set("a", 1, time.Minute)
+
+	v, ok := c.Get("a")
+	if !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %v; want 1, true", v, ok)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Error("Get(missing) should miss")
+	}
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	now := time.Now()
+	c := New[string, int](10)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1, time.Second)
+	now = now.Add(2 * time.Second)
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("expired entry should miss")
+	}
+	if c.Len() != 0 {
+		t.Errorf("expired entry should be removed on Get, Len = %d", c.Len())
+	}
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2)
+	c.Set("a", 1, time.Minute)
+	c.Set("b", 2, time.Minute)
+	c.Get("a") // a is now most recently used
+	c.Set("c", 3, time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("a should still be cached")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestLRU_Unbounded(t *testing.T) {
+	c := New[int, int](0)
+	for i := 0; i < 100; i++ {
+		c.Set(i, i, time.Minute)
+	}
+	if c.Len() != 100 {
+		t.Errorf("Len = %d, want 100", c.Len())
+	}
+}
+
+func TestLRU_DeleteAndClear(t *testing.T) {
+	c := New[string, int](10)
+	c.Set("user:1", 1, time.Minute)
+	c.Set("user:2", 2, time.Minute)
+	c.Set("role:1", 3, time.Minute)
+
+	c.Delete("user:1")
+	if _, ok := c.Get("user:1"); ok {
+		t.Error("user:1 should be deleted")
+	}
+
+	c.DeleteFunc(func(k string) bool { return k[:5] == "user:" })
+	if c.Len() != 1 {
+		t.Errorf("Len after DeleteFunc = %d, want 1", c.Len())
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("Len after Clear = %d, want 0", c.Len())
+	}
+}
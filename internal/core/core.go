@@ -0,0 +1,441 @@
+// Package core is the transport-independent authentication and
+// authorization pipeline shared by the middleware packages and scim.
+//
+// Each of them extracts a Request from its transport, runs the pipeline,
+// and maps the resulting *Error to its own error representation (Kratos
+// errors, gRPC status, HTTP problem details, SCIM errors, ...), so that
+// every transport enforces the same rules with the same messages.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/risk"
+	"github.com/chimerakang/iam-go/serviceaccount"
+	"github.com/chimerakang/iam-go/session"
+)
+
+// Kind classifies a pipeline failure.
+type Kind int
+
+const (
+	// Unauthenticated: the caller's credentials are missing or rejected.
+	Unauthenticated Kind = iota + 1
+	// PermissionDenied: the caller is authenticated but not allowed.
+	PermissionDenied
+	// Internal: the pipeline could not reach a decision.
+	Internal
+)
+
+// RFC 6750 section 3.1 error codes, for Error.Bearer.
+const (
+	BearerInvalidRequest    = "invalid_request"
+	BearerInvalidToken      = "invalid_token"
+	BearerInsufficientScope = "insufficient_scope"
+)
+
+// Error is a pipeline failure. Message is safe to return to the caller.
+type Error struct {
+	Kind    Kind
+	Message string
+
+	// Bearer is the RFC 6750 error code for HTTP challenges; empty when no
+	// credentials were sent or the failure is not about the token.
+	Bearer string
+	// Scope lists the permissions that were required, for
+	// BearerInsufficientScope.
+	Scope []string
+	// StepUp is set when the caller must re-authenticate, more strongly or
+	// more recently (Kind is Unauthenticated).
+	StepUp *iam.StepUpError
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Challenge returns the WWW-Authenticate value for e: an RFC 9470 challenge
+// for step-up, an RFC 6750 Bearer challenge otherwise (a bare one when no
+// credentials were sent, section 3.1), or "" for failures that have none.
+func (e *Error) Challenge() string {
+	switch {
+	case e.StepUp != nil:
+		return e.StepUp.Challenge()
+	case e.Bearer != "":
+		c := fmt.Sprintf(`Bearer error=%q, error_description=%q`, e.Bearer, e.Message)
+		if len(e.Scope) > 0 {
+			c += fmt.Sprintf(`, scope=%q`, strings.Join(e.Scope, " "))
+		}
+		return c
+	case e.Kind == Unauthenticated:
+		return "Bearer"
+	}
+	return ""
+}
+
+func unauthenticated(bearer, msg string) *Error {
+	return &Error{Kind: Unauthenticated, Message: msg, Bearer: bearer}
+}
+
+func forbidden(msg string) *Error {
+	return &Error{Kind: PermissionDenied, Message: msg}
+}
+
+func internal(msg string) *Error {
+	return &Error{Kind: Internal, Message: msg}
+}
+
+// StepUp returns the Error for a step-up requirement.
+func StepUp(e *iam.StepUpError) *Error {
+	return &Error{Kind: Unauthenticated, Message: "step-up authentication required", StepUp: e}
+}
+
+// Config holds the authentication options common to all middleware.
+type Config struct {
+	RiskHook        *risk.Hook
+	Impersonation   *impersonate.Policy
+	APIKeys         iam.TokenVerifier
+	ServiceAccounts *serviceaccount.Resolver
+	Principals      []PrincipalRule
+
+	// TrustedProxies are the proxies whose forwarding headers are believed
+	// when fingerprinting the caller; see Fingerprint.
+	TrustedProxies []netip.Prefix
+}
+
+// PrincipalRule admits or rejects principal types for matching operations.
+type PrincipalRule struct {
+	Pattern string
+	Types   []iam.PrincipalType
+	Allow   bool
+}
+
+// Request is what the pipeline needs from the transport.
+type Request struct {
+	Operation     string // gRPC method, HTTP route or path
+	Authorization string // Authorization header
+	APIKey        string // X-API-Key header
+	Tenant        string // X-Tenant-ID header, for service accounts
+
+	// Peer describes the caller for the risk hook; only called if one is
+	// configured.
+	Peer func(ctx context.Context) Peer
+}
+
+// Authenticate verifies the request's API key or bearer token, resolves
+// service accounts, applies the principal rules and the impersonation
+// policy, and runs the risk hook. On success the returned context carries
+// the caller's identity (see WithIdentity) and, for bearer tokens, the
+// token (iam.WithAccessToken).
+func (c *Config) Authenticate(ctx context.Context, client *iam.Client, req Request) (context.Context, error) {
+	claims, token, err := c.verify(ctx, client, req)
+	if err != nil {
+		return ctx, err
+	}
+
+	if c.ServiceAccounts != nil {
+		claims, err = c.ServiceAccounts.Resolve(ctx, claims, req.Tenant)
+		switch {
+		case errors.Is(err, serviceaccount.ErrNoBinding):
+			return ctx, forbidden("service account has no role in this tenant")
+		case errors.Is(err, iam.ErrNotFound):
+			return ctx, unauthenticated(BearerInvalidToken, "unknown service account")
+		case err != nil:
+			return ctx, internal("service account lookup failed")
+		}
+	}
+	if !c.PrincipalAllowed(req.Operation, claims.Principal()) {
+		return ctx, forbidden("principal type not allowed")
+	}
+
+	ctx = WithIdentity(ctx, claims)
+	if token != "" {
+		ctx = iam.WithAccessToken(ctx, token)
+	}
+
+	if claims.Actor != nil {
+		if c.Impersonation == nil {
+			return ctx, forbidden("impersonation not permitted")
+		}
+		ctx, err = c.Impersonation.Authorize(ctx, claims, req.Operation)
+		if errors.Is(err, impersonate.ErrNotPermitted) {
+			return ctx, forbidden("impersonation not permitted")
+		}
+		if err != nil {
+			return ctx, internal("impersonation check failed")
+		}
+	}
+
+	if c.RiskHook != nil {
+		ctx, err = c.RiskHook.Check(ctx, risk.Request{
+			Claims:      claims,
+			SessionID:   claims.SessionID,
+			Fingerprint: c.fingerprint(ctx, req),
+		})
+		if errors.Is(err, risk.ErrStepUpRequired) {
+			return ctx, StepUp(&iam.StepUpError{})
+		}
+		if errors.Is(err, iam.ErrSessionInvalid) {
+			return ctx, unauthenticated(BearerInvalidToken, "session is no longer valid")
+		}
+		if err != nil {
+			return ctx, internal("risk check failed")
+		}
+	}
+	return ctx, nil
+}
+
+// verify authenticates the request with its API key, if API keys are enabled
+// and one was sent, or else with its bearer token. It also returns the
+// bearer token, or "" for API keys.
+func (c *Config) verify(ctx context.Context, client *iam.Client, req Request) (*iam.Claims, string, error) {
+	if c.APIKeys != nil {
+		if key := apikey.FromHeaders(req.APIKey, req.Authorization); key != "" {
+			claims, err := c.APIKeys.Verify(ctx, key)
+			if err != nil {
+				return nil, "", unauthenticated(BearerInvalidToken, "invalid API key")
+			}
+			return claims, "", nil
+		}
+	}
+
+	token := ExtractBearer(req.Authorization)
+	if token == "" {
+		return nil, "", unauthenticated("", "missing authorization token")
+	}
+
+	verifier := client.Verifier()
+	if verifier == nil {
+		return nil, "", internal("token verifier not configured")
+	}
+	claims, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, "", unauthenticated(BearerInvalidToken, "invalid token")
+	}
+	return claims, token, nil
+}
+
+// PrincipalAllowed applies the first principal rule matching operation.
+func (c *Config) PrincipalAllowed(operation string, principal iam.PrincipalType) bool {
+	for _, rule := range c.Principals {
+		if !MatchOperation(rule.Pattern, operation) {
+			continue
+		}
+		for _, t := range rule.Types {
+			if t == principal {
+				return rule.Allow
+			}
+		}
+		return !rule.Allow
+	}
+	return true
+}
+
+// WithIdentity stores claims and the identity derived from them in ctx.
+func WithIdentity(ctx context.Context, claims *iam.Claims) context.Context {
+	ctx = iam.WithClaims(ctx, claims)
+	ctx = iam.WithUserID(ctx, claims.Subject)
+	ctx = iam.WithTenantID(ctx, claims.TenantID)
+	ctx = iam.WithRoles(ctx, claims.Roles)
+	ctx = iam.WithSessionID(ctx, claims.SessionID)
+	return ctx
+}
+
+// Tenant checks that the authenticated user belongs to the tenant in ctx.
+// Service accounts have no memberships; their access to the tenant comes
+// from role bindings and is not checked here. Without a tenant service
+// every request passes.
+func Tenant(ctx context.Context, client *iam.Client) error {
+	svc := client.Tenants()
+	if svc == nil {
+		return nil
+	}
+
+	userID := iam.UserIDFromContext(ctx)
+	tenantID := iam.TenantIDFromContext(ctx)
+	if userID == "" || tenantID == "" {
+		return unauthenticated("", "missing user or tenant context")
+	}
+	if iam.PrincipalTypeFromContext(ctx) == iam.PrincipalServiceAccount {
+		return nil
+	}
+
+	ok, err := svc.ValidateMembership(ctx, userID, tenantID)
+	if err != nil {
+		return internal("tenant validation failed")
+	}
+	if !ok {
+		return forbidden("not a member of this tenant")
+	}
+	return nil
+}
+
+// RequireAll checks that the caller has every one of permissions.
+func RequireAll(ctx context.Context, client *iam.Client, permissions ...string) error {
+	authz := client.Authz()
+	if authz == nil {
+		return internal("authorizer not configured")
+	}
+	for _, perm := range permissions {
+		ok, err := CheckPermission(ctx, authz, perm)
+		if err != nil {
+			return internal("authorization check failed")
+		}
+		if !ok {
+			return denied(permissions)
+		}
+	}
+	return nil
+}
+
+// RequireAny checks that the caller has at least one of permissions.
+func RequireAny(ctx context.Context, client *iam.Client, permissions ...string) error {
+	authz := client.Authz()
+	if authz == nil {
+		return internal("authorizer not configured")
+	}
+	for _, perm := range permissions {
+		ok, err := CheckPermission(ctx, authz, perm)
+		if err != nil {
+			return internal("authorization check failed")
+		}
+		if ok {
+			return nil
+		}
+	}
+	return denied(permissions)
+}
+
+func denied(permissions []string) *Error {
+	return &Error{Kind: PermissionDenied, Message: "permission denied", Bearer: BearerInsufficientScope, Scope: permissions}
+}
+
+// CheckPermission checks permission for the caller. API key scopes limit
+// what the key's user may do, and during impersonation actor-scoped
+// permissions are checked against the actor.
+func CheckPermission(ctx context.Context, authz iam.Authorizer, permission string) (bool, error) {
+	if claims := iam.ClaimsFromContext(ctx); claims != nil && !apikey.Allows(claims.Scopes, permission) {
+		return false, nil
+	}
+	return impersonate.Check(ctx, authz, permission)
+}
+
+// RequireACR checks that the token's authentication context class is one
+// of values.
+func RequireACR(ctx context.Context, values ...string) error {
+	if !iam.ClaimsFromContext(ctx).SatisfiesACR(values...) {
+		return StepUp(&iam.StepUpError{ACRValues: values})
+	}
+	return nil
+}
+
+// RequireFreshAuth checks that the user authenticated within maxAge.
+func RequireFreshAuth(ctx context.Context, maxAge time.Duration) error {
+	if !iam.ClaimsFromContext(ctx).AuthenticatedWithin(maxAge, time.Now()) {
+		return StepUp(&iam.StepUpError{MaxAge: maxAge})
+	}
+	return nil
+}
+
+// NewSessionValidator returns a validator for client's session service, or
+// nil if it has none.
+func NewSessionValidator(client *iam.Client, opts []session.ValidatorOption) *session.Validator {
+	if svc := client.Sessions(); svc != nil {
+		return session.NewValidator(svc, opts...)
+	}
+	return nil
+}
+
+// CheckSession checks that the session in ctx is still active.
+func CheckSession(ctx context.Context, validator *session.Validator) error {
+	if validator == nil {
+		return internal("session service not configured")
+	}
+
+	err := validator.Check(ctx, iam.SessionIDFromContext(ctx))
+	if errors.Is(err, iam.ErrSessionInvalid) || errors.Is(err, session.ErrNoCurrentSession) {
+		return unauthenticated(BearerInvalidToken, "session is no longer valid")
+	}
+	if err != nil {
+		return internal("session validation failed")
+	}
+	return nil
+}
+
+// MatchOperation reports whether operation matches pattern; a trailing "*"
+// matches every operation with that prefix.
+func MatchOperation(pattern, operation string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(operation, prefix)
+	}
+	return pattern == operation
+}
+
+// ExtractBearer returns the token of a "Bearer <token>" Authorization
+// value, or "".
+func ExtractBearer(authorization string) string {
+	parts := strings.SplitN(authorization, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ""
+	}
+	return parts[1]
+}
+
+// AsError returns err as an *Error; other errors become Internal.
+func AsError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return internal("internal error")
+}
+
+// ClientToken returns a client-credentials access token for req, or the
+// exchanger's default token if req is nil.
+func ClientToken(ctx context.Context, client *iam.Client, req *iam.TokenRequest) (string, error) {
+	exchanger := client.OAuth2()
+	if exchanger == nil {
+		return "", internal("oauth2 exchanger not configured")
+	}
+	if req == nil {
+		token, err := exchanger.GetCachedToken(ctx)
+		if err != nil {
+			return "", unauthenticated("", "failed to obtain oauth2 token")
+		}
+		return token, nil
+	}
+
+	provider, ok := exchanger.(iam.TokenProvider)
+	if !ok {
+		return "", internal("oauth2 exchanger does not support token requests")
+	}
+	token, err := provider.GetToken(ctx, *req)
+	if err != nil {
+		return "", unauthenticated("", "failed to obtain oauth2 token")
+	}
+	return token.AccessToken, nil
+}
+
+// OnBehalfOfToken exchanges the caller's bearer token, stored by
+// Authenticate, for a token restricted to req (RFC 8693).
+func OnBehalfOfToken(ctx context.Context, client *iam.Client, req iam.TokenRequest) (string, error) {
+	exchanger, ok := client.OAuth2().(iam.OnBehalfOfExchanger)
+	if !ok {
+		return "", internal("oauth2 exchanger does not support on-behalf-of exchange")
+	}
+	subjectToken := iam.AccessTokenFromContext(ctx)
+	if subjectToken == "" {
+		return "", unauthenticated("", "no caller token to exchange")
+	}
+	token, err := exchanger.ExchangeOnBehalfOf(ctx, subjectToken, req)
+	if err != nil {
+		return "", unauthenticated("", "failed to obtain on-behalf-of token")
+	}
+	return token.AccessToken, nil
+}
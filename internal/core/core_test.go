@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+)
+
+func TestAuthenticate_Errors(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+	)
+	cfg := &Config{}
+
+	tests := []struct {
+		name          string
+		authorization string
+		kind          Kind
+		bearer        string
+	}{
+		{"missing", "", Unauthenticated, ""},
+		{"malformed", "Basic dXNlcjpwYXNz", Unauthenticated, ""},
+		{"invalid", "Bearer nobody", Unauthenticated, BearerInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cfg.Authenticate(context.Background(), client, Request{Authorization: tt.authorization})
+			e := AsError(err)
+			if e.Kind != tt.kind || e.Bearer != tt.bearer {
+				t.Errorf("error = %+v, want kind %d and bearer error %q", e, tt.kind, tt.bearer)
+			}
+		})
+	}
+
+	ctx, err := cfg.Authenticate(context.Background(), client, Request{Authorization: "bearer user123"})
+	if err != nil || iam.UserIDFromContext(ctx) != "user123" {
+		t.Errorf("Authenticate() = %q, %v; want user123", iam.UserIDFromContext(ctx), err)
+	}
+}
+
+func TestRequireAny_Denied(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+	)
+	ctx := iam.WithUserID(context.Background(), "user123")
+
+	e := AsError(RequireAny(ctx, client, "orders:read", "orders:write"))
+	if e.Kind != PermissionDenied || e.Bearer != BearerInsufficientScope || len(e.Scope) != 2 {
+		t.Errorf("error = %+v, want insufficient_scope for both permissions", e)
+	}
+}
+
+func TestMatchOperation(t *testing.T) {
+	tests := []struct {
+		pattern, operation string
+		want               bool
+	}{
+		{"/a.v1.A/Get", "/a.v1.A/Get", true},
+		{"/a.v1.A/Get", "/a.v1.A/GetAll", false},
+		{"/a.v1.A/*", "/a.v1.A/Get", true},
+		{"/a.v1.*", "/b.v1.B/Get", false},
+		{"*", "/anything", true},
+	}
+	for _, tt := range tests {
+		if got := MatchOperation(tt.pattern, tt.operation); got != tt.want {
+			t.Errorf("MatchOperation(%q, %q) = %v, want %v", tt.pattern, tt.operation, got, tt.want)
+		}
+	}
+}
+
+func TestAsError(t *testing.T) {
+	e := unauthenticated(BearerInvalidToken, "invalid token")
+	if got := AsError(fmt.Errorf("wrapped: %w", e)); got != e {
+		t.Errorf("AsError() = %+v, want the wrapped error", got)
+	}
+	if got := AsError(errors.New("boom")); got.Kind != Internal || got.Message != "internal error" {
+		t.Errorf("AsError() = %+v, want an internal error hiding the cause", got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	cfg := &Config{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+
+	tests := []struct {
+		name string
+		peer Peer
+		want string
+	}{
+		{"direct", Peer{RemoteAddr: "198.51.100.7:51234", ForwardedFor: []string{"203.0.113.9"}, RealIP: "203.0.113.9"}, "198.51.100.7"},
+		{"behind proxy", Peer{RemoteAddr: "10.0.0.1:443", ForwardedFor: []string{"203.0.113.9"}}, "203.0.113.9"},
+		{"spoofed entry", Peer{RemoteAddr: "10.0.0.1:443", ForwardedFor: []string{"1.2.3.4, 203.0.113.9, 10.0.0.2"}}, "203.0.113.9"},
+		{"several headers", Peer{RemoteAddr: "10.0.0.1:443", ForwardedFor: []string{"1.2.3.4", "203.0.113.9"}}, "203.0.113.9"},
+		{"real ip", Peer{RemoteAddr: "10.0.0.1", RealIP: "203.0.113.9"}, "203.0.113.9"},
+		{"only proxies", Peer{RemoteAddr: "10.0.0.1:443", ForwardedFor: []string{"10.0.0.3, 10.0.0.2"}}, "10.0.0.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.Fingerprint(tt.peer).IP; got != tt.want {
+				t.Errorf("IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := (&Config{}).Fingerprint(Peer{RemoteAddr: "[2001:db8::1]:443", ForwardedFor: []string{"203.0.113.9"}}).IP; got != "2001:db8::1" {
+		t.Errorf("without trusted proxies: IP = %q, want the peer address", got)
+	}
+}
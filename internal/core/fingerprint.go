@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/chimerakang/iam-go/risk"
+)
+
+// Peer describes where a request came from, as seen by the transport.
+type Peer struct {
+	RemoteAddr   string   // address of the connection's peer, host or host:port
+	ForwardedFor []string // X-Forwarded-For header values, in order
+	RealIP       string   // X-Real-IP header
+	UserAgent    string   // User-Agent header
+}
+
+// Fingerprint describes the caller of p for the risk hook. The client
+// address is p's RemoteAddr unless that is one of c.TrustedProxies; only
+// then are the forwarding headers read, taking the rightmost
+// X-Forwarded-For entry that is not itself a trusted proxy (entries further
+// left were supplied by the client and prove nothing), or X-Real-IP when
+// there is no X-Forwarded-For.
+func (c *Config) Fingerprint(p Peer) risk.Fingerprint {
+	fp := risk.Fingerprint{IP: host(p.RemoteAddr), UserAgent: p.UserAgent, At: time.Now()}
+	if !c.trustedProxy(fp.IP) {
+		return fp
+	}
+
+	var hops []string
+	for _, v := range p.ForwardedFor {
+		for _, hop := range strings.Split(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	if len(hops) == 0 {
+		if ip := strings.TrimSpace(p.RealIP); ip != "" {
+			fp.IP = ip
+		}
+		return fp
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		fp.IP = host(hops[i])
+		if !c.trustedProxy(fp.IP) {
+			break
+		}
+	}
+	return fp
+}
+
+// fingerprint returns the fingerprint for req, or the zero value if the
+// transport cannot describe its peer.
+func (c *Config) fingerprint(ctx context.Context, req Request) risk.Fingerprint {
+	if req.Peer == nil {
+		return risk.Fingerprint{}
+	}
+	return c.Fingerprint(req.Peer(ctx))
+}
+
+// trustedProxy reports whether ip belongs to one of c.TrustedProxies.
+func (c *Config) trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range c.TrustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// host strips the port, if any, from addr.
+func host(addr string) string {
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		return h
+	}
+	return addr
+}
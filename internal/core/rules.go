@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	iam "github.com/chimerakang/iam-go"
+	iamv1 "github.com/chimerakang/iam-go/proto/iam/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// rules caches LookupRule results by method; methods and their options do
+// not change at runtime.
+var rules sync.Map // string -> *iamv1.Rule (nil if none)
+
+// LookupRule returns the (iam.v1.rule) option of method, given as
+// "/package.Service/Method", or nil if the method is not registered or has
+// no rule. The method's proto file must be linked into the binary, which
+// importing its generated code does.
+func LookupRule(method string) *iamv1.Rule {
+	if r, ok := rules.Load(method); ok {
+		return r.(*iamv1.Rule)
+	}
+	r := findRule(method)
+	rules.Store(method, r)
+	return r
+}
+
+func findRule(method string) *iamv1.Rule {
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok {
+		return nil
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil || md.Options() == nil {
+		return nil
+	}
+	if !proto.HasExtension(md.Options(), iamv1.E_Rule) {
+		return nil
+	}
+	r, _ := proto.GetExtension(md.Options(), iamv1.E_Rule).(*iamv1.Rule)
+	return r
+}
+
+// Authorize enforces rule on a call: public methods pass, others are
+// authenticated and then checked for tenant membership and the permission,
+// as rule requires. A nil rule denies the call.
+func (c *Config) Authorize(ctx context.Context, client *iam.Client, rule *iamv1.Rule, req Request) (context.Context, error) {
+	if rule == nil {
+		return ctx, forbidden("no authorization rule for method")
+	}
+	if rule.GetPublic() {
+		return ctx, nil
+	}
+
+	ctx, err := c.Authenticate(ctx, client, req)
+	if err != nil {
+		return ctx, err
+	}
+	if rule.GetTenantRequired() {
+		if err := Tenant(ctx, client); err != nil {
+			return ctx, err
+		}
+	}
+	if p := rule.GetPermission(); p != "" {
+		if err := RequireAll(ctx, client, p); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+	iamv1 "github.com/chimerakang/iam-go/proto/iam/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// registerRulesService registers rulestest.v1.Orders, whose methods carry
+// (iam.v1.rule) options, as generated code would.
+func registerRulesService(t *testing.T) {
+	t.Helper()
+	registerOnce.Do(func() {
+		method := func(name string, rule *iamv1.Rule) *descriptorpb.MethodDescriptorProto {
+			m := &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String(name),
+				InputType:  proto.String(".google.protobuf.Empty"),
+				OutputType: proto.String(".google.protobuf.Empty"),
+			}
+			if rule != nil {
+				m.Options = &descriptorpb.MethodOptions{}
+				proto.SetExtension(m.Options, iamv1.E_Rule, rule)
+			}
+			return m
+		}
+		fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+			Name:       proto.String("rulestest/v1/orders.proto"),
+			Package:    proto.String("rulestest.v1"),
+			Syntax:     proto.String("proto3"),
+			Dependency: []string{"google/protobuf/empty.proto"},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("Orders"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					method("Ping", &iamv1.Rule{Public: true}),
+					method("List", &iamv1.Rule{}),
+					method("Create", &iamv1.Rule{Permission: "orders:write", TenantRequired: true}),
+					method("Delete", nil),
+				},
+			}},
+		}, protoregistry.GlobalFiles)
+		if err == nil {
+			err = protoregistry.GlobalFiles.RegisterFile(fd)
+		}
+		registerErr = err
+	})
+	if registerErr != nil {
+		t.Fatalf("registering test service: %v", registerErr)
+	}
+}
+
+func TestLookupRule(t *testing.T) {
+	registerRulesService(t)
+
+	if r := LookupRule("/rulestest.v1.Orders/Create"); r.GetPermission() != "orders:write" || !r.GetTenantRequired() {
+		t.Errorf("Create rule = %v", r)
+	}
+	if r := LookupRule("/rulestest.v1.Orders/List"); r == nil || r.GetPublic() || r.GetPermission() != "" {
+		t.Errorf("List rule = %v, want an empty rule", r)
+	}
+	for _, method := range []string{
+		"/rulestest.v1.Orders/Delete",  // no rule
+		"/rulestest.v1.Orders/Missing", // unknown method
+		"/rulestest.v1.Missing/List",   // unknown service
+		"/iam.v1.Rule/Permission",      // not a service
+		"malformed",
+	} {
+		if r := LookupRule(method); r != nil {
+			t.Errorf("LookupRule(%q) = %v, want nil", method, r)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	registerRulesService(t)
+	client := fake.NewClient(
+		fake.WithUser("writer", "tenant123", "w@example.com", nil),
+		fake.WithUser("reader", "tenant123", "r@example.com", nil),
+		fake.WithPermissions("writer", []string{"orders:write"}),
+	)
+	cfg := &Config{}
+
+	tests := []struct {
+		method string
+		token  string
+		want   Kind
+	}{
+		{"/rulestest.v1.Orders/Ping", "", 0},
+		{"/rulestest.v1.Orders/List", "", Unauthenticated},
+		{"/rulestest.v1.Orders/List", "reader", 0},
+		{"/rulestest.v1.Orders/Create", "reader", PermissionDenied},
+		{"/rulestest.v1.Orders/Create", "writer", 0},
+		{"/rulestest.v1.Orders/Delete", "writer", PermissionDenied},
+		{"/rulestest.v1.Orders/Missing", "writer", PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+"/"+tt.token, func(t *testing.T) {
+			req := Request{Operation: tt.method}
+			if tt.token != "" {
+				req.Authorization = "Bearer " + tt.token
+			}
+			ctx, err := cfg.Authorize(context.Background(), client, LookupRule(tt.method), req)
+			if tt.want == 0 {
+				if err != nil {
+					t.Fatalf("Authorize() error: %v", err)
+				}
+				if tt.token != "" && iam.UserIDFromContext(ctx) != tt.token {
+					t.Errorf("user = %q, want %q", iam.UserIDFromContext(ctx), tt.token)
+				}
+				return
+			}
+			if e := AsError(err); err == nil || e.Kind != tt.want {
+				t.Errorf("Authorize() error = %v, want kind %d", err, tt.want)
+			}
+		})
+	}
+}
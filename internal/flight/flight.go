@@ -0,0 +1,38 @@
+// Package flight coalesces concurrent backend loads of the same key, shared
+// by the caching service implementations in this module.
+package flight
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Timeout bounds a coalesced load. The load is detached from the callers'
+// cancellation, so it needs a deadline of its own.
+const Timeout = 10 * time.Second
+
+// Group runs one load per key at a time and hands the result to every
+// caller waiting on that key. The zero value is ready to use.
+type Group struct {
+	sf singleflight.Group
+}
+
+// Do calls fetch once for all concurrent callers with the same key. fetch
+// runs with the first caller's context values but without its cancellation,
+// bounded by Timeout, so one caller giving up does not fail the others; each
+// caller still returns ctx.Err() as soon as its own ctx is done.
+func (g *Group) Do(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ch := g.sf.DoChan(key, func() (interface{}, error) {
+		fctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), Timeout)
+		defer cancel()
+		return fetch(fctx)
+	})
+	select {
+	case r := <-ch:
+		return r.Val, r.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
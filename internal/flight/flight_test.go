@@ -0,0 +1,75 @@
+package flight
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDo_CanceledCallerDoesNotFailOthers(t *testing.T) {
+	var g Group
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	fetch := func(ctx context.Context) (interface{}, error) {
+		calls++
+		close(started)
+		select {
+		case <-release:
+			return "value", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	var firstErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, firstErr = g.Do(first, "key", fetch)
+	}()
+	<-started
+
+	var second interface{}
+	var secondErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		second, secondErr = g.Do(context.Background(), "key", fetch)
+	}()
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if !errors.Is(firstErr, context.Canceled) {
+		t.Errorf("canceled caller error = %v, want context.Canceled", firstErr)
+	}
+	if secondErr != nil || second != "value" {
+		t.Errorf("waiting caller = %v, %v; want the fetched value", second, secondErr)
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+}
+
+func TestDo_KeepsContextValues(t *testing.T) {
+	type key struct{}
+	var g Group
+	ctx := context.WithValue(context.Background(), key{}, "trace-1")
+
+	v, err := g.Do(ctx, "key", func(ctx context.Context) (interface{}, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("fetch context has no deadline")
+		}
+		return ctx.Value(key{}), nil
+	})
+	if err != nil || v != "trace-1" {
+		t.Errorf("Do() = %v, %v; want the caller's context value", v, err)
+	}
+}
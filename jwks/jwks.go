@@ -82,7 +82,7 @@ func (v *Verifier) Verify(ctx context.Context, tokenString string) (*iam.Claims,
 		return nil, fmt.Errorf("iam/jwks: invalid token claims")
 	}
 
-	return mapToIAMClaims(mapClaims), nil
+	return mapToIAMClaims(mapClaims)
 }
 
 // getKey returns the RSA public key for the given kid, fetching/refreshing as needed.
@@ -197,8 +197,9 @@ func (k *jwkKey) rsaPublicKey() (*rsa.PublicKey, error) {
 	}, nil
 }
 
-// mapToIAMClaims converts jwt.MapClaims to iam.Claims.
-func mapToIAMClaims(m jwt.MapClaims) *iam.Claims {
+// mapToIAMClaims converts jwt.MapClaims to iam.Claims. It fails if the "act"
+// claim is malformed.
+func mapToIAMClaims(m jwt.MapClaims) (*iam.Claims, error) {
 	c := &iam.Claims{
 		Extra: make(map[string]any),
 	}
@@ -215,12 +216,34 @@ func mapToIAMClaims(m jwt.MapClaims) *iam.Claims {
 	if v, ok := m["iss"].(string); ok {
 		c.Issuer = v
 	}
+	if v, ok := m["sid"].(string); ok {
+		c.SessionID = v
+	}
 	if v, ok := m["exp"].(float64); ok {
 		c.ExpiresAt = time.Unix(int64(v), 0)
 	}
 	if v, ok := m["iat"].(float64); ok {
 		c.IssuedAt = time.Unix(int64(v), 0)
 	}
+	actor, err := iam.ParseActor(m["act"])
+	if err != nil {
+		return nil, fmt.Errorf("iam/jwks: %w", err)
+	}
+	c.Actor = actor
+	c.PrincipalType = iam.ParsePrincipalType(m)
+	if v, ok := m["acr"].(string); ok {
+		c.ACR = v
+	}
+	if amr, ok := m["amr"].([]interface{}); ok {
+		for _, a := range amr {
+			if s, ok := a.(string); ok {
+				c.AMR = append(c.AMR, s)
+			}
+		}
+	}
+	if v, ok := m["auth_time"].(float64); ok {
+		c.AuthTime = time.Unix(int64(v), 0)
+	}
 	if roles, ok := m["roles"].([]interface{}); ok {
 		for _, r := range roles {
 			if s, ok := r.(string); ok {
@@ -233,7 +256,9 @@ func mapToIAMClaims(m jwt.MapClaims) *iam.Claims {
 	standard := map[string]bool{
 		"sub": true, "tenant_id": true, "email": true,
 		"iss": true, "exp": true, "iat": true, "roles": true,
-		"aud": true, "nbf": true, "jti": true,
+		"aud": true, "nbf": true, "jti": true, "sid": true,
+		"act": true, "acr": true, "amr": true, "auth_time": true,
+		"principal_type": true,
 	}
 	for k, v := range m {
 		if !standard[k] {
@@ -241,5 +266,5 @@ func mapToIAMClaims(m jwt.MapClaims) *iam.Claims {
 		}
 	}
 
-	return c
+	return c, nil
 }
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	iam "github.com/chimerakang/iam-go"
 	"github.com/chimerakang/iam-go/jwks"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -74,6 +75,7 @@ func TestVerify_ValidToken(t *testing.T) {
 		"exp":       now.Add(1 * time.Hour).Unix(),
 		"iat":       now.Unix(),
 		"email":     "test@example.com",
+		"sid":       "sess-789",
 	})
 
 	claims, err := verifier.Verify(context.Background(), tokenStr)
@@ -96,6 +98,12 @@ func TestVerify_ValidToken(t *testing.T) {
 	if claims.Email != "test@example.com" {
 		t.Errorf("Email = %q, want %q", claims.Email, "test@example.com")
 	}
+	if claims.SessionID != "sess-789" {
+		t.Errorf("SessionID = %q, want %q", claims.SessionID, "sess-789")
+	}
+	if _, ok := claims.Extra["sid"]; ok {
+		t.Error("sid should not be copied to Extra")
+	}
 	if claims.ExpiresAt.IsZero() {
 		t.Error("ExpiresAt should not be zero")
 	}
@@ -104,6 +112,137 @@ func TestVerify_ValidToken(t *testing.T) {
 	}
 }
 
+func TestVerify_ActClaim(t *testing.T) {
+	kid := "key-1"
+	privKey, server := testSetup(t, kid)
+	defer server.Close()
+
+	verifier := jwks.NewVerifier(server.URL)
+	tokenStr := signToken(t, privKey, kid, jwt.MapClaims{
+		"sub": "customer-42",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"act": map[string]any{
+			"sub": "support-7",
+			"iss": "https://admin.example.com",
+			"act": map[string]any{"sub": "escalation-bot"},
+		},
+	})
+
+	claims, err := verifier.Verify(context.Background(), tokenStr)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+
+	if claims.Actor == nil || claims.Actor.Subject != "support-7" || claims.Actor.Issuer != "https://admin.example.com" {
+		t.Fatalf("Actor = %+v, want support-7", claims.Actor)
+	}
+	if claims.Actor.Actor == nil || claims.Actor.Actor.Subject != "escalation-bot" {
+		t.Errorf("Actor.Actor = %+v, want escalation-bot", claims.Actor.Actor)
+	}
+	if _, ok := claims.Extra["act"]; ok {
+		t.Error("act should not be copied to Extra")
+	}
+}
+
+func TestVerify_MalformedActClaim(t *testing.T) {
+	kid := "key-1"
+	privKey, server := testSetup(t, kid)
+	defer server.Close()
+
+	verifier := jwks.NewVerifier(server.URL)
+	tests := []struct {
+		name string
+		act  any
+	}{
+		{"no sub", map[string]any{"client_id": "svc"}},
+		{"string", "support-7"},
+		{"nested", map[string]any{"sub": "support-7", "act": "escalation-bot"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenStr := signToken(t, privKey, kid, jwt.MapClaims{
+				"sub": "customer-42",
+				"exp": time.Now().Add(time.Hour).Unix(),
+				"act": tt.act,
+			})
+
+			if claims, err := verifier.Verify(context.Background(), tokenStr); err == nil {
+				t.Errorf("Verify() = %+v, want an error", claims)
+			}
+		})
+	}
+}
+
+func TestVerify_AuthContext(t *testing.T) {
+	kid := "key-1"
+	privKey, server := testSetup(t, kid)
+	defer server.Close()
+
+	verifier := jwks.NewVerifier(server.URL)
+	authTime := time.Now().Add(-5 * time.Minute).Truncate(time.Second)
+	tokenStr := signToken(t, privKey, kid, jwt.MapClaims{
+		"sub":       "user-123",
+		"exp":       time.Now().Add(time.Hour).Unix(),
+		"acr":       "mfa",
+		"amr":       []string{"pwd", "otp"},
+		"auth_time": authTime.Unix(),
+	})
+
+	claims, err := verifier.Verify(context.Background(), tokenStr)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+
+	if claims.ACR != "mfa" {
+		t.Errorf("ACR = %q, want mfa", claims.ACR)
+	}
+	if len(claims.AMR) != 2 || claims.AMR[0] != "pwd" || claims.AMR[1] != "otp" {
+		t.Errorf("AMR = %v, want [pwd otp]", claims.AMR)
+	}
+	if !claims.AuthTime.Equal(authTime) {
+		t.Errorf("AuthTime = %v, want %v", claims.AuthTime, authTime)
+	}
+	for _, k := range []string{"acr", "amr", "auth_time"} {
+		if _, ok := claims.Extra[k]; ok {
+			t.Errorf("%s should not be copied to Extra", k)
+		}
+	}
+}
+
+func TestVerify_PrincipalType(t *testing.T) {
+	kid := "key-1"
+	privKey, server := testSetup(t, kid)
+	defer server.Close()
+	verifier := jwks.NewVerifier(server.URL)
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   iam.PrincipalType
+	}{
+		{"user", jwt.MapClaims{"sub": "user-123"}, iam.PrincipalUser},
+		{"explicit", jwt.MapClaims{"sub": "sa-1", "principal_type": "service_account"}, iam.PrincipalServiceAccount},
+		{"grant type", jwt.MapClaims{"sub": "sa-1", "gty": "client_credentials"}, iam.PrincipalServiceAccount},
+		{"client subject", jwt.MapClaims{"sub": "app_1", "client_id": "app_1"}, iam.PrincipalServiceAccount},
+		{"user via client", jwt.MapClaims{"sub": "user-123", "client_id": "web"}, iam.PrincipalUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["exp"] = time.Now().Add(time.Hour).Unix()
+			claims, err := verifier.Verify(context.Background(), signToken(t, privKey, kid, tt.claims))
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if got := claims.Principal(); got != tt.want {
+				t.Errorf("Principal() = %q, want %q", got, tt.want)
+			}
+			if _, ok := claims.Extra["principal_type"]; ok {
+				t.Error("principal_type should not be copied to Extra")
+			}
+		})
+	}
+}
+
 func TestVerify_ExpiredToken(t *testing.T) {
 	kid := "key-1"
 	privKey, server := testSetup(t, kid)
@@ -25,6 +25,9 @@ type Metrics struct {
 
 	// Connection metrics
 	grpcConnectionState *prometheus.GaugeVec
+
+	// OAuth2 token metrics
+	tokenEventsTotal *prometheus.CounterVec
 }
 
 // New creates and registers Prometheus metrics.
@@ -81,6 +84,12 @@ func New(enabled bool) *Metrics {
 		Help: "gRPC connection state (0=disconnected, 1=connected)",
 	}, []string{"service"})
 
+	// OAuth2 token metrics
+	m.tokenEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iam_oauth2_token_events_total",
+		Help: "Total OAuth2 token lifecycle events (cache_hit, fetched, retry, failed, stale_served, refresh_reuse)",
+	}, []string{"event"})
+
 	return m
 }
 
@@ -144,3 +153,12 @@ func (m *Metrics) SetConnectionState(service string, connected bool) {
 	}
 	m.grpcConnectionState.WithLabelValues(service).Set(state)
 }
+
+// RecordTokenEvent records an OAuth2 token lifecycle event, e.g. from an
+// oauth2.WithEventHandler handler.
+func (m *Metrics) RecordTokenEvent(event string) {
+	if !m.enabled {
+		return
+	}
+	m.tokenEventsTotal.WithLabelValues(event).Inc()
+}
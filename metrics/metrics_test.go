@@ -32,6 +32,7 @@ func TestMetricsDisabled(t *testing.T) {
 	metrics.RecordCacheMiss("tenant")
 	metrics.SetCacheSize("user", 42)
 	metrics.SetConnectionState("grpc", true)
+	metrics.RecordTokenEvent("fetched")
 }
 
 func TestRecordAuthSuccess(t *testing.T) {
@@ -0,0 +1,118 @@
+package connectmw
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/core"
+)
+
+// ClientOption configures OAuth2ClientCredentials.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	defaultRequest *iam.TokenRequest
+	targets        []tokenTarget
+}
+
+// tokenTarget selects the token request for matching procedures.
+type tokenTarget struct {
+	pattern string
+	req     iam.TokenRequest
+}
+
+// WithTokenRequest requests tokens for req on every call that no
+// WithTargetToken rule matches.
+func WithTokenRequest(req iam.TokenRequest) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.defaultRequest = &req
+	}
+}
+
+// WithTargetToken requests tokens for req on calls to procedure, e.g.
+// WithTargetToken("/billing.v1.Billing/*", iam.TokenRequest{Audience: "billing"}).
+// Procedures are matched as in WithExcludedProcedures.
+func WithTargetToken(procedure string, req iam.TokenRequest) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.targets = append(cfg.targets, tokenTarget{pattern: procedure, req: req})
+	}
+}
+
+// OAuth2ClientCredentials returns a client interceptor that injects an
+// OAuth2 Bearer token into outgoing unary and streaming calls using client
+// credentials. The token is automatically cached and refreshed before
+// expiry. With WithTokenRequest or WithTargetToken the token is chosen per
+// procedure, which requires an exchanger implementing iam.TokenProvider.
+// Install it with connect.WithInterceptors when creating the client.
+func OAuth2ClientCredentials(client *iam.Client, opts ...ClientOption) connect.Interceptor {
+	cfg := &clientConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return &clientInterceptor{token: func(ctx context.Context, procedure string) (string, error) {
+		return core.ClientToken(ctx, client, cfg.tokenRequest(procedure))
+	}}
+}
+
+// --- internal helpers ---
+
+// tokenRequest returns the token request for procedure, or nil for the
+// exchanger's default token.
+func (cfg *clientConfig) tokenRequest(procedure string) *iam.TokenRequest {
+	for i, target := range cfg.targets {
+		if core.MatchOperation(target.pattern, procedure) {
+			return &cfg.targets[i].req
+		}
+	}
+	return cfg.defaultRequest
+}
+
+// clientInterceptor sets the Authorization header of outgoing calls.
+// Incoming calls are not affected.
+type clientInterceptor struct {
+	token func(ctx context.Context, procedure string) (string, error)
+}
+
+func (i *clientInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if !req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		token, err := i.token(ctx, req.Spec().Procedure)
+		if err != nil {
+			return nil, toError(err)
+		}
+		req.Header().Set("Authorization", "Bearer "+token)
+		return next(ctx, req)
+	}
+}
+
+func (i *clientInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		token, err := i.token(ctx, spec.Procedure)
+		if err != nil {
+			return &failedConn{StreamingClientConn: conn, err: toError(err)}
+		}
+		conn.RequestHeader().Set("Authorization", "Bearer "+token)
+		return conn
+	}
+}
+
+func (i *clientInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// failedConn is a stream that could not be authorized: sending and
+// receiving fail with err. The wrapped stream is never started, so nothing
+// reaches the server.
+type failedConn struct {
+	connect.StreamingClientConn
+	err error
+}
+
+func (c *failedConn) Send(any) error       { return c.err }
+func (c *failedConn) Receive(any) error    { return c.err }
+func (c *failedConn) CloseRequest() error  { return nil }
+func (c *failedConn) CloseResponse() error { return nil }
@@ -0,0 +1,100 @@
+package connectmw
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// recordingProvider issues "token-<audience>" and records the requests.
+type recordingProvider struct {
+	requests []iam.TokenRequest
+}
+
+func (p *recordingProvider) ExchangeToken(_ context.Context, _ []string) (*iam.OAuth2Token, error) {
+	return &iam.OAuth2Token{AccessToken: "default-token"}, nil
+}
+
+func (p *recordingProvider) GetCachedToken(_ context.Context) (string, error) {
+	return "default-token", nil
+}
+
+func (p *recordingProvider) GetToken(_ context.Context, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	p.requests = append(p.requests, req)
+	return &iam.OAuth2Token{AccessToken: "token-" + req.Audience}, nil
+}
+
+// authorizationServer serves the test procedures, returning the request's
+// Authorization header.
+func authorizationServer(t *testing.T) *http.ServeMux {
+	t.Helper()
+	whoAmI := func(_ context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[wrapperspb.StringValue], error) {
+		return connect.NewResponse(wrapperspb.String(req.Header().Get("Authorization"))), nil
+	}
+	watch := func(_ context.Context, req *connect.Request[emptypb.Empty], stream *connect.ServerStream[wrapperspb.StringValue]) error {
+		return stream.Send(wrapperspb.String(req.Header().Get("Authorization")))
+	}
+	mux := http.NewServeMux()
+	mux.Handle(whoAmIProcedure, connect.NewUnaryHandler(whoAmIProcedure, whoAmI))
+	mux.Handle(watchProcedure, connect.NewServerStreamHandler(watchProcedure, watch))
+	return mux
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithOAuth2App("app_test", "secret_test", []string{"iam:introspect"}),
+	)
+	s := newServerFor(t, authorizationServer(t))
+	opt := connect.WithInterceptors(OAuth2ClientCredentials(client))
+
+	for name, opts := range protocols {
+		t.Run(name, func(t *testing.T) {
+			opts := append([]connect.ClientOption{opt}, opts...)
+			if got, err := whoAmI(s, "", opts...); err != nil || len(got) <= len("Bearer ") {
+				t.Errorf("unary: Authorization = %q, %v; want a bearer token", got, err)
+			}
+			if got, err := watch(s, "", opts...); err != nil || len(got) <= len("Bearer ") {
+				t.Errorf("streaming: Authorization = %q, %v; want a bearer token", got, err)
+			}
+		})
+	}
+}
+
+func TestOAuth2ClientCredentials_NoExchanger(t *testing.T) {
+	client, _ := iam.NewClient(iam.Config{Endpoint: "localhost:9000"})
+	s := newServerFor(t, authorizationServer(t))
+	opt := connect.WithInterceptors(OAuth2ClientCredentials(client))
+
+	if _, err := whoAmI(s, "", opt); connect.CodeOf(err) != connect.CodeInternal {
+		t.Errorf("unary: got %v, want internal", err)
+	}
+	if _, err := watch(s, "", opt); connect.CodeOf(err) != connect.CodeInternal {
+		t.Errorf("streaming: got %v, want internal", err)
+	}
+}
+
+func TestOAuth2ClientCredentials_PerTarget(t *testing.T) {
+	provider := &recordingProvider{}
+	client, _ := iam.NewClient(iam.Config{Endpoint: "localhost:9000"}, iam.WithOAuth2Exchanger(provider))
+	s := newServerFor(t, authorizationServer(t))
+	opt := connect.WithInterceptors(OAuth2ClientCredentials(client,
+		WithTargetToken(watchProcedure, iam.TokenRequest{Audience: "stream", Scopes: []string{"watch"}}),
+		WithTokenRequest(iam.TokenRequest{Audience: "api"}),
+	))
+
+	if got, _ := watch(s, "", opt); got != "Bearer token-stream" {
+		t.Errorf("target match: Authorization = %q, want Bearer token-stream", got)
+	}
+	if got, _ := whoAmI(s, "", opt); got != "Bearer token-api" {
+		t.Errorf("fallback: Authorization = %q, want Bearer token-api", got)
+	}
+	if len(provider.requests) != 2 || len(provider.requests[0].Scopes) != 1 {
+		t.Errorf("unexpected token requests: %+v", provider.requests)
+	}
+}
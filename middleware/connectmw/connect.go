@@ -0,0 +1,345 @@
+// Package connectmw provides connectrpc.com/connect interceptors for IAM
+// integration.
+//
+// Use this package for services built on Connect. Its interceptors read
+// credentials from request headers, so they work for all three protocols a
+// Connect handler serves (Connect, gRPC and gRPC-Web), and wrap both unary
+// and streaming calls. They share one authentication and authorization
+// pipeline with kratosmw, grpcmw and httpmw and enforce the same rules.
+//
+// Install server interceptors with connect.WithInterceptors, in order:
+//
+//	connect.WithInterceptors(
+//		connectmw.Auth(client),
+//		connectmw.Tenant(client),
+//		connectmw.Require(client, "orders:read"),
+//	)
+//
+// Failures are *connect.Error values with CodeUnauthenticated or
+// CodePermissionDenied and an errdetails.ErrorInfo detail.
+package connectmw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/internal/core"
+	"github.com/chimerakang/iam-go/risk"
+	"github.com/chimerakang/iam-go/serviceaccount"
+	"github.com/chimerakang/iam-go/session"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// errdetails.ErrorInfo reasons attached to errors.
+const (
+	// ReasonStepUpRequired: the caller must re-authenticate, more strongly or
+	// more recently, before retrying. The ErrorInfo metadata carries the
+	// requirements ("acr_values", "max_age").
+	ReasonStepUpRequired = "STEP_UP_REQUIRED"
+	// ReasonInvalidRequest: the credentials are malformed.
+	ReasonInvalidRequest = "INVALID_REQUEST"
+	// ReasonInvalidToken: the credentials were rejected.
+	ReasonInvalidToken = "INVALID_TOKEN"
+	// ReasonInsufficientScope: the caller lacks a required permission. The
+	// ErrorInfo metadata lists the required permissions ("scope").
+	ReasonInsufficientScope = "INSUFFICIENT_SCOPE"
+)
+
+// AuthOption configures the Auth interceptor.
+type AuthOption func(*authConfig)
+
+type authConfig struct {
+	core.Config
+	excludedProcedures []string
+}
+
+// WithExcludedProcedures sets procedures that skip authentication, e.g.
+// "/grpc.health.v1.Health/Check". A trailing "*" matches every procedure
+// with that prefix.
+func WithExcludedProcedures(procedures ...string) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.excludedProcedures = append(cfg.excludedProcedures, procedures...)
+	}
+}
+
+// WithRiskHook evaluates every authenticated call with h, comparing the
+// caller's IP and User-Agent with the session's recorded fingerprint. See
+// WithTrustedProxies for services behind a reverse proxy. Rejected calls
+// fail with CodeUnauthenticated; step-up decisions carry an ErrorInfo with
+// ReasonStepUpRequired.
+func WithRiskHook(h *risk.Hook) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.RiskHook = h
+	}
+}
+
+// WithTrustedProxies names the reverse proxies in front of the service. The
+// risk hook sees the peer address as the client address unless it is one
+// of proxies; only then are X-Forwarded-For and X-Real-IP read, taking the
+// rightmost X-Forwarded-For entry that is not a trusted proxy.
+func WithTrustedProxies(proxies ...netip.Prefix) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.TrustedProxies = append(cfg.TrustedProxies, proxies...)
+	}
+}
+
+// WithImpersonation accepts impersonation tokens (tokens with an RFC 8693
+// "act" claim) whose actor is allowed by p. Without it such tokens are
+// rejected with CodePermissionDenied.
+func WithImpersonation(p *impersonate.Policy) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Impersonation = p
+	}
+}
+
+// WithAPIKeys also accepts API keys, sent as "X-API-Key: <key>" or
+// "Authorization: ApiKey <key>" and verified by v (typically an
+// apikey.Verifier). Require, RequireAny and RequireAll honor the key's
+// scopes.
+func WithAPIKeys(v iam.TokenVerifier) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.APIKeys = v
+	}
+}
+
+// WithServiceAccounts resolves service-account callers with r: the tenant
+// comes from the token or the X-Tenant-ID header, and the roles from the
+// account's bindings in that tenant. Accounts without a binding in the
+// requested tenant are rejected with CodePermissionDenied.
+func WithServiceAccounts(r *serviceaccount.Resolver) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.ServiceAccounts = r
+	}
+}
+
+// WithPrincipalTypes admits only the given principal types to procedure,
+// e.g. WithPrincipalTypes("/internal.v1.*", iam.PrincipalServiceAccount).
+// Procedures are matched as in WithExcludedProcedures. If several rules
+// match a procedure, the first one given applies. Other callers are
+// rejected with CodePermissionDenied.
+func WithPrincipalTypes(procedure string, allowed ...iam.PrincipalType) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Principals = append(cfg.Principals, core.PrincipalRule{Pattern: procedure, Types: allowed, Allow: true})
+	}
+}
+
+// WithDeniedPrincipalTypes rejects the given principal types from procedure
+// with CodePermissionDenied, e.g. keeping API keys away from admin
+// procedures. Procedures are matched as in WithExcludedProcedures.
+func WithDeniedPrincipalTypes(procedure string, denied ...iam.PrincipalType) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Principals = append(cfg.Principals, core.PrincipalRule{Pattern: procedure, Types: denied})
+	}
+}
+
+// Auth returns a server interceptor that verifies the call's bearer token
+// via client.Verifier() (or its API key, see WithAPIKeys). On success, it
+// stores the claims in the context (retrievable via iam.UserIDFromContext,
+// etc.). Missing or invalid credentials fail with CodeUnauthenticated.
+func Auth(client *iam.Client, opts ...AuthOption) connect.Interceptor {
+	cfg := &authConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return &serverInterceptor{check: func(ctx context.Context, call call) (context.Context, error) {
+		if cfg.excluded(call.procedure) {
+			return ctx, nil
+		}
+		return cfg.Authenticate(ctx, client, core.Request{
+			Operation:     call.procedure,
+			Authorization: call.header.Get("Authorization"),
+			APIKey:        call.header.Get(apikey.HeaderName),
+			Tenant:        call.header.Get(serviceaccount.TenantHeader),
+			Peer:          func(context.Context) core.Peer { return call.caller() },
+		})
+	}}
+}
+
+// Session returns a server interceptor that checks the token's session is
+// still active via client.Sessions(), enforcing the policies configured by
+// opts. Requires Auth to run first.
+func Session(client *iam.Client, opts ...session.ValidatorOption) connect.Interceptor {
+	validator := core.NewSessionValidator(client, opts)
+	return guard(func(ctx context.Context) error {
+		return core.CheckSession(ctx, validator)
+	})
+}
+
+// Tenant returns a server interceptor that validates tenant membership.
+// Requires Auth to run first. Service accounts have no memberships; their
+// access to the tenant comes from role bindings (see WithServiceAccounts)
+// and is not checked here.
+func Tenant(client *iam.Client) connect.Interceptor {
+	return guard(func(ctx context.Context) error {
+		return core.Tenant(ctx, client)
+	})
+}
+
+// Require returns a server interceptor that checks a single permission.
+// Requires Auth to run first. API key scopes are enforced, and during
+// impersonation actor-scoped permissions are checked against the actor (see
+// impersonate.Check).
+func Require(client *iam.Client, permission string) connect.Interceptor {
+	return RequireAll(client, permission)
+}
+
+// RequireAny returns a server interceptor that checks if the user has any
+// of the given permissions.
+func RequireAny(client *iam.Client, permissions ...string) connect.Interceptor {
+	return guard(func(ctx context.Context) error {
+		return core.RequireAny(ctx, client, permissions...)
+	})
+}
+
+// RequireAll returns a server interceptor that checks if the user has all
+// of the given permissions.
+func RequireAll(client *iam.Client, permissions ...string) connect.Interceptor {
+	return guard(func(ctx context.Context) error {
+		return core.RequireAll(ctx, client, permissions...)
+	})
+}
+
+// RequireACR returns a server interceptor that requires the token's
+// authentication context class ("acr" claim) to be one of values, e.g.
+// RequireACR("mfa"). Requires Auth to run first. Fails with
+// CodeUnauthenticated and an ErrorInfo with ReasonStepUpRequired.
+func RequireACR(values ...string) connect.Interceptor {
+	return guard(func(ctx context.Context) error {
+		return core.RequireACR(ctx, values...)
+	})
+}
+
+// RequireFreshAuth returns a server interceptor that requires the user to
+// have authenticated ("auth_time" claim) within maxAge. Requires Auth to
+// run first. Fails like RequireACR.
+func RequireFreshAuth(maxAge time.Duration) connect.Interceptor {
+	return guard(func(ctx context.Context) error {
+		return core.RequireFreshAuth(ctx, maxAge)
+	})
+}
+
+// --- internal helpers ---
+
+// call describes an incoming call for the server interceptors.
+type call struct {
+	procedure string
+	header    http.Header
+	peer      connect.Peer
+}
+
+// caller describes the caller.
+func (c call) caller() core.Peer {
+	return core.Peer{
+		RemoteAddr:   c.peer.Addr,
+		ForwardedFor: c.header.Values("X-Forwarded-For"),
+		RealIP:       c.header.Get("X-Real-IP"),
+		UserAgent:    c.header.Get("User-Agent"),
+	}
+}
+
+// serverInterceptor runs check on every incoming unary and streaming call
+// and passes the returned context on. Outgoing calls are not affected.
+type serverInterceptor struct {
+	check func(ctx context.Context, call call) (context.Context, error)
+}
+
+// guard returns a server interceptor that lets calls through if check
+// passes.
+func guard(check func(ctx context.Context) error) connect.Interceptor {
+	return &serverInterceptor{check: func(ctx context.Context, _ call) (context.Context, error) {
+		return ctx, check(ctx)
+	}}
+}
+
+func (i *serverInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		ctx, err := i.check(ctx, call{procedure: req.Spec().Procedure, header: req.Header(), peer: req.Peer()})
+		if err != nil {
+			return nil, toError(err)
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *serverInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *serverInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.check(ctx, call{procedure: conn.Spec().Procedure, header: conn.RequestHeader(), peer: conn.Peer()})
+		if err != nil {
+			return toError(err)
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (cfg *authConfig) excluded(procedure string) bool {
+	for _, pattern := range cfg.excludedProcedures {
+		if core.MatchOperation(pattern, procedure) {
+			return true
+		}
+	}
+	return false
+}
+
+// toError converts a pipeline error into a *connect.Error with an ErrorInfo
+// detail. Over the Connect protocol the WWW-Authenticate challenge is also
+// sent as a response header.
+func toError(err error) error {
+	e := core.AsError(err)
+	var code connect.Code
+	switch e.Kind {
+	case core.Unauthenticated:
+		code = connect.CodeUnauthenticated
+	case core.PermissionDenied:
+		code = connect.CodePermissionDenied
+	default:
+		return connect.NewError(connect.CodeInternal, errors.New(e.Message))
+	}
+
+	cerr := connect.NewError(code, errors.New(e.Message))
+	if c := e.Challenge(); c != "" {
+		cerr.Meta().Set("WWW-Authenticate", c)
+	}
+	if info := errorInfo(e); info != nil {
+		if detail, derr := connect.NewErrorDetail(info); derr == nil {
+			cerr.AddDetail(detail)
+		}
+	}
+	return cerr
+}
+
+// errorInfo describes e for clients, or returns nil if there is nothing to
+// add to its code and message.
+func errorInfo(e *core.Error) *errdetails.ErrorInfo {
+	info := &errdetails.ErrorInfo{Domain: "iam"}
+	switch {
+	case e.StepUp != nil:
+		info.Reason = ReasonStepUpRequired
+		info.Metadata = e.StepUp.Metadata()
+	case e.Bearer == core.BearerInvalidRequest:
+		info.Reason = ReasonInvalidRequest
+	case e.Bearer == core.BearerInvalidToken:
+		info.Reason = ReasonInvalidToken
+	case e.Bearer == core.BearerInsufficientScope:
+		info.Reason = ReasonInsufficientScope
+		info.Metadata = map[string]string{"scope": strings.Join(e.Scope, " ")}
+	default:
+		return nil
+	}
+	return info
+}
@@ -0,0 +1,336 @@
+package connectmw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+	"github.com/chimerakang/iam-go/risk"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	whoAmIProcedure = "/test.v1.Test/WhoAmI"
+	watchProcedure  = "/test.v1.Test/Watch"
+)
+
+// protocols are the client options for the protocols a Connect handler
+// serves.
+var protocols = map[string][]connect.ClientOption{
+	"connect":  nil,
+	"grpc":     {connect.WithGRPC()},
+	"grpc-web": {connect.WithGRPCWeb()},
+}
+
+// newServer serves a unary and a server-streaming procedure, both returning
+// the caller's user ID, behind interceptors.
+func newServer(t *testing.T, interceptors ...connect.Interceptor) *httptest.Server {
+	t.Helper()
+	whoAmI := func(ctx context.Context, _ *connect.Request[emptypb.Empty]) (*connect.Response[wrapperspb.StringValue], error) {
+		return connect.NewResponse(wrapperspb.String(iam.UserIDFromContext(ctx))), nil
+	}
+	watch := func(ctx context.Context, _ *connect.Request[emptypb.Empty], stream *connect.ServerStream[wrapperspb.StringValue]) error {
+		return stream.Send(wrapperspb.String(iam.UserIDFromContext(ctx)))
+	}
+
+	mux := http.NewServeMux()
+	opt := connect.WithInterceptors(interceptors...)
+	mux.Handle(whoAmIProcedure, connect.NewUnaryHandler(whoAmIProcedure, whoAmI, opt))
+	mux.Handle(watchProcedure, connect.NewServerStreamHandler(watchProcedure, watch, opt))
+	return newServerFor(t, mux)
+}
+
+// newServerFor serves h over TLS with HTTP/2, which the gRPC protocol
+// requires.
+func newServerFor(t *testing.T, h http.Handler) *httptest.Server {
+	t.Helper()
+	s := httptest.NewUnstartedServer(h)
+	s.EnableHTTP2 = true
+	s.StartTLS()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// whoAmI calls the unary procedure with token as bearer token.
+func whoAmI(s *httptest.Server, token string, opts ...connect.ClientOption) (string, error) {
+	c := connect.NewClient[emptypb.Empty, wrapperspb.StringValue](s.Client(), s.URL+whoAmIProcedure, opts...)
+	req := connect.NewRequest(&emptypb.Empty{})
+	if token != "" {
+		req.Header().Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.CallUnary(context.Background(), req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Msg.GetValue(), nil
+}
+
+// watch calls the streaming procedure with token as bearer token.
+func watch(s *httptest.Server, token string, opts ...connect.ClientOption) (string, error) {
+	c := connect.NewClient[emptypb.Empty, wrapperspb.StringValue](s.Client(), s.URL+watchProcedure, opts...)
+	req := connect.NewRequest(&emptypb.Empty{})
+	if token != "" {
+		req.Header().Set("Authorization", "Bearer "+token)
+	}
+	stream, err := c.CallServerStream(context.Background(), req)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+	var got string
+	for stream.Receive() {
+		got = stream.Msg().GetValue()
+	}
+	return got, stream.Err()
+}
+
+// codeOf returns the code of err, or 0 for success.
+func codeOf(err error) connect.Code {
+	if err == nil {
+		return 0
+	}
+	return connect.CodeOf(err)
+}
+
+// errorInfoOf returns the ErrorInfo detail of err, or nil.
+func errorInfoOf(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) {
+		t.Fatalf("error %v is not a *connect.Error", err)
+	}
+	for _, d := range cerr.Details() {
+		v, derr := d.Value()
+		if derr != nil {
+			t.Fatalf("decoding error detail: %v", derr)
+		}
+		if info, ok := v.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+func TestAuth_Protocols(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+	)
+	s := newServer(t, Auth(client))
+
+	for name, opts := range protocols {
+		t.Run(name, func(t *testing.T) {
+			if got, err := whoAmI(s, "user123", opts...); err != nil || got != "user123" {
+				t.Errorf("unary: got %q, %v; want user123", got, err)
+			}
+			if got, err := watch(s, "user123", opts...); err != nil || got != "user123" {
+				t.Errorf("streaming: got %q, %v; want user123", got, err)
+			}
+
+			_, err := whoAmI(s, "", opts...)
+			if connect.CodeOf(err) != connect.CodeUnauthenticated {
+				t.Errorf("unary without token: got %v, want unauthenticated", err)
+			}
+			_, err = watch(s, "", opts...)
+			if connect.CodeOf(err) != connect.CodeUnauthenticated {
+				t.Errorf("streaming without token: got %v, want unauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestAuth_InvalidToken(t *testing.T) {
+	client := fake.NewClient()
+	s := newServer(t, Auth(client))
+
+	_, err := whoAmI(s, "nobody")
+
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("got %v, want unauthenticated", err)
+	}
+	if info := errorInfoOf(t, err); info == nil || info.Reason != ReasonInvalidToken {
+		t.Errorf("ErrorInfo = %v, want reason %s", info, ReasonInvalidToken)
+	}
+	var cerr *connect.Error
+	if errors.As(err, &cerr) && !strings.HasPrefix(cerr.Meta().Get("WWW-Authenticate"), `Bearer error="invalid_token"`) {
+		t.Errorf("WWW-Authenticate = %q", cerr.Meta().Get("WWW-Authenticate"))
+	}
+}
+
+func TestAuth_ExcludedProcedure(t *testing.T) {
+	client := fake.NewClient()
+	s := newServer(t, Auth(client, WithExcludedProcedures("/test.v1.Test/Who*")))
+
+	if _, err := whoAmI(s, ""); err != nil {
+		t.Errorf("excluded procedure: got %v, want success", err)
+	}
+	if _, err := watch(s, ""); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("other procedure: got %v, want unauthenticated", err)
+	}
+}
+
+func TestAuth_PrincipalTypes(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+	)
+	s := newServer(t, Auth(client, WithPrincipalTypes(watchProcedure, iam.PrincipalServiceAccount)))
+
+	if _, err := whoAmI(s, "user123"); err != nil {
+		t.Errorf("unrestricted procedure: got %v, want success", err)
+	}
+	if _, err := watch(s, "user123"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("restricted procedure: got %v, want permission denied", err)
+	}
+}
+
+func TestTenant(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+	)
+	s := newServer(t, Auth(client), Tenant(client))
+
+	if _, err := whoAmI(s, "user123"); err != nil {
+		t.Errorf("member: got %v, want success", err)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithPermissions("user123", []string{"orders:read", "orders:write"}),
+	)
+
+	tests := []struct {
+		name string
+		mw   connect.Interceptor
+		want connect.Code
+	}{
+		{"require granted", Require(client, "orders:read"), 0},
+		{"require denied", Require(client, "orders:delete"), connect.CodePermissionDenied},
+		{"any granted", RequireAny(client, "orders:delete", "orders:write"), 0},
+		{"any denied", RequireAny(client, "orders:delete", "users:read"), connect.CodePermissionDenied},
+		{"all granted", RequireAll(client, "orders:read", "orders:write"), 0},
+		{"all denied", RequireAll(client, "orders:read", "orders:delete"), connect.CodePermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t, Auth(client), tt.mw)
+			if _, err := whoAmI(s, "user123"); codeOf(err) != tt.want {
+				t.Errorf("unary: got %v, want code %v", err, tt.want)
+			}
+			if _, err := watch(s, "user123"); codeOf(err) != tt.want {
+				t.Errorf("streaming: got %v, want code %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequire_InsufficientScope(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+	)
+	s := newServer(t, Auth(client), RequireAll(client, "orders:read", "orders:write"))
+
+	_, err := whoAmI(s, "user123", connect.WithGRPC())
+
+	info := errorInfoOf(t, err)
+	if info == nil || info.Reason != ReasonInsufficientScope || info.Metadata["scope"] != "orders:read orders:write" {
+		t.Errorf("ErrorInfo = %v, want the required permissions", info)
+	}
+}
+
+func TestRequireACR(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("pwd-user", "tenant123", "a@example.com", nil),
+		fake.WithUser("mfa-user", "tenant123", "b@example.com", nil),
+		fake.WithAuthContext("mfa-user", "mfa", []string{"pwd", "otp"}, time.Now()),
+	)
+	s := newServer(t, Auth(client), RequireACR("mfa"))
+
+	if _, err := whoAmI(s, "mfa-user"); err != nil {
+		t.Fatalf("mfa token: got %v, want success", err)
+	}
+	_, err := whoAmI(s, "pwd-user")
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("got %v, want unauthenticated", err)
+	}
+	if info := errorInfoOf(t, err); info == nil || info.Reason != ReasonStepUpRequired || info.Metadata["acr_values"] != "mfa" {
+		t.Errorf("ErrorInfo = %v, want %s with acr_values", info, ReasonStepUpRequired)
+	}
+}
+
+func TestRequireFreshAuth(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("stale", "tenant123", "b@example.com", nil),
+		fake.WithAuthContext("stale", "mfa", nil, time.Now().Add(-time.Hour)),
+	)
+	s := newServer(t, Auth(client), RequireFreshAuth(10*time.Minute))
+
+	_, err := watch(s, "stale")
+
+	if info := errorInfoOf(t, err); info == nil || info.Metadata["max_age"] != "600" {
+		t.Errorf("ErrorInfo = %v, want max_age 600", info)
+	}
+}
+
+func TestSession_Revoked(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithSession("user123", "sess1"),
+	)
+	s := newServer(t, Auth(client), Session(client))
+
+	if _, err := whoAmI(s, "sess1"); err != nil {
+		t.Fatalf("active session: got %v, want success", err)
+	}
+	_ = client.Sessions().Revoke(context.Background(), "sess1")
+	if _, err := whoAmI(s, "sess1"); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("revoked session: got %v, want unauthenticated", err)
+	}
+}
+
+func TestAuth_RiskHookFingerprint(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithSession("user123", "sess1"),
+	)
+	var got risk.Request
+	hook := risk.NewHook(risk.EvaluatorFunc(func(_ context.Context, req risk.Request) (risk.Assessment, error) {
+		got = req
+		return risk.Assessment{Action: risk.ActionAllow}, nil
+	}))
+
+	tests := []struct {
+		name string
+		opts []AuthOption
+		want string
+	}{
+		{"direct", nil, "127.0.0.1"},
+		{"behind proxy", []AuthOption{WithTrustedProxies(netip.MustParsePrefix("127.0.0.0/8"))}, "203.0.113.10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t, Auth(client, append(tt.opts, WithRiskHook(hook))...))
+			c := connect.NewClient[emptypb.Empty, wrapperspb.StringValue](s.Client(), s.URL+whoAmIProcedure)
+			req := connect.NewRequest(&emptypb.Empty{})
+			req.Header().Set("Authorization", "Bearer sess1")
+			req.Header().Set("X-Forwarded-For", "1.2.3.4, 203.0.113.10")
+
+			if _, err := c.CallUnary(context.Background(), req); err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+			if got.Fingerprint.IP != tt.want {
+				t.Errorf("IP = %q, want %q", got.Fingerprint.IP, tt.want)
+			}
+		})
+	}
+}
@@ -11,19 +11,36 @@ package grpcmw
 
 import (
 	"context"
+	"net/netip"
 	"strings"
+	"time"
 
 	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/internal/core"
+	"github.com/chimerakang/iam-go/risk"
+	"github.com/chimerakang/iam-go/serviceaccount"
+	"github.com/chimerakang/iam-go/session"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
+// ReasonStepUpRequired is the errdetails.ErrorInfo reason attached to
+// Unauthenticated errors when the caller must re-authenticate, more strongly
+// or more recently, before retrying. The ErrorInfo metadata carries the
+// requirements ("acr_values", "max_age").
+const ReasonStepUpRequired = "STEP_UP_REQUIRED"
+
 // AuthOption configures auth interceptor behavior.
 type AuthOption func(*authConfig)
 
 type authConfig struct {
+	core.Config
 	excludedMethods map[string]bool
 }
 
@@ -37,6 +54,75 @@ func WithExcludedMethods(methods ...string) AuthOption {
 	}
 }
 
+// WithRiskHook evaluates every authenticated call with h, comparing the
+// caller's address and user agent with the session's recorded fingerprint.
+// See WithTrustedProxies for services behind a reverse proxy. Rejected calls
+// fail with codes.Unauthenticated.
+func WithRiskHook(h *risk.Hook) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.RiskHook = h
+	}
+}
+
+// WithTrustedProxies names the reverse proxies in front of the service. The
+// risk hook sees the peer address as the client address unless it is one
+// of proxies; only then are the x-forwarded-for and x-real-ip metadata
+// read, taking the rightmost x-forwarded-for entry that is not a trusted
+// proxy.
+func WithTrustedProxies(proxies ...netip.Prefix) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.TrustedProxies = append(cfg.TrustedProxies, proxies...)
+	}
+}
+
+// WithImpersonation accepts impersonation tokens (tokens with an RFC 8693
+// "act" claim) whose actor is allowed by p. Without it such tokens are
+// rejected with codes.PermissionDenied.
+func WithImpersonation(p *impersonate.Policy) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Impersonation = p
+	}
+}
+
+// WithAPIKeys also accepts API keys, sent as "x-api-key: <key>" or
+// "authorization: ApiKey <key>" metadata and verified by v (typically an
+// apikey.Verifier). UnaryRequire honors the key's scopes.
+func WithAPIKeys(v iam.TokenVerifier) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.APIKeys = v
+	}
+}
+
+// WithServiceAccounts resolves service-account callers with r: the tenant
+// comes from the token or the x-tenant-id metadata, and the roles from the
+// account's bindings in that tenant. Accounts without a binding in the
+// requested tenant are rejected with codes.PermissionDenied.
+func WithServiceAccounts(r *serviceaccount.Resolver) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.ServiceAccounts = r
+	}
+}
+
+// WithPrincipalTypes admits only the given principal types to method, e.g.
+// WithPrincipalTypes("/internal.v1.Sync/*", iam.PrincipalServiceAccount).
+// A trailing "*" matches every method with that prefix. If several rules
+// match a method, the first one given applies. Other callers are rejected
+// with codes.PermissionDenied.
+func WithPrincipalTypes(method string, allowed ...iam.PrincipalType) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Principals = append(cfg.Principals, core.PrincipalRule{Pattern: method, Types: allowed, Allow: true})
+	}
+}
+
+// WithDeniedPrincipalTypes rejects the given principal types from method
+// with codes.PermissionDenied, e.g. keeping API keys away from "/admin.v1.*".
+// Methods are matched as in WithPrincipalTypes.
+func WithDeniedPrincipalTypes(method string, denied ...iam.PrincipalType) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Principals = append(cfg.Principals, core.PrincipalRule{Pattern: method, Types: denied})
+	}
+}
+
 // UnaryAuth returns a gRPC unary server interceptor that verifies JWT tokens.
 // On success, it stores claims in the context via iam.WithUserID, iam.WithClaims, etc.
 func UnaryAuth(client *iam.Client, opts ...AuthOption) grpc.UnaryServerInterceptor {
@@ -50,7 +136,7 @@ func UnaryAuth(client *iam.Client, opts ...AuthOption) grpc.UnaryServerIntercept
 			return handler(ctx, req)
 		}
 
-		ctx, err := authenticate(ctx, client)
+		ctx, err := cfg.authenticate(ctx, client, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
@@ -71,7 +157,7 @@ func StreamAuth(client *iam.Client, opts ...AuthOption) grpc.StreamServerInterce
 			return handler(srv, ss)
 		}
 
-		ctx, err := authenticate(ss.Context(), client)
+		ctx, err := cfg.authenticate(ss.Context(), client, info.FullMethod)
 		if err != nil {
 			return err
 		}
@@ -81,95 +167,160 @@ func StreamAuth(client *iam.Client, opts ...AuthOption) grpc.StreamServerInterce
 	}
 }
 
-// UnaryTenant returns a gRPC unary server interceptor that validates tenant membership.
+// UnarySession returns a gRPC unary server interceptor that checks the token's
+// session is still active, enforcing the policies configured by opts.
 // Requires UnaryAuth to run first.
-func UnaryTenant(client *iam.Client) grpc.UnaryServerInterceptor {
+func UnarySession(client *iam.Client, opts ...session.ValidatorOption) grpc.UnaryServerInterceptor {
+	validator := core.NewSessionValidator(client, opts)
+
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		svc := client.Tenants()
-		if svc == nil {
-			return handler(ctx, req)
+		if err := core.CheckSession(ctx, validator); err != nil {
+			return nil, toStatus(err)
 		}
+		return handler(ctx, req)
+	}
+}
 
-		userID := iam.UserIDFromContext(ctx)
-		tenantID := iam.TenantIDFromContext(ctx)
-		if userID == "" || tenantID == "" {
-			return nil, status.Error(codes.Unauthenticated, "missing user or tenant context")
-		}
+// StreamSession returns a gRPC stream server interceptor that checks the
+// token's session is still active. Requires StreamAuth to run first.
+func StreamSession(client *iam.Client, opts ...session.ValidatorOption) grpc.StreamServerInterceptor {
+	validator := core.NewSessionValidator(client, opts)
 
-		ok, err := svc.ValidateMembership(ctx, userID, tenantID)
-		if err != nil {
-			return nil, status.Error(codes.Internal, "tenant validation failed")
-		}
-		if !ok {
-			return nil, status.Error(codes.PermissionDenied, "not a member of this tenant")
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := core.CheckSession(ss.Context(), validator); err != nil {
+			return toStatus(err)
 		}
+		return handler(srv, ss)
+	}
+}
 
+// UnaryTenant returns a gRPC unary server interceptor that validates tenant membership.
+// Requires UnaryAuth to run first. Service accounts have no memberships;
+// their access to the tenant comes from role bindings (see
+// WithServiceAccounts) and is not checked here.
+func UnaryTenant(client *iam.Client) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := core.Tenant(ctx, client); err != nil {
+			return nil, toStatus(err)
+		}
 		return handler(ctx, req)
 	}
 }
 
 // UnaryRequire returns a gRPC unary server interceptor that checks a single permission.
-// Requires UnaryAuth to run first.
+// Requires UnaryAuth to run first. API key scopes are enforced, and during
+// impersonation actor-scoped permissions are checked against the actor (see
+// impersonate.Check).
 func UnaryRequire(client *iam.Client, permission string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		authz := client.Authz()
-		if authz == nil {
-			return nil, status.Error(codes.Internal, "authorizer not configured")
+		if err := core.RequireAll(ctx, client, permission); err != nil {
+			return nil, toStatus(err)
 		}
+		return handler(ctx, req)
+	}
+}
 
-		ok, err := authz.Check(ctx, permission)
-		if err != nil {
-			return nil, status.Error(codes.Internal, "authorization check failed")
-		}
-		if !ok {
-			return nil, status.Error(codes.PermissionDenied, "permission denied")
+// UnaryRequireACR returns a gRPC unary server interceptor that requires the
+// token's authentication context class ("acr" claim) to be one of values,
+// e.g. UnaryRequireACR("mfa"). Requires UnaryAuth to run first.
+// Fails with codes.Unauthenticated and an ErrorInfo with ReasonStepUpRequired.
+func UnaryRequireACR(values ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := core.RequireACR(ctx, values...); err != nil {
+			return nil, toStatus(err)
 		}
+		return handler(ctx, req)
+	}
+}
 
+// UnaryRequireFreshAuth returns a gRPC unary server interceptor that requires
+// the user to have authenticated ("auth_time" claim) within maxAge.
+// Requires UnaryAuth to run first. Fails like UnaryRequireACR.
+func UnaryRequireFreshAuth(maxAge time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := core.RequireFreshAuth(ctx, maxAge); err != nil {
+			return nil, toStatus(err)
+		}
 		return handler(ctx, req)
 	}
 }
 
 // --- internal helpers ---
 
-func authenticate(ctx context.Context, client *iam.Client) (context.Context, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return ctx, status.Error(codes.Unauthenticated, "missing metadata")
+// stepUpError converts e into an Unauthenticated status carrying an
+// ErrorInfo with ReasonStepUpRequired and e's requirements as metadata.
+func stepUpError(e *iam.StepUpError) error {
+	st := status.New(codes.Unauthenticated, "step-up authentication required")
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   ReasonStepUpRequired,
+		Domain:   "iam",
+		Metadata: e.Metadata(),
+	}); err == nil {
+		st = detailed
 	}
+	return st.Err()
+}
 
-	tokenStr := extractBearerFromMD(md)
-	if tokenStr == "" {
-		return ctx, status.Error(codes.Unauthenticated, "missing authorization token")
+// toStatus converts a pipeline error into a gRPC status error.
+func toStatus(err error) error {
+	e := core.AsError(err)
+	switch {
+	case e.StepUp != nil:
+		return stepUpError(e.StepUp)
+	case e.Kind == core.Unauthenticated:
+		return status.Error(codes.Unauthenticated, e.Message)
+	case e.Kind == core.PermissionDenied:
+		return status.Error(codes.PermissionDenied, e.Message)
 	}
+	return status.Error(codes.Internal, e.Message)
+}
 
-	verifier := client.Verifier()
-	if verifier == nil {
-		return ctx, status.Error(codes.Internal, "token verifier not configured")
+// authenticate runs the shared authentication pipeline on the call's
+// metadata.
+func (cfg *authConfig) authenticate(ctx context.Context, client *iam.Client, method string) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, status.Error(codes.Unauthenticated, "missing metadata")
 	}
 
-	claims, err := verifier.Verify(ctx, tokenStr)
+	ctx, err := cfg.Authenticate(ctx, client, request(md, method))
 	if err != nil {
-		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+		return ctx, toStatus(err)
 	}
+	return ctx, nil
+}
 
-	ctx = iam.WithClaims(ctx, claims)
-	ctx = iam.WithUserID(ctx, claims.Subject)
-	ctx = iam.WithTenantID(ctx, claims.TenantID)
-	ctx = iam.WithRoles(ctx, claims.Roles)
+// request describes a call to method with metadata md for the
+// authentication pipeline.
+func request(md metadata.MD, method string) core.Request {
+	return core.Request{
+		Operation:     method,
+		Authorization: firstValue(md, "authorization"),
+		APIKey:        firstValue(md, strings.ToLower(apikey.HeaderName)),
+		Tenant:        firstValue(md, strings.ToLower(serviceaccount.TenantHeader)),
+		Peer:          callerPeer,
+	}
+}
 
-	return ctx, nil
+func firstValue(md metadata.MD, key string) string {
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
 }
 
-func extractBearerFromMD(md metadata.MD) string {
-	vals := md.Get("authorization")
-	if len(vals) == 0 {
-		return ""
+// callerPeer describes the caller of the current call.
+func callerPeer(ctx context.Context) core.Peer {
+	md, _ := metadata.FromIncomingContext(ctx)
+	p := core.Peer{
+		ForwardedFor: md.Get("x-forwarded-for"),
+		RealIP:       firstValue(md, "x-real-ip"),
+		UserAgent:    firstValue(md, "user-agent"),
 	}
-	parts := strings.SplitN(vals[0], " ", 2)
-	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-		return ""
+	if gp, ok := peer.FromContext(ctx); ok {
+		p.RemoteAddr = gp.Addr.String()
 	}
-	return parts[1]
+	return p
 }
 
 // wrappedStream wraps grpc.ServerStream to override Context().
@@ -2,12 +2,23 @@ package grpcmw
 
 import (
 	"context"
+	"net"
+	"net/netip"
 	"testing"
+	"time"
 
 	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
 	"github.com/chimerakang/iam-go/fake"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/internal/core"
+	"github.com/chimerakang/iam-go/risk"
+	"github.com/chimerakang/iam-go/serviceaccount"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -21,7 +32,7 @@ func TestAuthenticate_Success(t *testing.T) {
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
 	// Call authenticate helper
-	newCtx, err := authenticate(ctx, client)
+	newCtx, err := (&authConfig{}).authenticate(ctx, client, "")
 
 	if err != nil {
 		t.Fatalf("authenticate returned error: %v", err)
@@ -34,6 +45,278 @@ func TestAuthenticate_Success(t *testing.T) {
 	if tenantID := iam.TenantIDFromContext(newCtx); tenantID != "tenant123" {
 		t.Errorf("expected tenantID tenant123, got %s", tenantID)
 	}
+	if token := iam.AccessTokenFromContext(newCtx); token != "user123" {
+		t.Errorf("expected access token user123, got %s", token)
+	}
+}
+
+func TestAuthenticate_SessionID(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+	)
+
+	md := metadata.Pairs("authorization", "Bearer sess1")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	newCtx, err := (&authConfig{}).authenticate(ctx, client, "")
+
+	if err != nil {
+		t.Fatalf("authenticate returned error: %v", err)
+	}
+	if sid := iam.SessionIDFromContext(newCtx); sid != "sess1" {
+		t.Errorf("expected session ID sess1, got %s", sid)
+	}
+}
+
+func TestUnarySession(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+		fake.WithSession("user123", "sess2"),
+	)
+	_ = client.Sessions().Revoke(context.Background(), "sess2")
+	interceptor := UnarySession(client)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	_, err := interceptor(fake.ContextWithSession(context.Background(), "user123", "sess1"), nil, &grpc.UnaryServerInfo{}, handler)
+	if err != nil {
+		t.Fatalf("expected active session to pass, got %v", err)
+	}
+
+	_, err = interceptor(fake.ContextWithSession(context.Background(), "user123", "sess2"), nil, &grpc.UnaryServerInfo{}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated for revoked session, got %v", err)
+	}
+}
+
+func TestUnaryAuth_RiskHook(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+	)
+	var got risk.Request
+	hook := risk.NewHook(risk.EvaluatorFunc(func(_ context.Context, req risk.Request) (risk.Assessment, error) {
+		got = req
+		return risk.Assessment{Score: 100, Action: risk.ActionRevoke}, nil
+	}))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	md := metadata.Pairs("authorization", "Bearer sess1", "user-agent", "grpc-go/1.79", "x-forwarded-for", "203.0.113.10")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443}})
+	interceptor := UnaryAuth(client, WithRiskHook(hook), WithTrustedProxies(netip.MustParsePrefix("10.0.0.0/8")))
+
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}, handler)
+
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+	if got.SessionID != "sess1" || got.Fingerprint.IP != "203.0.113.10" || got.Fingerprint.UserAgent != "grpc-go/1.79" {
+		t.Errorf("unexpected risk request: %+v", got)
+	}
+}
+
+func TestUnaryAuth_RiskHookIgnoresUntrustedForwarding(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithSession("user123", "sess1"),
+	)
+	var got risk.Request
+	hook := risk.NewHook(risk.EvaluatorFunc(func(_ context.Context, req risk.Request) (risk.Assessment, error) {
+		got = req
+		return risk.Assessment{Action: risk.ActionAllow}, nil
+	}))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	md := metadata.Pairs("authorization", "Bearer sess1", "x-forwarded-for", "203.0.113.10", "x-real-ip", "203.0.113.9")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("198.51.100.7"), Port: 51234}})
+
+	if _, err := UnaryAuth(client, WithRiskHook(hook))(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}, handler); err != nil {
+		t.Fatalf("UnaryAuth() error = %v", err)
+	}
+	if got.Fingerprint.IP != "198.51.100.7" {
+		t.Errorf("IP = %q, want the peer address", got.Fingerprint.IP)
+	}
+}
+
+func TestUnaryAuth_Impersonation(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("support", "tenant123", "support@example.com", nil),
+		fake.WithUser("intern", "tenant123", "intern@example.com", nil),
+		fake.WithUser("customer", "tenant123", "customer@example.com", nil),
+		fake.WithImpersonation("support-token", "support", "customer"),
+		fake.WithImpersonation("intern-token", "intern", "customer"),
+		fake.WithPermissions("support", []string{impersonate.DefaultPermission}),
+	)
+	policy := impersonate.NewPolicy(client.Authz())
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	var captured context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = ctx
+		return "ok", nil
+	}
+	call := func(token string, opts ...AuthOption) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		_, err := UnaryAuth(client, opts...)(ctx, nil, info, handler)
+		return err
+	}
+
+	if err := call("support-token"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("without WithImpersonation: expected PermissionDenied, got %v", err)
+	}
+	if err := call("intern-token", WithImpersonation(policy)); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("actor without permission: expected PermissionDenied, got %v", err)
+	}
+	if err := call("support-token", WithImpersonation(policy)); err != nil {
+		t.Fatalf("expected impersonation to be admitted, got %v", err)
+	}
+	if iam.UserIDFromContext(captured) != "customer" || iam.ActorIDFromContext(captured) != "support" {
+		t.Errorf("user = %q, actor = %q", iam.UserIDFromContext(captured), iam.ActorIDFromContext(captured))
+	}
+}
+
+// stepUpInfo returns the ErrorInfo attached to a step-up error, or nil.
+func stepUpInfo(err error) *errdetails.ErrorInfo {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Reason == ReasonStepUpRequired {
+			return info
+		}
+	}
+	return nil
+}
+
+func TestUnaryRequireACR(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	interceptor := UnaryRequireACR("mfa", "hwk")
+
+	ctx := iam.WithClaims(context.Background(), &iam.Claims{Subject: "u1", ACR: "hwk"})
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	ctx = iam.WithClaims(context.Background(), &iam.Claims{Subject: "u1", ACR: "pwd"})
+	_, err := interceptor(ctx, nil, info, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	if d := stepUpInfo(err); d == nil || d.Metadata["acr_values"] != "mfa hwk" {
+		t.Errorf("unexpected error details: %v", d)
+	}
+}
+
+func TestUnaryRequireFreshAuth(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	interceptor := UnaryRequireFreshAuth(5 * time.Minute)
+
+	ctx := iam.WithClaims(context.Background(), &iam.Claims{Subject: "u1", AuthTime: time.Now().Add(-time.Minute)})
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	for name, claims := range map[string]*iam.Claims{
+		"stale":        {Subject: "u1", AuthTime: time.Now().Add(-time.Hour)},
+		"no auth_time": {Subject: "u1"},
+	} {
+		_, err := interceptor(iam.WithClaims(context.Background(), claims), nil, info, handler)
+		if d := stepUpInfo(err); d == nil || d.Metadata["max_age"] != "300" {
+			t.Errorf("%s: expected step-up error with max_age 300, got %v", name, err)
+		}
+	}
+}
+
+func TestUnaryAuth_APIKey(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithPermissions("user123", []string{"orders:read", "orders:write"}),
+		fake.WithAPIKey("iam_ci_secret", "user123", "tenant123", []string{"orders:read"}),
+	)
+	auth := UnaryAuth(client, WithAPIKeys(apikey.NewVerifier(client.APIKeys())))
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	call := func(perm string, md metadata.MD) error {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := auth(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return UnaryRequire(client, perm)(ctx, req, info, func(context.Context, interface{}) (interface{}, error) { return "ok", nil })
+		})
+		return err
+	}
+
+	if err := call("orders:read", metadata.Pairs("x-api-key", "iam_ci_secret")); err != nil {
+		t.Errorf("x-api-key: expected success, got %v", err)
+	}
+	if err := call("orders:read", metadata.Pairs("authorization", "ApiKey iam_ci_secret")); err != nil {
+		t.Errorf("authorization ApiKey: expected success, got %v", err)
+	}
+	if err := call("orders:write", metadata.Pairs("x-api-key", "iam_ci_secret")); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("out of scope: expected PermissionDenied, got %v", err)
+	}
+	if err := call("orders:read", metadata.Pairs("x-api-key", "iam_ci_wrong")); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("wrong key: expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestUnaryAuth_ServiceAccount(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithServiceAccount("sa-sync", "sync"),
+		fake.WithServiceAccountRole("sa-sync", "t1", "reader"),
+		fake.WithPermissions("sa-sync", []string{"records:sync"}),
+	)
+	auth := UnaryAuth(client,
+		WithServiceAccounts(serviceaccount.NewResolver(client.ServiceAccounts())),
+		WithPrincipalTypes("/internal.v1.Sync/*", iam.PrincipalServiceAccount),
+	)
+	var captured context.Context
+	call := func(method string, md metadata.MD) error {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := auth(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			captured = ctx
+			return "ok", nil
+		})
+		return err
+	}
+
+	if err := call("/internal.v1.Sync/Pull", metadata.Pairs("authorization", "Bearer sa-sync", "x-tenant-id", "t1")); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if iam.TenantIDFromContext(captured) != "t1" || len(iam.RolesFromContext(captured)) != 1 || iam.RolesFromContext(captured)[0] != "reader" {
+		t.Errorf("unexpected context: tenant %q, roles %v", iam.TenantIDFromContext(captured), iam.RolesFromContext(captured))
+	}
+	if ok, _ := core.CheckPermission(captured, client.Authz(), "records:sync"); !ok {
+		t.Error("expected the service account's permission to be granted")
+	}
+
+	if err := call("/internal.v1.Sync/Pull", metadata.Pairs("authorization", "Bearer sa-sync", "x-tenant-id", "t2")); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("unbound tenant: expected PermissionDenied, got %v", err)
+	}
+	if err := call("/internal.v1.Sync/Pull", metadata.Pairs("authorization", "Bearer sa-unknown")); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("unknown token: expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestPrincipalAllowed(t *testing.T) {
+	cfg := &authConfig{}
+	WithDeniedPrincipalTypes("/admin.v1.Users/Delete", iam.PrincipalAPIKey)(cfg)
+	WithPrincipalTypes("/internal.v1.*", iam.PrincipalServiceAccount)(cfg)
+	WithPrincipalTypes("/admin.v1.*", iam.PrincipalUser)(cfg)
+
+	tests := []struct {
+		method    string
+		principal iam.PrincipalType
+		want      bool
+	}{
+		{"/internal.v1.Sync/Pull", iam.PrincipalServiceAccount, true},
+		{"/internal.v1.Sync/Pull", iam.PrincipalUser, false},
+		{"/admin.v1.Users/Delete", iam.PrincipalAPIKey, false},
+		{"/admin.v1.Users/Delete", iam.PrincipalUser, true}, // first matching rule applies
+		{"/admin.v1.Users/List", iam.PrincipalAPIKey, false},
+		{"/public.v1.Orders/List", iam.PrincipalAPIKey, true},
+	}
+	for _, tt := range tests {
+		if got := cfg.PrincipalAllowed(tt.method, tt.principal); got != tt.want {
+			t.Errorf("PrincipalAllowed(%s, %s) = %v, want %v", tt.method, tt.principal, got, tt.want)
+		}
+	}
 }
 
 func TestAuthenticate_MissingToken(t *testing.T) {
@@ -43,7 +326,7 @@ func TestAuthenticate_MissingToken(t *testing.T) {
 	md := metadata.New(map[string]string{})
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
-	_, err := authenticate(ctx, client)
+	_, err := (&authConfig{}).authenticate(ctx, client, "")
 
 	if err == nil {
 		t.Fatal("expected error for missing token")
@@ -62,7 +345,7 @@ func TestAuthenticate_InvalidToken(t *testing.T) {
 	md := metadata.Pairs("authorization", "Bearer unknown-user")
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
-	_, err := authenticate(ctx, client)
+	_, err := (&authConfig{}).authenticate(ctx, client, "")
 
 	if err == nil {
 		t.Fatal("expected error for invalid token")
@@ -123,7 +406,7 @@ func TestAuthenticateMultipleCases(t *testing.T) {
 			}
 			ctx := metadata.NewIncomingContext(context.Background(), md)
 
-			newCtx, err := authenticate(ctx, client)
+			newCtx, err := (&authConfig{}).authenticate(ctx, client, "")
 
 			if tc.expectErr {
 				if err == nil {
@@ -146,7 +429,7 @@ func TestAuthenticateMultipleCases(t *testing.T) {
 
 func TestExtractBearerFromMD_Success(t *testing.T) {
 	md := metadata.Pairs("authorization", "Bearer mytoken123")
-	token := extractBearerFromMD(md)
+	token := core.ExtractBearer(firstValue(md, "authorization"))
 
 	if token != "mytoken123" {
 		t.Errorf("expected mytoken123, got %s", token)
@@ -155,7 +438,7 @@ func TestExtractBearerFromMD_Success(t *testing.T) {
 
 func TestExtractBearerFromMD_Empty(t *testing.T) {
 	md := metadata.New(map[string]string{})
-	token := extractBearerFromMD(md)
+	token := core.ExtractBearer(firstValue(md, "authorization"))
 
 	if token != "" {
 		t.Errorf("expected empty string, got %s", token)
@@ -164,7 +447,7 @@ func TestExtractBearerFromMD_Empty(t *testing.T) {
 
 func TestExtractBearerFromMD_NoBearer(t *testing.T) {
 	md := metadata.Pairs("authorization", "Basic credentials")
-	token := extractBearerFromMD(md)
+	token := core.ExtractBearer(firstValue(md, "authorization"))
 
 	if token != "" {
 		t.Errorf("expected empty string for non-Bearer, got %s", token)
@@ -0,0 +1,73 @@
+package grpcmw
+
+import (
+	"context"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryRules returns a gRPC unary server interceptor that enforces the
+// (iam.v1.rule) option of the called method, declared in its proto
+// definition: public methods pass, others are authenticated as by UnaryAuth
+// and then checked for tenant membership and the rule's permission. Methods
+// without a rule are denied with codes.PermissionDenied, so a method cannot
+// be exposed by forgetting to protect it; use WithExcludedMethods for
+// methods defined outside your protos, such as health checks. It replaces
+// UnaryAuth, UnaryTenant and UnaryRequire for services that declare rules.
+func UnaryRules(client *iam.Client, opts ...AuthOption) grpc.UnaryServerInterceptor {
+	cfg := &authConfig{excludedMethods: make(map[string]bool)}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if cfg.excludedMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := cfg.authorize(ctx, client, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRules returns a gRPC stream server interceptor that enforces the
+// (iam.v1.rule) option of the called method; see UnaryRules.
+func StreamRules(client *iam.Client, opts ...AuthOption) grpc.StreamServerInterceptor {
+	cfg := &authConfig{excludedMethods: make(map[string]bool)}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if cfg.excludedMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := cfg.authorize(ss.Context(), client, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		wrapped := &wrappedStream{ServerStream: ss, ctx: ctx}
+		return handler(srv, wrapped)
+	}
+}
+
+// --- internal helpers ---
+
+// authorize enforces the (iam.v1.rule) option of method on the call.
+func (cfg *authConfig) authorize(ctx context.Context, client *iam.Client, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx, err := cfg.Authorize(ctx, client, core.LookupRule(method), request(md, method))
+	if err != nil {
+		return ctx, toStatus(err)
+	}
+	return ctx, nil
+}
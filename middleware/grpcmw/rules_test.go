@@ -0,0 +1,137 @@
+package grpcmw
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+	iamv1 "github.com/chimerakang/iam-go/proto/iam/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// registerRulesService registers rulestest.v1.Orders, whose methods carry
+// (iam.v1.rule) options, as generated code would.
+func registerRulesService(t *testing.T) {
+	t.Helper()
+	registerOnce.Do(func() {
+		method := func(name string, rule *iamv1.Rule) *descriptorpb.MethodDescriptorProto {
+			m := &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String(name),
+				InputType:  proto.String(".google.protobuf.Empty"),
+				OutputType: proto.String(".google.protobuf.Empty"),
+			}
+			if rule != nil {
+				m.Options = &descriptorpb.MethodOptions{}
+				proto.SetExtension(m.Options, iamv1.E_Rule, rule)
+			}
+			return m
+		}
+		fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+			Name:       proto.String("rulestest/v1/orders.proto"),
+			Package:    proto.String("rulestest.v1"),
+			Syntax:     proto.String("proto3"),
+			Dependency: []string{"google/protobuf/empty.proto"},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("Orders"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					method("Ping", &iamv1.Rule{Public: true}),
+					method("List", &iamv1.Rule{}),
+					method("Create", &iamv1.Rule{Permission: "orders:write", TenantRequired: true}),
+					method("Delete", nil),
+				},
+			}},
+		}, protoregistry.GlobalFiles)
+		if err == nil {
+			err = protoregistry.GlobalFiles.RegisterFile(fd)
+		}
+		registerErr = err
+	})
+	if registerErr != nil {
+		t.Fatalf("registering test service: %v", registerErr)
+	}
+}
+
+func TestUnaryRules(t *testing.T) {
+	registerRulesService(t)
+	client := fake.NewClient(
+		fake.WithUser("writer", "tenant123", "w@example.com", nil),
+		fake.WithUser("reader", "tenant123", "r@example.com", nil),
+		fake.WithPermissions("writer", []string{"orders:write"}),
+	)
+	interceptor := UnaryRules(client, WithExcludedMethods("/grpc.health.v1.Health/Check"))
+	var captured context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = ctx
+		return "ok", nil
+	}
+
+	tests := []struct {
+		method string
+		token  string
+		want   codes.Code
+	}{
+		{"/rulestest.v1.Orders/Ping", "", codes.OK},
+		{"/grpc.health.v1.Health/Check", "", codes.OK},
+		{"/rulestest.v1.Orders/List", "", codes.Unauthenticated},
+		{"/rulestest.v1.Orders/List", "reader", codes.OK},
+		{"/rulestest.v1.Orders/Create", "reader", codes.PermissionDenied},
+		{"/rulestest.v1.Orders/Create", "writer", codes.OK},
+		{"/rulestest.v1.Orders/Delete", "writer", codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+"/"+tt.token, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tt.token))
+			}
+			captured = nil
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.want {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want == codes.OK && tt.token != "" && iam.UserIDFromContext(captured) != tt.token {
+				t.Errorf("user = %q, want %q", iam.UserIDFromContext(captured), tt.token)
+			}
+		})
+	}
+}
+
+func TestStreamRules(t *testing.T) {
+	registerRulesService(t)
+	client := fake.NewClient(
+		fake.WithUser("reader", "tenant123", "r@example.com", nil),
+	)
+	interceptor := StreamRules(client)
+	var captured context.Context
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		captured = ss.Context()
+		return nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer reader"))
+
+	if err := interceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/rulestest.v1.Orders/List"}, handler); err != nil {
+		t.Fatalf("List: got %v, want success", err)
+	}
+	if iam.UserIDFromContext(captured) != "reader" {
+		t.Errorf("user = %q, want reader", iam.UserIDFromContext(captured))
+	}
+	err := interceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/rulestest.v1.Orders/Delete"}, handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Delete: got %v, want PermissionDenied", err)
+	}
+}
@@ -0,0 +1,119 @@
+package httpmw
+
+import (
+	"fmt"
+	"net/http"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/core"
+)
+
+// ClientOption configures OAuth2ClientCredentials and OAuth2OnBehalfOf.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	defaultRequest *iam.TokenRequest
+	targets        []tokenTarget
+}
+
+// tokenTarget selects the token request for matching URLs.
+type tokenTarget struct {
+	pattern string
+	req     iam.TokenRequest
+}
+
+// WithTokenRequest requests tokens for req on every call that no
+// WithTargetToken rule matches.
+func WithTokenRequest(req iam.TokenRequest) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.defaultRequest = &req
+	}
+}
+
+// WithTargetToken requests tokens for req on calls to url, given as
+// scheme://host/path, e.g.
+// WithTargetToken("https://billing.internal/*", iam.TokenRequest{Audience: "billing"}).
+// A trailing "*" matches every URL with that prefix.
+func WithTargetToken(url string, req iam.TokenRequest) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.targets = append(cfg.targets, tokenTarget{pattern: url, req: req})
+	}
+}
+
+// OAuth2ClientCredentials returns an http.RoundTripper that injects an
+// OAuth2 Bearer token into outgoing requests using client credentials, then
+// sends them with base (http.DefaultTransport if nil). The token is
+// automatically cached and refreshed before expiry. With WithTokenRequest or
+// WithTargetToken the token is chosen per URL, which requires an exchanger
+// implementing iam.TokenProvider.
+func OAuth2ClientCredentials(client *iam.Client, base http.RoundTripper, opts ...ClientOption) http.RoundTripper {
+	cfg := newClientConfig(opts)
+	return &transport{base: base, token: func(r *http.Request) (string, error) {
+		return core.ClientToken(r.Context(), client, cfg.tokenRequest(r))
+	}}
+}
+
+// OAuth2OnBehalfOf returns an http.RoundTripper that calls downstream
+// services on behalf of the current caller: it exchanges the caller's bearer
+// token, stored in the request context by Auth, for a token restricted to
+// the target chosen by WithTokenRequest or WithTargetToken (RFC 8693). The
+// exchanger must implement iam.OnBehalfOfExchanger. Outgoing requests must
+// carry the incoming request's context (http.NewRequestWithContext).
+func OAuth2OnBehalfOf(client *iam.Client, base http.RoundTripper, opts ...ClientOption) http.RoundTripper {
+	cfg := newClientConfig(opts)
+	return &transport{base: base, token: func(r *http.Request) (string, error) {
+		var req iam.TokenRequest
+		if tr := cfg.tokenRequest(r); tr != nil {
+			req = *tr
+		}
+		return core.OnBehalfOfToken(r.Context(), client, req)
+	}}
+}
+
+// --- internal helpers ---
+
+func newClientConfig(opts []ClientOption) *clientConfig {
+	cfg := &clientConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return cfg
+}
+
+// tokenRequest returns the token request for r's URL, or nil for the
+// exchanger's default token.
+func (cfg *clientConfig) tokenRequest(r *http.Request) *iam.TokenRequest {
+	url := r.URL.Scheme + "://" + r.URL.Host + r.URL.Path
+	for i, target := range cfg.targets {
+		if core.MatchOperation(target.pattern, url) {
+			return &cfg.targets[i].req
+		}
+	}
+	return cfg.defaultRequest
+}
+
+// transport sets the Authorization header of each request to a token.
+type transport struct {
+	base  http.RoundTripper
+	token func(r *http.Request) (string, error)
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := t.token(r)
+	if err != nil {
+		if r.Body != nil {
+			_ = r.Body.Close()
+		}
+		return nil, fmt.Errorf("iam/httpmw: %w", err)
+	}
+
+	// A RoundTripper must not modify the caller's request
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,135 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+)
+
+// recordingProvider issues "token-<audience>" and records the requests.
+type recordingProvider struct {
+	requests []iam.TokenRequest
+}
+
+func (p *recordingProvider) ExchangeToken(_ context.Context, _ []string) (*iam.OAuth2Token, error) {
+	return &iam.OAuth2Token{AccessToken: "default-token"}, nil
+}
+
+func (p *recordingProvider) GetCachedToken(_ context.Context) (string, error) {
+	return "default-token", nil
+}
+
+func (p *recordingProvider) GetToken(_ context.Context, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	p.requests = append(p.requests, req)
+	return &iam.OAuth2Token{AccessToken: "token-" + req.Audience}, nil
+}
+
+// recordingTransport records the Authorization header it is asked to send.
+type recordingTransport struct {
+	authorization string
+}
+
+func (t *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.authorization = r.Header.Get("Authorization")
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithOAuth2App("app_test", "secret_test", []string{"iam:introspect"}),
+	)
+	base := &recordingTransport{}
+	r := httptest.NewRequest(http.MethodGet, "https://api.internal/orders", nil)
+
+	resp, err := OAuth2ClientCredentials(client, base).RoundTrip(r)
+	if err != nil {
+		t.Fatalf("RoundTrip() error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if !strings.HasPrefix(base.authorization, "Bearer ") || len(base.authorization) == len("Bearer ") {
+		t.Errorf("Authorization = %q, want a bearer token", base.authorization)
+	}
+	if r.Header.Get("Authorization") != "" {
+		t.Error("the caller's request was modified")
+	}
+}
+
+func TestOAuth2ClientCredentials_NoExchanger(t *testing.T) {
+	client, _ := iam.NewClient(iam.Config{Endpoint: "localhost:9000"})
+	r := httptest.NewRequest(http.MethodGet, "https://api.internal/orders", nil)
+
+	if _, err := OAuth2ClientCredentials(client, &recordingTransport{}).RoundTrip(r); err == nil {
+		t.Fatal("expected error when oauth2 exchanger not configured")
+	}
+}
+
+func TestOAuth2ClientCredentials_PerTarget(t *testing.T) {
+	provider := &recordingProvider{}
+	client, _ := iam.NewClient(iam.Config{Endpoint: "localhost:9000"}, iam.WithOAuth2Exchanger(provider))
+	opts := []ClientOption{
+		WithTargetToken("https://billing.internal/*", iam.TokenRequest{Audience: "billing", Scopes: []string{"charge"}}),
+		WithTokenRequest(iam.TokenRequest{Audience: "api"}),
+	}
+
+	tests := []struct {
+		name string
+		opts []ClientOption
+		url  string
+		want string
+	}{
+		{"no options uses default token", nil, "https://billing.internal/charges", "Bearer default-token"},
+		{"target match", opts, "https://billing.internal/charges", "Bearer token-billing"},
+		{"fallback to token request", opts, "https://ledger.internal/entries", "Bearer token-api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &recordingTransport{}
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if _, err := OAuth2ClientCredentials(client, base, tt.opts...).RoundTrip(r); err != nil {
+				t.Fatalf("RoundTrip() error: %v", err)
+			}
+			if base.authorization != tt.want {
+				t.Errorf("Authorization = %q, want %q", base.authorization, tt.want)
+			}
+		})
+	}
+	if len(provider.requests) != 2 || len(provider.requests[0].Scopes) != 1 {
+		t.Errorf("unexpected token requests: %+v", provider.requests)
+	}
+}
+
+func TestOAuth2OnBehalfOf(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithOAuth2App("svc-orders", "secret_test", nil),
+	)
+	base := &recordingTransport{}
+	rt := OAuth2OnBehalfOf(client, base, WithTargetToken("https://billing.internal/*", iam.TokenRequest{Audience: "billing"}))
+
+	// Without an authenticated caller there is nothing to exchange
+	r := httptest.NewRequest(http.MethodPost, "https://billing.internal/charges", strings.NewReader("{}"))
+	if _, err := rt.RoundTrip(r); err == nil {
+		t.Fatal("expected error without a caller token")
+	}
+
+	r = r.WithContext(iam.WithAccessToken(context.Background(), "user123"))
+	if _, err := rt.RoundTrip(r); err != nil {
+		t.Fatalf("RoundTrip() error: %v", err)
+	}
+
+	// The downstream token is the caller's, with the service as actor
+	token := strings.TrimPrefix(base.authorization, "Bearer ")
+	claims, err := client.Verifier().Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify(%q) error: %v", token, err)
+	}
+	if claims.Subject != "user123" || claims.Actor == nil || claims.Actor.Subject != "svc-orders" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
@@ -0,0 +1,272 @@
+// Package httpmw provides net/http middleware for IAM integration.
+//
+// Every middleware has the standard func(http.Handler) http.Handler
+// signature, so it works with net/http, chi, gorilla/mux and other routers,
+// and with echo through echo.WrapMiddleware. Use kratosmw for Kratos
+// services, grpcmw for plain gRPC and connectmw for Connect; all of them
+// share one authentication and authorization pipeline and enforce the same
+// rules.
+//
+// Failures are answered with an RFC 6750 WWW-Authenticate challenge where
+// applicable and an RFC 9457 problem details body (application/problem+json).
+package httpmw
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/internal/core"
+	"github.com/chimerakang/iam-go/risk"
+	"github.com/chimerakang/iam-go/serviceaccount"
+	"github.com/chimerakang/iam-go/session"
+)
+
+// AuthOption configures Auth middleware behavior.
+type AuthOption func(*authConfig)
+
+type authConfig struct {
+	core.Config
+	excludedPaths []string
+}
+
+// WithExcludedPaths sets request paths that skip authentication (e.g.
+// "/healthz"). A trailing "*" matches every path with that prefix.
+func WithExcludedPaths(paths ...string) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.excludedPaths = append(cfg.excludedPaths, paths...)
+	}
+}
+
+// WithRiskHook evaluates every authenticated request with h, comparing the
+// caller's IP and User-Agent with the session's recorded fingerprint. See
+// WithTrustedProxies for services behind a reverse proxy. Rejected requests
+// get 401; when h asks for step-up authentication the response carries an
+// RFC 9470 challenge.
+func WithRiskHook(h *risk.Hook) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.RiskHook = h
+	}
+}
+
+// WithTrustedProxies names the reverse proxies in front of the service. The
+// risk hook sees the connection's remote address as the client address
+// unless it is one of proxies; only then are X-Forwarded-For and X-Real-IP
+// read, taking the rightmost X-Forwarded-For entry that is not a trusted
+// proxy.
+func WithTrustedProxies(proxies ...netip.Prefix) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.TrustedProxies = append(cfg.TrustedProxies, proxies...)
+	}
+}
+
+// WithImpersonation accepts impersonation tokens (tokens with an RFC 8693
+// "act" claim) whose actor is allowed by p. Without it such tokens are
+// rejected with 403.
+func WithImpersonation(p *impersonate.Policy) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Impersonation = p
+	}
+}
+
+// WithAPIKeys also accepts API keys, sent as "X-API-Key: <key>" or
+// "Authorization: ApiKey <key>" and verified by v (typically an
+// apikey.Verifier). Require, RequireAny and RequireAll honor the key's
+// scopes.
+func WithAPIKeys(v iam.TokenVerifier) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.APIKeys = v
+	}
+}
+
+// WithServiceAccounts resolves service-account callers with r: the tenant
+// comes from the token or the X-Tenant-ID header, and the roles from the
+// account's bindings in that tenant. Accounts without a binding in the
+// requested tenant are rejected with 403.
+func WithServiceAccounts(r *serviceaccount.Resolver) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.ServiceAccounts = r
+	}
+}
+
+// WithPrincipalTypes admits only the given principal types to path, e.g.
+// WithPrincipalTypes("/internal/*", iam.PrincipalServiceAccount). A
+// trailing "*" matches every path with that prefix. If several rules match
+// a path, the first one given applies. Other callers are rejected with 403.
+func WithPrincipalTypes(path string, allowed ...iam.PrincipalType) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Principals = append(cfg.Principals, core.PrincipalRule{Pattern: path, Types: allowed, Allow: true})
+	}
+}
+
+// WithDeniedPrincipalTypes rejects the given principal types from path with
+// 403, e.g. keeping API keys away from "/admin/*". Paths are matched as in
+// WithPrincipalTypes.
+func WithDeniedPrincipalTypes(path string, denied ...iam.PrincipalType) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Principals = append(cfg.Principals, core.PrincipalRule{Pattern: path, Types: denied})
+	}
+}
+
+// Auth returns middleware that verifies the request's bearer token via
+// client.Verifier() (or its API key, see WithAPIKeys). On success, it stores
+// the claims in the request context (retrievable via iam.UserIDFromContext,
+// etc.). Missing or invalid credentials are answered with 401.
+func Auth(client *iam.Client, opts ...AuthOption) func(http.Handler) http.Handler {
+	cfg := &authConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.excluded(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, err := cfg.Authenticate(r.Context(), client, core.Request{
+				Operation:     r.URL.Path,
+				Authorization: r.Header.Get("Authorization"),
+				APIKey:        r.Header.Get(apikey.HeaderName),
+				Tenant:        r.Header.Get(serviceaccount.TenantHeader),
+				Peer:          func(context.Context) core.Peer { return peer(r) },
+			})
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Session returns middleware that checks the token's session is still
+// active via client.Sessions(), enforcing the policies configured by opts.
+// Requires Auth to run first. Revoked, expired or non-compliant sessions
+// are answered with 401.
+func Session(client *iam.Client, opts ...session.ValidatorOption) func(http.Handler) http.Handler {
+	validator := core.NewSessionValidator(client, opts)
+	return guard(func(ctx context.Context) error {
+		return core.CheckSession(ctx, validator)
+	})
+}
+
+// Tenant returns middleware that validates tenant membership. Requires
+// Auth to run first. Service accounts have no memberships; their access to
+// the tenant comes from role bindings (see WithServiceAccounts) and is not
+// checked here. Non-members are answered with 403.
+func Tenant(client *iam.Client) func(http.Handler) http.Handler {
+	return guard(func(ctx context.Context) error {
+		return core.Tenant(ctx, client)
+	})
+}
+
+// Require returns middleware that checks a single permission. Requires
+// Auth to run first. API key scopes are enforced, and during impersonation
+// actor-scoped permissions are checked against the actor (see
+// impersonate.Check). Denied requests are answered with 403 and an
+// insufficient_scope challenge.
+func Require(client *iam.Client, permission string) func(http.Handler) http.Handler {
+	return RequireAll(client, permission)
+}
+
+// RequireAny returns middleware that checks if the user has any of the
+// given permissions.
+func RequireAny(client *iam.Client, permissions ...string) func(http.Handler) http.Handler {
+	return guard(func(ctx context.Context) error {
+		return core.RequireAny(ctx, client, permissions...)
+	})
+}
+
+// RequireAll returns middleware that checks if the user has all of the
+// given permissions.
+func RequireAll(client *iam.Client, permissions ...string) func(http.Handler) http.Handler {
+	return guard(func(ctx context.Context) error {
+		return core.RequireAll(ctx, client, permissions...)
+	})
+}
+
+// RequireACR returns middleware that requires the token's authentication
+// context class ("acr" claim) to be one of values, e.g. RequireACR("mfa").
+// Requires Auth to run first. Other requests are answered with 401 and an
+// RFC 9470 WWW-Authenticate challenge naming the acceptable values.
+func RequireACR(values ...string) func(http.Handler) http.Handler {
+	return guard(func(ctx context.Context) error {
+		return core.RequireACR(ctx, values...)
+	})
+}
+
+// RequireFreshAuth returns middleware that requires the user to have
+// authenticated ("auth_time" claim) within maxAge. Requires Auth to run
+// first. Fails like RequireACR, with a max_age challenge.
+func RequireFreshAuth(maxAge time.Duration) func(http.Handler) http.Handler {
+	return guard(func(ctx context.Context) error {
+		return core.RequireFreshAuth(ctx, maxAge)
+	})
+}
+
+// --- internal helpers ---
+
+// guard returns middleware that lets requests through if check passes.
+func guard(check func(ctx context.Context) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := check(r.Context()); err != nil {
+				writeError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (cfg *authConfig) excluded(path string) bool {
+	for _, pattern := range cfg.excludedPaths {
+		if core.MatchOperation(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError answers a failed check: 401 or 403 with an RFC 6750 (or, for
+// step-up, RFC 9470) WWW-Authenticate challenge, or 500, each with a problem
+// details body.
+func writeError(w http.ResponseWriter, err error) {
+	e := core.AsError(err)
+	p := &Problem{Detail: e.Message}
+	switch e.Kind {
+	case core.Unauthenticated:
+		p.Status = http.StatusUnauthorized
+	case core.PermissionDenied:
+		p.Status = http.StatusForbidden
+	default:
+		p.Status = http.StatusInternalServerError
+	}
+	if c := e.Challenge(); c != "" && p.Status != http.StatusInternalServerError {
+		w.Header().Set("WWW-Authenticate", c)
+	}
+	if e.StepUp != nil {
+		p.Extensions = make(map[string]any)
+		for k, v := range e.StepUp.Metadata() {
+			p.Extensions[k] = v
+		}
+	}
+	WriteProblem(w, p)
+}
+
+// peer describes the caller of r.
+func peer(r *http.Request) core.Peer {
+	return core.Peer{
+		RemoteAddr:   r.RemoteAddr,
+		ForwardedFor: r.Header.Values("X-Forwarded-For"),
+		RealIP:       r.Header.Get("X-Real-IP"),
+		UserAgent:    r.UserAgent(),
+	}
+}
@@ -0,0 +1,339 @@
+package httpmw
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
+	"github.com/chimerakang/iam-go/fake"
+	"github.com/chimerakang/iam-go/risk"
+)
+
+// serve runs r through mw and records the response. The handler behind mw
+// stores its request context in *captured.
+func serve(mw func(http.Handler) http.Handler, r *http.Request, captured *context.Context) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if captured != nil {
+			*captured = r.Context()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})).ServeHTTP(w, r)
+	return w
+}
+
+func request(path, token string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+// problem decodes the problem details body of w.
+func problem(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, ProblemContentType)
+	}
+	var p map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decoding problem: %v", err)
+	}
+	return p
+}
+
+func TestAuth_Success(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+	)
+	var captured context.Context
+
+	w := serve(Auth(client), request("/orders", "user123"), &captured)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if iam.UserIDFromContext(captured) != "user123" || iam.TenantIDFromContext(captured) != "tenant123" {
+		t.Errorf("user = %q, tenant = %q", iam.UserIDFromContext(captured), iam.TenantIDFromContext(captured))
+	}
+	if iam.AccessTokenFromContext(captured) != "user123" {
+		t.Errorf("access token not stored in context")
+	}
+}
+
+func TestAuth_MissingToken(t *testing.T) {
+	client := fake.NewClient()
+
+	w := serve(Auth(client), request("/orders", ""), nil)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	if got := w.Header().Get("WWW-Authenticate"); got != "Bearer" {
+		t.Errorf("WWW-Authenticate = %q, want a bare Bearer challenge", got)
+	}
+	p := problem(t, w)
+	if p["type"] != "about:blank" || p["title"] != "Unauthorized" || p["status"] != float64(401) || p["detail"] != "missing authorization token" {
+		t.Errorf("problem = %v", p)
+	}
+}
+
+func TestAuth_InvalidToken(t *testing.T) {
+	client := fake.NewClient()
+
+	w := serve(Auth(client), request("/orders", "nobody"), nil)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	want := `Bearer error="invalid_token", error_description="invalid token"`
+	if got := w.Header().Get("WWW-Authenticate"); got != want {
+		t.Errorf("WWW-Authenticate = %q, want %q", got, want)
+	}
+}
+
+func TestAuth_ExcludedPath(t *testing.T) {
+	client := fake.NewClient()
+	mw := Auth(client, WithExcludedPaths("/healthz", "/public/*"))
+
+	for _, path := range []string{"/healthz", "/public/logo.png"} {
+		if w := serve(mw, request(path, ""), nil); w.Code != http.StatusNoContent {
+			t.Errorf("%s: status = %d, want 204", path, w.Code)
+		}
+	}
+	if w := serve(mw, request("/orders", ""), nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("/orders: status = %d, want 401", w.Code)
+	}
+}
+
+func TestAuth_APIKey(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithPermissions("user123", []string{"orders:read", "orders:write"}),
+		fake.WithAPIKey("iam_ci_secret", "user123", "tenant123", []string{"orders:read"}),
+	)
+	mw := Auth(client, WithAPIKeys(apikey.NewVerifier(client.APIKeys())))
+	r := request("/orders", "")
+	r.Header.Set(apikey.HeaderName, "iam_ci_secret")
+	var captured context.Context
+
+	if w := serve(mw, r, &captured); w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if iam.PrincipalTypeFromContext(captured) != iam.PrincipalAPIKey {
+		t.Errorf("principal = %q, want api key", iam.PrincipalTypeFromContext(captured))
+	}
+
+	// The key's scopes cap its permissions
+	r = r.WithContext(captured)
+	if w := serve(Require(client, "orders:write"), r, nil); w.Code != http.StatusForbidden {
+		t.Errorf("orders:write: status = %d, want 403", w.Code)
+	}
+}
+
+func TestAuth_PrincipalTypes(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+	)
+	mw := Auth(client, WithPrincipalTypes("/internal/*", iam.PrincipalServiceAccount))
+
+	if w := serve(mw, request("/internal/jobs", "user123"), nil); w.Code != http.StatusForbidden {
+		t.Errorf("/internal/jobs: status = %d, want 403", w.Code)
+	}
+	if w := serve(mw, request("/orders", "user123"), nil); w.Code != http.StatusNoContent {
+		t.Errorf("/orders: status = %d, want 204", w.Code)
+	}
+}
+
+func TestAuth_RiskHook(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+	)
+	var got risk.Request
+	hook := risk.NewHook(risk.EvaluatorFunc(func(_ context.Context, req risk.Request) (risk.Assessment, error) {
+		got = req
+		return risk.Assessment{Score: 60, Action: risk.ActionStepUp}, nil
+	}))
+	r := request("/orders", "sess1")
+	r.Header.Set("User-Agent", "test-agent")
+	r.Header.Set("X-Forwarded-For", "203.0.113.10, 10.0.0.1")
+
+	proxies := []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24"), netip.MustParsePrefix("10.0.0.0/8")}
+
+	w := serve(Auth(client, WithRiskHook(hook), WithTrustedProxies(proxies...)), r, nil)
+
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Header().Get("WWW-Authenticate"), "insufficient_user_authentication") {
+		t.Errorf("status = %d, WWW-Authenticate = %q; want a step-up challenge", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+	if got.SessionID != "sess1" || got.Fingerprint.IP != "203.0.113.10" || got.Fingerprint.UserAgent != "test-agent" {
+		t.Errorf("unexpected risk request: %+v", got)
+	}
+}
+
+func TestAuth_RiskHookIgnoresUntrustedForwarding(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithSession("user123", "sess1"),
+	)
+	var got risk.Request
+	hook := risk.NewHook(risk.EvaluatorFunc(func(_ context.Context, req risk.Request) (risk.Assessment, error) {
+		got = req
+		return risk.Assessment{Action: risk.ActionAllow}, nil
+	}))
+	r := request("/orders", "sess1")
+	r.RemoteAddr = "198.51.100.7:51234"
+	r.Header.Set("X-Forwarded-For", "203.0.113.10")
+	r.Header.Set("X-Real-IP", "203.0.113.9")
+
+	serve(Auth(client, WithRiskHook(hook)), r, nil)
+
+	if got.Fingerprint.IP != "198.51.100.7" {
+		t.Errorf("IP = %q, want the remote address", got.Fingerprint.IP)
+	}
+}
+
+func TestSession_Revoked(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+	)
+	r := request("/", "").WithContext(fake.ContextWithSession(context.Background(), "user123", "sess1"))
+
+	if w := serve(Session(client), r, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204 for an active session", w.Code)
+	}
+	_ = client.Sessions().Revoke(context.Background(), "sess1")
+	if w := serve(Session(client), r, nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 for a revoked session", w.Code)
+	}
+}
+
+func TestTenant(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+	)
+	ctx := iam.WithUserID(context.Background(), "user123")
+
+	if w := serve(Tenant(client), request("/", "").WithContext(iam.WithTenantID(ctx, "tenant123")), nil); w.Code != http.StatusNoContent {
+		t.Errorf("member: status = %d, want 204", w.Code)
+	}
+	if w := serve(Tenant(client), request("/", "").WithContext(iam.WithTenantID(ctx, "other")), nil); w.Code != http.StatusForbidden {
+		t.Errorf("non-member: status = %d, want 403", w.Code)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithPermissions("user123", []string{"orders:read", "orders:write"}),
+	)
+	r := request("/", "").WithContext(iam.WithUserID(context.Background(), "user123"))
+
+	tests := []struct {
+		name string
+		mw   func(http.Handler) http.Handler
+		want int
+	}{
+		{"require granted", Require(client, "orders:read"), http.StatusNoContent},
+		{"require denied", Require(client, "orders:delete"), http.StatusForbidden},
+		{"any granted", RequireAny(client, "orders:delete", "orders:write"), http.StatusNoContent},
+		{"any denied", RequireAny(client, "orders:delete", "users:read"), http.StatusForbidden},
+		{"all granted", RequireAll(client, "orders:read", "orders:write"), http.StatusNoContent},
+		{"all denied", RequireAll(client, "orders:read", "orders:delete"), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(tt.mw, r, nil); w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequire_InsufficientScope(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+	)
+	r := request("/", "").WithContext(iam.WithUserID(context.Background(), "user123"))
+
+	w := serve(RequireAll(client, "orders:read", "orders:write"), r, nil)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", w.Code)
+	}
+	if got := w.Header().Get("WWW-Authenticate"); !strings.HasPrefix(got, `Bearer error="insufficient_scope"`) ||
+		!strings.Contains(got, `scope="orders:read orders:write"`) {
+		t.Errorf("WWW-Authenticate = %q", got)
+	}
+	if p := problem(t, w); p["status"] != float64(403) || p["title"] != "Forbidden" {
+		t.Errorf("problem = %v", p)
+	}
+}
+
+func TestRequireACR(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("pwd-user", "tenant123", "a@example.com", nil),
+		fake.WithUser("mfa-user", "tenant123", "b@example.com", nil),
+		fake.WithAuthContext("mfa-user", "mfa", []string{"pwd", "otp"}, time.Now()),
+	)
+	mw := func(next http.Handler) http.Handler { return Auth(client)(RequireACR("mfa")(next)) }
+
+	if w := serve(mw, request("/payouts", "mfa-user"), nil); w.Code != http.StatusNoContent {
+		t.Fatalf("mfa token: status = %d, want 204", w.Code)
+	}
+
+	w := serve(mw, request("/payouts", "pwd-user"), nil)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	want := `Bearer error="insufficient_user_authentication", error_description="A different authentication level is required", acr_values="mfa"`
+	if got := w.Header().Get("WWW-Authenticate"); got != want {
+		t.Errorf("WWW-Authenticate = %q, want %q", got, want)
+	}
+	if p := problem(t, w); p["acr_values"] != "mfa" {
+		t.Errorf("problem = %v, want acr_values extension", p)
+	}
+}
+
+func TestRequireFreshAuth(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("stale", "tenant123", "b@example.com", nil),
+		fake.WithAuthContext("stale", "mfa", nil, time.Now().Add(-time.Hour)),
+	)
+	mw := func(next http.Handler) http.Handler { return Auth(client)(RequireFreshAuth(10 * time.Minute)(next)) }
+
+	w := serve(mw, request("/", "stale"), nil)
+
+	if got := w.Header().Get("WWW-Authenticate"); w.Code != http.StatusUnauthorized || !strings.Contains(got, `max_age="600"`) {
+		t.Errorf("status = %d, WWW-Authenticate = %q", w.Code, got)
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteProblem(w, &Problem{
+		Type:       "https://example.com/problems/out-of-credit",
+		Status:     http.StatusPaymentRequired,
+		Detail:     "Your balance is 30, but that costs 50.",
+		Instance:   "/account/12345/msgs/abc",
+		Extensions: map[string]any{"balance": 30, "status": "ignored"},
+	})
+
+	if w.Code != http.StatusPaymentRequired {
+		t.Fatalf("status = %d, want 402", w.Code)
+	}
+	p := problem(t, w)
+	if p["title"] != "Payment Required" || p["status"] != float64(402) || p["balance"] != float64(30) ||
+		p["instance"] != "/account/12345/msgs/abc" || p["type"] != "https://example.com/problems/out-of-credit" {
+		t.Errorf("problem = %v", p)
+	}
+}
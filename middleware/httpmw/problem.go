@@ -0,0 +1,62 @@
+package httpmw
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemContentType is the media type of problem details responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details object. Type defaults to
+// "about:blank" and Title to the status text, as the RFC recommends for
+// problems without a more specific type.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+
+	// Extensions are additional members, e.g. "acr_values" for step-up
+	// authentication failures.
+	Extensions map[string]any
+}
+
+// MarshalJSON encodes p with its extension members inlined.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	if m["type"] == "" {
+		m["type"] = "about:blank"
+	}
+	m["title"] = p.Title
+	if p.Title == "" {
+		m["title"] = http.StatusText(p.Status)
+	}
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// WriteProblem writes p as the response, with p.Status as status code, so
+// that handlers can answer errors in the same format as the middleware.
+func WriteProblem(w http.ResponseWriter, p *Problem) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(append(body, '\n'))
+}
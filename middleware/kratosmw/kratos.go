@@ -7,18 +7,32 @@ package kratosmw
 
 import (
 	"context"
-	"strings"
+	"net/netip"
+	"time"
 
 	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/internal/core"
+	"github.com/chimerakang/iam-go/risk"
+	"github.com/chimerakang/iam-go/serviceaccount"
+	"github.com/chimerakang/iam-go/session"
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/transport"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/grpc/peer"
 )
 
+// ReasonStepUpRequired is the error reason returned when the caller must
+// re-authenticate, more strongly or more recently, before retrying.
+const ReasonStepUpRequired = "STEP_UP_REQUIRED"
+
 // AuthOption configures Auth middleware behavior.
 type AuthOption func(*authConfig)
 
 type authConfig struct {
+	core.Config
 	excludedOperations map[string]bool
 }
 
@@ -32,6 +46,76 @@ func WithExcludedOperations(ops ...string) AuthOption {
 	}
 }
 
+// WithRiskHook evaluates every authenticated request with h, comparing the
+// caller's IP and User-Agent with the session's recorded fingerprint. See
+// WithTrustedProxies for services behind a reverse proxy. Requests the hook
+// rejects fail with errors.Unauthorized; reason ReasonStepUpRequired asks
+// the client to re-authenticate.
+func WithRiskHook(h *risk.Hook) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.RiskHook = h
+	}
+}
+
+// WithTrustedProxies names the reverse proxies in front of the service. The
+// risk hook sees the connection's remote address as the client address
+// unless it is one of proxies; only then are X-Forwarded-For and X-Real-IP
+// read, taking the rightmost X-Forwarded-For entry that is not a trusted
+// proxy.
+func WithTrustedProxies(proxies ...netip.Prefix) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.TrustedProxies = append(cfg.TrustedProxies, proxies...)
+	}
+}
+
+// WithImpersonation accepts impersonation tokens (tokens with an RFC 8693
+// "act" claim) whose actor is allowed by p. Without it such tokens are
+// rejected with errors.Forbidden.
+func WithImpersonation(p *impersonate.Policy) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Impersonation = p
+	}
+}
+
+// WithAPIKeys also accepts API keys, sent as "X-API-Key: <key>" or
+// "Authorization: ApiKey <key>" and verified by v (typically an
+// apikey.Verifier). Require and RequireAny honor the key's scopes.
+func WithAPIKeys(v iam.TokenVerifier) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.APIKeys = v
+	}
+}
+
+// WithServiceAccounts resolves service-account callers with r: the tenant
+// comes from the token or the X-Tenant-ID header, and the roles from the
+// account's bindings in that tenant. Accounts without a binding in the
+// requested tenant are rejected with errors.Forbidden.
+func WithServiceAccounts(r *serviceaccount.Resolver) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.ServiceAccounts = r
+	}
+}
+
+// WithPrincipalTypes admits only the given principal types to operation,
+// e.g. WithPrincipalTypes("/internal.v1.Sync/*", iam.PrincipalServiceAccount).
+// A trailing "*" matches every operation with that prefix. If several rules
+// match an operation, the first one given applies. Other callers are
+// rejected with errors.Forbidden.
+func WithPrincipalTypes(operation string, allowed ...iam.PrincipalType) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Principals = append(cfg.Principals, core.PrincipalRule{Pattern: operation, Types: allowed, Allow: true})
+	}
+}
+
+// WithDeniedPrincipalTypes rejects the given principal types from operation
+// with errors.Forbidden, e.g. keeping API keys away from "/admin.v1.*".
+// Operations are matched as in WithPrincipalTypes.
+func WithDeniedPrincipalTypes(operation string, denied ...iam.PrincipalType) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.Principals = append(cfg.Principals, core.PrincipalRule{Pattern: operation, Types: denied})
+	}
+}
+
 // Auth returns Kratos middleware that verifies JWT tokens via client.Verifier().
 // On success, it stores claims in the context (retrievable via iam.UserIDFromContext, etc.).
 // Returns kratos errors.Unauthorized if the token is missing or invalid.
@@ -52,26 +136,29 @@ func Auth(client *iam.Client, opts ...AuthOption) middleware.Middleware {
 				return handler(ctx, req)
 			}
 
-			tokenStr := extractBearerToken(tr.RequestHeader().Get("Authorization"))
-			if tokenStr == "" {
-				return nil, errors.Unauthorized("UNAUTHORIZED", "missing authorization token")
-			}
-
-			verifier := client.Verifier()
-			if verifier == nil {
-				return nil, errors.InternalServer("INTERNAL", "token verifier not configured")
-			}
-
-			claims, err := verifier.Verify(ctx, tokenStr)
+			ctx, err := cfg.Authenticate(ctx, client, request(tr))
 			if err != nil {
-				return nil, errors.Unauthorized("UNAUTHORIZED", "invalid token")
+				return nil, toError(ctx, err)
 			}
 
-			ctx = iam.WithClaims(ctx, claims)
-			ctx = iam.WithUserID(ctx, claims.Subject)
-			ctx = iam.WithTenantID(ctx, claims.TenantID)
-			ctx = iam.WithRoles(ctx, claims.Roles)
+			return handler(ctx, req)
+		}
+	}
+}
+
+// Session returns Kratos middleware that checks the token's session is still
+// active via client.Sessions(), enforcing the policies configured by opts.
+// Requires Auth middleware to run first (uses the session ID from context).
+// Returns kratos errors.Unauthorized if the session was revoked, expired or
+// violates a policy.
+func Session(client *iam.Client, opts ...session.ValidatorOption) middleware.Middleware {
+	validator := core.NewSessionValidator(client, opts)
 
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := core.CheckSession(ctx, validator); err != nil {
+				return nil, toError(ctx, err)
+			}
 			return handler(ctx, req)
 		}
 	}
@@ -79,53 +166,61 @@ func Auth(client *iam.Client, opts ...AuthOption) middleware.Middleware {
 
 // Tenant returns Kratos middleware that validates tenant membership.
 // Requires Auth middleware to run first (uses claims from context).
+// Service accounts have no memberships; their access to the tenant comes
+// from role bindings (see WithServiceAccounts) and is not checked here.
 // Returns kratos errors.Forbidden if the user does not belong to the tenant.
 func Tenant(client *iam.Client) middleware.Middleware {
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (interface{}, error) {
-			svc := client.Tenants()
-			if svc == nil {
-				return handler(ctx, req)
-			}
-
-			userID := iam.UserIDFromContext(ctx)
-			tenantID := iam.TenantIDFromContext(ctx)
-			if userID == "" || tenantID == "" {
-				return nil, errors.Unauthorized("UNAUTHORIZED", "missing user or tenant context")
+			if err := core.Tenant(ctx, client); err != nil {
+				return nil, toError(ctx, err)
 			}
-
-			ok, err := svc.ValidateMembership(ctx, userID, tenantID)
-			if err != nil {
-				return nil, errors.InternalServer("INTERNAL", "tenant validation failed")
-			}
-			if !ok {
-				return nil, errors.Forbidden("FORBIDDEN", "not a member of this tenant")
-			}
-
 			return handler(ctx, req)
 		}
 	}
 }
 
 // Require returns Kratos middleware that checks a single permission.
-// Requires Auth middleware to run first (uses user context).
+// Requires Auth middleware to run first (uses user context). API key scopes
+// are enforced, and during impersonation actor-scoped permissions are
+// checked against the actor (see impersonate.Check).
 // Returns kratos errors.Forbidden if the permission is denied.
 func Require(client *iam.Client, permission string) middleware.Middleware {
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (interface{}, error) {
-			authz := client.Authz()
-			if authz == nil {
-				return nil, errors.InternalServer("INTERNAL", "authorizer not configured")
+			if err := core.RequireAll(ctx, client, permission); err != nil {
+				return nil, toError(ctx, err)
 			}
+			return handler(ctx, req)
+		}
+	}
+}
 
-			ok, err := authz.Check(ctx, permission)
-			if err != nil {
-				return nil, errors.InternalServer("INTERNAL", "authorization check failed")
-			}
-			if !ok {
-				return nil, errors.Forbidden("FORBIDDEN", "permission denied")
+// RequireACR returns Kratos middleware that requires the token's
+// authentication context class ("acr" claim) to be one of values, e.g.
+// RequireACR("mfa"). Requires Auth middleware to run first.
+// Returns errors.Unauthorized with reason ReasonStepUpRequired and, over HTTP,
+// an RFC 9470 WWW-Authenticate challenge naming the acceptable values.
+func RequireACR(values ...string) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := core.RequireACR(ctx, values...); err != nil {
+				return nil, toError(ctx, err)
 			}
+			return handler(ctx, req)
+		}
+	}
+}
 
+// RequireFreshAuth returns Kratos middleware that requires the user to have
+// authenticated ("auth_time" claim) within maxAge. Requires Auth middleware
+// to run first. Fails like RequireACR, with a max_age challenge.
+func RequireFreshAuth(maxAge time.Duration) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := core.RequireFreshAuth(ctx, maxAge); err != nil {
+				return nil, toError(ctx, err)
+			}
 			return handler(ctx, req)
 		}
 	}
@@ -135,43 +230,115 @@ func Require(client *iam.Client, permission string) middleware.Middleware {
 func RequireAny(client *iam.Client, permissions ...string) middleware.Middleware {
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (interface{}, error) {
-			authz := client.Authz()
-			if authz == nil {
-				return nil, errors.InternalServer("INTERNAL", "authorizer not configured")
+			if err := core.RequireAny(ctx, client, permissions...); err != nil {
+				return nil, toError(ctx, err)
 			}
+			return handler(ctx, req)
+		}
+	}
+}
 
-			for _, perm := range permissions {
-				ok, err := authz.Check(ctx, perm)
-				if err != nil {
-					return nil, errors.InternalServer("INTERNAL", "authorization check failed")
-				}
-				if ok {
-					return handler(ctx, req)
-				}
+// RequireAll returns Kratos middleware that checks if the user has all of the given permissions.
+func RequireAll(client *iam.Client, permissions ...string) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := core.RequireAll(ctx, client, permissions...); err != nil {
+				return nil, toError(ctx, err)
 			}
-
-			return nil, errors.Forbidden("FORBIDDEN", "permission denied")
+			return handler(ctx, req)
 		}
 	}
 }
 
+// ClientOption configures OAuth2ClientCredentials.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	defaultRequest *iam.TokenRequest
+	targets        []tokenTarget
+}
+
+// tokenTarget selects the token request for matching operations.
+type tokenTarget struct {
+	pattern string
+	req     iam.TokenRequest
+}
+
+// WithTokenRequest requests tokens for req (scopes, audience, resource
+// indicators) on calls no WithTargetToken rule matches. Without it those
+// calls use the exchanger's default token.
+func WithTokenRequest(req iam.TokenRequest) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.defaultRequest = &req
+	}
+}
+
+// WithTargetToken requests tokens for req on calls to operation, e.g.
+// WithTargetToken("/billing.v1.Billing/*", iam.TokenRequest{Audience: "billing"}).
+// Operations are matched as in WithPrincipalTypes.
+func WithTargetToken(operation string, req iam.TokenRequest) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.targets = append(cfg.targets, tokenTarget{pattern: operation, req: req})
+	}
+}
+
 // OAuth2ClientCredentials returns Kratos client-side middleware that injects
 // an OAuth2 Bearer token into outgoing requests using client credentials.
-// The token is automatically cached and refreshed before expiry.
-func OAuth2ClientCredentials(client *iam.Client) middleware.Middleware {
+// The token is automatically cached and refreshed before expiry. With
+// WithTokenRequest or WithTargetToken the token is chosen per target, which
+// requires an exchanger implementing iam.TokenProvider.
+func OAuth2ClientCredentials(client *iam.Client, opts ...ClientOption) middleware.Middleware {
+	cfg := &clientConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (interface{}, error) {
-			exchanger := client.OAuth2()
-			if exchanger == nil {
-				return nil, errors.InternalServer("INTERNAL", "oauth2 exchanger not configured")
+			tr, ok := transport.FromClientContext(ctx)
+			var operation string
+			if ok {
+				operation = tr.Operation()
 			}
 
-			token, err := exchanger.GetCachedToken(ctx)
+			token, err := core.ClientToken(ctx, client, cfg.tokenRequest(operation))
 			if err != nil {
-				return nil, errors.Unauthorized("UNAUTHORIZED", "failed to obtain oauth2 token")
+				return nil, toError(ctx, err)
 			}
+			if ok {
+				tr.RequestHeader().Set("Authorization", "Bearer "+token)
+			}
+
+			return handler(ctx, req)
+		}
+	}
+}
+
+// OAuth2OnBehalfOf returns Kratos client-side middleware that calls
+// downstream services on behalf of the current caller: it exchanges the
+// caller's bearer token, stored by Auth, for a token restricted to the
+// target chosen by WithTokenRequest or WithTargetToken (RFC 8693). The
+// exchanger must implement iam.OnBehalfOfExchanger.
+func OAuth2OnBehalfOf(client *iam.Client, opts ...ClientOption) middleware.Middleware {
+	cfg := &clientConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
 
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			var tokenReq iam.TokenRequest
 			tr, ok := transport.FromClientContext(ctx)
+			if ok {
+				if r := cfg.tokenRequest(tr.Operation()); r != nil {
+					tokenReq = *r
+				}
+			}
+
+			token, err := core.OnBehalfOfToken(ctx, client, tokenReq)
+			if err != nil {
+				return nil, toError(ctx, err)
+			}
 			if ok {
 				tr.RequestHeader().Set("Authorization", "Bearer "+token)
 			}
@@ -183,13 +350,65 @@ func OAuth2ClientCredentials(client *iam.Client) middleware.Middleware {
 
 // --- internal helpers ---
 
-func extractBearerToken(auth string) string {
-	if auth == "" {
-		return ""
+// request describes the call on tr for the authentication pipeline.
+func request(tr transport.Transporter) core.Request {
+	h := tr.RequestHeader()
+	return core.Request{
+		Operation:     tr.Operation(),
+		Authorization: h.Get("Authorization"),
+		APIKey:        h.Get(apikey.HeaderName),
+		Tenant:        h.Get(serviceaccount.TenantHeader),
+		Peer:          func(ctx context.Context) core.Peer { return callerPeer(ctx, tr) },
+	}
+}
+
+// tokenRequest returns the token request for operation, or nil for the
+// exchanger's default token.
+func (cfg *clientConfig) tokenRequest(operation string) *iam.TokenRequest {
+	for i, target := range cfg.targets {
+		if core.MatchOperation(target.pattern, operation) {
+			return &cfg.targets[i].req
+		}
+	}
+	return cfg.defaultRequest
+}
+
+// toError converts a pipeline error into a Kratos error.
+func toError(ctx context.Context, err error) error {
+	e := core.AsError(err)
+	switch {
+	case e.StepUp != nil:
+		return stepUpError(ctx, e.StepUp)
+	case e.Kind == core.Unauthenticated:
+		return errors.Unauthorized("UNAUTHORIZED", e.Message)
+	case e.Kind == core.PermissionDenied:
+		return errors.Forbidden("FORBIDDEN", e.Message)
+	}
+	return errors.InternalServer("INTERNAL", e.Message)
+}
+
+// stepUpError converts e into an Unauthorized error with reason
+// ReasonStepUpRequired and e's requirements as metadata. HTTP responses also
+// get the RFC 9470 WWW-Authenticate challenge.
+func stepUpError(ctx context.Context, e *iam.StepUpError) error {
+	if tr, ok := transport.FromServerContext(ctx); ok && tr.Kind() == transport.KindHTTP {
+		tr.ReplyHeader().Set("WWW-Authenticate", e.Challenge())
+	}
+	return errors.Unauthorized(ReasonStepUpRequired, "step-up authentication required").WithMetadata(e.Metadata())
+}
+
+// callerPeer describes the caller of the current request.
+func callerPeer(ctx context.Context, tr transport.Transporter) core.Peer {
+	h := tr.RequestHeader()
+	p := core.Peer{
+		ForwardedFor: h.Values("X-Forwarded-For"),
+		RealIP:       h.Get("X-Real-IP"),
+		UserAgent:    h.Get("User-Agent"),
 	}
-	parts := strings.SplitN(auth, " ", 2)
-	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-		return ""
+	if ht, ok := tr.(khttp.Transporter); ok {
+		p.RemoteAddr = ht.Request().RemoteAddr
+	} else if gp, ok := peer.FromContext(ctx); ok {
+		p.RemoteAddr = gp.Addr.String()
 	}
-	return parts[1]
+	return p
 }
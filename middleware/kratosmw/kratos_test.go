@@ -2,19 +2,28 @@ package kratosmw
 
 import (
 	"context"
+	"net"
+	"net/netip"
 	"strings"
 	"testing"
+	"time"
 
 	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
 	"github.com/chimerakang/iam-go/fake"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/risk"
+	"github.com/chimerakang/iam-go/serviceaccount"
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/transport"
+	"google.golang.org/grpc/peer"
 )
 
 // mockTransport implements transport.Transporter
 type mockTransport struct {
 	headers map[string]string
+	reply   map[string]string // reply headers; discarded if nil
 	op      string
 }
 
@@ -22,7 +31,12 @@ func (m *mockTransport) Kind() transport.Kind              { return transport.Ki
 func (m *mockTransport) Endpoint() string                 { return "mock://test" }
 func (m *mockTransport) Operation() string                { return m.op }
 func (m *mockTransport) RequestHeader() transport.Header  { return &mockHeader{headers: m.headers} }
-func (m *mockTransport) ReplyHeader() transport.Header    { return &mockHeader{headers: make(map[string]string)} }
+func (m *mockTransport) ReplyHeader() transport.Header    {
+	if m.reply == nil {
+		return &mockHeader{headers: make(map[string]string)}
+	}
+	return &mockHeader{headers: m.reply}
+}
 
 type mockHeader struct {
 	headers map[string]string
@@ -89,6 +103,323 @@ func TestAuth_Success(t *testing.T) {
 	if tenantID := iam.TenantIDFromContext(capturedCtx); tenantID != "tenant123" {
 		t.Errorf("expected tenantID tenant123, got %s", tenantID)
 	}
+	if token := iam.AccessTokenFromContext(capturedCtx); token != "user123" {
+		t.Errorf("expected access token user123, got %s", token)
+	}
+}
+
+func TestAuth_SessionID(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+	)
+
+	var capturedCtx context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		capturedCtx = ctx
+		return "ok", nil
+	}
+
+	// Fake verifier accepts session IDs as session-bound tokens
+	tr := &mockTransport{
+		headers: map[string]string{"Authorization": "Bearer sess1"},
+		op:      "/test/operation",
+	}
+	ctx := mockServerContext(context.Background(), tr)
+
+	if _, err := Auth(client)(handler)(ctx, nil); err != nil {
+		t.Fatalf("middleware returned error: %v", err)
+	}
+	if sid := iam.SessionIDFromContext(capturedCtx); sid != "sess1" {
+		t.Errorf("expected session ID sess1, got %s", sid)
+	}
+}
+
+func TestSession_Valid(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	ctx := fake.ContextWithSession(context.Background(), "user123", "sess1")
+
+	if _, err := Session(client)(handler)(ctx, nil); err != nil {
+		t.Fatalf("middleware returned error: %v", err)
+	}
+}
+
+func TestSession_Revoked(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+	)
+	_ = client.Sessions().Revoke(context.Background(), "sess1")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	ctx := fake.ContextWithSession(context.Background(), "user123", "sess1")
+
+	_, err := Session(client)(handler)(ctx, nil)
+
+	if !errors.IsUnauthorized(err) {
+		t.Errorf("expected Unauthorized for revoked session, got %v", err)
+	}
+}
+
+func TestAuth_RiskHook(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithSession("user123", "sess1"),
+	)
+	var got risk.Request
+	hook := risk.NewHook(risk.EvaluatorFunc(func(_ context.Context, req risk.Request) (risk.Assessment, error) {
+		got = req
+		return risk.Assessment{Score: 60, Action: risk.ActionStepUp}, nil
+	}))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	tr := &mockTransport{
+		headers: map[string]string{
+			"Authorization":   "Bearer sess1",
+			"User-Agent":      "test-agent",
+			"X-Forwarded-For": "203.0.113.10, 10.0.0.1",
+		},
+		op: "/test/operation",
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443}})
+	mw := Auth(client, WithRiskHook(hook), WithTrustedProxies(netip.MustParsePrefix("10.0.0.0/8")))
+
+	_, err := mw(handler)(mockServerContext(ctx, tr), nil)
+
+	if !errors.IsUnauthorized(err) || errors.Reason(err) != "STEP_UP_REQUIRED" {
+		t.Errorf("expected Unauthorized STEP_UP_REQUIRED, got %v", err)
+	}
+	if got.SessionID != "sess1" || got.Fingerprint.IP != "203.0.113.10" || got.Fingerprint.UserAgent != "test-agent" {
+		t.Errorf("unexpected risk request: %+v", got)
+	}
+}
+
+func TestAuth_Impersonation(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("support", "tenant123", "support@example.com", nil),
+		fake.WithUser("customer", "tenant123", "customer@example.com", nil),
+		fake.WithImpersonation("imp-token", "support", "customer"),
+		fake.WithPermissions("support", []string{impersonate.DefaultPermission}),
+		fake.WithPermissions("customer", []string{"orders:read", "billing:refund"}),
+	)
+	policy := impersonate.NewPolicy(client.Authz(), impersonate.WithActorPermissions("billing:*"))
+	tr := &mockTransport{headers: map[string]string{"Authorization": "Bearer imp-token"}, op: "/test/operation"}
+	ctx := mockServerContext(context.Background(), tr)
+	var captured context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = ctx
+		return "ok", nil
+	}
+
+	// Rejected unless impersonation is enabled
+	if _, err := Auth(client)(handler)(ctx, nil); !errors.IsForbidden(err) {
+		t.Fatalf("expected Forbidden without WithImpersonation, got %v", err)
+	}
+
+	if _, err := Auth(client, WithImpersonation(policy))(handler)(ctx, nil); err != nil {
+		t.Fatalf("expected impersonation to be admitted, got %v", err)
+	}
+	if iam.UserIDFromContext(captured) != "customer" || iam.ActorIDFromContext(captured) != "support" {
+		t.Errorf("user = %q, actor = %q", iam.UserIDFromContext(captured), iam.ActorIDFromContext(captured))
+	}
+
+	// Actor-scoped permissions are checked against the support user
+	if _, err := Require(client, "orders:read")(handler)(captured, nil); err != nil {
+		t.Errorf("orders:read: expected success, got %v", err)
+	}
+	if _, err := Require(client, "billing:refund")(handler)(captured, nil); !errors.IsForbidden(err) {
+		t.Errorf("billing:refund: expected Forbidden, got %v", err)
+	}
+}
+
+func TestAuth_ImpersonationDenied(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("intern", "tenant123", "intern@example.com", nil),
+		fake.WithUser("customer", "tenant123", "customer@example.com", nil),
+		fake.WithImpersonation("imp-token", "intern", "customer"),
+	)
+	tr := &mockTransport{headers: map[string]string{"Authorization": "Bearer imp-token"}, op: "/test/operation"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	_, err := Auth(client, WithImpersonation(impersonate.NewPolicy(client.Authz())))(handler)(mockServerContext(context.Background(), tr), nil)
+
+	if !errors.IsForbidden(err) {
+		t.Errorf("expected Forbidden, got %v", err)
+	}
+}
+
+func TestRequireACR(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("pwd-user", "tenant123", "a@example.com", nil),
+		fake.WithUser("mfa-user", "tenant123", "b@example.com", nil),
+		fake.WithAuthContext("mfa-user", "mfa", []string{"pwd", "otp"}, time.Now()),
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	call := func(token string) (*mockTransport, error) {
+		tr := &mockTransport{
+			headers: map[string]string{"Authorization": "Bearer " + token},
+			reply:   make(map[string]string),
+			op:      "/payouts.v1.Payouts/UpdateBankAccount",
+		}
+		_, err := Auth(client)(RequireACR("mfa")(handler))(mockServerContext(context.Background(), tr), nil)
+		return tr, err
+	}
+
+	if _, err := call("mfa-user"); err != nil {
+		t.Fatalf("expected success for mfa token, got %v", err)
+	}
+
+	tr, err := call("pwd-user")
+	if !errors.IsUnauthorized(err) || errors.Reason(err) != ReasonStepUpRequired {
+		t.Fatalf("expected Unauthorized %s, got %v", ReasonStepUpRequired, err)
+	}
+	if md := errors.FromError(err).Metadata; md["acr_values"] != "mfa" {
+		t.Errorf("unexpected metadata: %v", md)
+	}
+	want := `Bearer error="insufficient_user_authentication", error_description="A different authentication level is required", acr_values="mfa"`
+	if got := tr.reply["WWW-Authenticate"]; got != want {
+		t.Errorf("WWW-Authenticate = %q, want %q", got, want)
+	}
+}
+
+func TestRequireFreshAuth(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("fresh", "tenant123", "a@example.com", nil),
+		fake.WithUser("stale", "tenant123", "b@example.com", nil),
+		fake.WithAuthContext("stale", "mfa", nil, time.Now().Add(-time.Hour)),
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	call := func(token string) (*mockTransport, error) {
+		tr := &mockTransport{headers: map[string]string{"Authorization": "Bearer " + token}, reply: make(map[string]string)}
+		_, err := Auth(client)(RequireFreshAuth(10*time.Minute)(handler))(mockServerContext(context.Background(), tr), nil)
+		return tr, err
+	}
+
+	if _, err := call("fresh"); err != nil {
+		t.Fatalf("expected success for fresh token, got %v", err)
+	}
+	tr, err := call("stale")
+	if errors.Reason(err) != ReasonStepUpRequired || errors.FromError(err).Metadata["max_age"] != "600" {
+		t.Fatalf("expected %s with max_age 600, got %v", ReasonStepUpRequired, err)
+	}
+	if got := tr.reply["WWW-Authenticate"]; !strings.Contains(got, `max_age="600"`) {
+		t.Errorf("WWW-Authenticate = %q", got)
+	}
+}
+
+func TestAuth_APIKey(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithPermissions("user123", []string{"orders:read", "orders:write"}),
+		fake.WithAPIKey("iam_ci_secret", "user123", "tenant123", []string{"orders:read"}),
+	)
+	mw := Auth(client, WithAPIKeys(apikey.NewVerifier(client.APIKeys())))
+	var captured context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = ctx
+		return "ok", nil
+	}
+
+	for _, headers := range []map[string]string{
+		{"X-API-Key": "iam_ci_secret"},
+		{"Authorization": "ApiKey iam_ci_secret"},
+	} {
+		tr := &mockTransport{headers: headers, op: "/test/operation"}
+		if _, err := mw(handler)(mockServerContext(context.Background(), tr), nil); err != nil {
+			t.Fatalf("%v: expected success, got %v", headers, err)
+		}
+		if iam.UserIDFromContext(captured) != "user123" || iam.TenantIDFromContext(captured) != "tenant123" {
+			t.Errorf("unexpected context for %v", headers)
+		}
+	}
+
+	// Scopes limit the user's permissions
+	if _, err := Require(client, "orders:read")(handler)(captured, nil); err != nil {
+		t.Errorf("orders:read: expected success, got %v", err)
+	}
+	if _, err := Require(client, "orders:write")(handler)(captured, nil); !errors.IsForbidden(err) {
+		t.Errorf("orders:write: expected Forbidden, got %v", err)
+	}
+
+	tr := &mockTransport{headers: map[string]string{"X-API-Key": "iam_ci_wrong"}, op: "/test/operation"}
+	if _, err := mw(handler)(mockServerContext(context.Background(), tr), nil); !errors.IsUnauthorized(err) {
+		t.Errorf("wrong key: expected Unauthorized, got %v", err)
+	}
+}
+
+func TestAuth_ServiceAccount(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithTenant("t1", "acme", "active"),
+		fake.WithServiceAccount("sa-sync", "sync"),
+		fake.WithServiceAccountRole("sa-sync", "t1", "reader"),
+	)
+	mw := Auth(client, WithServiceAccounts(serviceaccount.NewResolver(client.ServiceAccounts())))
+	var captured context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = ctx
+		return "ok", nil
+	}
+
+	tr := &mockTransport{headers: map[string]string{"Authorization": "Bearer sa-sync", "X-Tenant-ID": "t1"}, op: "/test/operation"}
+	if _, err := mw(handler)(mockServerContext(context.Background(), tr), nil); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if iam.TenantIDFromContext(captured) != "t1" || strings.Join(iam.RolesFromContext(captured), ",") != "reader" {
+		t.Errorf("unexpected context: tenant %q, roles %v", iam.TenantIDFromContext(captured), iam.RolesFromContext(captured))
+	}
+	if iam.PrincipalTypeFromContext(captured) != iam.PrincipalServiceAccount {
+		t.Errorf("PrincipalTypeFromContext = %q", iam.PrincipalTypeFromContext(captured))
+	}
+
+	// Bindings replace tenant membership
+	if _, err := Tenant(client)(handler)(captured, nil); err != nil {
+		t.Errorf("Tenant: expected success, got %v", err)
+	}
+
+	tr = &mockTransport{headers: map[string]string{"Authorization": "Bearer sa-sync", "X-Tenant-ID": "t2"}, op: "/test/operation"}
+	if _, err := mw(handler)(mockServerContext(context.Background(), tr), nil); !errors.IsForbidden(err) {
+		t.Errorf("unbound tenant: expected Forbidden, got %v", err)
+	}
+}
+
+func TestAuth_PrincipalTypes(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", nil),
+		fake.WithAPIKey("iam_ci_secret", "user123", "tenant123", nil),
+		fake.WithServiceAccount("sa-sync", "sync"),
+	)
+	mw := Auth(client,
+		WithAPIKeys(apikey.NewVerifier(client.APIKeys())),
+		WithPrincipalTypes("/internal.v1.Sync/*", iam.PrincipalServiceAccount),
+		WithDeniedPrincipalTypes("/admin.v1.Users/Delete", iam.PrincipalAPIKey),
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	tests := []struct {
+		op      string
+		headers map[string]string
+		allowed bool
+	}{
+		{"/internal.v1.Sync/Pull", map[string]string{"Authorization": "Bearer sa-sync"}, true},
+		{"/internal.v1.Sync/Pull", map[string]string{"Authorization": "Bearer user123"}, false},
+		{"/internal.v1.Sync/Pull", map[string]string{"X-API-Key": "iam_ci_secret"}, false},
+		{"/admin.v1.Users/Delete", map[string]string{"Authorization": "Bearer user123"}, true},
+		{"/admin.v1.Users/Delete", map[string]string{"X-API-Key": "iam_ci_secret"}, false},
+		{"/public.v1.Orders/List", map[string]string{"Authorization": "Bearer sa-sync"}, true},
+	}
+	for _, tt := range tests {
+		tr := &mockTransport{headers: tt.headers, op: tt.op}
+		_, err := mw(handler)(mockServerContext(context.Background(), tr), nil)
+		if tt.allowed && err != nil {
+			t.Errorf("%s %v: expected success, got %v", tt.op, tt.headers, err)
+		}
+		if !tt.allowed && !errors.IsForbidden(err) {
+			t.Errorf("%s %v: expected Forbidden, got %v", tt.op, tt.headers, err)
+		}
+	}
 }
 
 func TestAuth_MissingToken(t *testing.T) {
@@ -331,3 +662,104 @@ func TestOAuth2ClientCredentials_NoExchanger(t *testing.T) {
 		t.Fatal("expected error when oauth2 exchanger not configured")
 	}
 }
+
+// recordingProvider issues "token-<audience>" and records the requests.
+type recordingProvider struct {
+	requests []iam.TokenRequest
+}
+
+func (p *recordingProvider) ExchangeToken(_ context.Context, _ []string) (*iam.OAuth2Token, error) {
+	return &iam.OAuth2Token{AccessToken: "default-token"}, nil
+}
+
+func (p *recordingProvider) GetCachedToken(_ context.Context) (string, error) {
+	return "default-token", nil
+}
+
+func (p *recordingProvider) GetToken(_ context.Context, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	p.requests = append(p.requests, req)
+	return &iam.OAuth2Token{AccessToken: "token-" + req.Audience}, nil
+}
+
+func TestOAuth2ClientCredentials_PerTarget(t *testing.T) {
+	provider := &recordingProvider{}
+	client, _ := iam.NewClient(iam.Config{Endpoint: "localhost:9000"}, iam.WithOAuth2Exchanger(provider))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	tests := []struct {
+		name string
+		opts []ClientOption
+		op   string
+		want string
+	}{
+		{"no options uses default token", nil, "/billing.v1.Billing/Charge", "Bearer default-token"},
+		{"target match", []ClientOption{
+			WithTargetToken("/billing.v1.Billing/*", iam.TokenRequest{Audience: "billing", Scopes: []string{"charge"}}),
+			WithTokenRequest(iam.TokenRequest{Audience: "api"}),
+		}, "/billing.v1.Billing/Charge", "Bearer token-billing"},
+		{"fallback to token request", []ClientOption{
+			WithTargetToken("/billing.v1.Billing/*", iam.TokenRequest{Audience: "billing"}),
+			WithTokenRequest(iam.TokenRequest{Audience: "api"}),
+		}, "/ledger.v1.Ledger/Post", "Bearer token-api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &mockTransport{headers: make(map[string]string), op: tt.op}
+			if _, err := OAuth2ClientCredentials(client, tt.opts...)(handler)(mockClientContext(context.Background(), tr), nil); err != nil {
+				t.Fatalf("middleware returned error: %v", err)
+			}
+			if got := tr.headers["Authorization"]; got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if len(provider.requests) != 2 || len(provider.requests[0].Scopes) != 1 {
+		t.Errorf("unexpected token requests: %+v", provider.requests)
+	}
+}
+
+func TestOAuth2OnBehalfOf(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"admin"}),
+		fake.WithOAuth2App("svc-orders", "secret_test", nil),
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	mw := OAuth2OnBehalfOf(client, WithTargetToken("/billing.v1.Billing/*", iam.TokenRequest{Audience: "billing"}))
+
+	// Without an authenticated caller there is nothing to exchange
+	tr := &mockTransport{headers: make(map[string]string), op: "/billing.v1.Billing/Charge"}
+	if _, err := mw(handler)(mockClientContext(context.Background(), tr), nil); errors.Code(err) != 401 {
+		t.Fatalf("expected 401, got %v", err)
+	}
+
+	ctx := iam.WithAccessToken(context.Background(), "user123")
+	if _, err := mw(handler)(mockClientContext(ctx, tr), nil); err != nil {
+		t.Fatalf("middleware returned error: %v", err)
+	}
+
+	// The downstream token is the caller's, with the service as actor
+	token := strings.TrimPrefix(tr.headers["Authorization"], "Bearer ")
+	claims, err := client.Verifier().Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify(%q) error: %v", token, err)
+	}
+	if claims.Subject != "user123" || claims.Actor == nil || claims.Actor.Subject != "svc-orders" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestRequireAll(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("user123", "tenant123", "test@example.com", []string{"user"}),
+		fake.WithPermissions("user123", []string{"user:read", "user:write"}),
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	ctx := iam.WithUserID(context.Background(), "user123")
+
+	if _, err := RequireAll(client, "user:read", "user:write")(handler)(ctx, nil); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if _, err := RequireAll(client, "user:read", "user:delete")(handler)(ctx, nil); !errors.IsForbidden(err) {
+		t.Errorf("expected Forbidden, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package kratosmw
+
+import (
+	"context"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/core"
+	iamv1 "github.com/chimerakang/iam-go/proto/iam/v1"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// Rules returns Kratos server middleware that enforces the (iam.v1.rule)
+// option of the called method, declared in its proto definition: public
+// methods pass, others are authenticated as by Auth and then checked for
+// tenant membership and the rule's permission. Methods without a rule are
+// denied with errors.Forbidden, so a route cannot be exposed by forgetting
+// to protect it; use WithExcludedOperations for methods defined outside
+// your protos, such as health checks. It replaces Auth, Tenant and Require
+// for services that declare rules.
+func Rules(client *iam.Client, opts ...AuthOption) middleware.Middleware {
+	cfg := &authConfig{excludedOperations: make(map[string]bool)}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if ok && cfg.excludedOperations[tr.Operation()] {
+				return handler(ctx, req)
+			}
+
+			var (
+				rule *iamv1.Rule
+				r    core.Request
+			)
+			if ok {
+				rule = core.LookupRule(tr.Operation())
+				r = request(tr)
+			}
+			ctx, err := cfg.Authorize(ctx, client, rule, r)
+			if err != nil {
+				return nil, toError(ctx, err)
+			}
+
+			return handler(ctx, req)
+		}
+	}
+}
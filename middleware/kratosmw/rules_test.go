@@ -0,0 +1,122 @@
+package kratosmw
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+	iamv1 "github.com/chimerakang/iam-go/proto/iam/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// registerRulesService registers rulestest.v1.Orders, whose methods carry
+// (iam.v1.rule) options, as generated code would.
+func registerRulesService(t *testing.T) {
+	t.Helper()
+	registerOnce.Do(func() {
+		method := func(name string, rule *iamv1.Rule) *descriptorpb.MethodDescriptorProto {
+			m := &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String(name),
+				InputType:  proto.String(".google.protobuf.Empty"),
+				OutputType: proto.String(".google.protobuf.Empty"),
+			}
+			if rule != nil {
+				m.Options = &descriptorpb.MethodOptions{}
+				proto.SetExtension(m.Options, iamv1.E_Rule, rule)
+			}
+			return m
+		}
+		fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+			Name:       proto.String("rulestest/v1/orders.proto"),
+			Package:    proto.String("rulestest.v1"),
+			Syntax:     proto.String("proto3"),
+			Dependency: []string{"google/protobuf/empty.proto"},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("Orders"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					method("Ping", &iamv1.Rule{Public: true}),
+					method("List", &iamv1.Rule{}),
+					method("Create", &iamv1.Rule{Permission: "orders:write", TenantRequired: true}),
+					method("Delete", nil),
+				},
+			}},
+		}, protoregistry.GlobalFiles)
+		if err == nil {
+			err = protoregistry.GlobalFiles.RegisterFile(fd)
+		}
+		registerErr = err
+	})
+	if registerErr != nil {
+		t.Fatalf("registering test service: %v", registerErr)
+	}
+}
+
+func TestRules(t *testing.T) {
+	registerRulesService(t)
+	client := fake.NewClient(
+		fake.WithUser("writer", "tenant123", "w@example.com", nil),
+		fake.WithUser("reader", "tenant123", "r@example.com", nil),
+		fake.WithPermissions("writer", []string{"orders:write"}),
+	)
+	mw := Rules(client, WithExcludedOperations("/grpc.health.v1.Health/Check"))
+	var captured context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		captured = ctx
+		return "ok", nil
+	}
+
+	tests := []struct {
+		op    string
+		token string
+		want  int
+	}{
+		{"/rulestest.v1.Orders/Ping", "", 200},
+		{"/grpc.health.v1.Health/Check", "", 200},
+		{"/rulestest.v1.Orders/List", "", 401},
+		{"/rulestest.v1.Orders/List", "reader", 200},
+		{"/rulestest.v1.Orders/Create", "reader", 403},
+		{"/rulestest.v1.Orders/Create", "writer", 200},
+		{"/rulestest.v1.Orders/Delete", "writer", 403},
+		{"/orders/{id}", "writer", 403},
+	}
+	for _, tt := range tests {
+		t.Run(tt.op+"/"+tt.token, func(t *testing.T) {
+			headers := make(map[string]string)
+			if tt.token != "" {
+				headers["Authorization"] = "Bearer " + tt.token
+			}
+			tr := &mockTransport{headers: headers, op: tt.op}
+			captured = nil
+			_, err := mw(handler)(mockServerContext(context.Background(), tr), nil)
+			if tt.want == 200 {
+				if err != nil {
+					t.Fatalf("got %v, want success", err)
+				}
+				if tt.token != "" && iam.UserIDFromContext(captured) != tt.token {
+					t.Errorf("user = %q, want %q", iam.UserIDFromContext(captured), tt.token)
+				}
+				return
+			}
+			if errors.Code(err) != tt.want {
+				t.Errorf("got %v, want %d", err, tt.want)
+			}
+		})
+	}
+
+	// Without a transport the method is unknown
+	if _, err := mw(handler)(context.Background(), nil); !errors.IsForbidden(err) {
+		t.Errorf("without transport: got %v, want Forbidden", err)
+	}
+}
@@ -0,0 +1,206 @@
+package oauth2
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AuthMethod is how the client authenticates to the token endpoint
+// (token_endpoint_auth_method, RFC 7591).
+type AuthMethod string
+
+// Client authentication methods.
+const (
+	// AuthClientSecretPost sends client_id and client_secret in the form body.
+	AuthClientSecretPost AuthMethod = "client_secret_post"
+
+	// AuthClientSecretBasic sends them as HTTP Basic credentials (RFC 6749
+	// section 2.3.1).
+	AuthClientSecretBasic AuthMethod = "client_secret_basic"
+
+	// AuthPrivateKeyJWT sends a JWT client assertion signed with the
+	// client's private key (RFC 7523 section 2.2); no secret is shared.
+	AuthPrivateKeyJWT AuthMethod = "private_key_jwt"
+
+	// AuthNone only sends client_id, for public clients such as CLIs that
+	// cannot keep a secret (RFC 7591 "none"); see UserLogin.
+	AuthNone AuthMethod = "none"
+)
+
+// ClientAssertionTypeJWT is the client_assertion_type of private_key_jwt
+// assertions.
+const ClientAssertionTypeJWT = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// DefaultAssertionLifetime is how long a client assertion is valid.
+const DefaultAssertionLifetime = 2 * time.Minute
+
+// WithAuthMethod selects the client authentication method. By default the
+// Exchanger uses AuthPrivateKeyJWT if a private key is configured, AuthNone
+// without a client secret and AuthClientSecretPost otherwise; with
+// WithDiscovery the default comes from the server's metadata.
+func WithAuthMethod(m AuthMethod) Option {
+	return func(e *Exchanger) { e.authMethod = m }
+}
+
+// WithPrivateKey sets the key that signs private_key_jwt client assertions:
+// an *rsa.PrivateKey (RS256) or *ecdsa.PrivateKey (ES256, ES384 or ES512 by
+// curve), e.g. from ParsePrivateKeyPEM. keyID, if not empty, is sent as the
+// assertion's "kid" header so the server can pick the registered public key.
+func WithPrivateKey(key crypto.Signer, keyID string) Option {
+	return func(e *Exchanger) {
+		e.privateKey = key
+		e.keyID = keyID
+	}
+}
+
+// WithAssertionAudience sets the "aud" of client assertions. Default: the
+// token endpoint URL. Some servers expect their issuer identifier instead.
+func WithAssertionAudience(aud string) Option {
+	return func(e *Exchanger) { e.assertionAudience = aud }
+}
+
+// WithAssertionLifetime sets how long client assertions are valid. Default:
+// DefaultAssertionLifetime.
+func WithAssertionLifetime(d time.Duration) Option {
+	return func(e *Exchanger) { e.assertionLifetime = d }
+}
+
+// ParsePrivateKeyPEM parses an RSA or EC private key in PEM form: PKCS #8
+// ("PRIVATE KEY"), PKCS #1 ("RSA PRIVATE KEY") or SEC 1 ("EC PRIVATE KEY").
+// Encrypted keys are not supported.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("oauth2: no private key found in PEM data")
+		}
+
+		var key any
+		var err error
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, fmt.Errorf("oauth2: encrypted private keys are not supported")
+		default:
+			continue // e.g. "EC PARAMETERS"
+		}
+		if err != nil {
+			return nil, fmt.Errorf("oauth2: parsing %s: %w", block.Type, err)
+		}
+
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return k, nil
+		case *ecdsa.PrivateKey:
+			return k, nil
+		}
+		return nil, fmt.Errorf("oauth2: unsupported private key type %T", key)
+	}
+}
+
+// authenticate adds the client authentication for method to the request
+// header or form. audience is the token endpoint URL.
+func (e *Exchanger) authenticate(header http.Header, form url.Values, method AuthMethod, audience string) error {
+	switch method {
+	case AuthClientSecretBasic:
+		// RFC 6749 section 2.3.1: form-encode the credentials first
+		credentials := url.QueryEscape(e.clientID) + ":" + url.QueryEscape(e.clientSecret)
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	case AuthClientSecretPost:
+		form.Set("client_id", e.clientID)
+		form.Set("client_secret", e.clientSecret)
+	case AuthNone:
+		form.Set("client_id", e.clientID)
+	case AuthPrivateKeyJWT:
+		if e.assertionAudience != "" {
+			audience = e.assertionAudience
+		}
+		assertion, err := e.clientAssertion(audience)
+		if err != nil {
+			return err
+		}
+		form.Set("client_id", e.clientID)
+		form.Set("client_assertion_type", ClientAssertionTypeJWT)
+		form.Set("client_assertion", assertion)
+	default:
+		return fmt.Errorf("oauth2: unsupported client authentication method %q", method)
+	}
+	return nil
+}
+
+// clientAssertion returns a signed RFC 7523 client assertion. Every
+// assertion gets a fresh "jti", so servers can reject replays.
+func (e *Exchanger) clientAssertion(audience string) (string, error) {
+	if e.privateKey == nil {
+		return "", fmt.Errorf("oauth2: private_key_jwt requires a private key (WithPrivateKey)")
+	}
+	method, err := signingMethod(e.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", fmt.Errorf("oauth2: generating assertion ID: %w", err)
+	}
+	lifetime := e.assertionLifetime
+	if lifetime <= 0 {
+		lifetime = DefaultAssertionLifetime
+	}
+	now := time.Now()
+
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"iss": e.clientID,
+		"sub": e.clientID,
+		"aud": audience,
+		"jti": hex.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(lifetime).Unix(),
+	})
+	if e.keyID != "" {
+		token.Header["kid"] = e.keyID
+	}
+	signed, err := token.SignedString(e.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("oauth2: signing client assertion: %w", err)
+	}
+	return signed, nil
+}
+
+// signingMethod picks the JWS algorithm for key.
+func signingMethod(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+		return nil, fmt.Errorf("oauth2: unsupported EC curve %s", k.Curve.Params().Name)
+	}
+	return nil, fmt.Errorf("oauth2: unsupported private key type %T", key)
+}
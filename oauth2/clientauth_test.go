@@ -0,0 +1,165 @@
+package oauth2_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chimerakang/iam-go/oauth2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// recordingTokenServer accepts any client and records the last token request.
+func recordingTokenServer(t *testing.T, last *http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		*last = *r
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "tok",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientAuth_SecretBasic(t *testing.T) {
+	var last http.Request
+	server := recordingTokenServer(t, &last)
+	e := oauth2.New("app:1", "s3cr/t", server.URL, nil, oauth2.WithAuthMethod(oauth2.AuthClientSecretBasic))
+
+	if _, err := e.ExchangeToken(context.Background(), nil); err != nil {
+		t.Fatalf("ExchangeToken() error: %v", err)
+	}
+
+	// RFC 6749 section 2.3.1: credentials are form-encoded before base64
+	user, pass, ok := last.BasicAuth()
+	if !ok || user != "app%3A1" || pass != "s3cr%2Ft" {
+		t.Errorf("BasicAuth = %q, %q, %v", user, pass, ok)
+	}
+	if last.PostForm.Get("client_secret") != "" || last.PostForm.Get("client_id") != "" {
+		t.Errorf("credentials must not be in the form: %v", last.PostForm)
+	}
+}
+
+func TestClientAuth_SecretPostIsDefault(t *testing.T) {
+	var last http.Request
+	server := recordingTokenServer(t, &last)
+	e := oauth2.New("app_test", "secret_test", server.URL, nil)
+
+	if _, err := e.ExchangeToken(context.Background(), nil); err != nil {
+		t.Fatalf("ExchangeToken() error: %v", err)
+	}
+
+	if last.PostForm.Get("client_id") != "app_test" || last.PostForm.Get("client_secret") != "secret_test" {
+		t.Errorf("unexpected form: %v", last.PostForm)
+	}
+	if last.Header.Get("Authorization") != "" {
+		t.Error("unexpected Authorization header")
+	}
+}
+
+func TestClientAuth_PrivateKeyJWT(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	tests := []struct {
+		name string
+		key  crypto.Signer
+		alg  string
+	}{
+		{"RSA", rsaKey, "RS256"},
+		{"EC", ecKey, "ES384"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var last http.Request
+			server := recordingTokenServer(t, &last)
+			e := oauth2.New("svc-billing", "", server.URL, nil, oauth2.WithPrivateKey(tt.key, "key-2024"))
+
+			var jtis []string
+			for range 2 {
+				if _, err := e.ExchangeToken(context.Background(), nil); err != nil {
+					t.Fatalf("ExchangeToken() error: %v", err)
+				}
+				if last.PostForm.Get("client_assertion_type") != oauth2.ClientAssertionTypeJWT || last.PostForm.Get("client_secret") != "" {
+					t.Fatalf("unexpected form: %v", last.PostForm)
+				}
+
+				claims := jwt.MapClaims{}
+				token, err := jwt.ParseWithClaims(last.PostForm.Get("client_assertion"), claims, func(*jwt.Token) (interface{}, error) {
+					return tt.key.Public(), nil
+				}, jwt.WithAudience(server.URL), jwt.WithIssuer("svc-billing"), jwt.WithExpirationRequired())
+				if err != nil {
+					t.Fatalf("assertion does not verify: %v", err)
+				}
+				if token.Method.Alg() != tt.alg || token.Header["kid"] != "key-2024" || claims["sub"] != "svc-billing" {
+					t.Errorf("unexpected assertion: header %v, claims %v", token.Header, claims)
+				}
+				exp, _ := claims.GetExpirationTime()
+				if exp.After(time.Now().Add(oauth2.DefaultAssertionLifetime + time.Second)) {
+					t.Errorf("exp %v exceeds the assertion lifetime", exp)
+				}
+				jtis = append(jtis, claims["jti"].(string))
+			}
+			if jtis[0] == "" || jtis[0] == jtis[1] {
+				t.Errorf("expected a fresh jti per assertion, got %v", jtis)
+			}
+		})
+	}
+}
+
+func TestClientAuth_PrivateKeyJWTWithoutKey(t *testing.T) {
+	e := oauth2.New("svc", "", "http://127.0.0.1:1/token", nil, oauth2.WithAuthMethod(oauth2.AuthPrivateKeyJWT))
+
+	if _, err := e.ExchangeToken(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "private key") {
+		t.Errorf("expected missing key error, got %v", err)
+	}
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+	sec1, _ := x509.MarshalECPrivateKey(ecKey)
+	encode := func(typ string, b []byte) []byte { return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}) }
+
+	tests := []struct {
+		name string
+		data []byte
+		ok   bool
+	}{
+		{"PKCS1 RSA", encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), true},
+		{"PKCS8 EC", encode("PRIVATE KEY", pkcs8), true},
+		{"SEC1 EC with parameters", append(encode("EC PARAMETERS", []byte{6, 8, 42, 134, 72, 206, 61, 3, 1, 7}), encode("EC PRIVATE KEY", sec1)...), true},
+		{"encrypted", encode("ENCRYPTED PRIVATE KEY", []byte{1}), false},
+		{"certificate only", encode("CERTIFICATE", []byte{1}), false},
+		{"garbage", []byte("not pem"), false},
+	}
+	for _, tt := range tests {
+		key, err := oauth2.ParsePrivateKeyPEM(tt.data)
+		if tt.ok && (err != nil || key == nil) {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
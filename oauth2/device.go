@@ -0,0 +1,156 @@
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// GrantTypeDeviceCode is the RFC 8628 device authorization grant type.
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceCode is what the user needs to approve a device login: the code to
+// enter at VerificationURI.
+type DeviceCode struct {
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string // VerificationURI with the code filled in; may be empty
+	ExpiresAt               time.Time
+}
+
+// WithDevicePrompt sets how LoginDevice shows the user code. The default
+// prints it to stderr.
+func WithDevicePrompt(prompt func(DeviceCode)) LoginOption {
+	return func(l *UserLogin) { l.prompt = prompt }
+}
+
+// WithDevicePollInterval sets how often LoginDevice polls the token
+// endpoint when the server does not say (RFC 8628 default: 5 seconds).
+func WithDevicePollInterval(d time.Duration) LoginOption {
+	return func(l *UserLogin) { l.pollInterval = d }
+}
+
+func defaultPrompt(c DeviceCode) {
+	fmt.Fprintf(os.Stderr, "To log in, visit %s and enter the code %s\n", c.VerificationURI, c.UserCode)
+	if c.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "or open %s\n", c.VerificationURIComplete)
+	}
+}
+
+// deviceResponse is the raw JSON response from the device authorization
+// endpoint.
+type deviceResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int32  `json:"expires_in"`
+	Interval                int32  `json:"interval"`
+}
+
+// LoginDevice logs the user in with the Device Authorization Grant
+// (RFC 8628), for machines without a browser: the user approves the login
+// on another device with the code shown by the prompt. It polls the token
+// endpoint until the user approves or denies the login, the code expires
+// or ctx is done.
+func (l *UserLogin) LoginDevice(ctx context.Context) (*UserToken, error) {
+	deviceURL, err := l.deviceAuthorizationURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokenURL, method, err := l.exchanger.endpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	if len(l.exchanger.defaultScopes) > 0 {
+		form.Set("scope", strings.Join(l.exchanger.defaultScopes, " "))
+	}
+	if method == AuthClientSecretBasic {
+		form.Set("client_id", l.exchanger.clientID) // RFC 8628 section 3.1
+	}
+	body, err := l.exchanger.post(ctx, deviceURL, tokenURL, method, form)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: device authorization failed: %w", err)
+	}
+	var dr deviceResponse
+	if err := json.Unmarshal(body, &dr); err != nil {
+		return nil, fmt.Errorf("oauth2: failed to decode device authorization response: %w", err)
+	}
+	if dr.DeviceCode == "" || dr.UserCode == "" || dr.VerificationURI == "" {
+		return nil, fmt.Errorf("oauth2: incomplete device authorization response")
+	}
+
+	expiresAt := time.Now().Add(time.Duration(dr.ExpiresIn) * time.Second)
+	l.prompt(DeviceCode{
+		UserCode:                dr.UserCode,
+		VerificationURI:         dr.VerificationURI,
+		VerificationURIComplete: dr.VerificationURIComplete,
+		ExpiresAt:               expiresAt,
+	})
+
+	interval := time.Duration(dr.Interval) * time.Second
+	if interval <= 0 {
+		interval = l.pollInterval
+	}
+	poll := url.Values{
+		"grant_type":  {GrantTypeDeviceCode},
+		"device_code": {dr.DeviceCode},
+	}
+	for {
+		if dr.ExpiresIn > 0 && time.Now().Add(interval).After(expiresAt) {
+			return nil, fmt.Errorf("oauth2: device code expired")
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		token, err := l.exchanger.requestToken(ctx, poll)
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		switch errorCode(err) {
+		case "":
+			if err != nil {
+				return nil, err
+			}
+			return l.save(ctx, token)
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "access_denied":
+			return nil, fmt.Errorf("oauth2: device login denied by the user")
+		case "expired_token":
+			return nil, fmt.Errorf("oauth2: device code expired")
+		default:
+			return nil, fmt.Errorf("oauth2: device login failed: %w", err)
+		}
+	}
+}
+
+// deviceAuthorizationURL returns the configured or discovered device
+// authorization endpoint.
+func (l *UserLogin) deviceAuthorizationURL(ctx context.Context) (string, error) {
+	if l.deviceURL != "" {
+		return l.deviceURL, nil
+	}
+	if l.exchanger.issuer != "" {
+		md, err := l.exchanger.discover(ctx)
+		if err != nil {
+			return "", err
+		}
+		if md.DeviceAuthorizationEndpoint != "" {
+			return md.DeviceAuthorizationEndpoint, nil
+		}
+	}
+	return "", fmt.Errorf("oauth2: no device authorization endpoint configured")
+}
@@ -0,0 +1,59 @@
+package oauth2_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chimerakang/iam-go/oauth2"
+)
+
+func TestLoginDevice(t *testing.T) {
+	s := newLoginServer(t)
+	s.polls = 2
+	var prompted oauth2.DeviceCode
+	login := newUserLogin(t, s,
+		oauth2.WithDevicePollInterval(10*time.Millisecond),
+		oauth2.WithDevicePrompt(func(c oauth2.DeviceCode) { prompted = c }))
+
+	ut, err := login.LoginDevice(context.Background())
+	if err != nil {
+		t.Fatalf("LoginDevice() error: %v", err)
+	}
+	if prompted.UserCode != "WDJB-MJHT" || prompted.VerificationURI != s.URL+"/activate" {
+		t.Errorf("prompt = %+v, want the user code and verification URI", prompted)
+	}
+	if ut.Claims == nil || ut.Claims.Subject != "alice" {
+		t.Errorf("claims = %+v, want alice", ut.Claims)
+	}
+	if s.polls != 0 {
+		t.Errorf("%d pending polls left, want 0", s.polls)
+	}
+}
+
+func TestLoginDevice_Denied(t *testing.T) {
+	s := newLoginServer(t)
+	s.deny = true
+	login := newUserLogin(t, s,
+		oauth2.WithDevicePollInterval(10*time.Millisecond),
+		oauth2.WithDevicePrompt(func(oauth2.DeviceCode) {}))
+
+	if _, err := login.LoginDevice(context.Background()); err == nil {
+		t.Error("expected error when the user denies the login")
+	}
+}
+
+func TestLoginDevice_Canceled(t *testing.T) {
+	s := newLoginServer(t)
+	s.polls = 1 << 30
+	login := newUserLogin(t, s,
+		oauth2.WithDevicePollInterval(10*time.Millisecond),
+		oauth2.WithDevicePrompt(func(oauth2.DeviceCode) {}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := login.LoginDevice(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("LoginDevice() error = %v, want context.DeadlineExceeded", err)
+	}
+}
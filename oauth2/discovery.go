@@ -0,0 +1,112 @@
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ServerMetadata is the subset of authorization server metadata (RFC 8414,
+// OpenID Connect Discovery) the Exchanger uses.
+type ServerMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint"` // RFC 8628
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// WithDiscovery looks up the server metadata of issuer before the first
+// token request. The discovered token endpoint is used if New was given an
+// empty token URL, and the authentication method is chosen from
+// token_endpoint_auth_methods_supported unless set with WithAuthMethod:
+// private_key_jwt if a private key is configured, none without a client
+// secret, otherwise the first of client_secret_basic and client_secret_post
+// that the server supports.
+func WithDiscovery(issuer string) Option {
+	return func(e *Exchanger) { e.issuer = issuer }
+}
+
+// Discover fetches the metadata of the authorization server identified by
+// issuer, trying the RFC 8414 location first and the OpenID Connect one
+// second. The metadata's issuer must equal issuer.
+func Discover(ctx context.Context, client *http.Client, issuer string) (*ServerMetadata, error) {
+	u, err := url.Parse(issuer)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("oauth2: invalid issuer %q", issuer)
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+
+	var lastErr error
+	for _, wellKnown := range []string{
+		u.Scheme + "://" + u.Host + "/.well-known/oauth-authorization-server" + path,
+		u.Scheme + "://" + u.Host + path + "/.well-known/openid-configuration",
+	} {
+		md, err := fetchMetadata(ctx, client, wellKnown)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if strings.TrimSuffix(md.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+			return nil, fmt.Errorf("oauth2: metadata issuer %q does not match %q", md.Issuer, issuer)
+		}
+		return md, nil
+	}
+	return nil, lastErr
+}
+
+func fetchMetadata(ctx context.Context, client *http.Client, metadataURL string) (*ServerMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", metadataURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: metadata request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: failed to read metadata: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth2: %s returned %d", metadataURL, resp.StatusCode)
+	}
+
+	var md ServerMetadata
+	if err := json.Unmarshal(body, &md); err != nil {
+		return nil, fmt.Errorf("oauth2: failed to decode metadata: %w", err)
+	}
+	return &md, nil
+}
+
+// chooseAuthMethod picks the client authentication method for a server
+// supporting methods. RFC 8414 makes client_secret_basic the default when
+// the server lists none.
+func chooseAuthMethod(supported []string, haveKey, haveSecret bool) (AuthMethod, error) {
+	if len(supported) == 0 {
+		supported = []string{string(AuthClientSecretBasic)}
+	}
+	candidates := []AuthMethod{AuthClientSecretBasic, AuthClientSecretPost}
+	switch {
+	case haveKey:
+		candidates = []AuthMethod{AuthPrivateKeyJWT}
+	case !haveSecret:
+		// Public clients only identify themselves
+		return AuthNone, nil
+	}
+	for _, m := range candidates {
+		if slices.Contains(supported, string(m)) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("oauth2: server supports none of %v (supported: %v)", candidates, supported)
+}
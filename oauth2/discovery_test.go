@@ -0,0 +1,113 @@
+package oauth2_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chimerakang/iam-go/oauth2"
+)
+
+// newMetadataServer serves metadata at path (with the server's URL plus
+// issuerPath as issuer) and a token endpoint accepting client_secret_basic.
+func newMetadataServer(t *testing.T, path, issuerPath string, methods []string, fetches *int32) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if fetches != nil {
+			atomic.AddInt32(fetches, 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                server.URL + issuerPath,
+			"token_endpoint":                        server.URL + "/oauth/token",
+			"token_endpoint_auth_methods_supported": methods,
+		})
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "app_test" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "discovered_token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiscover(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		issuerPath string
+	}{
+		{"RFC 8414", "/.well-known/oauth-authorization-server", ""},
+		{"RFC 8414 with path", "/.well-known/oauth-authorization-server/tenants/acme", "/tenants/acme"},
+		{"OpenID Connect fallback", "/tenants/acme/.well-known/openid-configuration", "/tenants/acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMetadataServer(t, tt.path, tt.issuerPath, []string{"client_secret_basic"}, nil)
+
+			md, err := oauth2.Discover(context.Background(), server.Client(), server.URL+tt.issuerPath)
+			if err != nil {
+				t.Fatalf("Discover() error: %v", err)
+			}
+			if md.TokenEndpoint != server.URL+"/oauth/token" || len(md.TokenEndpointAuthMethodsSupported) != 1 {
+				t.Errorf("unexpected metadata: %+v", md)
+			}
+		})
+	}
+}
+
+func TestDiscover_IssuerMismatch(t *testing.T) {
+	server := newMetadataServer(t, "/.well-known/oauth-authorization-server", "/other", nil, nil)
+
+	if _, err := oauth2.Discover(context.Background(), server.Client(), server.URL); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected issuer mismatch, got %v", err)
+	}
+}
+
+func TestExchanger_WithDiscovery(t *testing.T) {
+	var fetches int32
+	server := newMetadataServer(t, "/.well-known/oauth-authorization-server", "",
+		[]string{"client_secret_post", "client_secret_basic"}, &fetches)
+	e := oauth2.New("app_test", "secret_test", "", nil, oauth2.WithDiscovery(server.URL))
+
+	for range 2 {
+		token, err := e.ExchangeToken(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("ExchangeToken() error: %v", err)
+		}
+		if token.AccessToken != "discovered_token" {
+			t.Errorf("AccessToken = %q", token.AccessToken)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("metadata fetched %d times, want 1", n)
+	}
+}
+
+func TestExchanger_WithDiscoveryUnsupportedMethod(t *testing.T) {
+	server := newMetadataServer(t, "/.well-known/oauth-authorization-server", "", []string{"client_secret_basic"}, nil)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	e := oauth2.New("app_test", "", "", nil, oauth2.WithDiscovery(server.URL), oauth2.WithPrivateKey(key, ""))
+
+	if _, err := e.ExchangeToken(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "private_key_jwt") {
+		t.Errorf("expected unsupported method error, got %v", err)
+	}
+}
@@ -1,9 +1,23 @@
 // Package oauth2 provides an OAuth2 Client Credentials token exchanger for M2M authentication.
+//
+// The Exchanger also implements iam.TenantSwitcher via the RFC 8693 token
+// exchange grant, letting a user's token be re-issued for another tenant, and
+// iam.OnBehalfOfExchanger, exchanging a caller's token for a down-scoped one
+// to call downstream services on the caller's behalf.
+//
+// UserLogin logs users in from command-line tools with the Authorization Code
+// grant and PKCE or the Device Authorization Grant, keeping their tokens in a
+// TokenStore. SessionTokens keeps users' tokens per session for
+// backend-for-frontend services.
 package oauth2
 
 import (
 	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,7 +27,8 @@ import (
 	"time"
 
 	iam "github.com/chimerakang/iam-go"
-	"golang.org/x/sync/singleflight"
+	"github.com/chimerakang/iam-go/internal/cache"
+	"github.com/chimerakang/iam-go/internal/flight"
 )
 
 // Exchanger implements iam.OAuth2TokenExchanger using HTTP token endpoint.
@@ -24,15 +39,65 @@ type Exchanger struct {
 	defaultScopes []string
 	refreshBuffer time.Duration
 	httpClient    *http.Client
+	cacheSize     int
 
-	mu    sync.RWMutex
-	token *iam.OAuth2Token
+	// Retry and background refresh
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	background  bool
+	handlers    []func(TokenEvent)
 
-	sf singleflight.Group
+	// Client authentication
+	authMethod        AuthMethod // "" to choose automatically
+	privateKey        crypto.Signer
+	keyID             string
+	assertionAudience string
+	assertionLifetime time.Duration
+	issuer            string // discovery issuer; "" disables discovery
+
+	tokens       *cache.LRU[tokenKey, *iam.OAuth2Token]
+	delegated    *cache.LRU[delegationKey, *iam.OAuth2Token] // on-behalf-of tokens
+	rotated      *cache.LRU[string, *iam.OAuth2Token]        // refresh token hash → successor
+	noActorToken bool
+
+	mu       sync.RWMutex
+	metadata *ServerMetadata // discovered metadata
+
+	flight flight.Group // coalesces token endpoint and discovery requests
+
+	bgMu     sync.Mutex
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+	timers   map[tokenKey]*time.Timer
 }
 
-// compile-time check
-var _ iam.OAuth2TokenExchanger = (*Exchanger)(nil)
+// compile-time checks
+var (
+	_ iam.OAuth2TokenExchanger = (*Exchanger)(nil)
+	_ iam.TenantSwitcher       = (*Exchanger)(nil)
+	_ iam.TokenProvider        = (*Exchanger)(nil)
+	_ iam.OnBehalfOfExchanger  = (*Exchanger)(nil)
+	_ io.Closer                = (*Exchanger)(nil)
+)
+
+// RFC 8693 token exchange identifiers.
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// GrantTypeRefreshToken is the refresh token grant type (RFC 6749 section 6).
+const GrantTypeRefreshToken = "refresh_token"
+
+// ErrRefreshTokenReused is returned by Refresh for a refresh token that was
+// already rotated, once its successor is no longer fresh. Callers should
+// use the successor, e.g. by reloading it from their TokenStore.
+var ErrRefreshTokenReused = errors.New("oauth2: refresh token was already rotated")
+
+// rotatedTTL is how long a rotated refresh token is remembered, to hand out
+// its successor or detect its reuse.
+const rotatedTTL = 24 * time.Hour
 
 // Option configures the Exchanger.
 type Option func(*Exchanger)
@@ -47,7 +112,9 @@ func WithRefreshBuffer(d time.Duration) Option {
 	return func(e *Exchanger) { e.refreshBuffer = d }
 }
 
-// New creates a new OAuth2 token exchanger.
+// New creates a new OAuth2 token exchanger. clientSecret may be empty when
+// authenticating with a private key (WithPrivateKey) and for public clients
+// such as CLIs (AuthNone), and tokenURL when it is discovered (WithDiscovery).
 func New(clientID, clientSecret, tokenURL string, scopes []string, opts ...Option) *Exchanger {
 	e := &Exchanger{
 		clientID:      clientID,
@@ -56,40 +123,218 @@ func New(clientID, clientSecret, tokenURL string, scopes []string, opts ...Optio
 		defaultScopes: scopes,
 		refreshBuffer: 5 * time.Minute,
 		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		cacheSize:     DefaultTokenCacheSize,
+		maxAttempts:   DefaultMaxAttempts,
+		backoffBase:   DefaultBackoffBase,
+		backoffMax:    DefaultBackoffMax,
 	}
 	for _, o := range opts {
 		o(e)
 	}
+	e.tokens = cache.New[tokenKey, *iam.OAuth2Token](e.cacheSize)
+	e.delegated = cache.New[delegationKey, *iam.OAuth2Token](e.cacheSize)
+	e.rotated = cache.New[string, *iam.OAuth2Token](e.cacheSize)
+	e.bgCtx, e.bgCancel = context.WithCancel(context.Background())
+	e.timers = make(map[tokenKey]*time.Timer)
 	return e
 }
 
 // tokenResponse is the raw JSON response from the token endpoint.
 type tokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int32  `json:"expires_in"`
-	Scope       string `json:"scope"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int32  `json:"expires_in"`
+	Scope        string `json:"scope"`
+	RefreshToken string `json:"refresh_token"`
 }
 
-// ExchangeToken requests a new access token using client credentials.
+// ExchangeToken requests a new access token using client credentials. The
+// token is not cached; see GetToken.
 func (e *Exchanger) ExchangeToken(ctx context.Context, scopes []string) (*iam.OAuth2Token, error) {
+	return e.exchange(ctx, iam.TokenRequest{Scopes: scopes})
+}
+
+// exchange requests a token for req using client credentials.
+func (e *Exchanger) exchange(ctx context.Context, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	scopes := req.Scopes
 	if len(scopes) == 0 {
 		scopes = e.defaultScopes
 	}
 
 	form := url.Values{
-		"grant_type":    {"client_credentials"},
-		"client_id":     {e.clientID},
-		"client_secret": {e.clientSecret},
+		"grant_type": {"client_credentials"},
 	}
+	setTarget(form, scopes, req)
+
+	return e.requestToken(ctx, form)
+}
+
+// setTarget adds the scopes and req's audience and RFC 8707 resource
+// indicators to form.
+func setTarget(form url.Values, scopes []string, req iam.TokenRequest) {
 	if len(scopes) > 0 {
 		form.Set("scope", strings.Join(scopes, " "))
 	}
+	if req.Audience != "" {
+		form.Set("audience", req.Audience)
+	}
+	for _, r := range req.Resources {
+		form.Add("resource", r)
+	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", e.tokenURL, strings.NewReader(form.Encode()))
+// SwitchTenant exchanges subjectToken for a token scoped to tenantID using the
+// RFC 8693 token exchange grant. The IAM server validates that the subject
+// belongs to the tenant; the caller authenticates with its client credentials.
+func (e *Exchanger) SwitchTenant(ctx context.Context, subjectToken, tenantID string) (*iam.OAuth2Token, error) {
+	if subjectToken == "" || tenantID == "" {
+		return nil, fmt.Errorf("oauth2: subject token and tenant ID are required")
+	}
+
+	form := url.Values{
+		"grant_type":           {GrantTypeTokenExchange},
+		"subject_token":        {subjectToken},
+		"subject_token_type":   {TokenTypeAccessToken},
+		"requested_token_type": {TokenTypeAccessToken},
+		"tenant_id":            {tenantID},
+	}
+
+	return e.requestToken(ctx, form)
+}
+
+// Refresh redeems refreshToken for a new access token with the
+// refresh_token grant. Servers that rotate refresh tokens return a new one,
+// which must replace refreshToken; otherwise the returned token carries
+// refreshToken over. The token is not cached.
+//
+// Rotation is tracked: concurrent refreshes with the same token share one
+// request, which runs to completion even if every caller gives up so that a
+// rotation the server performed is always recorded, and a refresh token that
+// was already rotated yields its successor while that is still fresh. After
+// that, Refresh returns
+// ErrRefreshTokenReused without presenting the old token to the server,
+// which may take reuse as theft and revoke the whole token family. For the
+// same reason failed refreshes are not retried (see WithRetry): the server
+// may have rotated the token before the response was lost.
+func (e *Exchanger) Refresh(ctx context.Context, refreshToken string) (*iam.OAuth2Token, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("oauth2: refresh token is required")
+	}
+
+	sum := sha256.Sum256([]byte(refreshToken))
+	key := hex.EncodeToString(sum[:])
+	if next, ok := e.rotated.Get(key); ok {
+		if time.Now().Before(next.ExpiresAt.Add(-e.refreshBuffer)) {
+			e.emit(TokenEvent{Type: EventCacheHit, Grant: GrantTypeRefreshToken, ExpiresAt: next.ExpiresAt})
+			return next, nil
+		}
+		e.emit(TokenEvent{Type: EventReuseDetected, Grant: GrantTypeRefreshToken, Err: ErrRefreshTokenReused})
+		return nil, ErrRefreshTokenReused
+	}
+
+	result, err := e.flight.Do(ctx, "refresh\x00"+key, func(ctx context.Context) (interface{}, error) {
+		start := time.Now()
+		token, err := e.requestTokenOnce(ctx, url.Values{
+			"grant_type":    {GrantTypeRefreshToken},
+			"refresh_token": {refreshToken},
+		})
+		ev := TokenEvent{Grant: GrantTypeRefreshToken, Latency: time.Since(start)}
+		if err != nil {
+			ev.Type, ev.Err = EventFailed, err
+			e.emit(ev)
+			return nil, err
+		}
+
+		switch token.RefreshToken {
+		case "":
+			token.RefreshToken = refreshToken
+		case refreshToken:
+		default:
+			e.rotated.Set(key, token, rotatedTTL)
+			ev.Rotated = true
+		}
+		ev.Type, ev.ExpiresAt = EventFetched, token.ExpiresAt
+		e.emit(ev)
+		return token, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 refresh failed: %w", err)
+	}
+	return result.(*iam.OAuth2Token), nil
+}
+
+// requestToken authenticates with the client credentials and posts form to
+// the token endpoint, retrying transient failures (see WithRetry).
+func (e *Exchanger) requestToken(ctx context.Context, form url.Values) (*iam.OAuth2Token, error) {
+	for attempt := 1; ; attempt++ {
+		token, err := e.requestTokenOnce(ctx, form)
+		if err == nil || attempt >= e.maxAttempts || !retryable(ctx, err) {
+			return token, err
+		}
+
+		delay := e.backoff(attempt)
+		if after := retryAfter(err); after > delay {
+			delay = after
+		}
+		if delay > e.backoffMax {
+			return nil, err // the server asks us to wait longer than we would
+		}
+		e.emit(TokenEvent{Type: EventRetry, Grant: form.Get("grant_type"), Attempt: attempt, Delay: delay, Err: err})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// requestTokenOnce makes a single token request.
+func (e *Exchanger) requestTokenOnce(ctx context.Context, form url.Values) (*iam.OAuth2Token, error) {
+	tokenURL, method, err := e.endpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body, err := e.post(ctx, tokenURL, tokenURL, method, form)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokenResp tokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("oauth2: failed to decode response: %w", err)
+	}
+
+	if tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2: empty access_token in response")
+	}
+
+	return &iam.OAuth2Token{
+		AccessToken:  tokenResp.AccessToken,
+		TokenType:    tokenResp.TokenType,
+		ExpiresIn:    tokenResp.ExpiresIn,
+		ExpiresAt:    time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+		Scope:        tokenResp.Scope,
+		RefreshToken: tokenResp.RefreshToken,
+	}, nil
+}
+
+// post authenticates with method and posts form to target, returning the
+// body of a 200 response. audience is the token endpoint URL, which client
+// assertions are addressed to.
+func (e *Exchanger) post(ctx context.Context, target, audience string, method AuthMethod, form url.Values) ([]byte, error) {
+	header := make(http.Header)
+	if err := e.authenticate(header, form, method, audience); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", target, strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("oauth2: failed to create request: %w", err)
 	}
+	req.Header = header
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := e.httpClient.Do(req)
@@ -104,48 +349,82 @@ func (e *Exchanger) ExchangeToken(ctx context.Context, scopes []string) (*iam.OA
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("oauth2: token endpoint returned %d: %s", resp.StatusCode, string(body))
+		return nil, &statusError{
+			code:       resp.StatusCode,
+			body:       string(body),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
+	return body, nil
+}
 
-	var tokenResp tokenResponse
-	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		return nil, fmt.Errorf("oauth2: failed to decode response: %w", err)
+// endpoint returns the token endpoint and client authentication method,
+// discovering them first if WithDiscovery was given.
+func (e *Exchanger) endpoint(ctx context.Context) (string, AuthMethod, error) {
+	tokenURL, method := e.tokenURL, e.authMethod
+	if e.issuer == "" {
+		switch {
+		case method != "":
+		case e.privateKey != nil:
+			method = AuthPrivateKeyJWT
+		case e.clientSecret == "":
+			method = AuthNone
+		default:
+			method = AuthClientSecretPost
+		}
+		return tokenURL, method, nil
 	}
 
-	if tokenResp.AccessToken == "" {
-		return nil, fmt.Errorf("oauth2: empty access_token in response")
+	md, err := e.discover(ctx)
+	if err != nil {
+		return "", "", err
 	}
-
-	return &iam.OAuth2Token{
-		AccessToken: tokenResp.AccessToken,
-		TokenType:   tokenResp.TokenType,
-		ExpiresIn:   tokenResp.ExpiresIn,
-		ExpiresAt:   time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
-		Scope:       tokenResp.Scope,
-	}, nil
+	if tokenURL == "" {
+		tokenURL = md.TokenEndpoint
+	}
+	if method == "" {
+		if method, err = chooseAuthMethod(md.TokenEndpointAuthMethodsSupported, e.privateKey != nil, e.clientSecret != ""); err != nil {
+			return "", "", err
+		}
+	}
+	if tokenURL == "" {
+		return "", "", fmt.Errorf("oauth2: issuer %q has no token endpoint", e.issuer)
+	}
+	return tokenURL, method, nil
 }
 
-// GetCachedToken returns a valid cached token, or fetches a new one if expired/missing.
-func (e *Exchanger) GetCachedToken(ctx context.Context) (string, error) {
+// discover returns the issuer's metadata, fetching it once (see
+// flight.Group). Failures are not cached, so a later request retries.
+func (e *Exchanger) discover(ctx context.Context) (*ServerMetadata, error) {
 	e.mu.RLock()
-	if e.token != nil && time.Now().Before(e.token.ExpiresAt.Add(-e.refreshBuffer)) {
-		defer e.mu.RUnlock()
-		return e.token.AccessToken, nil
-	}
+	md := e.metadata
 	e.mu.RUnlock()
+	if md != nil {
+		return md, nil
+	}
 
-	// singleflight prevents thundering herd
-	result, err, _ := e.sf.Do("token", func() (interface{}, error) {
-		return e.ExchangeToken(ctx, e.defaultScopes)
+	result, err := e.flight.Do(ctx, "discovery", func(ctx context.Context) (interface{}, error) {
+		md, err := Discover(ctx, e.httpClient, e.issuer)
+		if err != nil {
+			return nil, err
+		}
+		e.mu.Lock()
+		e.metadata = md
+		e.mu.Unlock()
+		return md, nil
 	})
 	if err != nil {
-		return "", fmt.Errorf("oauth2 token exchange failed: %w", err)
+		return nil, err
 	}
+	return result.(*ServerMetadata), nil
+}
 
-	token := result.(*iam.OAuth2Token)
-	e.mu.Lock()
-	e.token = token
-	e.mu.Unlock()
-
+// GetCachedToken returns a valid cached token for the default scopes, or
+// fetches a new one if expired/missing.
+func (e *Exchanger) GetCachedToken(ctx context.Context) (string, error) {
+	token, err := e.GetToken(ctx, iam.TokenRequest{})
+	if err != nil {
+		return "", err
+	}
 	return token.AccessToken, nil
 }
@@ -3,6 +3,7 @@ package oauth2_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -231,3 +232,190 @@ func TestExchangeToken_ServerError(t *testing.T) {
 		t.Fatal("expected error for server error")
 	}
 }
+
+func TestSwitchTenant(t *testing.T) {
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = map[string]string{}
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "tenant-scoped-token",
+			"token_type":   "Bearer",
+			"expires_in":   900,
+		})
+	}))
+	defer server.Close()
+
+	e := oauth2.New("app_test", "secret_test", server.URL, nil)
+
+	token, err := e.SwitchTenant(context.Background(), "user-token", "tenant-2")
+	if err != nil {
+		t.Fatalf("SwitchTenant() error: %v", err)
+	}
+	if token.AccessToken != "tenant-scoped-token" {
+		t.Errorf("AccessToken = %q, want %q", token.AccessToken, "tenant-scoped-token")
+	}
+
+	want := map[string]string{
+		"grant_type":         oauth2.GrantTypeTokenExchange,
+		"subject_token":      "user-token",
+		"subject_token_type": oauth2.TokenTypeAccessToken,
+		"tenant_id":          "tenant-2",
+		"client_id":          "app_test",
+		"client_secret":      "secret_test",
+	}
+	for k, v := range want {
+		if form[k] != v {
+			t.Errorf("form[%q] = %q, want %q", k, form[k], v)
+		}
+	}
+}
+
+func TestSwitchTenant_MissingArguments(t *testing.T) {
+	e := oauth2.New("app_test", "secret_test", "http://unused", nil)
+
+	if _, err := e.SwitchTenant(context.Background(), "", "tenant-2"); err == nil {
+		t.Error("expected error for empty subject token")
+	}
+	if _, err := e.SwitchTenant(context.Background(), "user-token", ""); err == nil {
+		t.Error("expected error for empty tenant ID")
+	}
+}
+
+func TestRefresh_Rotation(t *testing.T) {
+	s := newLoginServer(t)
+	s.refresh["rt-0"] = true
+	var rotated atomic.Int32
+	e := oauth2.New("cli", "", s.URL+"/token", nil,
+		oauth2.WithRefreshBuffer(0),
+		oauth2.WithEventHandler(func(ev oauth2.TokenEvent) {
+			if ev.Rotated {
+				rotated.Add(1)
+			}
+		}))
+	ctx := context.Background()
+
+	// Concurrent refreshes with one token share a single request
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := e.Refresh(ctx, "rt-0")
+			if err != nil {
+				t.Errorf("Refresh() error: %v", err)
+				return
+			}
+			if token.RefreshToken == "rt-0" {
+				t.Error("Refresh() should return the rotated refresh token")
+			}
+			tokens[i] = token.AccessToken
+		}()
+	}
+	wg.Wait()
+	for _, tok := range tokens[1:] {
+		if tok != tokens[0] {
+			t.Fatal("refreshes with the same token returned different tokens")
+		}
+	}
+
+	// A late caller with the rotated-away token gets the successor, without
+	// presenting the used token to the server
+	token, err := e.Refresh(ctx, "rt-0")
+	if err != nil {
+		t.Fatalf("Refresh() with a rotated token error: %v", err)
+	}
+	if token.AccessToken != tokens[0] {
+		t.Error("Refresh() with a rotated token should return its successor")
+	}
+	if rotated.Load() != 1 {
+		t.Errorf("rotations = %d, want 1", rotated.Load())
+	}
+}
+
+func TestRefresh_CanceledCallerStillRecordsRotation(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "at-1",
+			"refresh_token": "rt-1",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	}))
+	defer server.Close()
+	e := oauth2.New("cli", "", server.URL, nil, oauth2.WithRefreshBuffer(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := e.Refresh(ctx, "rt-0")
+		done <- err
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Refresh() error = %v, want context.Canceled", err)
+	}
+	close(release)
+
+	// The server rotated rt-0 after the caller gave up; the successor is
+	// handed out instead of presenting rt-0 again
+	token, err := e.Refresh(context.Background(), "rt-0")
+	if err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+	if token.AccessToken != "at-1" || calls.Load() != 1 {
+		t.Errorf("token = %+v after %d requests, want the successor from one request", token, calls.Load())
+	}
+}
+
+func TestRefresh_ReuseDetected(t *testing.T) {
+	s := newLoginServer(t)
+	s.refresh["rt-0"] = true
+	var reuses atomic.Int32
+	// The server's tokens expire within the default refresh buffer, so the
+	// successor is never fresh enough to hand out again
+	e := oauth2.New("cli", "", s.URL+"/token", nil, oauth2.WithEventHandler(func(ev oauth2.TokenEvent) {
+		if ev.Type == oauth2.EventReuseDetected {
+			reuses.Add(1)
+		}
+	}))
+	ctx := context.Background()
+
+	if _, err := e.Refresh(ctx, "rt-0"); err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+	if _, err := e.Refresh(ctx, "rt-0"); !errors.Is(err, oauth2.ErrRefreshTokenReused) {
+		t.Errorf("Refresh() with a rotated token error = %v, want ErrRefreshTokenReused", err)
+	}
+	if reuses.Load() != 1 {
+		t.Errorf("reuse events = %d, want 1", reuses.Load())
+	}
+}
+
+func TestRefresh_NotRotated(t *testing.T) {
+	e := oauth2.New("app_test", "secret_test", newSequentialTokenServer(t).URL, nil)
+
+	token, err := e.Refresh(context.Background(), "rt-0")
+	if err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+	if token.RefreshToken != "rt-0" {
+		t.Errorf("RefreshToken = %q, want the unrotated rt-0", token.RefreshToken)
+	}
+	if _, err := e.Refresh(context.Background(), ""); err == nil {
+		t.Error("expected error for an empty refresh token")
+	}
+}
@@ -0,0 +1,326 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// ErrLoginRequired is returned by UserLogin.Token when there is no usable
+// token: the user never logged in, logged out, or the refresh token was
+// revoked or has expired.
+var ErrLoginRequired = errors.New("oauth2: login required")
+
+// UserToken is a user's token together with its verified claims.
+type UserToken struct {
+	Token  *iam.OAuth2Token
+	Claims *iam.Claims // nil without a verifier (WithTokenVerifier)
+}
+
+// UserLogin logs users in from command-line tools, with the Authorization
+// Code grant and PKCE through the system browser (LoginBrowser) or with the
+// Device Authorization Grant on machines without one (LoginDevice). Tokens
+// are kept in a TokenStore and refreshed as they expire (Token).
+//
+// The Exchanger provides the client ID, scopes and token endpoint; CLIs are
+// public clients, so its client secret is usually empty (AuthNone).
+type UserLogin struct {
+	exchanger *Exchanger
+	authURL   string
+	deviceURL string
+	verifier  iam.TokenVerifier
+	tokens    storedTokens
+	storeKey  string
+	browser   func(string) error
+	prompt    func(DeviceCode)
+
+	pollInterval time.Duration
+}
+
+// LoginOption configures a UserLogin.
+type LoginOption func(*UserLogin)
+
+// WithAuthorizationURL sets the authorization endpoint. By default it is
+// discovered (WithDiscovery on the Exchanger).
+func WithAuthorizationURL(u string) LoginOption {
+	return func(l *UserLogin) { l.authURL = u }
+}
+
+// WithDeviceAuthorizationURL sets the RFC 8628 device authorization
+// endpoint. By default it is discovered (WithDiscovery on the Exchanger).
+func WithDeviceAuthorizationURL(u string) LoginOption {
+	return func(l *UserLogin) { l.deviceURL = u }
+}
+
+// WithTokenVerifier verifies issued tokens, e.g. with a jwks.Verifier, and
+// fills UserToken.Claims. Tokens failing verification are rejected.
+func WithTokenVerifier(v iam.TokenVerifier) LoginOption {
+	return func(l *UserLogin) { l.verifier = v }
+}
+
+// WithTokenStore persists tokens in store under key (the client ID if
+// empty). By default tokens are kept in a MemoryStore, and only live as
+// long as the process.
+func WithTokenStore(store TokenStore, key string) LoginOption {
+	return func(l *UserLogin) { l.tokens.store, l.storeKey = store, key }
+}
+
+// WithBrowser sets how LoginBrowser opens the authorization URL. The
+// default prints the URL to stderr and tries OpenBrowser.
+func WithBrowser(open func(url string) error) LoginOption {
+	return func(l *UserLogin) { l.browser = open }
+}
+
+// NewUserLogin creates a UserLogin for the Exchanger's client.
+func NewUserLogin(e *Exchanger, opts ...LoginOption) *UserLogin {
+	l := &UserLogin{
+		exchanger: e,
+		tokens:    storedTokens{exchanger: e, store: NewMemoryStore()},
+		browser:   defaultBrowser,
+		prompt:    defaultPrompt,
+
+		pollInterval: 5 * time.Second,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	if l.storeKey == "" {
+		l.storeKey = e.clientID
+	}
+	return l
+}
+
+// defaultBrowser prints u so that users can open it by hand if no browser
+// starts, e.g. over SSH.
+func defaultBrowser(u string) error {
+	fmt.Fprintf(os.Stderr, "Opening %s in your browser...\n", u)
+	if err := OpenBrowser(u); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not open a browser; please open the URL above.")
+	}
+	return nil
+}
+
+// OpenBrowser opens u in the system's default browser.
+func OpenBrowser(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("oauth2: opening browser: %w", err)
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// LoginBrowser logs the user in with the Authorization Code grant and PKCE
+// (RFC 7636). It listens on a loopback port for the redirect (RFC 8252),
+// opens the authorization URL with the browser and waits until the user
+// completes the login or ctx is done.
+func (l *UserLogin) LoginBrowser(ctx context.Context) (*UserToken, error) {
+	authURL, err := l.authorizationURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: listening for redirect: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/callback", ln.Addr())
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var res result
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, "Login failed: invalid state.", http.StatusBadRequest)
+			return // ignore forged or stale redirects
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("oauth2: authorization failed: %s %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("code") == "":
+			res.err = fmt.Errorf("oauth2: authorization response has no code")
+		default:
+			res.code = q.Get("code")
+		}
+		if res.err != nil {
+			http.Error(w, "Login failed. You can close this window.", http.StatusForbidden)
+		} else {
+			_, _ = fmt.Fprintln(w, "Login succeeded. You can close this window.")
+		}
+		select {
+		case results <- res:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = srv.Serve(ln) }()
+	defer func() { _ = srv.Close() }()
+
+	sum := sha256.Sum256([]byte(verifier))
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {l.exchanger.clientID},
+		"redirect_uri":          {redirectURI},
+		"state":                 {state},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+		"code_challenge_method": {"S256"},
+	}
+	if len(l.exchanger.defaultScopes) > 0 {
+		q.Set("scope", strings.Join(l.exchanger.defaultScopes, " "))
+	}
+	if err := l.browser(withQuery(authURL, q)); err != nil {
+		return nil, err
+	}
+
+	var res result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-results:
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	// Codes are single-use, and servers may revoke the tokens issued for a
+	// code that is redeemed twice, so a failed redemption is not retried.
+	token, err := l.exchanger.requestTokenOnce(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {res.code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: redeeming authorization code: %w", err)
+	}
+	return l.save(ctx, token)
+}
+
+// Token returns the user's token, refreshing it if it is about to expire.
+// A rotated refresh token replaces the stored one. It returns
+// ErrLoginRequired if the user must log in (again).
+func (l *UserLogin) Token(ctx context.Context) (*UserToken, error) {
+	token, err := l.tokens.get(ctx, l.storeKey)
+	if err != nil {
+		return nil, err
+	}
+	return l.verify(ctx, token)
+}
+
+// Logout forgets the user's token.
+func (l *UserLogin) Logout(ctx context.Context) error {
+	return l.tokens.delete(ctx, l.storeKey)
+}
+
+// save verifies and stores a newly issued token.
+func (l *UserLogin) save(ctx context.Context, token *iam.OAuth2Token) (*UserToken, error) {
+	ut, err := l.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.tokens.save(ctx, l.storeKey, token); err != nil {
+		return nil, err
+	}
+	return ut, nil
+}
+
+// verify checks token with the configured verifier, if any.
+func (l *UserLogin) verify(ctx context.Context, token *iam.OAuth2Token) (*UserToken, error) {
+	ut := &UserToken{Token: token}
+	if l.verifier == nil {
+		return ut, nil
+	}
+	claims, err := l.verifier.Verify(ctx, token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: verifying issued token: %w", err)
+	}
+	ut.Claims = claims
+	return ut, nil
+}
+
+// authorizationURL returns the configured or discovered authorization
+// endpoint.
+func (l *UserLogin) authorizationURL(ctx context.Context) (string, error) {
+	if l.authURL != "" {
+		return l.authURL, nil
+	}
+	if l.exchanger.issuer != "" {
+		md, err := l.exchanger.discover(ctx)
+		if err != nil {
+			return "", err
+		}
+		if md.AuthorizationEndpoint != "" {
+			return md.AuthorizationEndpoint, nil
+		}
+	}
+	return "", fmt.Errorf("oauth2: no authorization endpoint configured")
+}
+
+// withQuery appends q to u, which may already have a query.
+func withQuery(u string, q url.Values) string {
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+	return u + sep + q.Encode()
+}
+
+// randomString returns n random bytes, base64url-encoded.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("oauth2: generating random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// errorCode returns the OAuth error code ("invalid_grant", ...) of a token
+// endpoint error response, or "" if err is not one.
+func errorCode(err error) string {
+	var se *statusError
+	if !errors.As(err, &se) {
+		return ""
+	}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal([]byte(se.body), &body) != nil {
+		return ""
+	}
+	return body.Error
+}
@@ -0,0 +1,351 @@
+package oauth2_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/jwks"
+	"github.com/chimerakang/iam-go/oauth2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// loginServer is an authorization server for public clients. It issues JWT
+// access tokens for "alice" that are valid for an hour but reported as
+// expiring within a minute, so that every UserLogin.Token call refreshes.
+// Refresh tokens rotate: each one can be used once.
+type loginServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu         sync.Mutex
+	n          int
+	challenges map[string]string // code → PKCE challenge
+	redirects  map[string]string // code → redirect_uri
+	refresh    map[string]bool   // usable refresh tokens
+	polls      int               // device polls before approval
+	deny       bool
+
+	redemptions  int  // authorization code redemptions
+	lostResponse bool // answer redemptions with 503 after redeeming the code
+}
+
+func newLoginServer(t *testing.T) *loginServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &loginServer{
+		key:        key,
+		challenges: make(map[string]string),
+		redirects:  make(map[string]string),
+		refresh:    make(map[string]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]interface{}{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("client_id") != "cli" || q.Get("code_challenge_method") != "S256" || q.Get("response_type") != "code" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.n++
+		code := fmt.Sprintf("code-%d", s.n)
+		s.challenges[code] = q.Get("code_challenge")
+		s.redirects[code] = q.Get("redirect_uri")
+		s.mu.Unlock()
+		http.Redirect(w, r, q.Get("redirect_uri")+"?"+url.Values{"code": {code}, "state": {q.Get("state")}}.Encode(), http.StatusFound)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("client_id") != "cli" {
+			oauthError(w, "invalid_client")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      "dev-1",
+			"user_code":        "WDJB-MJHT",
+			"verification_uri": s.URL + "/activate",
+			"expires_in":       60,
+		})
+	})
+	mux.HandleFunc("/token", s.token)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *loginServer) token(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	form := r.PostForm
+	if form.Get("client_id") != "cli" || form.Get("client_secret") != "" {
+		oauthError(w, "invalid_client")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch form.Get("grant_type") {
+	case "authorization_code":
+		code := form.Get("code")
+		sum := sha256.Sum256([]byte(form.Get("code_verifier")))
+		challenge, ok := s.challenges[code]
+		delete(s.challenges, code)
+		s.redemptions++
+		if !ok || challenge != base64.RawURLEncoding.EncodeToString(sum[:]) || s.redirects[code] != form.Get("redirect_uri") {
+			oauthError(w, "invalid_grant")
+			return
+		}
+		if s.lostResponse {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+	case "refresh_token":
+		if !s.refresh[form.Get("refresh_token")] {
+			oauthError(w, "invalid_grant")
+			return
+		}
+		delete(s.refresh, form.Get("refresh_token"))
+	case oauth2.GrantTypeDeviceCode:
+		switch {
+		case form.Get("device_code") != "dev-1":
+			oauthError(w, "invalid_grant")
+			return
+		case s.deny:
+			oauthError(w, "access_denied")
+			return
+		case s.polls > 0:
+			s.polls--
+			oauthError(w, "authorization_pending")
+			return
+		}
+	default:
+		oauthError(w, "unsupported_grant_type")
+		return
+	}
+	s.issue(w)
+}
+
+// issue writes a new access token and refresh token.
+func (s *loginServer) issue(w http.ResponseWriter) {
+	s.n++
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub":       "alice",
+		"tenant_id": "acme",
+		"exp":       time.Now().Add(time.Hour).Unix(),
+		"jti":       fmt.Sprint(s.n),
+	})
+	jwtToken.Header["kid"] = "key-1"
+	access, _ := jwtToken.SignedString(s.key)
+	refresh := fmt.Sprintf("rt-%d", s.n)
+	s.refresh[refresh] = true
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token":  access,
+		"token_type":    "Bearer",
+		"expires_in":    60,
+		"refresh_token": refresh,
+	})
+}
+
+// revokeAll invalidates every refresh token.
+func (s *loginServer) revokeAll() {
+	s.mu.Lock()
+	s.refresh = make(map[string]bool)
+	s.mu.Unlock()
+}
+
+func oauthError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+// followRedirects plays the browser: it requests the authorization URL and
+// follows the redirect to the loopback listener.
+func followRedirects(u string) error {
+	resp, err := http.Get(u)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("callback returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func newUserLogin(t *testing.T, s *loginServer, opts ...oauth2.LoginOption) *oauth2.UserLogin {
+	t.Helper()
+	e := oauth2.New("cli", "", s.URL+"/token", []string{"openid", "offline_access"})
+	opts = append([]oauth2.LoginOption{
+		oauth2.WithAuthorizationURL(s.URL + "/authorize"),
+		oauth2.WithDeviceAuthorizationURL(s.URL + "/device"),
+		oauth2.WithTokenVerifier(jwks.NewVerifier(s.URL + "/jwks")),
+		oauth2.WithBrowser(followRedirects),
+	}, opts...)
+	return oauth2.NewUserLogin(e, opts...)
+}
+
+func TestLoginBrowser(t *testing.T) {
+	s := newLoginServer(t)
+	store := oauth2.NewFileStore(filepath.Join(t.TempDir(), "tokens.json"))
+	login := newUserLogin(t, s, oauth2.WithTokenStore(store, "default"))
+	ctx := context.Background()
+
+	if _, err := login.Token(ctx); !errors.Is(err, oauth2.ErrLoginRequired) {
+		t.Fatalf("Token() before login error = %v, want ErrLoginRequired", err)
+	}
+
+	ut, err := login.LoginBrowser(ctx)
+	if err != nil {
+		t.Fatalf("LoginBrowser() error: %v", err)
+	}
+	if ut.Claims == nil || ut.Claims.Subject != "alice" || ut.Claims.TenantID != "acme" {
+		t.Errorf("claims = %+v, want alice in acme", ut.Claims)
+	}
+	stored, err := store.Load(ctx, "default")
+	if err != nil || stored.AccessToken != ut.Token.AccessToken || stored.RefreshToken == "" {
+		t.Errorf("stored token = %+v, %v; want the issued token", stored, err)
+	}
+}
+
+func TestLoginBrowser_Denied(t *testing.T) {
+	s := newLoginServer(t)
+	login := newUserLogin(t, s, oauth2.WithBrowser(func(u string) error {
+		// The user declines: the server redirects with an error
+		parsed, _ := url.Parse(u)
+		q := parsed.Query()
+		return followRedirects(q.Get("redirect_uri") + "?" + url.Values{"error": {"access_denied"}, "state": {q.Get("state")}}.Encode())
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := login.LoginBrowser(ctx); err == nil {
+		t.Error("expected error when the user denies access")
+	}
+}
+
+func TestLoginBrowser_WrongState(t *testing.T) {
+	s := newLoginServer(t)
+	login := newUserLogin(t, s, oauth2.WithBrowser(func(u string) error {
+		parsed, _ := url.Parse(u)
+		forged := parsed.Query().Get("redirect_uri") + "?code=stolen&state=forged"
+		if err := followRedirects(forged); err == nil {
+			return errors.New("redirect with a forged state was accepted")
+		}
+		return followRedirects(u)
+	}))
+
+	if _, err := login.LoginBrowser(context.Background()); err != nil {
+		t.Fatalf("LoginBrowser() error: %v", err)
+	}
+}
+
+func TestLoginBrowser_CodeNotRedeemedTwice(t *testing.T) {
+	s := newLoginServer(t)
+	s.lostResponse = true
+	login := newUserLogin(t, s)
+
+	if _, err := login.LoginBrowser(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.redemptions != 1 {
+		t.Errorf("code redeemed %d times, want 1", s.redemptions)
+	}
+}
+
+func TestUserLogin_RefreshRotation(t *testing.T) {
+	s := newLoginServer(t)
+	store := oauth2.NewFileStore(filepath.Join(t.TempDir(), "tokens.json"))
+	login := newUserLogin(t, s, oauth2.WithTokenStore(store, ""))
+	ctx := context.Background()
+
+	first, err := login.LoginBrowser(ctx)
+	if err != nil {
+		t.Fatalf("LoginBrowser() error: %v", err)
+	}
+
+	// Tokens expire within the refresh buffer, so Token refreshes
+	second, err := login.Token(ctx)
+	if err != nil {
+		t.Fatalf("Token() error: %v", err)
+	}
+	if second.Token.AccessToken == first.Token.AccessToken || second.Token.RefreshToken == first.Token.RefreshToken {
+		t.Error("Token() should have refreshed and rotated the refresh token")
+	}
+	if second.Claims == nil || second.Claims.Subject != "alice" {
+		t.Errorf("claims = %+v, want alice", second.Claims)
+	}
+	stored, _ := store.Load(ctx, "cli")
+	if stored == nil || stored.RefreshToken != second.Token.RefreshToken {
+		t.Errorf("stored refresh token = %+v, want the rotated one", stored)
+	}
+
+	// Once the refresh token is revoked, the user must log in again
+	s.revokeAll()
+	if _, err := login.Token(ctx); !errors.Is(err, oauth2.ErrLoginRequired) {
+		t.Fatalf("Token() after revocation error = %v, want ErrLoginRequired", err)
+	}
+	if _, err := store.Load(ctx, "cli"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("revoked token should be deleted from the store, got %v", err)
+	}
+}
+
+func TestUserLogin_Logout(t *testing.T) {
+	s := newLoginServer(t)
+	login := newUserLogin(t, s)
+	ctx := context.Background()
+
+	if _, err := login.LoginBrowser(ctx); err != nil {
+		t.Fatalf("LoginBrowser() error: %v", err)
+	}
+	if err := login.Logout(ctx); err != nil {
+		t.Fatalf("Logout() error: %v", err)
+	}
+	if _, err := login.Token(ctx); !errors.Is(err, oauth2.ErrLoginRequired) {
+		t.Errorf("Token() after logout error = %v, want ErrLoginRequired", err)
+	}
+}
+
+func TestUserLogin_VerificationFails(t *testing.T) {
+	s := newLoginServer(t)
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer other.Close()
+	login := newUserLogin(t, s, oauth2.WithTokenVerifier(jwks.NewVerifier(other.URL)))
+
+	if _, err := login.LoginBrowser(context.Background()); err == nil {
+		t.Error("expected error when the issued token cannot be verified")
+	}
+}
@@ -0,0 +1,118 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// WithoutActorToken omits the actor_token from on-behalf-of exchanges, for
+// servers that identify the acting service by its client authentication
+// alone.
+func WithoutActorToken() Option {
+	return func(e *Exchanger) { e.noActorToken = true }
+}
+
+// delegationKey identifies a cached on-behalf-of token: the subject token,
+// by hash, and the target.
+type delegationKey struct {
+	subject string
+	target  tokenKey
+}
+
+// OnBehalfOf exchanges the bearer token of the current request, as stored
+// by the auth middleware (iam.AccessTokenFromContext), for a token to call
+// the target described by req on the caller's behalf. See
+// ExchangeOnBehalfOf.
+func (e *Exchanger) OnBehalfOf(ctx context.Context, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	subjectToken := iam.AccessTokenFromContext(ctx)
+	if subjectToken == "" {
+		return nil, fmt.Errorf("oauth2: no subject token in context")
+	}
+	return e.ExchangeOnBehalfOf(ctx, subjectToken, req)
+}
+
+// ExchangeOnBehalfOf exchanges subjectToken, the token of this service's
+// caller, for a token to call the target described by req on the caller's
+// behalf, using the RFC 8693 token exchange grant. The issued token is
+// restricted to req's scopes, audience and resources; unlike GetToken the
+// default scopes do not apply. Unless WithoutActorToken is given, the
+// Exchanger's own client-credentials token is sent as actor_token, so the
+// server names this service in the new token's "act" claim.
+//
+// Tokens are cached per subject token and target, and are never kept past
+// the subject token's expiry when the context carries its claims.
+func (e *Exchanger) ExchangeOnBehalfOf(ctx context.Context, subjectToken string, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	if subjectToken == "" {
+		return nil, fmt.Errorf("oauth2: subject token is required")
+	}
+
+	sum := sha256.Sum256([]byte(subjectToken))
+	key := delegationKey{
+		subject: hex.EncodeToString(sum[:]),
+		target:  tokenKey{scope: sortedSet(req.Scopes), audience: req.Audience, resources: sortedSet(req.Resources)},
+	}
+	if token, ok := e.delegated.Get(key); ok && time.Now().Before(token.ExpiresAt.Add(-e.refreshBuffer)) {
+		e.emit(TokenEvent{Type: EventCacheHit, Grant: GrantTypeTokenExchange, Request: req, ExpiresAt: token.ExpiresAt})
+		return token, nil
+	}
+
+	// Do not cache past the subject's own expiry
+	var subjectExpiry time.Time
+	if claims := iam.ClaimsFromContext(ctx); claims != nil && iam.AccessTokenFromContext(ctx) == subjectToken {
+		subjectExpiry = claims.ExpiresAt
+	}
+
+	result, err := e.flight.Do(ctx, "obo\x00"+key.subject+"\x00"+key.target.String(), func(ctx context.Context) (interface{}, error) {
+		start := time.Now()
+		token, err := e.exchangeOnBehalfOf(ctx, subjectToken, req)
+		ev := TokenEvent{Grant: GrantTypeTokenExchange, Request: req, Latency: time.Since(start)}
+		if err != nil {
+			ev.Type, ev.Err = EventFailed, err
+			e.emit(ev)
+			return nil, err
+		}
+
+		expiry := token.ExpiresAt
+		if !subjectExpiry.IsZero() && subjectExpiry.Before(expiry) {
+			expiry = subjectExpiry
+		}
+		if ttl := time.Until(expiry); ttl > 0 {
+			e.delegated.Set(key, token, ttl)
+		}
+		ev.Type, ev.ExpiresAt = EventFetched, token.ExpiresAt
+		e.emit(ev)
+		return token, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 token exchange failed: %w", err)
+	}
+	return result.(*iam.OAuth2Token), nil
+}
+
+// exchangeOnBehalfOf requests a delegated token for req.
+func (e *Exchanger) exchangeOnBehalfOf(ctx context.Context, subjectToken string, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	form := url.Values{
+		"grant_type":           {GrantTypeTokenExchange},
+		"subject_token":        {subjectToken},
+		"subject_token_type":   {TokenTypeAccessToken},
+		"requested_token_type": {TokenTypeAccessToken},
+	}
+	setTarget(form, req.Scopes, req)
+
+	if !e.noActorToken {
+		actor, err := e.GetToken(ctx, iam.TokenRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("oauth2: obtaining actor token: %w", err)
+		}
+		form.Set("actor_token", actor.AccessToken)
+		form.Set("actor_token_type", TokenTypeAccessToken)
+	}
+
+	return e.requestToken(ctx, form)
+}
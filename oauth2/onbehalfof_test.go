@@ -0,0 +1,113 @@
+package oauth2_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/oauth2"
+)
+
+// newDelegationServer issues client-credentials tokens ("svc-token") and
+// on-behalf-of tokens ("<subject>/<audience>/<scope>/<n>"), rejecting
+// exchanges whose actor_token is not svc-token (unless allowMissingActor).
+func newDelegationServer(t *testing.T, exchanges *atomic.Int32, allowMissingActor bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("client_id") != "app_test" || r.PostForm.Get("client_secret") != "secret_test" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+
+		token := "svc-token"
+		if r.PostForm.Get("grant_type") == oauth2.GrantTypeTokenExchange {
+			actor := r.PostForm.Get("actor_token")
+			if r.PostForm.Get("subject_token_type") != oauth2.TokenTypeAccessToken ||
+				(actor != "svc-token" && !(allowMissingActor && actor == "")) {
+				http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+				return
+			}
+			n := exchanges.Add(1)
+			token = fmt.Sprintf("%s/%s/%s/%d", r.PostForm.Get("subject_token"), r.PostForm.Get("audience"), r.PostForm.Get("scope"), n)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": token,
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExchangeOnBehalfOf(t *testing.T) {
+	var exchanges atomic.Int32
+	server := newDelegationServer(t, &exchanges, false)
+	e := oauth2.New("app_test", "secret_test", server.URL, []string{"svc:default"})
+	ctx := context.Background()
+	billing := iam.TokenRequest{Audience: "billing", Scopes: []string{"billing:read"}}
+
+	tests := []struct {
+		subject string
+		req     iam.TokenRequest
+		want    string
+	}{
+		{"alice-token", billing, "alice-token/billing/billing:read/1"},
+		{"alice-token", billing, "alice-token/billing/billing:read/1"}, // cached
+		{"alice-token", iam.TokenRequest{Audience: "ledger"}, "alice-token/ledger//2"},
+		{"bob-token", billing, "bob-token/billing/billing:read/3"},
+	}
+	for _, tt := range tests {
+		token, err := e.ExchangeOnBehalfOf(ctx, tt.subject, tt.req)
+		if err != nil {
+			t.Fatalf("ExchangeOnBehalfOf(%q, %+v) error: %v", tt.subject, tt.req, err)
+		}
+		if token.AccessToken != tt.want {
+			t.Errorf("ExchangeOnBehalfOf(%q, %+v) = %q, want %q", tt.subject, tt.req, token.AccessToken, tt.want)
+		}
+	}
+	if exchanges.Load() != 3 {
+		t.Errorf("exchanges = %d, want 3", exchanges.Load())
+	}
+}
+
+func TestOnBehalfOf_FromContext(t *testing.T) {
+	var exchanges atomic.Int32
+	server := newDelegationServer(t, &exchanges, true)
+	e := oauth2.New("app_test", "secret_test", server.URL, nil, oauth2.WithoutActorToken())
+	req := iam.TokenRequest{Audience: "billing"}
+
+	if _, err := e.OnBehalfOf(context.Background(), req); err == nil {
+		t.Error("expected error without a subject token in context")
+	}
+
+	// The subject token expired: the exchanged token must not outlive it
+	ctx := iam.WithAccessToken(context.Background(), "alice-token")
+	ctx = iam.WithClaims(ctx, &iam.Claims{Subject: "alice", ExpiresAt: time.Now().Add(-time.Second)})
+	for range 2 {
+		if _, err := e.OnBehalfOf(ctx, req); err != nil {
+			t.Fatalf("OnBehalfOf() error: %v", err)
+		}
+	}
+	if exchanges.Load() != 2 {
+		t.Errorf("exchanges = %d, want 2 (not cached past subject expiry)", exchanges.Load())
+	}
+}
+
+func TestExchangeOnBehalfOf_ActorTokenRejected(t *testing.T) {
+	var exchanges atomic.Int32
+	server := newDelegationServer(t, &exchanges, false)
+	e := oauth2.New("app_test", "secret_test", server.URL, nil, oauth2.WithoutActorToken())
+
+	if _, err := e.ExchangeOnBehalfOf(context.Background(), "alice-token", iam.TokenRequest{}); err == nil {
+		t.Error("expected the server to reject an exchange without actor_token")
+	}
+}
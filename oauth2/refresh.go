@@ -0,0 +1,224 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// Retry defaults.
+const (
+	DefaultMaxAttempts = 3
+	DefaultBackoffBase = 200 * time.Millisecond
+	DefaultBackoffMax  = 10 * time.Second
+)
+
+// WithRetry sets how many times a token request is attempted when the token
+// endpoint is unreachable or answers 429 or 5xx. Default:
+// DefaultMaxAttempts; 1 disables retries. Refresh token requests and
+// authorization code redemptions are never retried, since a retry could
+// present an already rotated token or an already redeemed code.
+func WithRetry(maxAttempts int) Option {
+	return func(e *Exchanger) { e.maxAttempts = maxAttempts }
+}
+
+// WithBackoff sets the delay before the first retry, doubled for each
+// further one with jitter, and its maximum. A Retry-After header is honored
+// if it does not exceed max; otherwise the request fails without waiting.
+func WithBackoff(base, max time.Duration) Option {
+	return func(e *Exchanger) {
+		e.backoffBase = base
+		e.backoffMax = max
+	}
+}
+
+// WithBackgroundRefresh renews cached tokens in the background when they
+// enter the refresh buffer (see WithRefreshBuffer), so requests never wait
+// for the token endpoint while a token is valid. Failed refreshes are
+// retried with backoff until the token expires. Call Close to stop it.
+func WithBackgroundRefresh() Option {
+	return func(e *Exchanger) { e.background = true }
+}
+
+// WithEventHandler calls h for every token lifecycle event, e.g. to record
+// metrics. Handlers run synchronously and must not block.
+func WithEventHandler(h func(TokenEvent)) Option {
+	return func(e *Exchanger) { e.handlers = append(e.handlers, h) }
+}
+
+// TokenEventType identifies a token lifecycle event.
+type TokenEventType string
+
+// Token lifecycle events.
+const (
+	// EventCacheHit: a cached token was returned, by GetToken or, for a
+	// rotated refresh token, by Refresh.
+	EventCacheHit TokenEventType = "cache_hit"
+
+	// EventFetched: a token was obtained from the token endpoint.
+	EventFetched TokenEventType = "fetched"
+
+	// EventRetry: a token request failed and will be retried after Delay.
+	EventRetry TokenEventType = "retry"
+
+	// EventFailed: fetching a token failed after all attempts.
+	EventFailed TokenEventType = "failed"
+
+	// EventStaleServed: refreshing failed, and the cached token, which has
+	// not expired yet, was returned instead.
+	EventStaleServed TokenEventType = "stale_served"
+
+	// EventReuseDetected: Refresh was called with a refresh token that had
+	// already been rotated (ErrRefreshTokenReused).
+	EventReuseDetected TokenEventType = "refresh_reuse"
+)
+
+// TokenEvent describes a token lifecycle event.
+type TokenEvent struct {
+	Type       TokenEventType
+	Grant      string           // grant_type of the token request
+	Request    iam.TokenRequest // target of cached tokens; empty for EventRetry
+	Background bool             // set for fetches by the background refresher
+	Attempt    int              // failed attempt, for EventRetry
+	Delay      time.Duration    // wait before the next attempt, for EventRetry
+	Latency    time.Duration    // duration of the fetch, for EventFetched and EventFailed
+	ExpiresAt  time.Time        // expiry of the returned token
+	Rotated    bool             // a refresh returned a new refresh token
+	Err        error
+}
+
+// Close stops the background refresher. Cached tokens stay usable.
+func (e *Exchanger) Close() error {
+	e.bgMu.Lock()
+	defer e.bgMu.Unlock()
+	e.bgCancel()
+	for key, timer := range e.timers {
+		timer.Stop()
+		delete(e.timers, key)
+	}
+	return nil
+}
+
+func (e *Exchanger) emit(ev TokenEvent) {
+	for _, h := range e.handlers {
+		h(ev)
+	}
+}
+
+// schedule arranges the background refresh of token, cached under key.
+func (e *Exchanger) schedule(key tokenKey, req iam.TokenRequest, token *iam.OAuth2Token) {
+	if !e.background {
+		return
+	}
+	delay := time.Until(token.ExpiresAt.Add(-e.refreshBuffer))
+	if delay <= 0 {
+		// Tokens shorter-lived than the buffer are renewed halfway
+		delay = time.Until(token.ExpiresAt) / 2
+	}
+	e.scheduleIn(key, req, delay, 0)
+}
+
+// scheduleIn refreshes key after delay. attempt counts failed refreshes.
+func (e *Exchanger) scheduleIn(key tokenKey, req iam.TokenRequest, delay time.Duration, attempt int) {
+	e.bgMu.Lock()
+	defer e.bgMu.Unlock()
+	if e.bgCtx.Err() != nil {
+		return // closed
+	}
+	if timer, ok := e.timers[key]; ok {
+		timer.Stop()
+	}
+	e.timers[key] = time.AfterFunc(delay, func() { e.refresh(key, req, attempt) })
+}
+
+// refresh renews the token cached under key. Tokens that expired or were
+// evicted in the meantime are left to the next GetToken.
+func (e *Exchanger) refresh(key tokenKey, req iam.TokenRequest, attempt int) {
+	if _, ok := e.tokens.Get(key); !ok || e.bgCtx.Err() != nil {
+		e.bgMu.Lock()
+		delete(e.timers, key)
+		e.bgMu.Unlock()
+		return
+	}
+
+	// On success fetch schedules the next refresh
+	if _, err := e.fetch(e.bgCtx, key, req, true); err != nil {
+		attempt++
+		delay := e.backoff(attempt)
+		if after := retryAfter(err); after > delay {
+			delay = after
+		}
+		e.scheduleIn(key, req, delay, attempt)
+	}
+}
+
+// backoff returns the delay before retry number attempt (1-based): the base
+// doubled per attempt, capped at the maximum, with "equal jitter" so that
+// clients do not retry in lockstep.
+func (e *Exchanger) backoff(attempt int) time.Duration {
+	delay := e.backoffBase
+	for i := 1; i < attempt && delay < e.backoffMax; i++ {
+		delay *= 2
+	}
+	if delay > e.backoffMax {
+		delay = e.backoffMax
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// statusError is a non-200 response from the token endpoint.
+type statusError struct {
+	code       int
+	body       string
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("oauth2: token endpoint returned %d: %s", e.code, e.body)
+}
+
+// retryable reports whether a failed token request may succeed if repeated.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+	var ue *url.Error
+	return errors.As(err, &ue) // network errors
+}
+
+// retryAfter returns the Retry-After delay of err, or 0.
+func retryAfter(err error) time.Duration {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.retryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
@@ -0,0 +1,246 @@
+package oauth2_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/oauth2"
+)
+
+// newFlakyServer answers with status(n) for the n-th request (1-based), or a
+// token valid for expiresIn seconds if status returns 200.
+func newFlakyServer(t *testing.T, expiresIn int, status func(n int32) int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if code := status(n); code != http.StatusOK {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			http.Error(w, `{"error":"temporarily_unavailable"}`, code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// eventRecorder collects token events.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []oauth2.TokenEvent
+}
+
+func (r *eventRecorder) handle(ev oauth2.TokenEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+func (r *eventRecorder) count(typ oauth2.TokenEventType) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, ev := range r.events {
+		if ev.Type == typ {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *eventRecorder) backgroundFetches() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, ev := range r.events {
+		if ev.Type == oauth2.EventFetched && ev.Background {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRetry_TransientFailures(t *testing.T) {
+	server, calls := newFlakyServer(t, 3600, func(n int32) int {
+		if n < 3 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}, nil)
+	rec := &eventRecorder{}
+	e := oauth2.New("app_test", "secret_test", server.URL, nil,
+		oauth2.WithBackoff(time.Millisecond, 10*time.Millisecond),
+		oauth2.WithEventHandler(rec.handle),
+	)
+
+	token, err := e.ExchangeToken(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ExchangeToken() error: %v", err)
+	}
+	if token.AccessToken != "token-3" || calls.Load() != 3 {
+		t.Errorf("got %q after %d calls, want token-3 after 3", token.AccessToken, calls.Load())
+	}
+	if rec.count(oauth2.EventRetry) != 2 {
+		t.Errorf("retry events = %d, want 2", rec.count(oauth2.EventRetry))
+	}
+}
+
+func TestRetry_NotRetried(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+	}{
+		{"client error", http.StatusUnauthorized, nil},
+		{"Retry-After beyond backoff maximum", http.StatusTooManyRequests, http.Header{"Retry-After": {"120"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newFlakyServer(t, 3600, func(int32) int { return tt.status }, tt.header)
+			e := oauth2.New("app_test", "secret_test", server.URL, nil, oauth2.WithBackoff(time.Millisecond, time.Second))
+
+			if _, err := e.ExchangeToken(context.Background(), nil); err == nil {
+				t.Fatal("expected error")
+			}
+			if calls.Load() != 1 {
+				t.Errorf("server was called %d times, want 1", calls.Load())
+			}
+		})
+	}
+}
+
+func TestRetry_RefreshNotRetried(t *testing.T) {
+	// The first attempt may have rotated the refresh token before failing;
+	// presenting it again would look like reuse.
+	server, calls := newFlakyServer(t, 3600, func(n int32) int {
+		if n == 1 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}, nil)
+	e := oauth2.New("app_test", "secret_test", server.URL, nil, oauth2.WithBackoff(time.Millisecond, time.Second))
+
+	if _, err := e.Refresh(context.Background(), "rt-0"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("server was called %d times, want 1", calls.Load())
+	}
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(t, 3600, func(n int32) int {
+		if n == 1 {
+			return http.StatusTooManyRequests
+		}
+		return http.StatusOK
+	}, http.Header{"Retry-After": {"1"}})
+	rec := &eventRecorder{}
+	e := oauth2.New("app_test", "secret_test", server.URL, nil,
+		oauth2.WithBackoff(time.Millisecond, 2*time.Second),
+		oauth2.WithEventHandler(rec.handle),
+	)
+
+	start := time.Now()
+	if _, err := e.ExchangeToken(context.Background(), nil); err != nil {
+		t.Fatalf("ExchangeToken() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || calls.Load() != 2 {
+		t.Errorf("retried after %v with %d calls, want >= 1s and 2 calls", elapsed, calls.Load())
+	}
+	if rec.events[0].Type != oauth2.EventRetry || rec.events[0].Delay != time.Second {
+		t.Errorf("unexpected event: %+v", rec.events[0])
+	}
+}
+
+func TestGetToken_ServesStaleTokenWhileRefreshFails(t *testing.T) {
+	server, _ := newFlakyServer(t, 60, func(n int32) int {
+		if n == 1 {
+			return http.StatusOK
+		}
+		return http.StatusServiceUnavailable
+	}, nil)
+	rec := &eventRecorder{}
+	e := oauth2.New("app_test", "secret_test", server.URL, nil,
+		oauth2.WithRefreshBuffer(2*time.Minute), // always in the refresh window
+		oauth2.WithRetry(1),
+		oauth2.WithEventHandler(rec.handle),
+	)
+	ctx := context.Background()
+
+	if _, err := e.GetToken(ctx, iam.TokenRequest{}); err != nil {
+		t.Fatalf("GetToken() error: %v", err)
+	}
+	token, err := e.GetToken(ctx, iam.TokenRequest{})
+	if err != nil {
+		t.Fatalf("GetToken() should serve the stale token, got error: %v", err)
+	}
+	if token.AccessToken != "token-1" {
+		t.Errorf("AccessToken = %q, want token-1", token.AccessToken)
+	}
+	if rec.count(oauth2.EventFailed) != 1 || rec.count(oauth2.EventStaleServed) != 1 {
+		t.Errorf("unexpected events: %+v", rec.events)
+	}
+
+	// Without a cached token the failure surfaces
+	if _, err := e.GetToken(ctx, iam.TokenRequest{Audience: "other"}); err == nil {
+		t.Error("expected error without a cached token")
+	}
+}
+
+func TestBackgroundRefresh(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, func(int32) int { return http.StatusOK }, nil)
+	rec := &eventRecorder{}
+	e := oauth2.New("app_test", "secret_test", server.URL, nil,
+		oauth2.WithRefreshBuffer(1900*time.Millisecond), // refresh ~100ms after each fetch
+		oauth2.WithBackgroundRefresh(),
+		oauth2.WithEventHandler(rec.handle),
+	)
+	ctx := context.Background()
+
+	first, err := e.GetToken(ctx, iam.TokenRequest{})
+	if err != nil {
+		t.Fatalf("GetToken() error: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for rec.backgroundFetches() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if rec.backgroundFetches() == 0 {
+		t.Fatal("token was not refreshed in the background")
+	}
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	refreshed := calls.Load()
+	second, err := e.GetToken(ctx, iam.TokenRequest{})
+	if err != nil {
+		t.Fatalf("GetToken() error: %v", err)
+	}
+	if second.AccessToken == first.AccessToken {
+		t.Errorf("expected the refreshed token, got %q", second.AccessToken)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if calls.Load() != refreshed {
+		t.Errorf("refresher kept running after Close: %d calls, want %d", calls.Load(), refreshed)
+	}
+}
@@ -0,0 +1,156 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// SessionTokens keeps users' tokens server-side, keyed by session ID, and
+// refreshes them as they expire. It is meant for backends-for-frontends:
+// the browser only holds the session cookie, while the BFF calls APIs with
+// the user's access token.
+//
+//	tokens := oauth2.NewSessionTokens(ex, store, oauth2.WithSessionService(client.Sessions()))
+//	// after the login callback
+//	err := tokens.Save(ctx, sessionID, token)
+//	// per request
+//	token, err := tokens.Token(ctx, sessionID)
+type SessionTokens struct {
+	tokens   storedTokens
+	sessions iam.SessionService
+}
+
+// SessionTokensOption configures SessionTokens.
+type SessionTokensOption func(*SessionTokens)
+
+// WithSessionService validates the session before handing out its tokens.
+// Tokens of revoked or expired sessions are deleted.
+func WithSessionService(svc iam.SessionService) SessionTokensOption {
+	return func(s *SessionTokens) { s.sessions = svc }
+}
+
+// NewSessionTokens creates SessionTokens refreshing with e and keeping
+// tokens in store, e.g. a MemoryStore or an encrypted FileStore.
+func NewSessionTokens(e *Exchanger, store TokenStore, opts ...SessionTokensOption) *SessionTokens {
+	s := &SessionTokens{tokens: storedTokens{exchanger: e, store: store}}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Save stores the tokens issued when the user logged in to sessionID.
+func (s *SessionTokens) Save(ctx context.Context, sessionID string, token *iam.OAuth2Token) error {
+	if sessionID == "" {
+		return fmt.Errorf("oauth2: session ID is required")
+	}
+	return s.tokens.save(ctx, sessionID, token)
+}
+
+// Token returns the access token of sessionID, refreshing it if it is about
+// to expire. It returns ErrLoginRequired if the session has no usable token,
+// and an error wrapping iam.ErrSessionInvalid if the session service (see
+// WithSessionService) no longer accepts the session.
+func (s *SessionTokens) Token(ctx context.Context, sessionID string) (*iam.OAuth2Token, error) {
+	if sessionID == "" {
+		return nil, ErrLoginRequired
+	}
+	if s.sessions != nil {
+		if _, err := s.sessions.Validate(ctx, sessionID); err != nil {
+			if errors.Is(err, iam.ErrSessionInvalid) {
+				if err := s.tokens.delete(ctx, sessionID); err != nil {
+					return nil, err
+				}
+			}
+			return nil, err
+		}
+	}
+	return s.tokens.get(ctx, sessionID)
+}
+
+// TokenFromContext returns the access token of the session in the request's
+// claims (iam.Claims.SessionID). See Token.
+func (s *SessionTokens) TokenFromContext(ctx context.Context) (*iam.OAuth2Token, error) {
+	claims := iam.ClaimsFromContext(ctx)
+	if claims == nil || claims.SessionID == "" {
+		return nil, ErrLoginRequired
+	}
+	return s.Token(ctx, claims.SessionID)
+}
+
+// Delete forgets the tokens of sessionID, e.g. on logout.
+func (s *SessionTokens) Delete(ctx context.Context, sessionID string) error {
+	return s.tokens.delete(ctx, sessionID)
+}
+
+// storedTokens keeps tokens in a TokenStore and refreshes them as they
+// expire. It backs UserLogin and SessionTokens.
+type storedTokens struct {
+	exchanger *Exchanger
+	store     TokenStore
+}
+
+// get returns the token stored under key, refreshing it first if needed.
+func (s storedTokens) get(ctx context.Context, key string) (*iam.OAuth2Token, error) {
+	token, err := s.load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Before(token.ExpiresAt.Add(-s.exchanger.refreshBuffer)) {
+		return token, nil
+	}
+	if token.RefreshToken == "" {
+		return nil, ErrLoginRequired
+	}
+
+	fresh, err := s.exchanger.Refresh(ctx, token.RefreshToken)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		// Another request rotated it; pick up the successor it stored
+		if latest, lerr := s.load(ctx, key); lerr == nil && latest.RefreshToken != token.RefreshToken {
+			return s.get(ctx, key)
+		}
+	}
+	if errors.Is(err, ErrRefreshTokenReused) || errorCode(err) == "invalid_grant" {
+		// Revoked, expired or already used: the user must log in again
+		if err := s.delete(ctx, key); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrLoginRequired, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.save(ctx, key, fresh); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
+
+func (s storedTokens) load(ctx context.Context, key string) (*iam.OAuth2Token, error) {
+	token, err := s.store.Load(ctx, key)
+	if errors.Is(err, iam.ErrNotFound) {
+		return nil, ErrLoginRequired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: loading token: %w", err)
+	}
+	return token, nil
+}
+
+func (s storedTokens) save(ctx context.Context, key string, token *iam.OAuth2Token) error {
+	if err := s.store.Save(ctx, key, token); err != nil {
+		return fmt.Errorf("oauth2: saving token: %w", err)
+	}
+	return nil
+}
+
+func (s storedTokens) delete(ctx context.Context, key string) error {
+	if err := s.store.Delete(ctx, key); err != nil {
+		return fmt.Errorf("oauth2: deleting token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package oauth2_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+	"github.com/chimerakang/iam-go/oauth2"
+)
+
+func TestSessionTokens(t *testing.T) {
+	s := newLoginServer(t)
+	s.refresh["rt-0"] = true
+	client := fake.NewClient(fake.WithSession("alice", "sess-1"))
+	store := oauth2.NewMemoryStore()
+	e := oauth2.New("cli", "", s.URL+"/token", nil)
+	tokens := oauth2.NewSessionTokens(e, store, oauth2.WithSessionService(client.Sessions()))
+	ctx := context.Background()
+
+	if _, err := tokens.Token(ctx, "sess-1"); !errors.Is(err, oauth2.ErrLoginRequired) {
+		t.Fatalf("Token() before Save() error = %v, want ErrLoginRequired", err)
+	}
+
+	// The access token has expired: Token refreshes and stores the rotation
+	expired := &iam.OAuth2Token{AccessToken: "old", RefreshToken: "rt-0", ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := tokens.Save(ctx, "sess-1", expired); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	ctx = iam.WithClaims(ctx, &iam.Claims{Subject: "alice", SessionID: "sess-1"})
+	token, err := tokens.TokenFromContext(ctx)
+	if err != nil {
+		t.Fatalf("TokenFromContext() error: %v", err)
+	}
+	if token.AccessToken == "old" || token.RefreshToken == "rt-0" {
+		t.Errorf("token = %+v, want a refreshed and rotated token", token)
+	}
+	stored, _ := store.Load(ctx, "sess-1")
+	if stored == nil || stored.RefreshToken != token.RefreshToken {
+		t.Errorf("stored token = %+v, want the rotated refresh token", stored)
+	}
+
+	// Revoking the session drops its tokens
+	if err := client.Sessions().Revoke(ctx, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.Token(ctx, "sess-1"); !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("Token() for a revoked session error = %v, want ErrSessionInvalid", err)
+	}
+	if _, err := store.Load(ctx, "sess-1"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("tokens of a revoked session should be deleted, got %v", err)
+	}
+}
+
+func TestSessionTokens_RefreshRevoked(t *testing.T) {
+	s := newLoginServer(t)
+	store := oauth2.NewMemoryStore()
+	tokens := oauth2.NewSessionTokens(oauth2.New("cli", "", s.URL+"/token", nil), store)
+	ctx := context.Background()
+
+	expired := &iam.OAuth2Token{AccessToken: "old", RefreshToken: "revoked", ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := tokens.Save(ctx, "sess-1", expired); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if _, err := tokens.Token(ctx, "sess-1"); !errors.Is(err, oauth2.ErrLoginRequired) {
+		t.Errorf("Token() with a revoked refresh token error = %v, want ErrLoginRequired", err)
+	}
+	if _, err := store.Load(ctx, "sess-1"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("unusable tokens should be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// TokenStore persists user tokens between runs, e.g. so a CLI does not ask
+// the user to log in every time. Implementations must be safe for
+// concurrent use.
+type TokenStore interface {
+	// Load returns the token saved under key, or an error wrapping
+	// iam.ErrNotFound.
+	Load(ctx context.Context, key string) (*iam.OAuth2Token, error)
+
+	// Save stores token under key, replacing any previous one.
+	Save(ctx context.Context, key string, token *iam.OAuth2Token) error
+
+	// Delete removes the token saved under key. Deleting a missing key is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore is a TokenStore keeping tokens in memory, e.g. for a single
+// backend-for-frontend instance. Tokens are lost on restart, and are only
+// removed by Delete.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	tokens map[string]iam.OAuth2Token
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tokens: make(map[string]iam.OAuth2Token)}
+}
+
+// Load implements TokenStore.
+func (s *MemoryStore) Load(_ context.Context, key string) (*iam.OAuth2Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, fmt.Errorf("oauth2: no stored token for %q: %w", key, iam.ErrNotFound)
+	}
+	return &token, nil
+}
+
+// Save implements TokenStore.
+func (s *MemoryStore) Save(_ context.Context, key string, token *iam.OAuth2Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[key] = *token
+	return nil
+}
+
+// Delete implements TokenStore.
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, key)
+	return nil
+}
+
+// FileStore is a TokenStore keeping tokens in a single JSON file readable
+// only by the current user, optionally encrypted (NewEncryptedFileStore).
+// Writes replace the file atomically.
+//
+// A FileStore is for a single process: every Save rewrites the whole file
+// under an in-process lock, so processes sharing the file overwrite each
+// other's tokens. Replicated services need a shared TokenStore such as a
+// database.
+type FileStore struct {
+	path string
+	aead cipher.AEAD // nil for plain JSON
+	mu   sync.Mutex
+}
+
+// compile-time checks
+var (
+	_ TokenStore = (*MemoryStore)(nil)
+	_ TokenStore = (*FileStore)(nil)
+)
+
+// NewFileStore returns a FileStore writing to path. The file and its parent
+// directory are created on the first Save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// NewEncryptedFileStore returns a FileStore that encrypts the file with
+// AES-256-GCM under key, which must be 32 bytes. Use it where others may
+// read the file, e.g. for a single-instance backend-for-frontend's session
+// tokens on a mounted volume; keep the key in a secret manager, not next to
+// the file.
+func NewEncryptedFileStore(path string, key []byte) (*FileStore, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("oauth2: encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: creating cipher: %w", err)
+	}
+	return &FileStore{path: path, aead: aead}, nil
+}
+
+// DefaultTokenFile returns the default token file for app, under the user's
+// configuration directory (e.g. ~/.config/<app>/tokens.json).
+func DefaultTokenFile(app string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("oauth2: locating config directory: %w", err)
+	}
+	return filepath.Join(dir, app, "tokens.json"), nil
+}
+
+// storedToken is the on-disk form of an iam.OAuth2Token.
+type storedToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresAt    int64  `json:"expires_at"` // Unix seconds
+}
+
+// Load implements TokenStore.
+func (s *FileStore) Load(_ context.Context, key string) (*iam.OAuth2Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	st, ok := tokens[key]
+	if !ok {
+		return nil, fmt.Errorf("oauth2: no stored token for %q: %w", key, iam.ErrNotFound)
+	}
+	return fromStored(st), nil
+}
+
+// Save implements TokenStore.
+func (s *FileStore) Save(_ context.Context, key string, token *iam.OAuth2Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = toStored(token)
+	return s.write(tokens)
+}
+
+// Delete implements TokenStore.
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return s.write(tokens)
+}
+
+// read loads the token file; a missing file holds no tokens.
+func (s *FileStore) read() (map[string]storedToken, error) {
+	tokens := make(map[string]storedToken)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: reading token file: %w", err)
+	}
+	if s.aead != nil {
+		if data, err = s.open(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("oauth2: decoding token file %s: %w", s.path, err)
+	}
+	return tokens, nil
+}
+
+// write replaces the token file with tokens, via a temporary file so that
+// readers never see a partial write.
+func (s *FileStore) write(tokens map[string]storedToken) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("oauth2: encoding tokens: %w", err)
+	}
+	if s.aead != nil {
+		if data, err = s.seal(data); err != nil {
+			return err
+		}
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("oauth2: creating token directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tokens-*")
+	if err != nil {
+		return fmt.Errorf("oauth2: writing token file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	// CreateTemp already uses mode 0600
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("oauth2: writing token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("oauth2: writing token file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("oauth2: writing token file: %w", err)
+	}
+	return nil
+}
+
+// seal encrypts data, prefixing the random nonce.
+func (s *FileStore) seal(data []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("oauth2: generating nonce: %w", err)
+	}
+	return s.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// open decrypts data written by seal.
+func (s *FileStore) open(data []byte) ([]byte, error) {
+	n := s.aead.NonceSize()
+	if len(data) < n {
+		return nil, fmt.Errorf("oauth2: token file %s is truncated", s.path)
+	}
+	plain, err := s.aead.Open(nil, data[:n], data[n:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: decrypting token file %s (wrong key?): %w", s.path, err)
+	}
+	return plain, nil
+}
+
+func toStored(t *iam.OAuth2Token) storedToken {
+	st := storedToken{
+		AccessToken:  t.AccessToken,
+		TokenType:    t.TokenType,
+		Scope:        t.Scope,
+		RefreshToken: t.RefreshToken,
+	}
+	if !t.ExpiresAt.IsZero() {
+		st.ExpiresAt = t.ExpiresAt.Unix()
+	}
+	return st
+}
+
+func fromStored(st storedToken) *iam.OAuth2Token {
+	t := &iam.OAuth2Token{
+		AccessToken:  st.AccessToken,
+		TokenType:    st.TokenType,
+		Scope:        st.Scope,
+		RefreshToken: st.RefreshToken,
+	}
+	if st.ExpiresAt != 0 {
+		t.ExpiresAt = time.Unix(st.ExpiresAt, 0)
+		t.ExpiresIn = int32(time.Until(t.ExpiresAt).Seconds())
+	}
+	return t
+}
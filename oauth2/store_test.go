@@ -0,0 +1,130 @@
+package oauth2_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/oauth2"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app", "tokens.json")
+	store := oauth2.NewFileStore(path)
+	ctx := context.Background()
+
+	if _, err := store.Load(ctx, "alice"); !errors.Is(err, iam.ErrNotFound) {
+		t.Fatalf("Load() on a missing file error = %v, want ErrNotFound", err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	token := &iam.OAuth2Token{AccessToken: "at", TokenType: "Bearer", RefreshToken: "rt", ExpiresAt: expiresAt}
+	if err := store.Save(ctx, "alice", token); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if err := store.Save(ctx, "bob", &iam.OAuth2Token{AccessToken: "bob-at"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("token file mode = %o, want 600", perm)
+	}
+
+	// A new store reads what the previous one wrote
+	got, err := oauth2.NewFileStore(path).Load(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got.AccessToken != "at" || got.RefreshToken != "rt" || !got.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Load() = %+v, want the saved token", got)
+	}
+
+	if err := store.Delete(ctx, "alice"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if err := store.Delete(ctx, "alice"); err != nil {
+		t.Errorf("deleting a missing key should not fail: %v", err)
+	}
+	if _, err := store.Load(ctx, "alice"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("Load() after Delete() error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Load(ctx, "bob"); err != nil {
+		t.Errorf("other tokens should be kept: %v", err)
+	}
+}
+
+func TestFileStore_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := oauth2.NewFileStore(path).Load(context.Background(), "alice"); err == nil || errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("Load() of a corrupt file error = %v, want a decoding error", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := oauth2.NewMemoryStore()
+	ctx := context.Background()
+
+	token := &iam.OAuth2Token{AccessToken: "at", RefreshToken: "rt"}
+	if err := store.Save(ctx, "sess-1", token); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	token.AccessToken = "modified"
+
+	got, err := store.Load(ctx, "sess-1")
+	if err != nil || got.AccessToken != "at" {
+		t.Errorf("Load() = %+v, %v; want a copy of the saved token", got, err)
+	}
+	if err := store.Delete(ctx, "sess-1"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if _, err := store.Load(ctx, "sess-1"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("Load() after Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.bin")
+	key := bytes.Repeat([]byte{7}, 32)
+	store, err := oauth2.NewEncryptedFileStore(path, key)
+	if err != nil {
+		t.Fatalf("NewEncryptedFileStore() error: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Save(ctx, "sess-1", &iam.OAuth2Token{AccessToken: "secret-access", RefreshToken: "secret-refresh"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret-")) {
+		t.Error("token file contains plaintext tokens")
+	}
+
+	reopened, _ := oauth2.NewEncryptedFileStore(path, key)
+	got, err := reopened.Load(ctx, "sess-1")
+	if err != nil || got.RefreshToken != "secret-refresh" {
+		t.Errorf("Load() = %+v, %v; want the saved token", got, err)
+	}
+
+	wrong, _ := oauth2.NewEncryptedFileStore(path, bytes.Repeat([]byte{8}, 32))
+	if _, err := wrong.Load(ctx, "sess-1"); err == nil {
+		t.Error("expected error decrypting with the wrong key")
+	}
+	if _, err := oauth2.NewEncryptedFileStore(path, []byte("short")); err == nil {
+		t.Error("expected error for a key that is not 32 bytes")
+	}
+}
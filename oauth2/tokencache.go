@@ -0,0 +1,120 @@
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// DefaultTokenCacheSize is how many tokens the Exchanger caches by default.
+const DefaultTokenCacheSize = 100
+
+// WithTokenCacheSize sets how many tokens (one per distinct TokenRequest)
+// the Exchanger caches. The least recently used token is evicted first;
+// size <= 0 means unbounded.
+func WithTokenCacheSize(size int) Option {
+	return func(e *Exchanger) { e.cacheSize = size }
+}
+
+// tokenKey identifies a cached token. Scopes and resources are sorted, so
+// requests differing only in order share a token.
+type tokenKey struct {
+	scope     string
+	audience  string
+	resources string
+}
+
+// String returns the flight.Group key.
+func (k tokenKey) String() string {
+	return "token\x00" + k.scope + "\x00" + k.audience + "\x00" + k.resources
+}
+
+// keyFor returns the cache key of req, with the default scopes applied.
+func (e *Exchanger) keyFor(req iam.TokenRequest) tokenKey {
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = e.defaultScopes
+	}
+	return tokenKey{
+		scope:     sortedSet(scopes),
+		audience:  req.Audience,
+		resources: sortedSet(req.Resources),
+	}
+}
+
+// GetToken returns a valid cached token for req, or fetches a new one with
+// client credentials. Tokens are cached per (scopes, audience, resources),
+// and concurrent requests for the same key share one token request. An
+// empty req.Scopes means the default scopes given to New.
+//
+// If refreshing a token fails while the cached one has not expired yet, the
+// cached token is returned (EventStaleServed).
+func (e *Exchanger) GetToken(ctx context.Context, req iam.TokenRequest) (*iam.OAuth2Token, error) {
+	key := e.keyFor(req)
+	cached, ok := e.tokens.Get(key)
+	// With background refresh the refresher renews tokens in the buffer window
+	if ok && (e.background || time.Now().Before(cached.ExpiresAt.Add(-e.refreshBuffer))) {
+		e.emit(TokenEvent{Type: EventCacheHit, Grant: "client_credentials", Request: req, ExpiresAt: cached.ExpiresAt})
+		return cached, nil
+	}
+
+	token, err := e.fetch(ctx, key, req, false)
+	if err != nil {
+		if ok && time.Now().Before(cached.ExpiresAt) {
+			e.emit(TokenEvent{Type: EventStaleServed, Grant: "client_credentials", Request: req, ExpiresAt: cached.ExpiresAt, Err: err})
+			return cached, nil
+		}
+		return nil, fmt.Errorf("oauth2 token exchange failed: %w", err)
+	}
+	return token, nil
+}
+
+// Invalidate drops the cached token for req if it is accessToken, e.g.
+// after a resource server rejected it, so the next GetToken fetches a new
+// one. A newer token cached in the meantime is kept.
+func (e *Exchanger) Invalidate(req iam.TokenRequest, accessToken string) {
+	key := e.keyFor(req)
+	if token, ok := e.tokens.Get(key); ok && token.AccessToken == accessToken {
+		e.tokens.Delete(key)
+	}
+}
+
+// fetch requests a token for req and caches it under key. Concurrent
+// fetches of a key share one token request (see flight.Group).
+func (e *Exchanger) fetch(ctx context.Context, key tokenKey, req iam.TokenRequest, background bool) (*iam.OAuth2Token, error) {
+	result, err := e.flight.Do(ctx, key.String(), func(ctx context.Context) (interface{}, error) {
+		start := time.Now()
+		token, err := e.exchange(ctx, req)
+		ev := TokenEvent{Grant: "client_credentials", Request: req, Background: background, Latency: time.Since(start)}
+		if err != nil {
+			ev.Type, ev.Err = EventFailed, err
+			e.emit(ev)
+			return nil, err
+		}
+		if ttl := time.Until(token.ExpiresAt); ttl > 0 {
+			e.tokens.Set(key, token, ttl)
+			e.schedule(key, req, token)
+		}
+		ev.Type, ev.ExpiresAt = EventFetched, token.ExpiresAt
+		e.emit(ev)
+		return token, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*iam.OAuth2Token), nil
+}
+
+// sortedSet returns the distinct values sorted and joined by spaces.
+func sortedSet(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	s := slices.Clone(values)
+	slices.Sort(s)
+	return strings.Join(slices.Compact(s), " ")
+}
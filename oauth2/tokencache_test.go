@@ -0,0 +1,109 @@
+package oauth2_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/oauth2"
+)
+
+// newAudienceServer issues "<audience>|<scope>|<resources>" tokens and counts requests.
+func newAudienceServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond) // simulate latency
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": r.PostForm.Get("audience") + "|" + r.PostForm.Get("scope") + "|" + strings.Join(r.PostForm["resource"], ","),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetToken_PerTarget(t *testing.T) {
+	var calls atomic.Int32
+	server := newAudienceServer(t, &calls)
+	e := oauth2.New("app_test", "secret_test", server.URL, []string{"iam:introspect"})
+	ctx := context.Background()
+
+	tests := []struct {
+		req  iam.TokenRequest
+		want string
+	}{
+		{iam.TokenRequest{}, "|iam:introspect|"},
+		{iam.TokenRequest{Audience: "billing", Scopes: []string{"charge", "refund"}}, "billing|charge refund|"},
+		{iam.TokenRequest{Audience: "billing", Scopes: []string{"refund", "charge"}}, "billing|charge refund|"}, // same key, other order
+		{iam.TokenRequest{Resources: []string{"https://ledger.example.com/", "https://audit.example.com/"}}, "|iam:introspect|https://ledger.example.com/,https://audit.example.com/"},
+	}
+	for _, tt := range tests {
+		token, err := e.GetToken(ctx, tt.req)
+		if err != nil {
+			t.Fatalf("GetToken(%+v) error: %v", tt.req, err)
+		}
+		if token.AccessToken != tt.want {
+			t.Errorf("GetToken(%+v) = %q, want %q", tt.req, token.AccessToken, tt.want)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("server was called %d times, want 3 (one per target)", calls.Load())
+	}
+
+	// GetCachedToken shares the default-scopes entry
+	if token, err := e.GetCachedToken(ctx); err != nil || token != "|iam:introspect|" {
+		t.Errorf("GetCachedToken() = %q, %v", token, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("server was called %d times, want 3", calls.Load())
+	}
+}
+
+func TestGetToken_SingleflightPerKey(t *testing.T) {
+	var calls atomic.Int32
+	server := newAudienceServer(t, &calls)
+	e := oauth2.New("app_test", "secret_test", server.URL, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(audience string) {
+			defer wg.Done()
+			if _, err := e.GetToken(context.Background(), iam.TokenRequest{Audience: audience}); err != nil {
+				t.Errorf("GetToken() error: %v", err)
+			}
+		}([]string{"a", "b"}[i%2])
+	}
+	wg.Wait()
+
+	if calls.Load() != 2 {
+		t.Errorf("server was called %d times, want 2 (one per audience)", calls.Load())
+	}
+}
+
+func TestGetToken_CacheSize(t *testing.T) {
+	var calls atomic.Int32
+	server := newAudienceServer(t, &calls)
+	e := oauth2.New("app_test", "secret_test", server.URL, nil, oauth2.WithTokenCacheSize(1))
+	ctx := context.Background()
+
+	for _, audience := range []string{"a", "b", "a"} {
+		if _, err := e.GetToken(ctx, iam.TokenRequest{Audience: audience}); err != nil {
+			t.Fatalf("GetToken() error: %v", err)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("server was called %d times, want 3 (a evicted by b)", calls.Load())
+	}
+}
@@ -0,0 +1,120 @@
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	iam "github.com/chimerakang/iam-go"
+	"google.golang.org/grpc/credentials"
+)
+
+// Transport returns an http.RoundTripper that authenticates requests with a
+// Bearer token for req from GetToken, then sends them with base
+// (http.DefaultTransport if nil). If the server answers 401, the token is
+// invalidated and the request is retried once with a fresh one; requests
+// whose body cannot be replayed (no GetBody) are not retried.
+//
+//	client := &http.Client{Transport: ex.Transport(iam.TokenRequest{Audience: "billing"}, nil)}
+func (e *Exchanger) Transport(req iam.TokenRequest, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{exchanger: e, req: req, base: base}
+}
+
+type transport struct {
+	exchanger *Exchanger
+	req       iam.TokenRequest
+	base      http.RoundTripper
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := t.exchanger.GetToken(r.Context(), t.req)
+	if err != nil {
+		closeBody(r)
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(authorized(r, token.AccessToken))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if r.Body != nil && r.GetBody == nil {
+		return resp, nil
+	}
+
+	// The token was rejected, e.g. revoked or signed with a rotated key
+	t.exchanger.Invalidate(t.req, token.AccessToken)
+	fresh, err := t.exchanger.GetToken(r.Context(), t.req)
+	if err != nil || fresh.AccessToken == token.AccessToken {
+		return resp, nil
+	}
+	retry := authorized(r, fresh.AccessToken)
+	if r.GetBody != nil {
+		if retry.Body, err = r.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// authorized returns a copy of r carrying token; RoundTrippers must not
+// modify the caller's request.
+func authorized(r *http.Request, token string) *http.Request {
+	r2 := r.Clone(r.Context())
+	r2.Header.Set("Authorization", "Bearer "+token)
+	return r2
+}
+
+// closeBody closes the request body, as RoundTrippers must even on errors.
+func closeBody(r *http.Request) {
+	if r.Body != nil {
+		_ = r.Body.Close()
+	}
+}
+
+// CredentialsOption configures PerRPCCredentials.
+type CredentialsOption func(*rpcCredentials)
+
+// AllowInsecure lets the credentials be sent over connections without
+// transport security, e.g. to a local development server.
+func AllowInsecure() CredentialsOption {
+	return func(c *rpcCredentials) { c.insecure = true }
+}
+
+// PerRPCCredentials returns gRPC credentials that attach a Bearer token for
+// req from GetToken to every call, for use with grpc.WithPerRPCCredentials.
+// By default they are only sent over secure connections (see AllowInsecure).
+//
+//	conn, err := grpc.NewClient(target,
+//	    grpc.WithTransportCredentials(credentials.NewTLS(nil)),
+//	    grpc.WithPerRPCCredentials(ex.PerRPCCredentials(iam.TokenRequest{})))
+func (e *Exchanger) PerRPCCredentials(req iam.TokenRequest, opts ...CredentialsOption) credentials.PerRPCCredentials {
+	c := &rpcCredentials{exchanger: e, req: req}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+type rpcCredentials struct {
+	exchanger *Exchanger
+	req       iam.TokenRequest
+	insecure  bool
+}
+
+func (c *rpcCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := c.exchanger.GetToken(ctx, c.req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: obtaining token for RPC: %w", err)
+	}
+	return map[string]string{"authorization": "Bearer " + token.AccessToken}, nil
+}
+
+func (c *rpcCredentials) RequireTransportSecurity() bool {
+	return !c.insecure
+}
@@ -0,0 +1,129 @@
+package oauth2_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/oauth2"
+)
+
+// newSequentialTokenServer issues tok-1, tok-2, ... valid for an hour.
+func newSequentialTokenServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var n atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("tok-%d", n.Add(1)),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newResourceServer rejects tok-1, as if it had been revoked, and echoes
+// the request body for other tokens.
+func newResourceServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("Authorization") == "Bearer tok-1" {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s %s", r.Header.Get("Authorization"), body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTransport_RetriesOnceOn401(t *testing.T) {
+	e := oauth2.New("app_test", "secret_test", newSequentialTokenServer(t).URL, nil)
+	var calls atomic.Int32
+	api := newResourceServer(t, &calls)
+	client := &http.Client{Transport: e.Transport(iam.TokenRequest{Audience: "api"}, nil)}
+
+	req, _ := http.NewRequest("POST", api.URL, strings.NewReader("payload"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || string(body) != "Bearer tok-2 payload" {
+		t.Errorf("got %d %q, want 200 with tok-2 and the replayed body", resp.StatusCode, body)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("the caller's request must not be modified")
+	}
+
+	// The fresh token is cached
+	resp, err = client.Get(api.URL)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if calls.Load() != 3 {
+		t.Errorf("resource server was called %d times, want 3", calls.Load())
+	}
+}
+
+func TestTransport_NonReplayableBody(t *testing.T) {
+	e := oauth2.New("app_test", "secret_test", newSequentialTokenServer(t).URL, nil)
+	var calls atomic.Int32
+	api := newResourceServer(t, &calls)
+	client := &http.Client{Transport: e.Transport(iam.TokenRequest{}, nil)}
+
+	// A body without GetBody cannot be sent twice
+	req, _ := http.NewRequest("POST", api.URL, io.NopCloser(strings.NewReader("payload")))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized || calls.Load() != 1 {
+		t.Errorf("got %d after %d calls, want 401 after 1", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestTransport_TokenError(t *testing.T) {
+	e := oauth2.New("app_test", "secret_test", "http://127.0.0.1:1/token", nil, oauth2.WithRetry(1))
+	client := &http.Client{Transport: e.Transport(iam.TokenRequest{}, nil)}
+
+	if _, err := client.Get("http://127.0.0.1:1/api"); err == nil {
+		t.Error("expected error when no token can be obtained")
+	}
+}
+
+func TestPerRPCCredentials(t *testing.T) {
+	e := oauth2.New("app_test", "secret_test", newSequentialTokenServer(t).URL, nil)
+
+	creds := e.PerRPCCredentials(iam.TokenRequest{Audience: "iam"})
+	md, err := creds.GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("GetRequestMetadata() error: %v", err)
+	}
+	if md["authorization"] != "Bearer tok-1" {
+		t.Errorf("authorization = %q, want %q", md["authorization"], "Bearer tok-1")
+	}
+	if !creds.RequireTransportSecurity() {
+		t.Error("credentials should require transport security by default")
+	}
+	if e.PerRPCCredentials(iam.TokenRequest{}, oauth2.AllowInsecure()).RequireTransportSecurity() {
+		t.Error("AllowInsecure should lift the transport security requirement")
+	}
+}
@@ -9,6 +9,8 @@ package iamv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -311,9 +313,22 @@ func (x *GetUserRequest) GetUserId() string {
 }
 
 type ListUsersRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deprecated: offset paging skips or repeats rows under concurrent writes.
+	// Ignored when page_token is set.
+	Page     int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque cursor from a previous ListUsersResponse.next_page_token. Only
+	// valid with the same filters and order_by.
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Filters; empty fields match everything.
+	Role        string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"` // role ID or name
+	TenantId    string `protobuf:"bytes,5,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Status      string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	EmailPrefix string `protobuf:"bytes,7,opt,name=email_prefix,json=emailPrefix,proto3" json:"email_prefix,omitempty"` // case-insensitive
+	// Sort field: "id" (default), "email" or "name", optionally followed by
+	// " desc".
+	OrderBy       string `protobuf:"bytes,8,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -362,10 +377,55 @@ func (x *ListUsersRequest) GetPageSize() int32 {
 	return 0
 }
 
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetEmailPrefix() string {
+	if x != nil {
+		return x.EmailPrefix
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
 type ListUsersResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// Total number of users matching the filters, across all pages.
+	Total int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// Cursor for the next page; empty on the last page.
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -414,6 +474,13 @@ func (x *ListUsersResponse) GetTotal() int32 {
 	return 0
 }
 
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type GetUserRolesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -490,40 +557,2103 @@ func (x *GetUserRolesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserRolesResponse.ProtoReflect.Descriptor instead.
-func (*GetUserRolesResponse) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use GetUserRolesResponse.ProtoReflect.Descriptor instead.
+func (*GetUserRolesResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetUserRolesResponse) GetRoles() []*Role {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type BatchGetUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetUsersRequest) Reset() {
+	*x = BatchGetUsersRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetUsersRequest) ProtoMessage() {}
+
+func (x *BatchGetUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetUsersRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetUsersRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchGetUsersRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type BatchGetUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetUsersResponse) Reset() {
+	*x = BatchGetUsersResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetUsersResponse) ProtoMessage() {}
+
+func (x *BatchGetUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetUsersResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetUsersResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchGetUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	TenantId      string                 `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	RoleIds       []string               `protobuf:"bytes,4,rep,name=role_ids,json=roleIds,proto3" json:"role_ids,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SendInvite    bool                   `protobuf:"varint,6,opt,name=send_invite,json=sendInvite,proto3" json:"send_invite,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRoleIds() []string {
+	if x != nil {
+		return x.RoleIds
+	}
+	return nil
+}
+
+func (x *CreateUserRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CreateUserRequest) GetSendInvite() bool {
+	if x != nil {
+		return x.SendInvite
+	}
+	return false
+}
+
+type UpdateUserRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	UserId   string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email    string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name     string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Paths to update: "email", "name", "metadata".
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,5,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DisableUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableUserRequest) Reset() {
+	*x = DisableUserRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableUserRequest) ProtoMessage() {}
+
+func (x *DisableUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableUserRequest.ProtoReflect.Descriptor instead.
+func (*DisableUserRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DisableUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DisableUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type EnableUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableUserRequest) Reset() {
+	*x = EnableUserRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableUserRequest) ProtoMessage() {}
+
+func (x *EnableUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableUserRequest.ProtoReflect.Descriptor instead.
+func (*EnableUserRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EnableUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserResponse) Reset() {
+	*x = DeleteUserResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserResponse) ProtoMessage() {}
+
+func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{17}
+}
+
+type AssignRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RoleId        string                 `protobuf:"bytes,2,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRoleRequest) Reset() {
+	*x = AssignRoleRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleRequest) ProtoMessage() {}
+
+func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleRequest.ProtoReflect.Descriptor instead.
+func (*AssignRoleRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AssignRoleRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetRoleId() string {
+	if x != nil {
+		return x.RoleId
+	}
+	return ""
+}
+
+type RemoveRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RoleId        string                 `protobuf:"bytes,2,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRoleRequest) Reset() {
+	*x = RemoveRoleRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRoleRequest) ProtoMessage() {}
+
+func (x *RemoveRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRoleRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRoleRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RemoveRoleRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RemoveRoleRequest) GetRoleId() string {
+	if x != nil {
+		return x.RoleId
+	}
+	return ""
+}
+
+type ListRolesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TenantId string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Restricts the listing to the role with this ID or name.
+	Role string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	// Number of roles to skip.
+	Offset int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Maximum number of roles to return; the server applies a default when 0.
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolesRequest) Reset() {
+	*x = ListRolesRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolesRequest) ProtoMessage() {}
+
+func (x *ListRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolesRequest.ProtoReflect.Descriptor instead.
+func (*ListRolesRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListRolesRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListRolesRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ListRolesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListRolesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListRolesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Roles []*Role                `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	// Total number of roles matching the request, across all pages.
+	Total         int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolesResponse) Reset() {
+	*x = ListRolesResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolesResponse) ProtoMessage() {}
+
+func (x *ListRolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolesResponse.ProtoReflect.Descriptor instead.
+func (*ListRolesResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListRolesResponse) GetRoles() []*Role {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *ListRolesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ResolveTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveTenantRequest) Reset() {
+	*x = ResolveTenantRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveTenantRequest) ProtoMessage() {}
+
+func (x *ResolveTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveTenantRequest.ProtoReflect.Descriptor instead.
+func (*ResolveTenantRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ResolveTenantRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+type ValidateMembershipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateMembershipRequest) Reset() {
+	*x = ValidateMembershipRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateMembershipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateMembershipRequest) ProtoMessage() {}
+
+func (x *ValidateMembershipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateMembershipRequest.ProtoReflect.Descriptor instead.
+func (*ValidateMembershipRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ValidateMembershipRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ValidateMembershipRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type ValidateMembershipResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsMember      bool                   `protobuf:"varint,1,opt,name=is_member,json=isMember,proto3" json:"is_member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateMembershipResponse) Reset() {
+	*x = ValidateMembershipResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateMembershipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateMembershipResponse) ProtoMessage() {}
+
+func (x *ValidateMembershipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateMembershipResponse.ProtoReflect.Descriptor instead.
+func (*ValidateMembershipResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ValidateMembershipResponse) GetIsMember() bool {
+	if x != nil {
+		return x.IsMember
+	}
+	return false
+}
+
+type ListMembershipsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMembershipsRequest) Reset() {
+	*x = ListMembershipsRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMembershipsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMembershipsRequest) ProtoMessage() {}
+
+func (x *ListMembershipsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMembershipsRequest.ProtoReflect.Descriptor instead.
+func (*ListMembershipsRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListMembershipsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListMembershipsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Memberships   []*Membership          `protobuf:"bytes,1,rep,name=memberships,proto3" json:"memberships,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMembershipsResponse) Reset() {
+	*x = ListMembershipsResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMembershipsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMembershipsResponse) ProtoMessage() {}
+
+func (x *ListMembershipsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMembershipsResponse.ProtoReflect.Descriptor instead.
+func (*ListMembershipsResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListMembershipsResponse) GetMemberships() []*Membership {
+	if x != nil {
+		return x.Memberships
+	}
+	return nil
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListSessionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type RevokeSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionRequest) ProtoMessage() {}
+
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RevokeSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type RevokeSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionResponse) Reset() {
+	*x = RevokeSessionResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionResponse) ProtoMessage() {}
+
+func (x *RevokeSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{30}
+}
+
+type RevokeAllOtherSessionsRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserId           string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CurrentSessionId string                 `protobuf:"bytes,2,opt,name=current_session_id,json=currentSessionId,proto3" json:"current_session_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RevokeAllOtherSessionsRequest) Reset() {
+	*x = RevokeAllOtherSessionsRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllOtherSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllOtherSessionsRequest) ProtoMessage() {}
+
+func (x *RevokeAllOtherSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllOtherSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAllOtherSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RevokeAllOtherSessionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RevokeAllOtherSessionsRequest) GetCurrentSessionId() string {
+	if x != nil {
+		return x.CurrentSessionId
+	}
+	return ""
+}
+
+type RevokeAllOtherSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAllOtherSessionsResponse) Reset() {
+	*x = RevokeAllOtherSessionsResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllOtherSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllOtherSessionsResponse) ProtoMessage() {}
+
+func (x *RevokeAllOtherSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllOtherSessionsResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAllOtherSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{32}
+}
+
+type ValidateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateSessionRequest) Reset() {
+	*x = ValidateSessionRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateSessionRequest) ProtoMessage() {}
+
+func (x *ValidateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateSessionRequest.ProtoReflect.Descriptor instead.
+func (*ValidateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ValidateSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type ValidateSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Session       *Session               `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // e.g. "revoked", "expired" when valid is false
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateSessionResponse) Reset() {
+	*x = ValidateSessionResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateSessionResponse) ProtoMessage() {}
+
+func (x *ValidateSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateSessionResponse.ProtoReflect.Descriptor instead.
+func (*ValidateSessionResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ValidateSessionResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateSessionResponse) GetSession() *Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *ValidateSessionResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type TouchSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TouchSessionRequest) Reset() {
+	*x = TouchSessionRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TouchSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TouchSessionRequest) ProtoMessage() {}
+
+func (x *TouchSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TouchSessionRequest.ProtoReflect.Descriptor instead.
+func (*TouchSessionRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *TouchSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type TouchSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TouchSessionResponse) Reset() {
+	*x = TouchSessionResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TouchSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TouchSessionResponse) ProtoMessage() {}
+
+func (x *TouchSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TouchSessionResponse.ProtoReflect.Descriptor instead.
+func (*TouchSessionResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{36}
+}
+
+type ListDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesRequest) ProtoMessage() {}
+
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListDevicesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type RenameDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameDeviceRequest) Reset() {
+	*x = RenameDeviceRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameDeviceRequest) ProtoMessage() {}
+
+func (x *RenameDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameDeviceRequest.ProtoReflect.Descriptor instead.
+func (*RenameDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RenameDeviceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *RenameDeviceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SetDeviceTrustRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Trusted       bool                   `protobuf:"varint,2,opt,name=trusted,proto3" json:"trusted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDeviceTrustRequest) Reset() {
+	*x = SetDeviceTrustRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDeviceTrustRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDeviceTrustRequest) ProtoMessage() {}
+
+func (x *SetDeviceTrustRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDeviceTrustRequest.ProtoReflect.Descriptor instead.
+func (*SetDeviceTrustRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SetDeviceTrustRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *SetDeviceTrustRequest) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
+type APIKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,5,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Scopes        []string               `protobuf:"bytes,6,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`     // unset if the key does not expire
+	RevokedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`     // unset unless revoked
+	SecretHash    string                 `protobuf:"bytes,10,opt,name=secret_hash,json=secretHash,proto3" json:"secret_hash,omitempty"` // only set by LookupAPIKey
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_iam_v1_iam_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *APIKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *APIKey) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *APIKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *APIKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetSecretHash() string {
+	if x != nil {
+		return x.SecretHash
+	}
+	return ""
+}
+
+type CreateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Scopes        []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type CreateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"` // the full key; cannot be retrieved again
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListAPIKeysRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RevokeAPIKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+type RotateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyRequest) Reset() {
+	*x = RotateAPIKeyRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyRequest) ProtoMessage() {}
+
+func (x *RotateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RotateAPIKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+type LookupAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupAPIKeyRequest) Reset() {
+	*x = LookupAPIKeyRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupAPIKeyRequest) ProtoMessage() {}
+
+func (x *LookupAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*LookupAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *LookupAPIKeyRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type ServiceAccount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ClientId      string                 `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Bindings      []*RoleBinding         `protobuf:"bytes,6,rep,name=bindings,proto3" json:"bindings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceAccount) Reset() {
+	*x = ServiceAccount{}
+	mi := &file_iam_v1_iam_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceAccount) ProtoMessage() {}
+
+func (x *ServiceAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceAccount.ProtoReflect.Descriptor instead.
+func (*ServiceAccount) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ServiceAccount) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
 }
 
-func (x *GetUserRolesResponse) GetRoles() []*Role {
+func (x *ServiceAccount) GetBindings() []*RoleBinding {
 	if x != nil {
-		return x.Roles
+		return x.Bindings
 	}
 	return nil
 }
 
-type ResolveTenantRequest struct {
+// RoleBinding grants a service account a role in one tenant.
+type RoleBinding struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Role          *Role                  `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResolveTenantRequest) Reset() {
-	*x = ResolveTenantRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[10]
+func (x *RoleBinding) Reset() {
+	*x = RoleBinding{}
+	mi := &file_iam_v1_iam_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResolveTenantRequest) String() string {
+func (x *RoleBinding) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResolveTenantRequest) ProtoMessage() {}
+func (*RoleBinding) ProtoMessage() {}
 
-func (x *ResolveTenantRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[10]
+func (x *RoleBinding) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -534,41 +2664,49 @@ func (x *ResolveTenantRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResolveTenantRequest.ProtoReflect.Descriptor instead.
-func (*ResolveTenantRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use RoleBinding.ProtoReflect.Descriptor instead.
+func (*RoleBinding) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *ResolveTenantRequest) GetIdentifier() string {
+func (x *RoleBinding) GetTenantId() string {
 	if x != nil {
-		return x.Identifier
+		return x.TenantId
 	}
 	return ""
 }
 
-type ValidateMembershipRequest struct {
+func (x *RoleBinding) GetRole() *Role {
+	if x != nil {
+		return x.Role
+	}
+	return nil
+}
+
+type CreateServiceAccountRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	ClientId      string                 `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"` // empty to let the server register a client
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ValidateMembershipRequest) Reset() {
-	*x = ValidateMembershipRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[11]
+func (x *CreateServiceAccountRequest) Reset() {
+	*x = CreateServiceAccountRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateMembershipRequest) String() string {
+func (x *CreateServiceAccountRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateMembershipRequest) ProtoMessage() {}
+func (*CreateServiceAccountRequest) ProtoMessage() {}
 
-func (x *ValidateMembershipRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[11]
+func (x *CreateServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -579,47 +2717,54 @@ func (x *ValidateMembershipRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateMembershipRequest.ProtoReflect.Descriptor instead.
-func (*ValidateMembershipRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use CreateServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *ValidateMembershipRequest) GetUserId() string {
+func (x *CreateServiceAccountRequest) GetName() string {
 	if x != nil {
-		return x.UserId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ValidateMembershipRequest) GetTenantId() string {
+func (x *CreateServiceAccountRequest) GetDescription() string {
 	if x != nil {
-		return x.TenantId
+		return x.Description
 	}
 	return ""
 }
 
-type ValidateMembershipResponse struct {
+func (x *CreateServiceAccountRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type GetServiceAccountRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IsMember      bool                   `protobuf:"varint,1,opt,name=is_member,json=isMember,proto3" json:"is_member,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ValidateMembershipResponse) Reset() {
-	*x = ValidateMembershipResponse{}
-	mi := &file_iam_v1_iam_proto_msgTypes[12]
+func (x *GetServiceAccountRequest) Reset() {
+	*x = GetServiceAccountRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateMembershipResponse) String() string {
+func (x *GetServiceAccountRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateMembershipResponse) ProtoMessage() {}
+func (*GetServiceAccountRequest) ProtoMessage() {}
 
-func (x *ValidateMembershipResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[12]
+func (x *GetServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,40 +2775,40 @@ func (x *ValidateMembershipResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateMembershipResponse.ProtoReflect.Descriptor instead.
-func (*ValidateMembershipResponse) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use GetServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *ValidateMembershipResponse) GetIsMember() bool {
+func (x *GetServiceAccountRequest) GetId() string {
 	if x != nil {
-		return x.IsMember
+		return x.Id
 	}
-	return false
+	return ""
 }
 
-type ListSessionsRequest struct {
+type ListServiceAccountsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListSessionsRequest) Reset() {
-	*x = ListSessionsRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[13]
+func (x *ListServiceAccountsRequest) Reset() {
+	*x = ListServiceAccountsRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSessionsRequest) String() string {
+func (x *ListServiceAccountsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSessionsRequest) ProtoMessage() {}
+func (*ListServiceAccountsRequest) ProtoMessage() {}
 
-func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[13]
+func (x *ListServiceAccountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -674,40 +2819,40 @@ func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
-func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ListServiceAccountsRequest.ProtoReflect.Descriptor instead.
+func (*ListServiceAccountsRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *ListSessionsRequest) GetUserId() string {
+func (x *ListServiceAccountsRequest) GetTenantId() string {
 	if x != nil {
-		return x.UserId
+		return x.TenantId
 	}
 	return ""
 }
 
-type ListSessionsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ListServiceAccountsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccounts []*ServiceAccount      `protobuf:"bytes,1,rep,name=service_accounts,json=serviceAccounts,proto3" json:"service_accounts,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ListSessionsResponse) Reset() {
-	*x = ListSessionsResponse{}
-	mi := &file_iam_v1_iam_proto_msgTypes[14]
+func (x *ListServiceAccountsResponse) Reset() {
+	*x = ListServiceAccountsResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSessionsResponse) String() string {
+func (x *ListServiceAccountsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSessionsResponse) ProtoMessage() {}
+func (*ListServiceAccountsResponse) ProtoMessage() {}
 
-func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[14]
+func (x *ListServiceAccountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -718,40 +2863,40 @@ func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
-func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ListServiceAccountsResponse.ProtoReflect.Descriptor instead.
+func (*ListServiceAccountsResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *ListSessionsResponse) GetSessions() []*Session {
+func (x *ListServiceAccountsResponse) GetServiceAccounts() []*ServiceAccount {
 	if x != nil {
-		return x.Sessions
+		return x.ServiceAccounts
 	}
 	return nil
 }
 
-type RevokeSessionRequest struct {
+type DeleteServiceAccountRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RevokeSessionRequest) Reset() {
-	*x = RevokeSessionRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[15]
+func (x *DeleteServiceAccountRequest) Reset() {
+	*x = DeleteServiceAccountRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RevokeSessionRequest) String() string {
+func (x *DeleteServiceAccountRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokeSessionRequest) ProtoMessage() {}
+func (*DeleteServiceAccountRequest) ProtoMessage() {}
 
-func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[15]
+func (x *DeleteServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -762,39 +2907,39 @@ func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
-func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use DeleteServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *RevokeSessionRequest) GetSessionId() string {
+func (x *DeleteServiceAccountRequest) GetId() string {
 	if x != nil {
-		return x.SessionId
+		return x.Id
 	}
 	return ""
 }
 
-type RevokeSessionResponse struct {
+type DeleteServiceAccountResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RevokeSessionResponse) Reset() {
-	*x = RevokeSessionResponse{}
-	mi := &file_iam_v1_iam_proto_msgTypes[16]
+func (x *DeleteServiceAccountResponse) Reset() {
+	*x = DeleteServiceAccountResponse{}
+	mi := &file_iam_v1_iam_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RevokeSessionResponse) String() string {
+func (x *DeleteServiceAccountResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokeSessionResponse) ProtoMessage() {}
+func (*DeleteServiceAccountResponse) ProtoMessage() {}
 
-func (x *RevokeSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[16]
+func (x *DeleteServiceAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -805,34 +2950,35 @@ func (x *RevokeSessionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokeSessionResponse.ProtoReflect.Descriptor instead.
-func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use DeleteServiceAccountResponse.ProtoReflect.Descriptor instead.
+func (*DeleteServiceAccountResponse) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{56}
 }
 
-type RevokeAllOtherSessionsRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	UserId           string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	CurrentSessionId string                 `protobuf:"bytes,2,opt,name=current_session_id,json=currentSessionId,proto3" json:"current_session_id,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+type BindServiceAccountRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	RoleId        string                 `protobuf:"bytes,3,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RevokeAllOtherSessionsRequest) Reset() {
-	*x = RevokeAllOtherSessionsRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[17]
+func (x *BindServiceAccountRoleRequest) Reset() {
+	*x = BindServiceAccountRoleRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RevokeAllOtherSessionsRequest) String() string {
+func (x *BindServiceAccountRoleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokeAllOtherSessionsRequest) ProtoMessage() {}
+func (*BindServiceAccountRoleRequest) ProtoMessage() {}
 
-func (x *RevokeAllOtherSessionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[17]
+func (x *BindServiceAccountRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -843,46 +2989,56 @@ func (x *RevokeAllOtherSessionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokeAllOtherSessionsRequest.ProtoReflect.Descriptor instead.
-func (*RevokeAllOtherSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use BindServiceAccountRoleRequest.ProtoReflect.Descriptor instead.
+func (*BindServiceAccountRoleRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *RevokeAllOtherSessionsRequest) GetUserId() string {
+func (x *BindServiceAccountRoleRequest) GetId() string {
 	if x != nil {
-		return x.UserId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *RevokeAllOtherSessionsRequest) GetCurrentSessionId() string {
+func (x *BindServiceAccountRoleRequest) GetTenantId() string {
 	if x != nil {
-		return x.CurrentSessionId
+		return x.TenantId
 	}
 	return ""
 }
 
-type RevokeAllOtherSessionsResponse struct {
+func (x *BindServiceAccountRoleRequest) GetRoleId() string {
+	if x != nil {
+		return x.RoleId
+	}
+	return ""
+}
+
+type UnbindServiceAccountRoleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	RoleId        string                 `protobuf:"bytes,3,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RevokeAllOtherSessionsResponse) Reset() {
-	*x = RevokeAllOtherSessionsResponse{}
-	mi := &file_iam_v1_iam_proto_msgTypes[18]
+func (x *UnbindServiceAccountRoleRequest) Reset() {
+	*x = UnbindServiceAccountRoleRequest{}
+	mi := &file_iam_v1_iam_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RevokeAllOtherSessionsResponse) String() string {
+func (x *UnbindServiceAccountRoleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokeAllOtherSessionsResponse) ProtoMessage() {}
+func (*UnbindServiceAccountRoleRequest) ProtoMessage() {}
 
-func (x *RevokeAllOtherSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[18]
+func (x *UnbindServiceAccountRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -893,9 +3049,30 @@ func (x *RevokeAllOtherSessionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokeAllOtherSessionsResponse.ProtoReflect.Descriptor instead.
-func (*RevokeAllOtherSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use UnbindServiceAccountRoleRequest.ProtoReflect.Descriptor instead.
+func (*UnbindServiceAccountRoleRequest) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *UnbindServiceAccountRoleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UnbindServiceAccountRoleRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *UnbindServiceAccountRoleRequest) GetRoleId() string {
+	if x != nil {
+		return x.RoleId
+	}
+	return ""
 }
 
 type CreateSecretRequest struct {
@@ -907,7 +3084,7 @@ type CreateSecretRequest struct {
 
 func (x *CreateSecretRequest) Reset() {
 	*x = CreateSecretRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[19]
+	mi := &file_iam_v1_iam_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -919,7 +3096,7 @@ func (x *CreateSecretRequest) String() string {
 func (*CreateSecretRequest) ProtoMessage() {}
 
 func (x *CreateSecretRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[19]
+	mi := &file_iam_v1_iam_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -932,7 +3109,7 @@ func (x *CreateSecretRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateSecretRequest.ProtoReflect.Descriptor instead.
 func (*CreateSecretRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{19}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *CreateSecretRequest) GetDescription() string {
@@ -951,7 +3128,7 @@ type ListSecretsRequest struct {
 
 func (x *ListSecretsRequest) Reset() {
 	*x = ListSecretsRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[20]
+	mi := &file_iam_v1_iam_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -963,7 +3140,7 @@ func (x *ListSecretsRequest) String() string {
 func (*ListSecretsRequest) ProtoMessage() {}
 
 func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[20]
+	mi := &file_iam_v1_iam_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -976,7 +3153,7 @@ func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSecretsRequest.ProtoReflect.Descriptor instead.
 func (*ListSecretsRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{20}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *ListSecretsRequest) GetUserId() string {
@@ -995,7 +3172,7 @@ type ListSecretsResponse struct {
 
 func (x *ListSecretsResponse) Reset() {
 	*x = ListSecretsResponse{}
-	mi := &file_iam_v1_iam_proto_msgTypes[21]
+	mi := &file_iam_v1_iam_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1007,7 +3184,7 @@ func (x *ListSecretsResponse) String() string {
 func (*ListSecretsResponse) ProtoMessage() {}
 
 func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[21]
+	mi := &file_iam_v1_iam_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1020,7 +3197,7 @@ func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSecretsResponse.ProtoReflect.Descriptor instead.
 func (*ListSecretsResponse) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{21}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *ListSecretsResponse) GetSecrets() []*Secret {
@@ -1039,7 +3216,7 @@ type DeleteSecretRequest struct {
 
 func (x *DeleteSecretRequest) Reset() {
 	*x = DeleteSecretRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[22]
+	mi := &file_iam_v1_iam_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1051,7 +3228,7 @@ func (x *DeleteSecretRequest) String() string {
 func (*DeleteSecretRequest) ProtoMessage() {}
 
 func (x *DeleteSecretRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[22]
+	mi := &file_iam_v1_iam_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1064,7 +3241,7 @@ func (x *DeleteSecretRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSecretRequest.ProtoReflect.Descriptor instead.
 func (*DeleteSecretRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{22}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{62}
 }
 
 func (x *DeleteSecretRequest) GetSecretId() string {
@@ -1082,7 +3259,7 @@ type DeleteSecretResponse struct {
 
 func (x *DeleteSecretResponse) Reset() {
 	*x = DeleteSecretResponse{}
-	mi := &file_iam_v1_iam_proto_msgTypes[23]
+	mi := &file_iam_v1_iam_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1094,7 +3271,7 @@ func (x *DeleteSecretResponse) String() string {
 func (*DeleteSecretResponse) ProtoMessage() {}
 
 func (x *DeleteSecretResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[23]
+	mi := &file_iam_v1_iam_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1107,7 +3284,7 @@ func (x *DeleteSecretResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSecretResponse.ProtoReflect.Descriptor instead.
 func (*DeleteSecretResponse) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{23}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{63}
 }
 
 type VerifySecretRequest struct {
@@ -1120,7 +3297,7 @@ type VerifySecretRequest struct {
 
 func (x *VerifySecretRequest) Reset() {
 	*x = VerifySecretRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[24]
+	mi := &file_iam_v1_iam_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1132,7 +3309,7 @@ func (x *VerifySecretRequest) String() string {
 func (*VerifySecretRequest) ProtoMessage() {}
 
 func (x *VerifySecretRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[24]
+	mi := &file_iam_v1_iam_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1145,7 +3322,7 @@ func (x *VerifySecretRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifySecretRequest.ProtoReflect.Descriptor instead.
 func (*VerifySecretRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{24}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *VerifySecretRequest) GetApiKey() string {
@@ -1171,7 +3348,7 @@ type VerifySecretResponse struct {
 
 func (x *VerifySecretResponse) Reset() {
 	*x = VerifySecretResponse{}
-	mi := &file_iam_v1_iam_proto_msgTypes[25]
+	mi := &file_iam_v1_iam_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1183,7 +3360,7 @@ func (x *VerifySecretResponse) String() string {
 func (*VerifySecretResponse) ProtoMessage() {}
 
 func (x *VerifySecretResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[25]
+	mi := &file_iam_v1_iam_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1196,7 +3373,7 @@ func (x *VerifySecretResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifySecretResponse.ProtoReflect.Descriptor instead.
 func (*VerifySecretResponse) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{25}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *VerifySecretResponse) GetClaims() *Claims {
@@ -1215,7 +3392,7 @@ type RotateSecretRequest struct {
 
 func (x *RotateSecretRequest) Reset() {
 	*x = RotateSecretRequest{}
-	mi := &file_iam_v1_iam_proto_msgTypes[26]
+	mi := &file_iam_v1_iam_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1227,7 +3404,7 @@ func (x *RotateSecretRequest) String() string {
 func (*RotateSecretRequest) ProtoMessage() {}
 
 func (x *RotateSecretRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[26]
+	mi := &file_iam_v1_iam_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1240,7 +3417,7 @@ func (x *RotateSecretRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RotateSecretRequest.ProtoReflect.Descriptor instead.
 func (*RotateSecretRequest) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{26}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *RotateSecretRequest) GetSecretId() string {
@@ -1250,6 +3427,77 @@ func (x *RotateSecretRequest) GetSecretId() string {
 	return ""
 }
 
+// Rule declares the authorization requirements of an RPC method. The rule
+// interceptors (grpcmw.UnaryRules, kratosmw.Rules) enforce it and deny
+// methods that have none:
+//
+//	rpc CreateOrder(CreateOrderRequest) returns (Order) {
+//	  option (iam.v1.rule) = { permission: "orders:write", tenant_required: true };
+//	}
+type Rule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Permission the caller must hold, e.g. "orders:write". Empty requires
+	// authentication only.
+	Permission string `protobuf:"bytes,1,opt,name=permission,proto3" json:"permission,omitempty"`
+	// Whether the caller must be a member of the request's tenant.
+	TenantRequired bool `protobuf:"varint,2,opt,name=tenant_required,json=tenantRequired,proto3" json:"tenant_required,omitempty"`
+	// Public methods skip authentication; the other fields are ignored.
+	Public        bool `protobuf:"varint,3,opt,name=public,proto3" json:"public,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_iam_v1_iam_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *Rule) GetPermission() string {
+	if x != nil {
+		return x.Permission
+	}
+	return ""
+}
+
+func (x *Rule) GetTenantRequired() bool {
+	if x != nil {
+		return x.TenantRequired
+	}
+	return false
+}
+
+func (x *Rule) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
 // Claims contains the standard claims extracted from a verified token.
 type Claims struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1267,7 +3515,7 @@ type Claims struct {
 
 func (x *Claims) Reset() {
 	*x = Claims{}
-	mi := &file_iam_v1_iam_proto_msgTypes[27]
+	mi := &file_iam_v1_iam_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1279,7 +3527,7 @@ func (x *Claims) String() string {
 func (*Claims) ProtoMessage() {}
 
 func (x *Claims) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[27]
+	mi := &file_iam_v1_iam_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1292,7 +3540,7 @@ func (x *Claims) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Claims.ProtoReflect.Descriptor instead.
 func (*Claims) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{27}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{68}
 }
 
 func (x *Claims) GetSubject() string {
@@ -1360,13 +3608,14 @@ type User struct {
 	TenantId      string                 `protobuf:"bytes,4,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
 	Roles         []*Role                `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
 	Metadata      map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"` // "active", "disabled"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *User) Reset() {
 	*x = User{}
-	mi := &file_iam_v1_iam_proto_msgTypes[28]
+	mi := &file_iam_v1_iam_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1378,7 +3627,7 @@ func (x *User) String() string {
 func (*User) ProtoMessage() {}
 
 func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[28]
+	mi := &file_iam_v1_iam_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1391,7 +3640,7 @@ func (x *User) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use User.ProtoReflect.Descriptor instead.
 func (*User) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{28}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *User) GetId() string {
@@ -1436,6 +3685,13 @@ func (x *User) GetMetadata() map[string]string {
 	return nil
 }
 
+func (x *User) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 // Role represents a named role assigned to a user.
 type Role struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1447,7 +3703,7 @@ type Role struct {
 
 func (x *Role) Reset() {
 	*x = Role{}
-	mi := &file_iam_v1_iam_proto_msgTypes[29]
+	mi := &file_iam_v1_iam_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1459,7 +3715,7 @@ func (x *Role) String() string {
 func (*Role) ProtoMessage() {}
 
 func (x *Role) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[29]
+	mi := &file_iam_v1_iam_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1472,7 +3728,7 @@ func (x *Role) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Role.ProtoReflect.Descriptor instead.
 func (*Role) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{29}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *Role) GetId() string {
@@ -1502,7 +3758,7 @@ type Tenant struct {
 
 func (x *Tenant) Reset() {
 	*x = Tenant{}
-	mi := &file_iam_v1_iam_proto_msgTypes[30]
+	mi := &file_iam_v1_iam_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1514,7 +3770,7 @@ func (x *Tenant) String() string {
 func (*Tenant) ProtoMessage() {}
 
 func (x *Tenant) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[30]
+	mi := &file_iam_v1_iam_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1527,7 +3783,7 @@ func (x *Tenant) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
 func (*Tenant) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{30}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *Tenant) GetId() string {
@@ -1558,6 +3814,75 @@ func (x *Tenant) GetStatus() string {
 	return ""
 }
 
+// Membership represents a user's membership in a tenant.
+type Membership struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Role          *Role                  `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	JoinedAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Membership) Reset() {
+	*x = Membership{}
+	mi := &file_iam_v1_iam_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Membership) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Membership) ProtoMessage() {}
+
+func (x *Membership) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Membership.ProtoReflect.Descriptor instead.
+func (*Membership) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *Membership) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *Membership) GetRole() *Role {
+	if x != nil {
+		return x.Role
+	}
+	return nil
+}
+
+func (x *Membership) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Membership) GetJoinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return nil
+}
+
 // Session represents an active user session.
 type Session struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1567,13 +3892,19 @@ type Session struct {
 	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	UserAgent     string                 `protobuf:"bytes,5,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
 	Ip            string                 `protobuf:"bytes,6,opt,name=ip,proto3" json:"ip,omitempty"`
+	LastActiveAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,8,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Device        string                 `protobuf:"bytes,9,opt,name=device,proto3" json:"device,omitempty"` // e.g. "desktop", "mobile", "tablet"
+	Os            string                 `protobuf:"bytes,10,opt,name=os,proto3" json:"os,omitempty"`
+	Browser       string                 `protobuf:"bytes,11,opt,name=browser,proto3" json:"browser,omitempty"`
+	Location      *Location              `protobuf:"bytes,12,opt,name=location,proto3" json:"location,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Session) Reset() {
 	*x = Session{}
-	mi := &file_iam_v1_iam_proto_msgTypes[31]
+	mi := &file_iam_v1_iam_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1585,7 +3916,7 @@ func (x *Session) String() string {
 func (*Session) ProtoMessage() {}
 
 func (x *Session) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[31]
+	mi := &file_iam_v1_iam_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1598,7 +3929,7 @@ func (x *Session) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Session.ProtoReflect.Descriptor instead.
 func (*Session) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{31}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{73}
 }
 
 func (x *Session) GetId() string {
@@ -1643,6 +3974,234 @@ func (x *Session) GetIp() string {
 	return ""
 }
 
+func (x *Session) GetLastActiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActiveAt
+	}
+	return nil
+}
+
+func (x *Session) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Session) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *Session) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *Session) GetBrowser() string {
+	if x != nil {
+		return x.Browser
+	}
+	return ""
+}
+
+func (x *Session) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+// Location is the approximate geographic origin of a session.
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Country       string                 `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,4,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,5,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_iam_v1_iam_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *Location) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Location) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Location) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Location) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Location) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+// Device is a client a user has signed in from.
+type Device struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Trusted       bool                   `protobuf:"varint,4,opt,name=trusted,proto3" json:"trusted,omitempty"`
+	Device        string                 `protobuf:"bytes,5,opt,name=device,proto3" json:"device,omitempty"`
+	Os            string                 `protobuf:"bytes,6,opt,name=os,proto3" json:"os,omitempty"`
+	Browser       string                 `protobuf:"bytes,7,opt,name=browser,proto3" json:"browser,omitempty"`
+	FirstSeenAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=first_seen_at,json=firstSeenAt,proto3" json:"first_seen_at,omitempty"`
+	LastSeenAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_iam_v1_iam_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_iam_v1_iam_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *Device) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Device) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Device) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Device) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
+func (x *Device) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *Device) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *Device) GetBrowser() string {
+	if x != nil {
+		return x.Browser
+	}
+	return ""
+}
+
+func (x *Device) GetFirstSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeenAt
+	}
+	return nil
+}
+
+func (x *Device) GetLastSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return nil
+}
+
 // Secret represents an API key/secret pair.
 type Secret struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1658,7 +4217,7 @@ type Secret struct {
 
 func (x *Secret) Reset() {
 	*x = Secret{}
-	mi := &file_iam_v1_iam_proto_msgTypes[32]
+	mi := &file_iam_v1_iam_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1670,7 +4229,7 @@ func (x *Secret) String() string {
 func (*Secret) ProtoMessage() {}
 
 func (x *Secret) ProtoReflect() protoreflect.Message {
-	mi := &file_iam_v1_iam_proto_msgTypes[32]
+	mi := &file_iam_v1_iam_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1683,7 +4242,7 @@ func (x *Secret) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Secret.ProtoReflect.Descriptor instead.
 func (*Secret) Descriptor() ([]byte, []int) {
-	return file_iam_v1_iam_proto_rawDescGZIP(), []int{32}
+	return file_iam_v1_iam_proto_rawDescGZIP(), []int{76}
 }
 
 func (x *Secret) GetId() string {
@@ -1728,11 +4287,28 @@ func (x *Secret) GetExpiresAt() *timestamppb.Timestamp {
 	return nil
 }
 
+var file_iam_v1_iam_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*Rule)(nil),
+		Field:         50100,
+		Name:          "iam.v1.rule",
+		Tag:           "bytes,50100,opt,name=rule",
+		Filename:      "iam/v1/iam.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional iam.v1.Rule rule = 50100;
+	E_Rule = &file_iam_v1_iam_proto_extTypes[0]
+)
+
 var File_iam_v1_iam_proto protoreflect.FileDescriptor
 
 const file_iam_v1_iam_proto_rawDesc = "" +
 	"\n" +
-	"\x10iam/v1/iam.proto\x12\x06iam.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"Q\n" +
+	"\x10iam/v1/iam.proto\x12\x06iam.v1\x1a google/protobuf/descriptor.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"Q\n" +
 	"\x16CheckPermissionRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1e\n" +
 	"\n" +
@@ -1749,17 +4325,72 @@ const file_iam_v1_iam_proto_rawDesc = "" +
 	"\x16GetPermissionsResponse\x12 \n" +
 	"\vpermissions\x18\x01 \x03(\tR\vpermissions\")\n" +
 	"\x0eGetUserRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"C\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xe9\x01\n" +
 	"\x10ListUsersRequest\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"M\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x1b\n" +
+	"\ttenant_id\x18\x05 \x01(\tR\btenantId\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12!\n" +
+	"\femail_prefix\x18\a \x01(\tR\vemailPrefix\x12\x19\n" +
+	"\border_by\x18\b \x01(\tR\aorderBy\"u\n" +
 	"\x11ListUsersResponse\x12\"\n" +
 	"\x05users\x18\x01 \x03(\v2\f.iam.v1.UserR\x05users\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\".\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\".\n" +
 	"\x13GetUserRolesRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\":\n" +
 	"\x14GetUserRolesResponse\x12\"\n" +
-	"\x05roles\x18\x01 \x03(\v2\f.iam.v1.RoleR\x05roles\"6\n" +
+	"\x05roles\x18\x01 \x03(\v2\f.iam.v1.RoleR\x05roles\"1\n" +
+	"\x14BatchGetUsersRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\";\n" +
+	"\x15BatchGetUsersResponse\x12\"\n" +
+	"\x05users\x18\x01 \x03(\v2\f.iam.v1.UserR\x05users\"\x98\x02\n" +
+	"\x11CreateUserRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\ttenant_id\x18\x03 \x01(\tR\btenantId\x12\x19\n" +
+	"\brole_ids\x18\x04 \x03(\tR\aroleIds\x12C\n" +
+	"\bmetadata\x18\x05 \x03(\v2'.iam.v1.CreateUserRequest.MetadataEntryR\bmetadata\x12\x1f\n" +
+	"\vsend_invite\x18\x06 \x01(\bR\n" +
+	"sendInvite\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x95\x02\n" +
+	"\x11UpdateUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12C\n" +
+	"\bmetadata\x18\x04 \x03(\v2'.iam.v1.UpdateUserRequest.MetadataEntryR\bmetadata\x12;\n" +
+	"\vupdate_mask\x18\x05 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"E\n" +
+	"\x12DisableUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\",\n" +
+	"\x11EnableUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\",\n" +
+	"\x11DeleteUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x14\n" +
+	"\x12DeleteUserResponse\"E\n" +
+	"\x11AssignRoleRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\arole_id\x18\x02 \x01(\tR\x06roleId\"E\n" +
+	"\x11RemoveRoleRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\arole_id\x18\x02 \x01(\tR\x06roleId\"q\n" +
+	"\x10ListRolesRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"M\n" +
+	"\x11ListRolesResponse\x12\"\n" +
+	"\x05roles\x18\x01 \x03(\v2\f.iam.v1.RoleR\x05roles\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"6\n" +
 	"\x14ResolveTenantRequest\x12\x1e\n" +
 	"\n" +
 	"identifier\x18\x01 \x01(\tR\n" +
@@ -1768,7 +4399,11 @@ const file_iam_v1_iam_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
 	"\ttenant_id\x18\x02 \x01(\tR\btenantId\"9\n" +
 	"\x1aValidateMembershipResponse\x12\x1b\n" +
-	"\tis_member\x18\x01 \x01(\bR\bisMember\".\n" +
+	"\tis_member\x18\x01 \x01(\bR\bisMember\"1\n" +
+	"\x16ListMembershipsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"O\n" +
+	"\x17ListMembershipsResponse\x124\n" +
+	"\vmemberships\x18\x01 \x03(\v2\x12.iam.v1.MembershipR\vmemberships\".\n" +
 	"\x13ListSessionsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"C\n" +
 	"\x14ListSessionsResponse\x12+\n" +
@@ -1781,6 +4416,95 @@ const file_iam_v1_iam_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12,\n" +
 	"\x12current_session_id\x18\x02 \x01(\tR\x10currentSessionId\" \n" +
 	"\x1eRevokeAllOtherSessionsResponse\"7\n" +
+	"\x16ValidateSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"r\n" +
+	"\x17ValidateSessionResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12)\n" +
+	"\asession\x18\x02 \x01(\v2\x0f.iam.v1.SessionR\asession\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"4\n" +
+	"\x13TouchSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\x16\n" +
+	"\x14TouchSessionResponse\"-\n" +
+	"\x12ListDevicesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"?\n" +
+	"\x13ListDevicesResponse\x12(\n" +
+	"\adevices\x18\x01 \x03(\v2\x0e.iam.v1.DeviceR\adevices\"F\n" +
+	"\x13RenameDeviceRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"N\n" +
+	"\x15SetDeviceTrustRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x18\n" +
+	"\atrusted\x18\x02 \x01(\bR\atrusted\"\xe4\x02\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x1b\n" +
+	"\ttenant_id\x18\x05 \x01(\tR\btenantId\x12\x16\n" +
+	"\x06scopes\x18\x06 \x03(\tR\x06scopes\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"revoked_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x12\x1f\n" +
+	"\vsecret_hash\x18\n" +
+	" \x01(\tR\n" +
+	"secretHash\"\xb2\x01\n" +
+	"\x13CreateAPIKeyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\ttenant_id\x18\x03 \x01(\tR\btenantId\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"Q\n" +
+	"\x14CreateAPIKeyResponse\x12'\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x0e.iam.v1.APIKeyR\x06apiKey\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"-\n" +
+	"\x12ListAPIKeysRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"@\n" +
+	"\x13ListAPIKeysResponse\x12)\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\x0e.iam.v1.APIKeyR\aapiKeys\",\n" +
+	"\x13RevokeAPIKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\",\n" +
+	"\x13RotateAPIKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"-\n" +
+	"\x13LookupAPIKeyRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\"\xdf\x01\n" +
+	"\x0eServiceAccount\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tclient_id\x18\x04 \x01(\tR\bclientId\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12/\n" +
+	"\bbindings\x18\x06 \x03(\v2\x13.iam.v1.RoleBindingR\bbindings\"L\n" +
+	"\vRoleBinding\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12 \n" +
+	"\x04role\x18\x02 \x01(\v2\f.iam.v1.RoleR\x04role\"p\n" +
+	"\x1bCreateServiceAccountRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tclient_id\x18\x03 \x01(\tR\bclientId\"*\n" +
+	"\x18GetServiceAccountRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"9\n" +
+	"\x1aListServiceAccountsRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\"`\n" +
+	"\x1bListServiceAccountsResponse\x12A\n" +
+	"\x10service_accounts\x18\x01 \x03(\v2\x16.iam.v1.ServiceAccountR\x0fserviceAccounts\"-\n" +
+	"\x1bDeleteServiceAccountRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1e\n" +
+	"\x1cDeleteServiceAccountResponse\"e\n" +
+	"\x1dBindServiceAccountRoleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x17\n" +
+	"\arole_id\x18\x03 \x01(\tR\x06roleId\"g\n" +
+	"\x1fUnbindServiceAccountRoleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x17\n" +
+	"\arole_id\x18\x03 \x01(\tR\x06roleId\"7\n" +
 	"\x13CreateSecretRequest\x12 \n" +
 	"\vdescription\x18\x01 \x01(\tR\vdescription\"-\n" +
 	"\x12ListSecretsRequest\x12\x17\n" +
@@ -1797,7 +4521,13 @@ const file_iam_v1_iam_proto_rawDesc = "" +
 	"\x14VerifySecretResponse\x12&\n" +
 	"\x06claims\x18\x01 \x01(\v2\x0e.iam.v1.ClaimsR\x06claims\"2\n" +
 	"\x13RotateSecretRequest\x12\x1b\n" +
-	"\tsecret_id\x18\x01 \x01(\tR\bsecretId\"\xe2\x02\n" +
+	"\tsecret_id\x18\x01 \x01(\tR\bsecretId\"g\n" +
+	"\x04Rule\x12\x1e\n" +
+	"\n" +
+	"permission\x18\x01 \x01(\tR\n" +
+	"permission\x12'\n" +
+	"\x0ftenant_required\x18\x02 \x01(\bR\x0etenantRequired\x12\x16\n" +
+	"\x06public\x18\x03 \x01(\bR\x06public\"\xe2\x02\n" +
 	"\x06Claims\x12\x18\n" +
 	"\asubject\x18\x01 \x01(\tR\asubject\x12\x1b\n" +
 	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x14\n" +
@@ -1811,14 +4541,15 @@ const file_iam_v1_iam_proto_rawDesc = "" +
 	"\n" +
 	"ExtraEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf6\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8e\x02\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
 	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1b\n" +
 	"\ttenant_id\x18\x04 \x01(\tR\btenantId\x12\"\n" +
 	"\x05roles\x18\x05 \x03(\v2\f.iam.v1.RoleR\x05roles\x126\n" +
-	"\bmetadata\x18\x06 \x03(\v2\x1a.iam.v1.User.MetadataEntryR\bmetadata\x1a;\n" +
+	"\bmetadata\x18\x06 \x03(\v2\x1a.iam.v1.User.MetadataEntryR\bmetadata\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"*\n" +
@@ -1829,7 +4560,13 @@ const file_iam_v1_iam_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
 	"\x04slug\x18\x03 \x01(\tR\x04slug\x12\x16\n" +
-	"\x06status\x18\x04 \x01(\tR\x06status\"\xd7\x01\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"\xa7\x01\n" +
+	"\n" +
+	"Membership\x12&\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x0e.iam.v1.TenantR\x06tenant\x12 \n" +
+	"\x04role\x18\x02 \x01(\v2\f.iam.v1.RoleR\x04role\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x127\n" +
+	"\tjoined_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bjoinedAt\"\xa6\x03\n" +
 	"\aSession\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x129\n" +
@@ -1839,7 +4576,31 @@ const file_iam_v1_iam_proto_rawDesc = "" +
 	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1d\n" +
 	"\n" +
 	"user_agent\x18\x05 \x01(\tR\tuserAgent\x12\x0e\n" +
-	"\x02ip\x18\x06 \x01(\tR\x02ip\"\xe8\x01\n" +
+	"\x02ip\x18\x06 \x01(\tR\x02ip\x12@\n" +
+	"\x0elast_active_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\flastActiveAt\x12\x1b\n" +
+	"\tdevice_id\x18\b \x01(\tR\bdeviceId\x12\x16\n" +
+	"\x06device\x18\t \x01(\tR\x06device\x12\x0e\n" +
+	"\x02os\x18\n" +
+	" \x01(\tR\x02os\x12\x18\n" +
+	"\abrowser\x18\v \x01(\tR\abrowser\x12,\n" +
+	"\blocation\x18\f \x01(\v2\x10.iam.v1.LocationR\blocation\"\x8a\x01\n" +
+	"\bLocation\x12\x18\n" +
+	"\acountry\x18\x01 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x1a\n" +
+	"\blatitude\x18\x04 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x05 \x01(\x01R\tlongitude\"\x9f\x02\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
+	"\atrusted\x18\x04 \x01(\bR\atrusted\x12\x16\n" +
+	"\x06device\x18\x05 \x01(\tR\x06device\x12\x0e\n" +
+	"\x02os\x18\x06 \x01(\tR\x02os\x12\x18\n" +
+	"\abrowser\x18\a \x01(\tR\abrowser\x12>\n" +
+	"\rfirst_seen_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vfirstSeenAt\x12<\n" +
+	"\flast_seen_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastSeenAt\"\xe8\x01\n" +
 	"\x06Secret\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\x12\x1d\n" +
@@ -1853,24 +4614,60 @@ const file_iam_v1_iam_proto_rawDesc = "" +
 	"\fAuthzService\x12R\n" +
 	"\x0fCheckPermission\x12\x1e.iam.v1.CheckPermissionRequest\x1a\x1f.iam.v1.CheckPermissionResponse\x12b\n" +
 	"\x17CheckResourcePermission\x12&.iam.v1.CheckResourcePermissionRequest\x1a\x1f.iam.v1.CheckPermissionResponse\x12O\n" +
-	"\x0eGetPermissions\x12\x1d.iam.v1.GetPermissionsRequest\x1a\x1e.iam.v1.GetPermissionsResponse2\xcb\x01\n" +
+	"\x0eGetPermissions\x12\x1d.iam.v1.GetPermissionsRequest\x1a\x1e.iam.v1.GetPermissionsResponse2\x99\x02\n" +
 	"\vUserService\x12/\n" +
 	"\aGetUser\x12\x16.iam.v1.GetUserRequest\x1a\f.iam.v1.User\x12@\n" +
 	"\tListUsers\x12\x18.iam.v1.ListUsersRequest\x1a\x19.iam.v1.ListUsersResponse\x12I\n" +
-	"\fGetUserRoles\x12\x1b.iam.v1.GetUserRolesRequest\x1a\x1c.iam.v1.GetUserRolesResponse2\xab\x01\n" +
+	"\fGetUserRoles\x12\x1b.iam.v1.GetUserRolesRequest\x1a\x1c.iam.v1.GetUserRolesResponse\x12L\n" +
+	"\rBatchGetUsers\x12\x1c.iam.v1.BatchGetUsersRequest\x1a\x1d.iam.v1.BatchGetUsersResponse2\xe5\x03\n" +
+	"\x10UserAdminService\x125\n" +
+	"\n" +
+	"CreateUser\x12\x19.iam.v1.CreateUserRequest\x1a\f.iam.v1.User\x125\n" +
+	"\n" +
+	"UpdateUser\x12\x19.iam.v1.UpdateUserRequest\x1a\f.iam.v1.User\x127\n" +
+	"\vDisableUser\x12\x1a.iam.v1.DisableUserRequest\x1a\f.iam.v1.User\x125\n" +
+	"\n" +
+	"EnableUser\x12\x19.iam.v1.EnableUserRequest\x1a\f.iam.v1.User\x12C\n" +
+	"\n" +
+	"DeleteUser\x12\x19.iam.v1.DeleteUserRequest\x1a\x1a.iam.v1.DeleteUserResponse\x125\n" +
+	"\n" +
+	"AssignRole\x12\x19.iam.v1.AssignRoleRequest\x1a\f.iam.v1.User\x125\n" +
+	"\n" +
+	"RemoveRole\x12\x19.iam.v1.RemoveRoleRequest\x1a\f.iam.v1.User\x12@\n" +
+	"\tListRoles\x12\x18.iam.v1.ListRolesRequest\x1a\x19.iam.v1.ListRolesResponse2\xff\x01\n" +
 	"\rTenantService\x12=\n" +
 	"\rResolveTenant\x12\x1c.iam.v1.ResolveTenantRequest\x1a\x0e.iam.v1.Tenant\x12[\n" +
-	"\x12ValidateMembership\x12!.iam.v1.ValidateMembershipRequest\x1a\".iam.v1.ValidateMembershipResponse2\x92\x02\n" +
+	"\x12ValidateMembership\x12!.iam.v1.ValidateMembershipRequest\x1a\".iam.v1.ValidateMembershipResponse\x12R\n" +
+	"\x0fListMemberships\x12\x1e.iam.v1.ListMembershipsRequest\x1a\x1f.iam.v1.ListMembershipsResponse2\xf7\x04\n" +
 	"\x0eSessionService\x12I\n" +
 	"\fListSessions\x12\x1b.iam.v1.ListSessionsRequest\x1a\x1c.iam.v1.ListSessionsResponse\x12L\n" +
 	"\rRevokeSession\x12\x1c.iam.v1.RevokeSessionRequest\x1a\x1d.iam.v1.RevokeSessionResponse\x12g\n" +
-	"\x16RevokeAllOtherSessions\x12%.iam.v1.RevokeAllOtherSessionsRequest\x1a&.iam.v1.RevokeAllOtherSessionsResponse2\xe7\x02\n" +
+	"\x16RevokeAllOtherSessions\x12%.iam.v1.RevokeAllOtherSessionsRequest\x1a&.iam.v1.RevokeAllOtherSessionsResponse\x12R\n" +
+	"\x0fValidateSession\x12\x1e.iam.v1.ValidateSessionRequest\x1a\x1f.iam.v1.ValidateSessionResponse\x12I\n" +
+	"\fTouchSession\x12\x1b.iam.v1.TouchSessionRequest\x1a\x1c.iam.v1.TouchSessionResponse\x12F\n" +
+	"\vListDevices\x12\x1a.iam.v1.ListDevicesRequest\x1a\x1b.iam.v1.ListDevicesResponse\x12;\n" +
+	"\fRenameDevice\x12\x1b.iam.v1.RenameDeviceRequest\x1a\x0e.iam.v1.Device\x12?\n" +
+	"\x0eSetDeviceTrust\x12\x1d.iam.v1.SetDeviceTrustRequest\x1a\x0e.iam.v1.Device2\xe7\x02\n" +
+	"\rAPIKeyService\x12I\n" +
+	"\fCreateAPIKey\x12\x1b.iam.v1.CreateAPIKeyRequest\x1a\x1c.iam.v1.CreateAPIKeyResponse\x12F\n" +
+	"\vListAPIKeys\x12\x1a.iam.v1.ListAPIKeysRequest\x1a\x1b.iam.v1.ListAPIKeysResponse\x12;\n" +
+	"\fRevokeAPIKey\x12\x1b.iam.v1.RevokeAPIKeyRequest\x1a\x0e.iam.v1.APIKey\x12I\n" +
+	"\fRotateAPIKey\x12\x1b.iam.v1.RotateAPIKeyRequest\x1a\x1c.iam.v1.CreateAPIKeyResponse\x12;\n" +
+	"\fLookupAPIKey\x12\x1b.iam.v1.LookupAPIKeyRequest\x1a\x0e.iam.v1.APIKey2\xb4\x04\n" +
+	"\x15ServiceAccountService\x12S\n" +
+	"\x14CreateServiceAccount\x12#.iam.v1.CreateServiceAccountRequest\x1a\x16.iam.v1.ServiceAccount\x12M\n" +
+	"\x11GetServiceAccount\x12 .iam.v1.GetServiceAccountRequest\x1a\x16.iam.v1.ServiceAccount\x12^\n" +
+	"\x13ListServiceAccounts\x12\".iam.v1.ListServiceAccountsRequest\x1a#.iam.v1.ListServiceAccountsResponse\x12a\n" +
+	"\x14DeleteServiceAccount\x12#.iam.v1.DeleteServiceAccountRequest\x1a$.iam.v1.DeleteServiceAccountResponse\x12W\n" +
+	"\x16BindServiceAccountRole\x12%.iam.v1.BindServiceAccountRoleRequest\x1a\x16.iam.v1.ServiceAccount\x12[\n" +
+	"\x18UnbindServiceAccountRole\x12'.iam.v1.UnbindServiceAccountRoleRequest\x1a\x16.iam.v1.ServiceAccount2\xe7\x02\n" +
 	"\rSecretService\x12;\n" +
 	"\fCreateSecret\x12\x1b.iam.v1.CreateSecretRequest\x1a\x0e.iam.v1.Secret\x12F\n" +
 	"\vListSecrets\x12\x1a.iam.v1.ListSecretsRequest\x1a\x1b.iam.v1.ListSecretsResponse\x12I\n" +
 	"\fDeleteSecret\x12\x1b.iam.v1.DeleteSecretRequest\x1a\x1c.iam.v1.DeleteSecretResponse\x12I\n" +
 	"\fVerifySecret\x12\x1b.iam.v1.VerifySecretRequest\x1a\x1c.iam.v1.VerifySecretResponse\x12;\n" +
-	"\fRotateSecret\x12\x1b.iam.v1.RotateSecretRequest\x1a\x0e.iam.v1.SecretB2Z0github.com/chimerakang/iam-go/proto/iam/v1;iamv1b\x06proto3"
+	"\fRotateSecret\x12\x1b.iam.v1.RotateSecretRequest\x1a\x0e.iam.v1.Secret:B\n" +
+	"\x04rule\x12\x1e.google.protobuf.MethodOptions\x18\xb4\x87\x03 \x01(\v2\f.iam.v1.RuleR\x04ruleB2Z0github.com/chimerakang/iam-go/proto/iam/v1;iamv1b\x06proto3"
 
 var (
 	file_iam_v1_iam_proto_rawDescOnce sync.Once
@@ -1884,97 +4681,224 @@ func file_iam_v1_iam_proto_rawDescGZIP() []byte {
 	return file_iam_v1_iam_proto_rawDescData
 }
 
-var file_iam_v1_iam_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_iam_v1_iam_proto_msgTypes = make([]protoimpl.MessageInfo, 81)
 var file_iam_v1_iam_proto_goTypes = []any{
-	(*CheckPermissionRequest)(nil),         // 0: iam.v1.CheckPermissionRequest
-	(*CheckResourcePermissionRequest)(nil), // 1: iam.v1.CheckResourcePermissionRequest
-	(*CheckPermissionResponse)(nil),        // 2: iam.v1.CheckPermissionResponse
-	(*GetPermissionsRequest)(nil),          // 3: iam.v1.GetPermissionsRequest
-	(*GetPermissionsResponse)(nil),         // 4: iam.v1.GetPermissionsResponse
-	(*GetUserRequest)(nil),                 // 5: iam.v1.GetUserRequest
-	(*ListUsersRequest)(nil),               // 6: iam.v1.ListUsersRequest
-	(*ListUsersResponse)(nil),              // 7: iam.v1.ListUsersResponse
-	(*GetUserRolesRequest)(nil),            // 8: iam.v1.GetUserRolesRequest
-	(*GetUserRolesResponse)(nil),           // 9: iam.v1.GetUserRolesResponse
-	(*ResolveTenantRequest)(nil),           // 10: iam.v1.ResolveTenantRequest
-	(*ValidateMembershipRequest)(nil),      // 11: iam.v1.ValidateMembershipRequest
-	(*ValidateMembershipResponse)(nil),     // 12: iam.v1.ValidateMembershipResponse
-	(*ListSessionsRequest)(nil),            // 13: iam.v1.ListSessionsRequest
-	(*ListSessionsResponse)(nil),           // 14: iam.v1.ListSessionsResponse
-	(*RevokeSessionRequest)(nil),           // 15: iam.v1.RevokeSessionRequest
-	(*RevokeSessionResponse)(nil),          // 16: iam.v1.RevokeSessionResponse
-	(*RevokeAllOtherSessionsRequest)(nil),  // 17: iam.v1.RevokeAllOtherSessionsRequest
-	(*RevokeAllOtherSessionsResponse)(nil), // 18: iam.v1.RevokeAllOtherSessionsResponse
-	(*CreateSecretRequest)(nil),            // 19: iam.v1.CreateSecretRequest
-	(*ListSecretsRequest)(nil),             // 20: iam.v1.ListSecretsRequest
-	(*ListSecretsResponse)(nil),            // 21: iam.v1.ListSecretsResponse
-	(*DeleteSecretRequest)(nil),            // 22: iam.v1.DeleteSecretRequest
-	(*DeleteSecretResponse)(nil),           // 23: iam.v1.DeleteSecretResponse
-	(*VerifySecretRequest)(nil),            // 24: iam.v1.VerifySecretRequest
-	(*VerifySecretResponse)(nil),           // 25: iam.v1.VerifySecretResponse
-	(*RotateSecretRequest)(nil),            // 26: iam.v1.RotateSecretRequest
-	(*Claims)(nil),                         // 27: iam.v1.Claims
-	(*User)(nil),                           // 28: iam.v1.User
-	(*Role)(nil),                           // 29: iam.v1.Role
-	(*Tenant)(nil),                         // 30: iam.v1.Tenant
-	(*Session)(nil),                        // 31: iam.v1.Session
-	(*Secret)(nil),                         // 32: iam.v1.Secret
-	nil,                                    // 33: iam.v1.Claims.ExtraEntry
-	nil,                                    // 34: iam.v1.User.MetadataEntry
-	(*timestamppb.Timestamp)(nil),          // 35: google.protobuf.Timestamp
+	(*CheckPermissionRequest)(nil),          // 0: iam.v1.CheckPermissionRequest
+	(*CheckResourcePermissionRequest)(nil),  // 1: iam.v1.CheckResourcePermissionRequest
+	(*CheckPermissionResponse)(nil),         // 2: iam.v1.CheckPermissionResponse
+	(*GetPermissionsRequest)(nil),           // 3: iam.v1.GetPermissionsRequest
+	(*GetPermissionsResponse)(nil),          // 4: iam.v1.GetPermissionsResponse
+	(*GetUserRequest)(nil),                  // 5: iam.v1.GetUserRequest
+	(*ListUsersRequest)(nil),                // 6: iam.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),               // 7: iam.v1.ListUsersResponse
+	(*GetUserRolesRequest)(nil),             // 8: iam.v1.GetUserRolesRequest
+	(*GetUserRolesResponse)(nil),            // 9: iam.v1.GetUserRolesResponse
+	(*BatchGetUsersRequest)(nil),            // 10: iam.v1.BatchGetUsersRequest
+	(*BatchGetUsersResponse)(nil),           // 11: iam.v1.BatchGetUsersResponse
+	(*CreateUserRequest)(nil),               // 12: iam.v1.CreateUserRequest
+	(*UpdateUserRequest)(nil),               // 13: iam.v1.UpdateUserRequest
+	(*DisableUserRequest)(nil),              // 14: iam.v1.DisableUserRequest
+	(*EnableUserRequest)(nil),               // 15: iam.v1.EnableUserRequest
+	(*DeleteUserRequest)(nil),               // 16: iam.v1.DeleteUserRequest
+	(*DeleteUserResponse)(nil),              // 17: iam.v1.DeleteUserResponse
+	(*AssignRoleRequest)(nil),               // 18: iam.v1.AssignRoleRequest
+	(*RemoveRoleRequest)(nil),               // 19: iam.v1.RemoveRoleRequest
+	(*ListRolesRequest)(nil),                // 20: iam.v1.ListRolesRequest
+	(*ListRolesResponse)(nil),               // 21: iam.v1.ListRolesResponse
+	(*ResolveTenantRequest)(nil),            // 22: iam.v1.ResolveTenantRequest
+	(*ValidateMembershipRequest)(nil),       // 23: iam.v1.ValidateMembershipRequest
+	(*ValidateMembershipResponse)(nil),      // 24: iam.v1.ValidateMembershipResponse
+	(*ListMembershipsRequest)(nil),          // 25: iam.v1.ListMembershipsRequest
+	(*ListMembershipsResponse)(nil),         // 26: iam.v1.ListMembershipsResponse
+	(*ListSessionsRequest)(nil),             // 27: iam.v1.ListSessionsRequest
+	(*ListSessionsResponse)(nil),            // 28: iam.v1.ListSessionsResponse
+	(*RevokeSessionRequest)(nil),            // 29: iam.v1.RevokeSessionRequest
+	(*RevokeSessionResponse)(nil),           // 30: iam.v1.RevokeSessionResponse
+	(*RevokeAllOtherSessionsRequest)(nil),   // 31: iam.v1.RevokeAllOtherSessionsRequest
+	(*RevokeAllOtherSessionsResponse)(nil),  // 32: iam.v1.RevokeAllOtherSessionsResponse
+	(*ValidateSessionRequest)(nil),          // 33: iam.v1.ValidateSessionRequest
+	(*ValidateSessionResponse)(nil),         // 34: iam.v1.ValidateSessionResponse
+	(*TouchSessionRequest)(nil),             // 35: iam.v1.TouchSessionRequest
+	(*TouchSessionResponse)(nil),            // 36: iam.v1.TouchSessionResponse
+	(*ListDevicesRequest)(nil),              // 37: iam.v1.ListDevicesRequest
+	(*ListDevicesResponse)(nil),             // 38: iam.v1.ListDevicesResponse
+	(*RenameDeviceRequest)(nil),             // 39: iam.v1.RenameDeviceRequest
+	(*SetDeviceTrustRequest)(nil),           // 40: iam.v1.SetDeviceTrustRequest
+	(*APIKey)(nil),                          // 41: iam.v1.APIKey
+	(*CreateAPIKeyRequest)(nil),             // 42: iam.v1.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),            // 43: iam.v1.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),              // 44: iam.v1.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),             // 45: iam.v1.ListAPIKeysResponse
+	(*RevokeAPIKeyRequest)(nil),             // 46: iam.v1.RevokeAPIKeyRequest
+	(*RotateAPIKeyRequest)(nil),             // 47: iam.v1.RotateAPIKeyRequest
+	(*LookupAPIKeyRequest)(nil),             // 48: iam.v1.LookupAPIKeyRequest
+	(*ServiceAccount)(nil),                  // 49: iam.v1.ServiceAccount
+	(*RoleBinding)(nil),                     // 50: iam.v1.RoleBinding
+	(*CreateServiceAccountRequest)(nil),     // 51: iam.v1.CreateServiceAccountRequest
+	(*GetServiceAccountRequest)(nil),        // 52: iam.v1.GetServiceAccountRequest
+	(*ListServiceAccountsRequest)(nil),      // 53: iam.v1.ListServiceAccountsRequest
+	(*ListServiceAccountsResponse)(nil),     // 54: iam.v1.ListServiceAccountsResponse
+	(*DeleteServiceAccountRequest)(nil),     // 55: iam.v1.DeleteServiceAccountRequest
+	(*DeleteServiceAccountResponse)(nil),    // 56: iam.v1.DeleteServiceAccountResponse
+	(*BindServiceAccountRoleRequest)(nil),   // 57: iam.v1.BindServiceAccountRoleRequest
+	(*UnbindServiceAccountRoleRequest)(nil), // 58: iam.v1.UnbindServiceAccountRoleRequest
+	(*CreateSecretRequest)(nil),             // 59: iam.v1.CreateSecretRequest
+	(*ListSecretsRequest)(nil),              // 60: iam.v1.ListSecretsRequest
+	(*ListSecretsResponse)(nil),             // 61: iam.v1.ListSecretsResponse
+	(*DeleteSecretRequest)(nil),             // 62: iam.v1.DeleteSecretRequest
+	(*DeleteSecretResponse)(nil),            // 63: iam.v1.DeleteSecretResponse
+	(*VerifySecretRequest)(nil),             // 64: iam.v1.VerifySecretRequest
+	(*VerifySecretResponse)(nil),            // 65: iam.v1.VerifySecretResponse
+	(*RotateSecretRequest)(nil),             // 66: iam.v1.RotateSecretRequest
+	(*Rule)(nil),                            // 67: iam.v1.Rule
+	(*Claims)(nil),                          // 68: iam.v1.Claims
+	(*User)(nil),                            // 69: iam.v1.User
+	(*Role)(nil),                            // 70: iam.v1.Role
+	(*Tenant)(nil),                          // 71: iam.v1.Tenant
+	(*Membership)(nil),                      // 72: iam.v1.Membership
+	(*Session)(nil),                         // 73: iam.v1.Session
+	(*Location)(nil),                        // 74: iam.v1.Location
+	(*Device)(nil),                          // 75: iam.v1.Device
+	(*Secret)(nil),                          // 76: iam.v1.Secret
+	nil,                                     // 77: iam.v1.CreateUserRequest.MetadataEntry
+	nil,                                     // 78: iam.v1.UpdateUserRequest.MetadataEntry
+	nil,                                     // 79: iam.v1.Claims.ExtraEntry
+	nil,                                     // 80: iam.v1.User.MetadataEntry
+	(*fieldmaskpb.FieldMask)(nil),           // 81: google.protobuf.FieldMask
+	(*timestamppb.Timestamp)(nil),           // 82: google.protobuf.Timestamp
+	(*descriptorpb.MethodOptions)(nil),      // 83: google.protobuf.MethodOptions
 }
 var file_iam_v1_iam_proto_depIdxs = []int32{
-	28, // 0: iam.v1.ListUsersResponse.users:type_name -> iam.v1.User
-	29, // 1: iam.v1.GetUserRolesResponse.roles:type_name -> iam.v1.Role
-	31, // 2: iam.v1.ListSessionsResponse.sessions:type_name -> iam.v1.Session
-	32, // 3: iam.v1.ListSecretsResponse.secrets:type_name -> iam.v1.Secret
-	27, // 4: iam.v1.VerifySecretResponse.claims:type_name -> iam.v1.Claims
-	35, // 5: iam.v1.Claims.expires_at:type_name -> google.protobuf.Timestamp
-	35, // 6: iam.v1.Claims.issued_at:type_name -> google.protobuf.Timestamp
-	33, // 7: iam.v1.Claims.extra:type_name -> iam.v1.Claims.ExtraEntry
-	29, // 8: iam.v1.User.roles:type_name -> iam.v1.Role
-	34, // 9: iam.v1.User.metadata:type_name -> iam.v1.User.MetadataEntry
-	35, // 10: iam.v1.Session.created_at:type_name -> google.protobuf.Timestamp
-	35, // 11: iam.v1.Session.expires_at:type_name -> google.protobuf.Timestamp
-	35, // 12: iam.v1.Secret.created_at:type_name -> google.protobuf.Timestamp
-	35, // 13: iam.v1.Secret.expires_at:type_name -> google.protobuf.Timestamp
-	0,  // 14: iam.v1.AuthzService.CheckPermission:input_type -> iam.v1.CheckPermissionRequest
-	1,  // 15: iam.v1.AuthzService.CheckResourcePermission:input_type -> iam.v1.CheckResourcePermissionRequest
-	3,  // 16: iam.v1.AuthzService.GetPermissions:input_type -> iam.v1.GetPermissionsRequest
-	5,  // 17: iam.v1.UserService.GetUser:input_type -> iam.v1.GetUserRequest
-	6,  // 18: iam.v1.UserService.ListUsers:input_type -> iam.v1.ListUsersRequest
-	8,  // 19: iam.v1.UserService.GetUserRoles:input_type -> iam.v1.GetUserRolesRequest
-	10, // 20: iam.v1.TenantService.ResolveTenant:input_type -> iam.v1.ResolveTenantRequest
-	11, // 21: iam.v1.TenantService.ValidateMembership:input_type -> iam.v1.ValidateMembershipRequest
-	13, // 22: iam.v1.SessionService.ListSessions:input_type -> iam.v1.ListSessionsRequest
-	15, // 23: iam.v1.SessionService.RevokeSession:input_type -> iam.v1.RevokeSessionRequest
-	17, // 24: iam.v1.SessionService.RevokeAllOtherSessions:input_type -> iam.v1.RevokeAllOtherSessionsRequest
-	19, // 25: iam.v1.SecretService.CreateSecret:input_type -> iam.v1.CreateSecretRequest
-	20, // 26: iam.v1.SecretService.ListSecrets:input_type -> iam.v1.ListSecretsRequest
-	22, // 27: iam.v1.SecretService.DeleteSecret:input_type -> iam.v1.DeleteSecretRequest
-	24, // 28: iam.v1.SecretService.VerifySecret:input_type -> iam.v1.VerifySecretRequest
-	26, // 29: iam.v1.SecretService.RotateSecret:input_type -> iam.v1.RotateSecretRequest
-	2,  // 30: iam.v1.AuthzService.CheckPermission:output_type -> iam.v1.CheckPermissionResponse
-	2,  // 31: iam.v1.AuthzService.CheckResourcePermission:output_type -> iam.v1.CheckPermissionResponse
-	4,  // 32: iam.v1.AuthzService.GetPermissions:output_type -> iam.v1.GetPermissionsResponse
-	28, // 33: iam.v1.UserService.GetUser:output_type -> iam.v1.User
-	7,  // 34: iam.v1.UserService.ListUsers:output_type -> iam.v1.ListUsersResponse
-	9,  // 35: iam.v1.UserService.GetUserRoles:output_type -> iam.v1.GetUserRolesResponse
-	30, // 36: iam.v1.TenantService.ResolveTenant:output_type -> iam.v1.Tenant
-	12, // 37: iam.v1.TenantService.ValidateMembership:output_type -> iam.v1.ValidateMembershipResponse
-	14, // 38: iam.v1.SessionService.ListSessions:output_type -> iam.v1.ListSessionsResponse
-	16, // 39: iam.v1.SessionService.RevokeSession:output_type -> iam.v1.RevokeSessionResponse
-	18, // 40: iam.v1.SessionService.RevokeAllOtherSessions:output_type -> iam.v1.RevokeAllOtherSessionsResponse
-	32, // 41: iam.v1.SecretService.CreateSecret:output_type -> iam.v1.Secret
-	21, // 42: iam.v1.SecretService.ListSecrets:output_type -> iam.v1.ListSecretsResponse
-	23, // 43: iam.v1.SecretService.DeleteSecret:output_type -> iam.v1.DeleteSecretResponse
-	25, // 44: iam.v1.SecretService.VerifySecret:output_type -> iam.v1.VerifySecretResponse
-	32, // 45: iam.v1.SecretService.RotateSecret:output_type -> iam.v1.Secret
-	30, // [30:46] is the sub-list for method output_type
-	14, // [14:30] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	69, // 0: iam.v1.ListUsersResponse.users:type_name -> iam.v1.User
+	70, // 1: iam.v1.GetUserRolesResponse.roles:type_name -> iam.v1.Role
+	69, // 2: iam.v1.BatchGetUsersResponse.users:type_name -> iam.v1.User
+	77, // 3: iam.v1.CreateUserRequest.metadata:type_name -> iam.v1.CreateUserRequest.MetadataEntry
+	78, // 4: iam.v1.UpdateUserRequest.metadata:type_name -> iam.v1.UpdateUserRequest.MetadataEntry
+	81, // 5: iam.v1.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	70, // 6: iam.v1.ListRolesResponse.roles:type_name -> iam.v1.Role
+	72, // 7: iam.v1.ListMembershipsResponse.memberships:type_name -> iam.v1.Membership
+	73, // 8: iam.v1.ListSessionsResponse.sessions:type_name -> iam.v1.Session
+	73, // 9: iam.v1.ValidateSessionResponse.session:type_name -> iam.v1.Session
+	75, // 10: iam.v1.ListDevicesResponse.devices:type_name -> iam.v1.Device
+	82, // 11: iam.v1.APIKey.created_at:type_name -> google.protobuf.Timestamp
+	82, // 12: iam.v1.APIKey.expires_at:type_name -> google.protobuf.Timestamp
+	82, // 13: iam.v1.APIKey.revoked_at:type_name -> google.protobuf.Timestamp
+	82, // 14: iam.v1.CreateAPIKeyRequest.expires_at:type_name -> google.protobuf.Timestamp
+	41, // 15: iam.v1.CreateAPIKeyResponse.api_key:type_name -> iam.v1.APIKey
+	41, // 16: iam.v1.ListAPIKeysResponse.api_keys:type_name -> iam.v1.APIKey
+	82, // 17: iam.v1.ServiceAccount.created_at:type_name -> google.protobuf.Timestamp
+	50, // 18: iam.v1.ServiceAccount.bindings:type_name -> iam.v1.RoleBinding
+	70, // 19: iam.v1.RoleBinding.role:type_name -> iam.v1.Role
+	49, // 20: iam.v1.ListServiceAccountsResponse.service_accounts:type_name -> iam.v1.ServiceAccount
+	76, // 21: iam.v1.ListSecretsResponse.secrets:type_name -> iam.v1.Secret
+	68, // 22: iam.v1.VerifySecretResponse.claims:type_name -> iam.v1.Claims
+	82, // 23: iam.v1.Claims.expires_at:type_name -> google.protobuf.Timestamp
+	82, // 24: iam.v1.Claims.issued_at:type_name -> google.protobuf.Timestamp
+	79, // 25: iam.v1.Claims.extra:type_name -> iam.v1.Claims.ExtraEntry
+	70, // 26: iam.v1.User.roles:type_name -> iam.v1.Role
+	80, // 27: iam.v1.User.metadata:type_name -> iam.v1.User.MetadataEntry
+	71, // 28: iam.v1.Membership.tenant:type_name -> iam.v1.Tenant
+	70, // 29: iam.v1.Membership.role:type_name -> iam.v1.Role
+	82, // 30: iam.v1.Membership.joined_at:type_name -> google.protobuf.Timestamp
+	82, // 31: iam.v1.Session.created_at:type_name -> google.protobuf.Timestamp
+	82, // 32: iam.v1.Session.expires_at:type_name -> google.protobuf.Timestamp
+	82, // 33: iam.v1.Session.last_active_at:type_name -> google.protobuf.Timestamp
+	74, // 34: iam.v1.Session.location:type_name -> iam.v1.Location
+	82, // 35: iam.v1.Device.first_seen_at:type_name -> google.protobuf.Timestamp
+	82, // 36: iam.v1.Device.last_seen_at:type_name -> google.protobuf.Timestamp
+	82, // 37: iam.v1.Secret.created_at:type_name -> google.protobuf.Timestamp
+	82, // 38: iam.v1.Secret.expires_at:type_name -> google.protobuf.Timestamp
+	83, // 39: iam.v1.rule:extendee -> google.protobuf.MethodOptions
+	67, // 40: iam.v1.rule:type_name -> iam.v1.Rule
+	0,  // 41: iam.v1.AuthzService.CheckPermission:input_type -> iam.v1.CheckPermissionRequest
+	1,  // 42: iam.v1.AuthzService.CheckResourcePermission:input_type -> iam.v1.CheckResourcePermissionRequest
+	3,  // 43: iam.v1.AuthzService.GetPermissions:input_type -> iam.v1.GetPermissionsRequest
+	5,  // 44: iam.v1.UserService.GetUser:input_type -> iam.v1.GetUserRequest
+	6,  // 45: iam.v1.UserService.ListUsers:input_type -> iam.v1.ListUsersRequest
+	8,  // 46: iam.v1.UserService.GetUserRoles:input_type -> iam.v1.GetUserRolesRequest
+	10, // 47: iam.v1.UserService.BatchGetUsers:input_type -> iam.v1.BatchGetUsersRequest
+	12, // 48: iam.v1.UserAdminService.CreateUser:input_type -> iam.v1.CreateUserRequest
+	13, // 49: iam.v1.UserAdminService.UpdateUser:input_type -> iam.v1.UpdateUserRequest
+	14, // 50: iam.v1.UserAdminService.DisableUser:input_type -> iam.v1.DisableUserRequest
+	15, // 51: iam.v1.UserAdminService.EnableUser:input_type -> iam.v1.EnableUserRequest
+	16, // 52: iam.v1.UserAdminService.DeleteUser:input_type -> iam.v1.DeleteUserRequest
+	18, // 53: iam.v1.UserAdminService.AssignRole:input_type -> iam.v1.AssignRoleRequest
+	19, // 54: iam.v1.UserAdminService.RemoveRole:input_type -> iam.v1.RemoveRoleRequest
+	20, // 55: iam.v1.UserAdminService.ListRoles:input_type -> iam.v1.ListRolesRequest
+	22, // 56: iam.v1.TenantService.ResolveTenant:input_type -> iam.v1.ResolveTenantRequest
+	23, // 57: iam.v1.TenantService.ValidateMembership:input_type -> iam.v1.ValidateMembershipRequest
+	25, // 58: iam.v1.TenantService.ListMemberships:input_type -> iam.v1.ListMembershipsRequest
+	27, // 59: iam.v1.SessionService.ListSessions:input_type -> iam.v1.ListSessionsRequest
+	29, // 60: iam.v1.SessionService.RevokeSession:input_type -> iam.v1.RevokeSessionRequest
+	31, // 61: iam.v1.SessionService.RevokeAllOtherSessions:input_type -> iam.v1.RevokeAllOtherSessionsRequest
+	33, // 62: iam.v1.SessionService.ValidateSession:input_type -> iam.v1.ValidateSessionRequest
+	35, // 63: iam.v1.SessionService.TouchSession:input_type -> iam.v1.TouchSessionRequest
+	37, // 64: iam.v1.SessionService.ListDevices:input_type -> iam.v1.ListDevicesRequest
+	39, // 65: iam.v1.SessionService.RenameDevice:input_type -> iam.v1.RenameDeviceRequest
+	40, // 66: iam.v1.SessionService.SetDeviceTrust:input_type -> iam.v1.SetDeviceTrustRequest
+	42, // 67: iam.v1.APIKeyService.CreateAPIKey:input_type -> iam.v1.CreateAPIKeyRequest
+	44, // 68: iam.v1.APIKeyService.ListAPIKeys:input_type -> iam.v1.ListAPIKeysRequest
+	46, // 69: iam.v1.APIKeyService.RevokeAPIKey:input_type -> iam.v1.RevokeAPIKeyRequest
+	47, // 70: iam.v1.APIKeyService.RotateAPIKey:input_type -> iam.v1.RotateAPIKeyRequest
+	48, // 71: iam.v1.APIKeyService.LookupAPIKey:input_type -> iam.v1.LookupAPIKeyRequest
+	51, // 72: iam.v1.ServiceAccountService.CreateServiceAccount:input_type -> iam.v1.CreateServiceAccountRequest
+	52, // 73: iam.v1.ServiceAccountService.GetServiceAccount:input_type -> iam.v1.GetServiceAccountRequest
+	53, // 74: iam.v1.ServiceAccountService.ListServiceAccounts:input_type -> iam.v1.ListServiceAccountsRequest
+	55, // 75: iam.v1.ServiceAccountService.DeleteServiceAccount:input_type -> iam.v1.DeleteServiceAccountRequest
+	57, // 76: iam.v1.ServiceAccountService.BindServiceAccountRole:input_type -> iam.v1.BindServiceAccountRoleRequest
+	58, // 77: iam.v1.ServiceAccountService.UnbindServiceAccountRole:input_type -> iam.v1.UnbindServiceAccountRoleRequest
+	59, // 78: iam.v1.SecretService.CreateSecret:input_type -> iam.v1.CreateSecretRequest
+	60, // 79: iam.v1.SecretService.ListSecrets:input_type -> iam.v1.ListSecretsRequest
+	62, // 80: iam.v1.SecretService.DeleteSecret:input_type -> iam.v1.DeleteSecretRequest
+	64, // 81: iam.v1.SecretService.VerifySecret:input_type -> iam.v1.VerifySecretRequest
+	66, // 82: iam.v1.SecretService.RotateSecret:input_type -> iam.v1.RotateSecretRequest
+	2,  // 83: iam.v1.AuthzService.CheckPermission:output_type -> iam.v1.CheckPermissionResponse
+	2,  // 84: iam.v1.AuthzService.CheckResourcePermission:output_type -> iam.v1.CheckPermissionResponse
+	4,  // 85: iam.v1.AuthzService.GetPermissions:output_type -> iam.v1.GetPermissionsResponse
+	69, // 86: iam.v1.UserService.GetUser:output_type -> iam.v1.User
+	7,  // 87: iam.v1.UserService.ListUsers:output_type -> iam.v1.ListUsersResponse
+	9,  // 88: iam.v1.UserService.GetUserRoles:output_type -> iam.v1.GetUserRolesResponse
+	11, // 89: iam.v1.UserService.BatchGetUsers:output_type -> iam.v1.BatchGetUsersResponse
+	69, // 90: iam.v1.UserAdminService.CreateUser:output_type -> iam.v1.User
+	69, // 91: iam.v1.UserAdminService.UpdateUser:output_type -> iam.v1.User
+	69, // 92: iam.v1.UserAdminService.DisableUser:output_type -> iam.v1.User
+	69, // 93: iam.v1.UserAdminService.EnableUser:output_type -> iam.v1.User
+	17, // 94: iam.v1.UserAdminService.DeleteUser:output_type -> iam.v1.DeleteUserResponse
+	69, // 95: iam.v1.UserAdminService.AssignRole:output_type -> iam.v1.User
+	69, // 96: iam.v1.UserAdminService.RemoveRole:output_type -> iam.v1.User
+	21, // 97: iam.v1.UserAdminService.ListRoles:output_type -> iam.v1.ListRolesResponse
+	71, // 98: iam.v1.TenantService.ResolveTenant:output_type -> iam.v1.Tenant
+	24, // 99: iam.v1.TenantService.ValidateMembership:output_type -> iam.v1.ValidateMembershipResponse
+	26, // 100: iam.v1.TenantService.ListMemberships:output_type -> iam.v1.ListMembershipsResponse
+	28, // 101: iam.v1.SessionService.ListSessions:output_type -> iam.v1.ListSessionsResponse
+	30, // 102: iam.v1.SessionService.RevokeSession:output_type -> iam.v1.RevokeSessionResponse
+	32, // 103: iam.v1.SessionService.RevokeAllOtherSessions:output_type -> iam.v1.RevokeAllOtherSessionsResponse
+	34, // 104: iam.v1.SessionService.ValidateSession:output_type -> iam.v1.ValidateSessionResponse
+	36, // 105: iam.v1.SessionService.TouchSession:output_type -> iam.v1.TouchSessionResponse
+	38, // 106: iam.v1.SessionService.ListDevices:output_type -> iam.v1.ListDevicesResponse
+	75, // 107: iam.v1.SessionService.RenameDevice:output_type -> iam.v1.Device
+	75, // 108: iam.v1.SessionService.SetDeviceTrust:output_type -> iam.v1.Device
+	43, // 109: iam.v1.APIKeyService.CreateAPIKey:output_type -> iam.v1.CreateAPIKeyResponse
+	45, // 110: iam.v1.APIKeyService.ListAPIKeys:output_type -> iam.v1.ListAPIKeysResponse
+	41, // 111: iam.v1.APIKeyService.RevokeAPIKey:output_type -> iam.v1.APIKey
+	43, // 112: iam.v1.APIKeyService.RotateAPIKey:output_type -> iam.v1.CreateAPIKeyResponse
+	41, // 113: iam.v1.APIKeyService.LookupAPIKey:output_type -> iam.v1.APIKey
+	49, // 114: iam.v1.ServiceAccountService.CreateServiceAccount:output_type -> iam.v1.ServiceAccount
+	49, // 115: iam.v1.ServiceAccountService.GetServiceAccount:output_type -> iam.v1.ServiceAccount
+	54, // 116: iam.v1.ServiceAccountService.ListServiceAccounts:output_type -> iam.v1.ListServiceAccountsResponse
+	56, // 117: iam.v1.ServiceAccountService.DeleteServiceAccount:output_type -> iam.v1.DeleteServiceAccountResponse
+	49, // 118: iam.v1.ServiceAccountService.BindServiceAccountRole:output_type -> iam.v1.ServiceAccount
+	49, // 119: iam.v1.ServiceAccountService.UnbindServiceAccountRole:output_type -> iam.v1.ServiceAccount
+	76, // 120: iam.v1.SecretService.CreateSecret:output_type -> iam.v1.Secret
+	61, // 121: iam.v1.SecretService.ListSecrets:output_type -> iam.v1.ListSecretsResponse
+	63, // 122: iam.v1.SecretService.DeleteSecret:output_type -> iam.v1.DeleteSecretResponse
+	65, // 123: iam.v1.SecretService.VerifySecret:output_type -> iam.v1.VerifySecretResponse
+	76, // 124: iam.v1.SecretService.RotateSecret:output_type -> iam.v1.Secret
+	83, // [83:125] is the sub-list for method output_type
+	41, // [41:83] is the sub-list for method input_type
+	40, // [40:41] is the sub-list for extension type_name
+	39, // [39:40] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_iam_v1_iam_proto_init() }
@@ -1988,13 +4912,14 @@ func file_iam_v1_iam_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_iam_v1_iam_proto_rawDesc), len(file_iam_v1_iam_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   35,
-			NumExtensions: 0,
-			NumServices:   5,
+			NumMessages:   81,
+			NumExtensions: 1,
+			NumServices:   8,
 		},
 		GoTypes:           file_iam_v1_iam_proto_goTypes,
 		DependencyIndexes: file_iam_v1_iam_proto_depIdxs,
 		MessageInfos:      file_iam_v1_iam_proto_msgTypes,
+		ExtensionInfos:    file_iam_v1_iam_proto_extTypes,
 	}.Build()
 	File_iam_v1_iam_proto = out.File
 	file_iam_v1_iam_proto_goTypes = nil
@@ -207,9 +207,10 @@ var AuthzService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	UserService_GetUser_FullMethodName      = "/iam.v1.UserService/GetUser"
-	UserService_ListUsers_FullMethodName    = "/iam.v1.UserService/ListUsers"
-	UserService_GetUserRoles_FullMethodName = "/iam.v1.UserService/GetUserRoles"
+	UserService_GetUser_FullMethodName       = "/iam.v1.UserService/GetUser"
+	UserService_ListUsers_FullMethodName     = "/iam.v1.UserService/ListUsers"
+	UserService_GetUserRoles_FullMethodName  = "/iam.v1.UserService/GetUserRoles"
+	UserService_BatchGetUsers_FullMethodName = "/iam.v1.UserService/BatchGetUsers"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -224,6 +225,9 @@ type UserServiceClient interface {
 	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
 	// GetUserRoles returns the roles assigned to a user.
 	GetUserRoles(ctx context.Context, in *GetUserRolesRequest, opts ...grpc.CallOption) (*GetUserRolesResponse, error)
+	// BatchGetUsers returns the users with the given IDs. Unknown IDs are
+	// omitted from the response rather than failing the call.
+	BatchGetUsers(ctx context.Context, in *BatchGetUsersRequest, opts ...grpc.CallOption) (*BatchGetUsersResponse, error)
 }
 
 type userServiceClient struct {
@@ -264,6 +268,16 @@ func (c *userServiceClient) GetUserRoles(ctx context.Context, in *GetUserRolesRe
 	return out, nil
 }
 
+func (c *userServiceClient) BatchGetUsers(ctx context.Context, in *BatchGetUsersRequest, opts ...grpc.CallOption) (*BatchGetUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetUsersResponse)
+	err := c.cc.Invoke(ctx, UserService_BatchGetUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -276,6 +290,9 @@ type UserServiceServer interface {
 	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
 	// GetUserRoles returns the roles assigned to a user.
 	GetUserRoles(context.Context, *GetUserRolesRequest) (*GetUserRolesResponse, error)
+	// BatchGetUsers returns the users with the given IDs. Unknown IDs are
+	// omitted from the response rather than failing the call.
+	BatchGetUsers(context.Context, *BatchGetUsersRequest) (*BatchGetUsersResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -295,6 +312,9 @@ func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersReque
 func (UnimplementedUserServiceServer) GetUserRoles(context.Context, *GetUserRolesRequest) (*GetUserRolesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUserRoles not implemented")
 }
+func (UnimplementedUserServiceServer) BatchGetUsers(context.Context, *BatchGetUsersRequest) (*BatchGetUsersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetUsers not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -370,6 +390,24 @@ func _UserService_GetUserRoles_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_BatchGetUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).BatchGetUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_BatchGetUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).BatchGetUsers(ctx, req.(*BatchGetUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -389,6 +427,406 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserRoles",
 			Handler:    _UserService_GetUserRoles_Handler,
 		},
+		{
+			MethodName: "BatchGetUsers",
+			Handler:    _UserService_BatchGetUsers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "iam/v1/iam.proto",
+}
+
+const (
+	UserAdminService_CreateUser_FullMethodName  = "/iam.v1.UserAdminService/CreateUser"
+	UserAdminService_UpdateUser_FullMethodName  = "/iam.v1.UserAdminService/UpdateUser"
+	UserAdminService_DisableUser_FullMethodName = "/iam.v1.UserAdminService/DisableUser"
+	UserAdminService_EnableUser_FullMethodName  = "/iam.v1.UserAdminService/EnableUser"
+	UserAdminService_DeleteUser_FullMethodName  = "/iam.v1.UserAdminService/DeleteUser"
+	UserAdminService_AssignRole_FullMethodName  = "/iam.v1.UserAdminService/AssignRole"
+	UserAdminService_RemoveRole_FullMethodName  = "/iam.v1.UserAdminService/RemoveRole"
+	UserAdminService_ListRoles_FullMethodName   = "/iam.v1.UserAdminService/ListRoles"
+)
+
+// UserAdminServiceClient is the client API for UserAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserAdminService manages user accounts and role assignments.
+//
+// Validation failures return INVALID_ARGUMENT, duplicate emails or role
+// assignments ALREADY_EXISTS, and unknown users or roles NOT_FOUND.
+type UserAdminServiceClient interface {
+	// CreateUser creates a user, optionally emailing an invitation.
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// UpdateUser changes the fields listed in update_mask.
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// DisableUser blocks sign-in and revokes the user's sessions. Idempotent.
+	DisableUser(ctx context.Context, in *DisableUserRequest, opts ...grpc.CallOption) (*User, error)
+	// EnableUser re-enables a disabled user. Idempotent.
+	EnableUser(ctx context.Context, in *EnableUserRequest, opts ...grpc.CallOption) (*User, error)
+	// DeleteUser permanently deletes a user.
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	// AssignRole grants a role to a user.
+	AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*User, error)
+	// RemoveRole revokes a role from a user.
+	RemoveRole(ctx context.Context, in *RemoveRoleRequest, opts ...grpc.CallOption) (*User, error)
+	// ListRoles returns one page of the roles defined in a tenant, ordered by
+	// ID, whether or not any user holds them.
+	ListRoles(ctx context.Context, in *ListRolesRequest, opts ...grpc.CallOption) (*ListRolesResponse, error)
+}
+
+type userAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserAdminServiceClient(cc grpc.ClientConnInterface) UserAdminServiceClient {
+	return &userAdminServiceClient{cc}
+}
+
+func (c *userAdminServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserAdminService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserAdminService_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) DisableUser(ctx context.Context, in *DisableUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserAdminService_DisableUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) EnableUser(ctx context.Context, in *EnableUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserAdminService_EnableUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUserResponse)
+	err := c.cc.Invoke(ctx, UserAdminService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserAdminService_AssignRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) RemoveRole(ctx context.Context, in *RemoveRoleRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserAdminService_RemoveRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminServiceClient) ListRoles(ctx context.Context, in *ListRolesRequest, opts ...grpc.CallOption) (*ListRolesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRolesResponse)
+	err := c.cc.Invoke(ctx, UserAdminService_ListRoles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserAdminServiceServer is the server API for UserAdminService service.
+// All implementations must embed UnimplementedUserAdminServiceServer
+// for forward compatibility.
+//
+// UserAdminService manages user accounts and role assignments.
+//
+// Validation failures return INVALID_ARGUMENT, duplicate emails or role
+// assignments ALREADY_EXISTS, and unknown users or roles NOT_FOUND.
+type UserAdminServiceServer interface {
+	// CreateUser creates a user, optionally emailing an invitation.
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	// UpdateUser changes the fields listed in update_mask.
+	UpdateUser(context.Context, *UpdateUserRequest) (*User, error)
+	// DisableUser blocks sign-in and revokes the user's sessions. Idempotent.
+	DisableUser(context.Context, *DisableUserRequest) (*User, error)
+	// EnableUser re-enables a disabled user. Idempotent.
+	EnableUser(context.Context, *EnableUserRequest) (*User, error)
+	// DeleteUser permanently deletes a user.
+	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
+	// AssignRole grants a role to a user.
+	AssignRole(context.Context, *AssignRoleRequest) (*User, error)
+	// RemoveRole revokes a role from a user.
+	RemoveRole(context.Context, *RemoveRoleRequest) (*User, error)
+	// ListRoles returns one page of the roles defined in a tenant, ordered by
+	// ID, whether or not any user holds them.
+	ListRoles(context.Context, *ListRolesRequest) (*ListRolesResponse, error)
+	mustEmbedUnimplementedUserAdminServiceServer()
+}
+
+// UnimplementedUserAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserAdminServiceServer struct{}
+
+func (UnimplementedUserAdminServiceServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedUserAdminServiceServer) UpdateUser(context.Context, *UpdateUserRequest) (*User, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedUserAdminServiceServer) DisableUser(context.Context, *DisableUserRequest) (*User, error) {
+	return nil, status.Error(codes.Unimplemented, "method DisableUser not implemented")
+}
+func (UnimplementedUserAdminServiceServer) EnableUser(context.Context, *EnableUserRequest) (*User, error) {
+	return nil, status.Error(codes.Unimplemented, "method EnableUser not implemented")
+}
+func (UnimplementedUserAdminServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedUserAdminServiceServer) AssignRole(context.Context, *AssignRoleRequest) (*User, error) {
+	return nil, status.Error(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (UnimplementedUserAdminServiceServer) RemoveRole(context.Context, *RemoveRoleRequest) (*User, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveRole not implemented")
+}
+func (UnimplementedUserAdminServiceServer) ListRoles(context.Context, *ListRolesRequest) (*ListRolesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRoles not implemented")
+}
+func (UnimplementedUserAdminServiceServer) mustEmbedUnimplementedUserAdminServiceServer() {}
+func (UnimplementedUserAdminServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeUserAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserAdminServiceServer will
+// result in compilation errors.
+type UnsafeUserAdminServiceServer interface {
+	mustEmbedUnimplementedUserAdminServiceServer()
+}
+
+func RegisterUserAdminServiceServer(s grpc.ServiceRegistrar, srv UserAdminServiceServer) {
+	// If the following call panics, it indicates UnimplementedUserAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserAdminService_ServiceDesc, srv)
+}
+
+func _UserAdminService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_DisableUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).DisableUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_DisableUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).DisableUser(ctx, req.(*DisableUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_EnableUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).EnableUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_EnableUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).EnableUser(ctx, req.(*EnableUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_AssignRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).AssignRole(ctx, req.(*AssignRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_RemoveRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).RemoveRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_RemoveRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).RemoveRole(ctx, req.(*RemoveRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdminService_ListRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).ListRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_ListRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).ListRoles(ctx, req.(*ListRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserAdminService_ServiceDesc is the grpc.ServiceDesc for UserAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iam.v1.UserAdminService",
+	HandlerType: (*UserAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _UserAdminService_CreateUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _UserAdminService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DisableUser",
+			Handler:    _UserAdminService_DisableUser_Handler,
+		},
+		{
+			MethodName: "EnableUser",
+			Handler:    _UserAdminService_EnableUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _UserAdminService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "AssignRole",
+			Handler:    _UserAdminService_AssignRole_Handler,
+		},
+		{
+			MethodName: "RemoveRole",
+			Handler:    _UserAdminService_RemoveRole_Handler,
+		},
+		{
+			MethodName: "ListRoles",
+			Handler:    _UserAdminService_ListRoles_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "iam/v1/iam.proto",
@@ -397,6 +835,7 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 const (
 	TenantService_ResolveTenant_FullMethodName      = "/iam.v1.TenantService/ResolveTenant"
 	TenantService_ValidateMembership_FullMethodName = "/iam.v1.TenantService/ValidateMembership"
+	TenantService_ListMemberships_FullMethodName    = "/iam.v1.TenantService/ListMemberships"
 )
 
 // TenantServiceClient is the client API for TenantService service.
@@ -409,6 +848,8 @@ type TenantServiceClient interface {
 	ResolveTenant(ctx context.Context, in *ResolveTenantRequest, opts ...grpc.CallOption) (*Tenant, error)
 	// ValidateMembership checks if a user belongs to a tenant.
 	ValidateMembership(ctx context.Context, in *ValidateMembershipRequest, opts ...grpc.CallOption) (*ValidateMembershipResponse, error)
+	// ListMemberships returns all tenants a user belongs to.
+	ListMemberships(ctx context.Context, in *ListMembershipsRequest, opts ...grpc.CallOption) (*ListMembershipsResponse, error)
 }
 
 type tenantServiceClient struct {
@@ -419,123 +860,826 @@ func NewTenantServiceClient(cc grpc.ClientConnInterface) TenantServiceClient {
 	return &tenantServiceClient{cc}
 }
 
-func (c *tenantServiceClient) ResolveTenant(ctx context.Context, in *ResolveTenantRequest, opts ...grpc.CallOption) (*Tenant, error) {
+func (c *tenantServiceClient) ResolveTenant(ctx context.Context, in *ResolveTenantRequest, opts ...grpc.CallOption) (*Tenant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tenant)
+	err := c.cc.Invoke(ctx, TenantService_ResolveTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ValidateMembership(ctx context.Context, in *ValidateMembershipRequest, opts ...grpc.CallOption) (*ValidateMembershipResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateMembershipResponse)
+	err := c.cc.Invoke(ctx, TenantService_ValidateMembership_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListMemberships(ctx context.Context, in *ListMembershipsRequest, opts ...grpc.CallOption) (*ListMembershipsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMembershipsResponse)
+	err := c.cc.Invoke(ctx, TenantService_ListMemberships_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TenantServiceServer is the server API for TenantService service.
+// All implementations must embed UnimplementedTenantServiceServer
+// for forward compatibility.
+//
+// TenantService provides tenant resolution and membership validation.
+type TenantServiceServer interface {
+	// ResolveTenant looks up a tenant by ID or slug.
+	ResolveTenant(context.Context, *ResolveTenantRequest) (*Tenant, error)
+	// ValidateMembership checks if a user belongs to a tenant.
+	ValidateMembership(context.Context, *ValidateMembershipRequest) (*ValidateMembershipResponse, error)
+	// ListMemberships returns all tenants a user belongs to.
+	ListMemberships(context.Context, *ListMembershipsRequest) (*ListMembershipsResponse, error)
+	mustEmbedUnimplementedTenantServiceServer()
+}
+
+// UnimplementedTenantServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTenantServiceServer struct{}
+
+func (UnimplementedTenantServiceServer) ResolveTenant(context.Context, *ResolveTenantRequest) (*Tenant, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveTenant not implemented")
+}
+func (UnimplementedTenantServiceServer) ValidateMembership(context.Context, *ValidateMembershipRequest) (*ValidateMembershipResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ValidateMembership not implemented")
+}
+func (UnimplementedTenantServiceServer) ListMemberships(context.Context, *ListMembershipsRequest) (*ListMembershipsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListMemberships not implemented")
+}
+func (UnimplementedTenantServiceServer) mustEmbedUnimplementedTenantServiceServer() {}
+func (UnimplementedTenantServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeTenantServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TenantServiceServer will
+// result in compilation errors.
+type UnsafeTenantServiceServer interface {
+	mustEmbedUnimplementedTenantServiceServer()
+}
+
+func RegisterTenantServiceServer(s grpc.ServiceRegistrar, srv TenantServiceServer) {
+	// If the following call panics, it indicates UnimplementedTenantServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TenantService_ServiceDesc, srv)
+}
+
+func _TenantService_ResolveTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenantServiceServer).ResolveTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenantService_ResolveTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenantServiceServer).ResolveTenant(ctx, req.(*ResolveTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenantService_ValidateMembership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateMembershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenantServiceServer).ValidateMembership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenantService_ValidateMembership_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenantServiceServer).ValidateMembership(ctx, req.(*ValidateMembershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenantService_ListMemberships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMembershipsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenantServiceServer).ListMemberships(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenantService_ListMemberships_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenantServiceServer).ListMemberships(ctx, req.(*ListMembershipsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TenantService_ServiceDesc is the grpc.ServiceDesc for TenantService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TenantService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iam.v1.TenantService",
+	HandlerType: (*TenantServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResolveTenant",
+			Handler:    _TenantService_ResolveTenant_Handler,
+		},
+		{
+			MethodName: "ValidateMembership",
+			Handler:    _TenantService_ValidateMembership_Handler,
+		},
+		{
+			MethodName: "ListMemberships",
+			Handler:    _TenantService_ListMemberships_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "iam/v1/iam.proto",
+}
+
+const (
+	SessionService_ListSessions_FullMethodName           = "/iam.v1.SessionService/ListSessions"
+	SessionService_RevokeSession_FullMethodName          = "/iam.v1.SessionService/RevokeSession"
+	SessionService_RevokeAllOtherSessions_FullMethodName = "/iam.v1.SessionService/RevokeAllOtherSessions"
+	SessionService_ValidateSession_FullMethodName        = "/iam.v1.SessionService/ValidateSession"
+	SessionService_TouchSession_FullMethodName           = "/iam.v1.SessionService/TouchSession"
+	SessionService_ListDevices_FullMethodName            = "/iam.v1.SessionService/ListDevices"
+	SessionService_RenameDevice_FullMethodName           = "/iam.v1.SessionService/RenameDevice"
+	SessionService_SetDeviceTrust_FullMethodName         = "/iam.v1.SessionService/SetDeviceTrust"
+)
+
+// SessionServiceClient is the client API for SessionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SessionService provides session management for authenticated users.
+type SessionServiceClient interface {
+	// ListSessions returns all active sessions for a user.
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// RevokeSession terminates a specific session.
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	// RevokeAllOtherSessions terminates all sessions except the current one.
+	RevokeAllOtherSessions(ctx context.Context, in *RevokeAllOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeAllOtherSessionsResponse, error)
+	// ValidateSession reports whether a session is still active (not revoked or expired).
+	ValidateSession(ctx context.Context, in *ValidateSessionRequest, opts ...grpc.CallOption) (*ValidateSessionResponse, error)
+	// TouchSession records activity on a session, updating last_active_at.
+	TouchSession(ctx context.Context, in *TouchSessionRequest, opts ...grpc.CallOption) (*TouchSessionResponse, error)
+	// ListDevices returns the devices a user has signed in from.
+	ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error)
+	// RenameDevice sets a user-chosen display name for a device.
+	RenameDevice(ctx context.Context, in *RenameDeviceRequest, opts ...grpc.CallOption) (*Device, error)
+	// SetDeviceTrust marks a device as trusted or untrusted.
+	SetDeviceTrust(ctx context.Context, in *SetDeviceTrustRequest, opts ...grpc.CallOption) (*Device, error)
+}
+
+type sessionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSessionServiceClient(cc grpc.ClientConnInterface) SessionServiceClient {
+	return &sessionServiceClient{cc}
+}
+
+func (c *sessionServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, SessionService_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, SessionService_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) RevokeAllOtherSessions(ctx context.Context, in *RevokeAllOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeAllOtherSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAllOtherSessionsResponse)
+	err := c.cc.Invoke(ctx, SessionService_RevokeAllOtherSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) ValidateSession(ctx context.Context, in *ValidateSessionRequest, opts ...grpc.CallOption) (*ValidateSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateSessionResponse)
+	err := c.cc.Invoke(ctx, SessionService_ValidateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) TouchSession(ctx context.Context, in *TouchSessionRequest, opts ...grpc.CallOption) (*TouchSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TouchSessionResponse)
+	err := c.cc.Invoke(ctx, SessionService_TouchSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDevicesResponse)
+	err := c.cc.Invoke(ctx, SessionService_ListDevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) RenameDevice(ctx context.Context, in *RenameDeviceRequest, opts ...grpc.CallOption) (*Device, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Device)
+	err := c.cc.Invoke(ctx, SessionService_RenameDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) SetDeviceTrust(ctx context.Context, in *SetDeviceTrustRequest, opts ...grpc.CallOption) (*Device, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Device)
+	err := c.cc.Invoke(ctx, SessionService_SetDeviceTrust_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SessionServiceServer is the server API for SessionService service.
+// All implementations must embed UnimplementedSessionServiceServer
+// for forward compatibility.
+//
+// SessionService provides session management for authenticated users.
+type SessionServiceServer interface {
+	// ListSessions returns all active sessions for a user.
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// RevokeSession terminates a specific session.
+	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	// RevokeAllOtherSessions terminates all sessions except the current one.
+	RevokeAllOtherSessions(context.Context, *RevokeAllOtherSessionsRequest) (*RevokeAllOtherSessionsResponse, error)
+	// ValidateSession reports whether a session is still active (not revoked or expired).
+	ValidateSession(context.Context, *ValidateSessionRequest) (*ValidateSessionResponse, error)
+	// TouchSession records activity on a session, updating last_active_at.
+	TouchSession(context.Context, *TouchSessionRequest) (*TouchSessionResponse, error)
+	// ListDevices returns the devices a user has signed in from.
+	ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error)
+	// RenameDevice sets a user-chosen display name for a device.
+	RenameDevice(context.Context, *RenameDeviceRequest) (*Device, error)
+	// SetDeviceTrust marks a device as trusted or untrusted.
+	SetDeviceTrust(context.Context, *SetDeviceTrustRequest) (*Device, error)
+	mustEmbedUnimplementedSessionServiceServer()
+}
+
+// UnimplementedSessionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSessionServiceServer struct{}
+
+func (UnimplementedSessionServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedSessionServiceServer) RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedSessionServiceServer) RevokeAllOtherSessions(context.Context, *RevokeAllOtherSessionsRequest) (*RevokeAllOtherSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeAllOtherSessions not implemented")
+}
+func (UnimplementedSessionServiceServer) ValidateSession(context.Context, *ValidateSessionRequest) (*ValidateSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ValidateSession not implemented")
+}
+func (UnimplementedSessionServiceServer) TouchSession(context.Context, *TouchSessionRequest) (*TouchSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TouchSession not implemented")
+}
+func (UnimplementedSessionServiceServer) ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDevices not implemented")
+}
+func (UnimplementedSessionServiceServer) RenameDevice(context.Context, *RenameDeviceRequest) (*Device, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenameDevice not implemented")
+}
+func (UnimplementedSessionServiceServer) SetDeviceTrust(context.Context, *SetDeviceTrustRequest) (*Device, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetDeviceTrust not implemented")
+}
+func (UnimplementedSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {}
+func (UnimplementedSessionServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeSessionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SessionServiceServer will
+// result in compilation errors.
+type UnsafeSessionServiceServer interface {
+	mustEmbedUnimplementedSessionServiceServer()
+}
+
+func RegisterSessionServiceServer(s grpc.ServiceRegistrar, srv SessionServiceServer) {
+	// If the following call panics, it indicates UnimplementedSessionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SessionService_ServiceDesc, srv)
+}
+
+func _SessionService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionService_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionService_RevokeAllOtherSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllOtherSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).RevokeAllOtherSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_RevokeAllOtherSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).RevokeAllOtherSessions(ctx, req.(*RevokeAllOtherSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionService_ValidateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).ValidateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_ValidateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).ValidateSession(ctx, req.(*ValidateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionService_TouchSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TouchSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).TouchSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_TouchSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).TouchSession(ctx, req.(*TouchSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionService_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_ListDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).ListDevices(ctx, req.(*ListDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionService_RenameDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).RenameDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_RenameDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).RenameDevice(ctx, req.(*RenameDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionService_SetDeviceTrust_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDeviceTrustRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).SetDeviceTrust(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_SetDeviceTrust_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).SetDeviceTrust(ctx, req.(*SetDeviceTrustRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SessionService_ServiceDesc is the grpc.ServiceDesc for SessionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SessionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iam.v1.SessionService",
+	HandlerType: (*SessionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _SessionService_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _SessionService_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAllOtherSessions",
+			Handler:    _SessionService_RevokeAllOtherSessions_Handler,
+		},
+		{
+			MethodName: "ValidateSession",
+			Handler:    _SessionService_ValidateSession_Handler,
+		},
+		{
+			MethodName: "TouchSession",
+			Handler:    _SessionService_TouchSession_Handler,
+		},
+		{
+			MethodName: "ListDevices",
+			Handler:    _SessionService_ListDevices_Handler,
+		},
+		{
+			MethodName: "RenameDevice",
+			Handler:    _SessionService_RenameDevice_Handler,
+		},
+		{
+			MethodName: "SetDeviceTrust",
+			Handler:    _SessionService_SetDeviceTrust_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "iam/v1/iam.proto",
+}
+
+const (
+	APIKeyService_CreateAPIKey_FullMethodName = "/iam.v1.APIKeyService/CreateAPIKey"
+	APIKeyService_ListAPIKeys_FullMethodName  = "/iam.v1.APIKeyService/ListAPIKeys"
+	APIKeyService_RevokeAPIKey_FullMethodName = "/iam.v1.APIKeyService/RevokeAPIKey"
+	APIKeyService_RotateAPIKey_FullMethodName = "/iam.v1.APIKeyService/RotateAPIKey"
+	APIKeyService_LookupAPIKey_FullMethodName = "/iam.v1.APIKeyService/LookupAPIKey"
+)
+
+// APIKeyServiceClient is the client API for APIKeyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// APIKeyService manages long-lived API keys. A key is "<prefix>_<secret>";
+// the server stores only the prefix and the hex SHA-256 of the full key, and
+// returns the full key once, from CreateAPIKey and RotateAPIKey.
+//
+// Unknown keys or users return NOT_FOUND.
+type APIKeyServiceClient interface {
+	// CreateAPIKey issues a key for a user.
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	// ListAPIKeys returns a user's keys, without secret hashes.
+	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	// RevokeAPIKey disables a key. Idempotent.
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error)
+	// RotateAPIKey replaces a key's prefix and secret, keeping its ID.
+	RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	// LookupAPIKey returns the key with the given prefix, including its
+	// secret hash, so that SDKs can verify presented keys locally.
+	LookupAPIKey(ctx context.Context, in *LookupAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error)
+}
+
+type aPIKeyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAPIKeyServiceClient(cc grpc.ClientConnInterface) APIKeyServiceClient {
+	return &aPIKeyServiceClient{cc}
+}
+
+func (c *aPIKeyServiceClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, APIKeyService_CreateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeyServiceClient) ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, APIKeyService_ListAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeyServiceClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, APIKeyService_RevokeAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeyServiceClient) RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Tenant)
-	err := c.cc.Invoke(ctx, TenantService_ResolveTenant_FullMethodName, in, out, cOpts...)
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, APIKeyService_RotateAPIKey_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *tenantServiceClient) ValidateMembership(ctx context.Context, in *ValidateMembershipRequest, opts ...grpc.CallOption) (*ValidateMembershipResponse, error) {
+func (c *aPIKeyServiceClient) LookupAPIKey(ctx context.Context, in *LookupAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ValidateMembershipResponse)
-	err := c.cc.Invoke(ctx, TenantService_ValidateMembership_FullMethodName, in, out, cOpts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, APIKeyService_LookupAPIKey_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// TenantServiceServer is the server API for TenantService service.
-// All implementations must embed UnimplementedTenantServiceServer
+// APIKeyServiceServer is the server API for APIKeyService service.
+// All implementations must embed UnimplementedAPIKeyServiceServer
 // for forward compatibility.
 //
-// TenantService provides tenant resolution and membership validation.
-type TenantServiceServer interface {
-	// ResolveTenant looks up a tenant by ID or slug.
-	ResolveTenant(context.Context, *ResolveTenantRequest) (*Tenant, error)
-	// ValidateMembership checks if a user belongs to a tenant.
-	ValidateMembership(context.Context, *ValidateMembershipRequest) (*ValidateMembershipResponse, error)
-	mustEmbedUnimplementedTenantServiceServer()
-}
-
-// UnimplementedTenantServiceServer must be embedded to have
+// APIKeyService manages long-lived API keys. A key is "<prefix>_<secret>";
+// the server stores only the prefix and the hex SHA-256 of the full key, and
+// returns the full key once, from CreateAPIKey and RotateAPIKey.
+//
+// Unknown keys or users return NOT_FOUND.
+type APIKeyServiceServer interface {
+	// CreateAPIKey issues a key for a user.
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	// ListAPIKeys returns a user's keys, without secret hashes.
+	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	// RevokeAPIKey disables a key. Idempotent.
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*APIKey, error)
+	// RotateAPIKey replaces a key's prefix and secret, keeping its ID.
+	RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	// LookupAPIKey returns the key with the given prefix, including its
+	// secret hash, so that SDKs can verify presented keys locally.
+	LookupAPIKey(context.Context, *LookupAPIKeyRequest) (*APIKey, error)
+	mustEmbedUnimplementedAPIKeyServiceServer()
+}
+
+// UnimplementedAPIKeyServiceServer must be embedded to have
 // forward compatible implementations.
 //
 // NOTE: this should be embedded by value instead of pointer to avoid a nil
 // pointer dereference when methods are called.
-type UnimplementedTenantServiceServer struct{}
+type UnimplementedAPIKeyServiceServer struct{}
 
-func (UnimplementedTenantServiceServer) ResolveTenant(context.Context, *ResolveTenantRequest) (*Tenant, error) {
-	return nil, status.Error(codes.Unimplemented, "method ResolveTenant not implemented")
+func (UnimplementedAPIKeyServiceServer) CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateAPIKey not implemented")
 }
-func (UnimplementedTenantServiceServer) ValidateMembership(context.Context, *ValidateMembershipRequest) (*ValidateMembershipResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method ValidateMembership not implemented")
+func (UnimplementedAPIKeyServiceServer) ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAPIKeys not implemented")
 }
-func (UnimplementedTenantServiceServer) mustEmbedUnimplementedTenantServiceServer() {}
-func (UnimplementedTenantServiceServer) testEmbeddedByValue()                       {}
+func (UnimplementedAPIKeyServiceServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*APIKey, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedAPIKeyServiceServer) RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RotateAPIKey not implemented")
+}
+func (UnimplementedAPIKeyServiceServer) LookupAPIKey(context.Context, *LookupAPIKeyRequest) (*APIKey, error) {
+	return nil, status.Error(codes.Unimplemented, "method LookupAPIKey not implemented")
+}
+func (UnimplementedAPIKeyServiceServer) mustEmbedUnimplementedAPIKeyServiceServer() {}
+func (UnimplementedAPIKeyServiceServer) testEmbeddedByValue()                       {}
 
-// UnsafeTenantServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to TenantServiceServer will
+// UnsafeAPIKeyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to APIKeyServiceServer will
 // result in compilation errors.
-type UnsafeTenantServiceServer interface {
-	mustEmbedUnimplementedTenantServiceServer()
+type UnsafeAPIKeyServiceServer interface {
+	mustEmbedUnimplementedAPIKeyServiceServer()
 }
 
-func RegisterTenantServiceServer(s grpc.ServiceRegistrar, srv TenantServiceServer) {
-	// If the following call panics, it indicates UnimplementedTenantServiceServer was
+func RegisterAPIKeyServiceServer(s grpc.ServiceRegistrar, srv APIKeyServiceServer) {
+	// If the following call panics, it indicates UnimplementedAPIKeyServiceServer was
 	// embedded by pointer and is nil.  This will cause panics if an
 	// unimplemented method is ever invoked, so we test this at initialization
 	// time to prevent it from happening at runtime later due to I/O.
 	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
 		t.testEmbeddedByValue()
 	}
-	s.RegisterService(&TenantService_ServiceDesc, srv)
+	s.RegisterService(&APIKeyService_ServiceDesc, srv)
 }
 
-func _TenantService_ResolveTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ResolveTenantRequest)
+func _APIKeyService_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TenantServiceServer).ResolveTenant(ctx, in)
+		return srv.(APIKeyServiceServer).CreateAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: TenantService_ResolveTenant_FullMethodName,
+		FullMethod: APIKeyService_CreateAPIKey_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TenantServiceServer).ResolveTenant(ctx, req.(*ResolveTenantRequest))
+		return srv.(APIKeyServiceServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TenantService_ValidateMembership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ValidateMembershipRequest)
+func _APIKeyService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TenantServiceServer).ValidateMembership(ctx, in)
+		return srv.(APIKeyServiceServer).ListAPIKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: TenantService_ValidateMembership_FullMethodName,
+		FullMethod: APIKeyService_ListAPIKeys_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TenantServiceServer).ValidateMembership(ctx, req.(*ValidateMembershipRequest))
+		return srv.(APIKeyServiceServer).ListAPIKeys(ctx, req.(*ListAPIKeysRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// TenantService_ServiceDesc is the grpc.ServiceDesc for TenantService service.
+func _APIKeyService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeyServiceServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIKeyService_RevokeAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeyServiceServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeyService_RotateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeyServiceServer).RotateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIKeyService_RotateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeyServiceServer).RotateAPIKey(ctx, req.(*RotateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeyService_LookupAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeyServiceServer).LookupAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIKeyService_LookupAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeyServiceServer).LookupAPIKey(ctx, req.(*LookupAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// APIKeyService_ServiceDesc is the grpc.ServiceDesc for APIKeyService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
-var TenantService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "iam.v1.TenantService",
-	HandlerType: (*TenantServiceServer)(nil),
+var APIKeyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iam.v1.APIKeyService",
+	HandlerType: (*APIKeyServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "ResolveTenant",
-			Handler:    _TenantService_ResolveTenant_Handler,
+			MethodName: "CreateAPIKey",
+			Handler:    _APIKeyService_CreateAPIKey_Handler,
 		},
 		{
-			MethodName: "ValidateMembership",
-			Handler:    _TenantService_ValidateMembership_Handler,
+			MethodName: "ListAPIKeys",
+			Handler:    _APIKeyService_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _APIKeyService_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "RotateAPIKey",
+			Handler:    _APIKeyService_RotateAPIKey_Handler,
+		},
+		{
+			MethodName: "LookupAPIKey",
+			Handler:    _APIKeyService_LookupAPIKey_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -543,187 +1687,321 @@ var TenantService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	SessionService_ListSessions_FullMethodName           = "/iam.v1.SessionService/ListSessions"
-	SessionService_RevokeSession_FullMethodName          = "/iam.v1.SessionService/RevokeSession"
-	SessionService_RevokeAllOtherSessions_FullMethodName = "/iam.v1.SessionService/RevokeAllOtherSessions"
+	ServiceAccountService_CreateServiceAccount_FullMethodName     = "/iam.v1.ServiceAccountService/CreateServiceAccount"
+	ServiceAccountService_GetServiceAccount_FullMethodName        = "/iam.v1.ServiceAccountService/GetServiceAccount"
+	ServiceAccountService_ListServiceAccounts_FullMethodName      = "/iam.v1.ServiceAccountService/ListServiceAccounts"
+	ServiceAccountService_DeleteServiceAccount_FullMethodName     = "/iam.v1.ServiceAccountService/DeleteServiceAccount"
+	ServiceAccountService_BindServiceAccountRole_FullMethodName   = "/iam.v1.ServiceAccountService/BindServiceAccountRole"
+	ServiceAccountService_UnbindServiceAccountRole_FullMethodName = "/iam.v1.ServiceAccountService/UnbindServiceAccountRole"
 )
 
-// SessionServiceClient is the client API for SessionService service.
+// ServiceAccountServiceClient is the client API for ServiceAccountService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
-// SessionService provides session management for authenticated users.
-type SessionServiceClient interface {
-	// ListSessions returns all active sessions for a user.
-	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
-	// RevokeSession terminates a specific session.
-	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
-	// RevokeAllOtherSessions terminates all sessions except the current one.
-	RevokeAllOtherSessions(ctx context.Context, in *RevokeAllOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeAllOtherSessionsResponse, error)
+// ServiceAccountService manages service accounts: non-human principals that
+// authenticate with OAuth2 client credentials and receive tokens whose "sub"
+// is the account ID. Accounts belong to no tenant; they are bound to roles
+// per tenant.
+//
+// Validation failures return INVALID_ARGUMENT, duplicate client IDs
+// ALREADY_EXISTS, and unknown accounts, tenants or roles NOT_FOUND.
+type ServiceAccountServiceClient interface {
+	// CreateServiceAccount creates a service account.
+	CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*ServiceAccount, error)
+	// GetServiceAccount returns an account with its role bindings.
+	GetServiceAccount(ctx context.Context, in *GetServiceAccountRequest, opts ...grpc.CallOption) (*ServiceAccount, error)
+	// ListServiceAccounts returns the accounts bound in a tenant, or all
+	// accounts if tenant_id is empty.
+	ListServiceAccounts(ctx context.Context, in *ListServiceAccountsRequest, opts ...grpc.CallOption) (*ListServiceAccountsResponse, error)
+	// DeleteServiceAccount deletes an account and its bindings.
+	DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountRequest, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error)
+	// BindServiceAccountRole grants an account a role in a tenant. Idempotent.
+	BindServiceAccountRole(ctx context.Context, in *BindServiceAccountRoleRequest, opts ...grpc.CallOption) (*ServiceAccount, error)
+	// UnbindServiceAccountRole revokes a role binding. Idempotent.
+	UnbindServiceAccountRole(ctx context.Context, in *UnbindServiceAccountRoleRequest, opts ...grpc.CallOption) (*ServiceAccount, error)
+}
+
+type serviceAccountServiceClient struct {
+	cc grpc.ClientConnInterface
 }
 
-type sessionServiceClient struct {
-	cc grpc.ClientConnInterface
+func NewServiceAccountServiceClient(cc grpc.ClientConnInterface) ServiceAccountServiceClient {
+	return &serviceAccountServiceClient{cc}
 }
 
-func NewSessionServiceClient(cc grpc.ClientConnInterface) SessionServiceClient {
-	return &sessionServiceClient{cc}
+func (c *serviceAccountServiceClient) CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*ServiceAccount, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServiceAccount)
+	err := c.cc.Invoke(ctx, ServiceAccountService_CreateServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *sessionServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+func (c *serviceAccountServiceClient) GetServiceAccount(ctx context.Context, in *GetServiceAccountRequest, opts ...grpc.CallOption) (*ServiceAccount, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListSessionsResponse)
-	err := c.cc.Invoke(ctx, SessionService_ListSessions_FullMethodName, in, out, cOpts...)
+	out := new(ServiceAccount)
+	err := c.cc.Invoke(ctx, ServiceAccountService_GetServiceAccount_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *sessionServiceClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+func (c *serviceAccountServiceClient) ListServiceAccounts(ctx context.Context, in *ListServiceAccountsRequest, opts ...grpc.CallOption) (*ListServiceAccountsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(RevokeSessionResponse)
-	err := c.cc.Invoke(ctx, SessionService_RevokeSession_FullMethodName, in, out, cOpts...)
+	out := new(ListServiceAccountsResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_ListServiceAccounts_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *sessionServiceClient) RevokeAllOtherSessions(ctx context.Context, in *RevokeAllOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeAllOtherSessionsResponse, error) {
+func (c *serviceAccountServiceClient) DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountRequest, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(RevokeAllOtherSessionsResponse)
-	err := c.cc.Invoke(ctx, SessionService_RevokeAllOtherSessions_FullMethodName, in, out, cOpts...)
+	out := new(DeleteServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_DeleteServiceAccount_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// SessionServiceServer is the server API for SessionService service.
-// All implementations must embed UnimplementedSessionServiceServer
-// for forward compatibility.
-//
-// SessionService provides session management for authenticated users.
-type SessionServiceServer interface {
-	// ListSessions returns all active sessions for a user.
-	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
-	// RevokeSession terminates a specific session.
-	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
-	// RevokeAllOtherSessions terminates all sessions except the current one.
-	RevokeAllOtherSessions(context.Context, *RevokeAllOtherSessionsRequest) (*RevokeAllOtherSessionsResponse, error)
-	mustEmbedUnimplementedSessionServiceServer()
+func (c *serviceAccountServiceClient) BindServiceAccountRole(ctx context.Context, in *BindServiceAccountRoleRequest, opts ...grpc.CallOption) (*ServiceAccount, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServiceAccount)
+	err := c.cc.Invoke(ctx, ServiceAccountService_BindServiceAccountRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedSessionServiceServer must be embedded to have
+func (c *serviceAccountServiceClient) UnbindServiceAccountRole(ctx context.Context, in *UnbindServiceAccountRoleRequest, opts ...grpc.CallOption) (*ServiceAccount, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServiceAccount)
+	err := c.cc.Invoke(ctx, ServiceAccountService_UnbindServiceAccountRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ServiceAccountServiceServer is the server API for ServiceAccountService service.
+// All implementations must embed UnimplementedServiceAccountServiceServer
+// for forward compatibility.
+//
+// ServiceAccountService manages service accounts: non-human principals that
+// authenticate with OAuth2 client credentials and receive tokens whose "sub"
+// is the account ID. Accounts belong to no tenant; they are bound to roles
+// per tenant.
+//
+// Validation failures return INVALID_ARGUMENT, duplicate client IDs
+// ALREADY_EXISTS, and unknown accounts, tenants or roles NOT_FOUND.
+type ServiceAccountServiceServer interface {
+	// CreateServiceAccount creates a service account.
+	CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*ServiceAccount, error)
+	// GetServiceAccount returns an account with its role bindings.
+	GetServiceAccount(context.Context, *GetServiceAccountRequest) (*ServiceAccount, error)
+	// ListServiceAccounts returns the accounts bound in a tenant, or all
+	// accounts if tenant_id is empty.
+	ListServiceAccounts(context.Context, *ListServiceAccountsRequest) (*ListServiceAccountsResponse, error)
+	// DeleteServiceAccount deletes an account and its bindings.
+	DeleteServiceAccount(context.Context, *DeleteServiceAccountRequest) (*DeleteServiceAccountResponse, error)
+	// BindServiceAccountRole grants an account a role in a tenant. Idempotent.
+	BindServiceAccountRole(context.Context, *BindServiceAccountRoleRequest) (*ServiceAccount, error)
+	// UnbindServiceAccountRole revokes a role binding. Idempotent.
+	UnbindServiceAccountRole(context.Context, *UnbindServiceAccountRoleRequest) (*ServiceAccount, error)
+	mustEmbedUnimplementedServiceAccountServiceServer()
+}
+
+// UnimplementedServiceAccountServiceServer must be embedded to have
 // forward compatible implementations.
 //
 // NOTE: this should be embedded by value instead of pointer to avoid a nil
 // pointer dereference when methods are called.
-type UnimplementedSessionServiceServer struct{}
+type UnimplementedServiceAccountServiceServer struct{}
 
-func (UnimplementedSessionServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method ListSessions not implemented")
+func (UnimplementedServiceAccountServiceServer) CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*ServiceAccount, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateServiceAccount not implemented")
 }
-func (UnimplementedSessionServiceServer) RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method RevokeSession not implemented")
+func (UnimplementedServiceAccountServiceServer) GetServiceAccount(context.Context, *GetServiceAccountRequest) (*ServiceAccount, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetServiceAccount not implemented")
 }
-func (UnimplementedSessionServiceServer) RevokeAllOtherSessions(context.Context, *RevokeAllOtherSessionsRequest) (*RevokeAllOtherSessionsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method RevokeAllOtherSessions not implemented")
+func (UnimplementedServiceAccountServiceServer) ListServiceAccounts(context.Context, *ListServiceAccountsRequest) (*ListServiceAccountsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListServiceAccounts not implemented")
 }
-func (UnimplementedSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {}
-func (UnimplementedSessionServiceServer) testEmbeddedByValue()                        {}
+func (UnimplementedServiceAccountServiceServer) DeleteServiceAccount(context.Context, *DeleteServiceAccountRequest) (*DeleteServiceAccountResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) BindServiceAccountRole(context.Context, *BindServiceAccountRoleRequest) (*ServiceAccount, error) {
+	return nil, status.Error(codes.Unimplemented, "method BindServiceAccountRole not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) UnbindServiceAccountRole(context.Context, *UnbindServiceAccountRoleRequest) (*ServiceAccount, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnbindServiceAccountRole not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) mustEmbedUnimplementedServiceAccountServiceServer() {}
+func (UnimplementedServiceAccountServiceServer) testEmbeddedByValue()                               {}
 
-// UnsafeSessionServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to SessionServiceServer will
+// UnsafeServiceAccountServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServiceAccountServiceServer will
 // result in compilation errors.
-type UnsafeSessionServiceServer interface {
-	mustEmbedUnimplementedSessionServiceServer()
+type UnsafeServiceAccountServiceServer interface {
+	mustEmbedUnimplementedServiceAccountServiceServer()
 }
 
-func RegisterSessionServiceServer(s grpc.ServiceRegistrar, srv SessionServiceServer) {
-	// If the following call panics, it indicates UnimplementedSessionServiceServer was
+func RegisterServiceAccountServiceServer(s grpc.ServiceRegistrar, srv ServiceAccountServiceServer) {
+	// If the following call panics, it indicates UnimplementedServiceAccountServiceServer was
 	// embedded by pointer and is nil.  This will cause panics if an
 	// unimplemented method is ever invoked, so we test this at initialization
 	// time to prevent it from happening at runtime later due to I/O.
 	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
 		t.testEmbeddedByValue()
 	}
-	s.RegisterService(&SessionService_ServiceDesc, srv)
+	s.RegisterService(&ServiceAccountService_ServiceDesc, srv)
 }
 
-func _SessionService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListSessionsRequest)
+func _ServiceAccountService_CreateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SessionServiceServer).ListSessions(ctx, in)
+		return srv.(ServiceAccountServiceServer).CreateServiceAccount(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SessionService_ListSessions_FullMethodName,
+		FullMethod: ServiceAccountService_CreateServiceAccount_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SessionServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+		return srv.(ServiceAccountServiceServer).CreateServiceAccount(ctx, req.(*CreateServiceAccountRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SessionService_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RevokeSessionRequest)
+func _ServiceAccountService_GetServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceAccountRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SessionServiceServer).RevokeSession(ctx, in)
+		return srv.(ServiceAccountServiceServer).GetServiceAccount(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SessionService_RevokeSession_FullMethodName,
+		FullMethod: ServiceAccountService_GetServiceAccount_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SessionServiceServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+		return srv.(ServiceAccountServiceServer).GetServiceAccount(ctx, req.(*GetServiceAccountRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SessionService_RevokeAllOtherSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RevokeAllOtherSessionsRequest)
+func _ServiceAccountService_ListServiceAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServiceAccountsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SessionServiceServer).RevokeAllOtherSessions(ctx, in)
+		return srv.(ServiceAccountServiceServer).ListServiceAccounts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SessionService_RevokeAllOtherSessions_FullMethodName,
+		FullMethod: ServiceAccountService_ListServiceAccounts_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SessionServiceServer).RevokeAllOtherSessions(ctx, req.(*RevokeAllOtherSessionsRequest))
+		return srv.(ServiceAccountServiceServer).ListServiceAccounts(ctx, req.(*ListServiceAccountsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// SessionService_ServiceDesc is the grpc.ServiceDesc for SessionService service.
+func _ServiceAccountService_DeleteServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).DeleteServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_DeleteServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).DeleteServiceAccount(ctx, req.(*DeleteServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccountService_BindServiceAccountRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BindServiceAccountRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).BindServiceAccountRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_BindServiceAccountRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).BindServiceAccountRole(ctx, req.(*BindServiceAccountRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccountService_UnbindServiceAccountRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnbindServiceAccountRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).UnbindServiceAccountRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_UnbindServiceAccountRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).UnbindServiceAccountRole(ctx, req.(*UnbindServiceAccountRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ServiceAccountService_ServiceDesc is the grpc.ServiceDesc for ServiceAccountService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
-var SessionService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "iam.v1.SessionService",
-	HandlerType: (*SessionServiceServer)(nil),
+var ServiceAccountService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iam.v1.ServiceAccountService",
+	HandlerType: (*ServiceAccountServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "ListSessions",
-			Handler:    _SessionService_ListSessions_Handler,
+			MethodName: "CreateServiceAccount",
+			Handler:    _ServiceAccountService_CreateServiceAccount_Handler,
 		},
 		{
-			MethodName: "RevokeSession",
-			Handler:    _SessionService_RevokeSession_Handler,
+			MethodName: "GetServiceAccount",
+			Handler:    _ServiceAccountService_GetServiceAccount_Handler,
 		},
 		{
-			MethodName: "RevokeAllOtherSessions",
-			Handler:    _SessionService_RevokeAllOtherSessions_Handler,
+			MethodName: "ListServiceAccounts",
+			Handler:    _ServiceAccountService_ListServiceAccounts_Handler,
+		},
+		{
+			MethodName: "DeleteServiceAccount",
+			Handler:    _ServiceAccountService_DeleteServiceAccount_Handler,
+		},
+		{
+			MethodName: "BindServiceAccountRole",
+			Handler:    _ServiceAccountService_BindServiceAccountRole_Handler,
+		},
+		{
+			MethodName: "UnbindServiceAccountRole",
+			Handler:    _ServiceAccountService_UnbindServiceAccountRole_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -743,6 +2021,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
 // SecretService manages API key/secret pairs for service-to-service authentication.
+//
+// Deprecated: use APIKeyService, which never returns stored secrets.
 type SecretServiceClient interface {
 	// CreateSecret generates a new API key/secret pair.
 	CreateSecret(ctx context.Context, in *CreateSecretRequest, opts ...grpc.CallOption) (*Secret, error)
@@ -819,6 +2099,8 @@ func (c *secretServiceClient) RotateSecret(ctx context.Context, in *RotateSecret
 // for forward compatibility.
 //
 // SecretService manages API key/secret pairs for service-to-service authentication.
+//
+// Deprecated: use APIKeyService, which never returns stored secrets.
 type SecretServiceServer interface {
 	// CreateSecret generates a new API key/secret pair.
 	CreateSecret(context.Context, *CreateSecretRequest) (*Secret, error)
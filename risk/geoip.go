@@ -0,0 +1,91 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"sort"
+	"sync"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// GeoInfo is what a GeoIP database knows about an address.
+type GeoInfo struct {
+	Location iam.Location
+	ASN      uint32
+	ASOrg    string
+}
+
+// GeoIP resolves IP addresses to locations and autonomous systems without
+// network calls. Adapt an offline database (e.g. a MaxMind GeoLite2 reader)
+// to this interface.
+type GeoIP interface {
+	// Lookup returns what is known about ip, or false if nothing is.
+	Lookup(ip netip.Addr) (GeoInfo, bool)
+}
+
+// PrefixDB is an in-memory GeoIP database of CIDR prefixes, matched by
+// longest prefix. Useful for tests, private address plans and small
+// deployments without a commercial database. Safe for concurrent use.
+type PrefixDB struct {
+	mu      sync.RWMutex
+	entries []prefixEntry // sorted by descending prefix length
+}
+
+type prefixEntry struct {
+	prefix netip.Prefix
+	info   GeoInfo
+}
+
+// NewPrefixDB creates an empty PrefixDB.
+func NewPrefixDB() *PrefixDB {
+	return &PrefixDB{}
+}
+
+// Add maps every address in cidr (e.g. "203.0.113.0/24") to info.
+func (db *PrefixDB) Add(cidr string, info GeoInfo) error {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return fmt.Errorf("iam/risk: invalid prefix %q: %w", cidr, err)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.entries = append(db.entries, prefixEntry{prefix: prefix.Masked(), info: info})
+	sort.SliceStable(db.entries, func(i, j int) bool {
+		return db.entries[i].prefix.Bits() > db.entries[j].prefix.Bits()
+	})
+	return nil
+}
+
+// Lookup implements GeoIP.
+func (db *PrefixDB) Lookup(ip netip.Addr) (GeoInfo, bool) {
+	ip = ip.Unmap()
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	for _, e := range db.entries {
+		if e.prefix.Contains(ip) {
+			return e.info, true
+		}
+	}
+	return GeoInfo{}, false
+}
+
+const earthRadiusKm = 6371.0
+
+// distanceKm returns the great-circle distance between two locations.
+func distanceKm(a, b iam.Location) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// hasCoordinates reports whether loc carries a usable position.
+func hasCoordinates(loc iam.Location) bool {
+	return loc.Latitude != 0 || loc.Longitude != 0
+}
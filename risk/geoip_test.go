@@ -0,0 +1,49 @@
+package risk
+
+import (
+	"math"
+	"net/netip"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+func TestPrefixDB_LongestPrefix(t *testing.T) {
+	db := NewPrefixDB()
+	if err := db.Add("203.0.0.0/8", GeoInfo{ASN: 1, Location: iam.Location{Country: "AU"}}); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	if err := db.Add("203.0.113.0/24", GeoInfo{ASN: 2, Location: iam.Location{Country: "JP"}}); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	info, ok := db.Lookup(netip.MustParseAddr("203.0.113.7"))
+	if !ok || info.Location.Country != "JP" {
+		t.Errorf("expected most specific prefix (JP), got %+v", info)
+	}
+	info, ok = db.Lookup(netip.MustParseAddr("::ffff:203.1.1.1"))
+	if !ok || info.Location.Country != "AU" {
+		t.Errorf("expected IPv4-mapped address to match AU, got %+v", info)
+	}
+	if _, ok := db.Lookup(netip.MustParseAddr("198.51.100.1")); ok {
+		t.Error("expected no match for unknown address")
+	}
+}
+
+func TestPrefixDB_InvalidPrefix(t *testing.T) {
+	if err := NewPrefixDB().Add("not-a-prefix", GeoInfo{}); err == nil {
+		t.Fatal("expected error for invalid prefix")
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	taipei := iam.Location{Latitude: 25.03, Longitude: 121.56}
+	london := iam.Location{Latitude: 51.51, Longitude: -0.13}
+
+	if d := distanceKm(taipei, london); math.Abs(d-9780) > 50 {
+		t.Errorf("distance Taipei-London = %.0f km, want ~9780", d)
+	}
+	if d := distanceKm(taipei, taipei); d != 0 {
+		t.Errorf("distance to self = %v, want 0", d)
+	}
+}
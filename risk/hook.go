@@ -0,0 +1,148 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/audit"
+)
+
+// ErrStepUpRequired is returned by Hook.Check when the request must be
+// re-authenticated before it can proceed. It wraps iam.ErrStepUpRequired.
+var ErrStepUpRequired = fmt.Errorf("iam/risk: %w", iam.ErrStepUpRequired)
+
+// ErrSessionRevoked is returned by Hook.Check when the session was revoked
+// because of the assessment. It wraps iam.ErrSessionInvalid.
+var ErrSessionRevoked = fmt.Errorf("iam/risk: session revoked: %w", iam.ErrSessionInvalid)
+
+// AuditAction is the audit.Event action for risk alerts.
+const AuditAction = "session_risk"
+
+// Hook runs an Evaluator for the auth middleware and applies the resulting
+// Action: alerts and rejections are written to the audit log, and
+// ActionRevoke revokes the session.
+//
+// Evaluation errors are audited and the request proceeds — the hook is a
+// detection layer on top of token and session validation, not a gate — except
+// for errors wrapping iam.ErrSessionInvalid: a session that no longer exists
+// is rejected like a revoked one.
+type Hook struct {
+	evaluator Evaluator
+	sessions  iam.SessionService
+	logger    *audit.Logger
+}
+
+// HookOption configures Hook behavior.
+type HookOption func(*Hook)
+
+// WithRevoker sets the SessionService used to revoke sessions on ActionRevoke.
+// Without it, ActionRevoke only rejects the request.
+func WithRevoker(sessions iam.SessionService) HookOption {
+	return func(h *Hook) {
+		h.sessions = sessions
+	}
+}
+
+// WithAuditLogger sets the audit logger. Defaults to audit.FromContext.
+func WithAuditLogger(l *audit.Logger) HookOption {
+	return func(h *Hook) {
+		h.logger = l
+	}
+}
+
+// NewHook creates a Hook around evaluator.
+func NewHook(evaluator Evaluator, opts ...HookOption) *Hook {
+	h := &Hook{evaluator: evaluator}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Check evaluates req and applies the assessment. It returns ErrStepUpRequired
+// or ErrSessionRevoked when the request must be rejected, or the Revoke error
+// if the session could not be revoked. The returned context carries the
+// assessment (see FromContext).
+func (h *Hook) Check(ctx context.Context, req Request) (context.Context, error) {
+	a, err := h.evaluator.Evaluate(ctx, req)
+	if errors.Is(err, iam.ErrSessionInvalid) {
+		h.audit(ctx, req, a, "denied", err)
+		return ctx, ErrSessionRevoked
+	}
+	if err != nil {
+		h.audit(ctx, req, a, "error", err)
+		return ctx, nil
+	}
+	ctx = WithAssessment(ctx, a)
+
+	switch a.Action {
+	case ActionAlert:
+		h.audit(ctx, req, a, "flagged", nil)
+	case ActionStepUp:
+		h.audit(ctx, req, a, "denied", nil)
+		return ctx, ErrStepUpRequired
+	case ActionRevoke:
+		if h.sessions != nil && req.SessionID != "" {
+			if err := h.sessions.Revoke(ctx, req.SessionID); err != nil {
+				err = fmt.Errorf("iam/risk: revoke session: %w", err)
+				h.audit(ctx, req, a, "denied", err)
+				return ctx, err
+			}
+		}
+		h.audit(ctx, req, a, "denied", nil)
+		return ctx, ErrSessionRevoked
+	}
+	return ctx, nil
+}
+
+func (h *Hook) audit(ctx context.Context, req Request, a Assessment, result string, err error) {
+	logger := h.logger
+	if logger == nil {
+		logger = audit.FromContext(ctx)
+	}
+	if logger == nil {
+		return
+	}
+
+	names := make([]string, len(a.Signals))
+	for i, s := range a.Signals {
+		names[i] = s.Name
+	}
+	event := audit.Event{
+		RequestID: audit.RequestID(ctx),
+		ActorID:   iam.ActorIDFromContext(ctx),
+		Action:    AuditAction,
+		Resource:  req.SessionID,
+		Result:    result,
+		Details:   fmt.Sprintf("score=%d action=%s signals=%s", a.Score, a.Action, strings.Join(names, ",")),
+		IP:        req.Fingerprint.IP,
+		UserAgent: req.Fingerprint.UserAgent,
+	}
+	if req.Claims != nil {
+		event.UserID = req.Claims.Subject
+		event.PrincipalType = string(req.Claims.Principal())
+		event.TenantID = req.Claims.TenantID
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	logger.Log(event)
+}
+
+type contextKey string
+
+const contextKeyAssessment contextKey = "risk.assessment"
+
+// WithAssessment stores an assessment in the context.
+func WithAssessment(ctx context.Context, a Assessment) context.Context {
+	return context.WithValue(ctx, contextKeyAssessment, a)
+}
+
+// FromContext returns the assessment made for the current request, if any.
+func FromContext(ctx context.Context) (Assessment, bool) {
+	a, ok := ctx.Value(contextKeyAssessment).(Assessment)
+	return a, ok
+}
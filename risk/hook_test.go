@@ -0,0 +1,139 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/audit"
+	"github.com/chimerakang/iam-go/audit/audittest"
+	"github.com/chimerakang/iam-go/fake"
+)
+
+func staticEvaluator(a Assessment, err error) Evaluator {
+	return EvaluatorFunc(func(context.Context, Request) (Assessment, error) { return a, err })
+}
+
+func hookRequest() Request {
+	return Request{
+		Claims:      &iam.Claims{Subject: "u1", TenantID: "t1"},
+		SessionID:   "s1",
+		Fingerprint: Fingerprint{IP: "203.0.113.10"},
+	}
+}
+
+func TestHook_Allow(t *testing.T) {
+	rec := audittest.NewRecorder()
+	h := NewHook(staticEvaluator(Assessment{Score: 10}, nil), WithAuditLogger(rec.Logger))
+
+	ctx, err := h.Check(context.Background(), hookRequest())
+
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if a, ok := FromContext(ctx); !ok || a.Score != 10 {
+		t.Errorf("expected assessment in context, got %+v", a)
+	}
+	if n := len(rec.Events()); n != 0 {
+		t.Errorf("expected no audit events, got %d", n)
+	}
+}
+
+func TestHook_Alert(t *testing.T) {
+	rec := audittest.NewRecorder()
+	a := Assessment{Score: 40, Action: ActionAlert, Signals: []Signal{{Name: "new_country", Score: 40}}}
+	h := NewHook(staticEvaluator(a, nil), WithAuditLogger(rec.Logger))
+
+	if _, err := h.Check(context.Background(), hookRequest()); err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	got := rec.Events()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 audit event, got %d", len(got))
+	}
+	e := got[0]
+	if e.Action != AuditAction || e.Result != "flagged" || e.UserID != "u1" || e.IP != "203.0.113.10" {
+		t.Errorf("unexpected audit event: %+v", e)
+	}
+	if e.Details != "score=40 action=alert signals=new_country" {
+		t.Errorf("unexpected details: %s", e.Details)
+	}
+}
+
+func TestHook_StepUp(t *testing.T) {
+	h := NewHook(staticEvaluator(Assessment{Score: 60, Action: ActionStepUp}, nil))
+
+	_, err := h.Check(context.Background(), hookRequest())
+
+	if !errors.Is(err, ErrStepUpRequired) || !errors.Is(err, iam.ErrStepUpRequired) {
+		t.Errorf("expected ErrStepUpRequired, got %v", err)
+	}
+}
+
+func TestHook_Revoke(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", nil),
+		fake.WithSession("u1", "s1"),
+	)
+	h := NewHook(staticEvaluator(Assessment{Score: 100, Action: ActionRevoke}, nil), WithRevoker(client.Sessions()))
+
+	_, err := h.Check(context.Background(), hookRequest())
+
+	if !errors.Is(err, ErrSessionRevoked) || !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+	if _, err := client.Sessions().Validate(context.Background(), "s1"); !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("expected session s1 to be revoked, got %v", err)
+	}
+}
+
+// failingRevoker is a SessionService whose Revoke fails.
+type failingRevoker struct {
+	iam.SessionService
+	err error
+}
+
+func (r failingRevoker) Revoke(context.Context, string) error { return r.err }
+
+func TestHook_RevokeError(t *testing.T) {
+	revokeErr := errors.New("backend unavailable")
+	h := NewHook(staticEvaluator(Assessment{Score: 100, Action: ActionRevoke}, nil), WithRevoker(failingRevoker{err: revokeErr}))
+
+	_, err := h.Check(context.Background(), hookRequest())
+
+	if !errors.Is(err, revokeErr) || errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("expected the revoke error, got %v", err)
+	}
+}
+
+func TestHook_InvalidSessionRejected(t *testing.T) {
+	rec := audittest.NewRecorder()
+	evalErr := fmt.Errorf("load session: %w", iam.ErrSessionInvalid)
+	h := NewHook(staticEvaluator(Assessment{}, evalErr), WithAuditLogger(rec.Logger))
+
+	_, err := h.Check(context.Background(), hookRequest())
+
+	if !errors.Is(err, ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+	if got := rec.Events(); len(got) != 1 || got[0].Result != "denied" {
+		t.Errorf("expected one denied audit event, got %+v", got)
+	}
+}
+
+func TestHook_EvaluationErrorFailsOpen(t *testing.T) {
+	rec := audittest.NewRecorder()
+	h := NewHook(staticEvaluator(Assessment{}, errors.New("geoip unavailable")))
+
+	_, err := h.Check(audit.WithContext(context.Background(), rec.Logger), hookRequest())
+
+	if err != nil {
+		t.Fatalf("expected request to proceed, got %v", err)
+	}
+	if got := rec.Events(); len(got) != 1 || got[0].Result != "error" {
+		t.Errorf("expected one error audit event from context logger, got %+v", got)
+	}
+}
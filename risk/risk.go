@@ -0,0 +1,304 @@
+// Package risk scores requests for signs of session hijacking.
+//
+// The auth middleware builds a Request from the verified token and the
+// incoming connection (IP, User-Agent) and passes it to an Evaluator. The
+// default Engine compares that fingerprint with the one recorded on the
+// session — country, autonomous system, browser/OS family — and flags
+// impossible travel between consecutive uses. The resulting Assessment maps
+// to an Action; Hook applies it (step-up, revocation, audit alert).
+package risk
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/cache"
+	"github.com/chimerakang/iam-go/session"
+)
+
+// Fingerprint describes where a request came from.
+type Fingerprint struct {
+	IP        string
+	UserAgent string
+	At        time.Time
+}
+
+// Request is the input to an Evaluator.
+type Request struct {
+	Claims      *iam.Claims
+	SessionID   string
+	Fingerprint Fingerprint
+}
+
+// Signal is one reason a request looks risky.
+type Signal struct {
+	Name   string // e.g. "new_country", "impossible_travel"
+	Score  int
+	Detail string
+}
+
+// Action is what the caller should do about an assessed request.
+type Action int
+
+const (
+	// ActionAllow lets the request through.
+	ActionAllow Action = iota
+	// ActionAlert lets the request through and records an audit alert.
+	ActionAlert
+	// ActionStepUp rejects the request until the user re-authenticates.
+	ActionStepUp
+	// ActionRevoke revokes the session and rejects the request.
+	ActionRevoke
+)
+
+// String returns the action name used in audit events.
+func (a Action) String() string {
+	switch a {
+	case ActionAlert:
+		return "alert"
+	case ActionStepUp:
+		return "step_up"
+	case ActionRevoke:
+		return "revoke"
+	default:
+		return "allow"
+	}
+}
+
+// Assessment is the outcome of evaluating a request.
+type Assessment struct {
+	Score   int // 0-100
+	Signals []Signal
+	Action  Action
+}
+
+// Evaluator scores a request. Implementations must be safe for concurrent use.
+type Evaluator interface {
+	Evaluate(ctx context.Context, req Request) (Assessment, error)
+}
+
+// EvaluatorFunc adapts a function to Evaluator.
+type EvaluatorFunc func(ctx context.Context, req Request) (Assessment, error)
+
+// Evaluate implements Evaluator.
+func (f EvaluatorFunc) Evaluate(ctx context.Context, req Request) (Assessment, error) {
+	return f(ctx, req)
+}
+
+// Signal scores used by Engine.
+const (
+	ScoreIPChanged        = 10
+	ScoreNewASN           = 20
+	ScoreUserAgentChanged = 30
+	ScoreNewCountry       = 40
+	ScoreImpossibleTravel = 60
+)
+
+// Defaults for Engine options.
+const (
+	DefaultAlertThreshold  = 30
+	DefaultStepUpThreshold = 50
+	DefaultRevokeThreshold = 80
+	DefaultMaxTravelSpeed  = 1000.0 // km/h, roughly a commercial flight
+	DefaultBaselineTTL     = time.Minute
+)
+
+// minTravelDistanceKm ignores jumps within GeoIP accuracy.
+const minTravelDistanceKm = 100
+
+// Engine is the default Evaluator. It loads each session's recorded
+// fingerprint through SessionService.Validate (cached) and remembers the last
+// location a session was used from to detect impossible travel.
+type Engine struct {
+	sessions        iam.SessionService
+	geoip           GeoIP
+	alertThreshold  int
+	stepUpThreshold int
+	revokeThreshold int
+	maxTravelSpeed  float64
+	baselineTTL     time.Duration
+
+	baselines *cache.LRU[string, iam.Session]
+	lastSeen  *cache.LRU[string, observation]
+}
+
+type observation struct {
+	location iam.Location
+	at       time.Time
+}
+
+// Option configures Engine behavior.
+type Option func(*Engine)
+
+// WithGeoIP sets the GeoIP database. Without it only User-Agent and IP
+// changes are detected.
+func WithGeoIP(g GeoIP) Option {
+	return func(e *Engine) {
+		e.geoip = g
+	}
+}
+
+// WithThresholds sets the scores at which a request triggers an alert,
+// step-up and revocation (defaults: 30, 50, 80). A threshold <= 0 disables
+// that action.
+func WithThresholds(alert, stepUp, revoke int) Option {
+	return func(e *Engine) {
+		e.alertThreshold, e.stepUpThreshold, e.revokeThreshold = alert, stepUp, revoke
+	}
+}
+
+// WithMaxTravelSpeed sets the speed in km/h above which consecutive uses of a
+// session count as impossible travel (default: 1000).
+func WithMaxTravelSpeed(kmh float64) Option {
+	return func(e *Engine) {
+		e.maxTravelSpeed = kmh
+	}
+}
+
+// WithBaselineTTL sets how long a session's recorded fingerprint is cached
+// (default: 1 minute).
+func WithBaselineTTL(ttl time.Duration) Option {
+	return func(e *Engine) {
+		e.baselineTTL = ttl
+	}
+}
+
+// NewEngine creates an Engine that reads session fingerprints from sessions.
+func NewEngine(sessions iam.SessionService, opts ...Option) *Engine {
+	e := &Engine{
+		sessions:        sessions,
+		alertThreshold:  DefaultAlertThreshold,
+		stepUpThreshold: DefaultStepUpThreshold,
+		revokeThreshold: DefaultRevokeThreshold,
+		maxTravelSpeed:  DefaultMaxTravelSpeed,
+		baselineTTL:     DefaultBaselineTTL,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.baselines = cache.New[string, iam.Session](10000)
+	e.lastSeen = cache.New[string, observation](10000)
+	return e
+}
+
+// Evaluate implements Evaluator. Requests without a session ID are allowed:
+// there is no recorded fingerprint to compare with.
+func (e *Engine) Evaluate(ctx context.Context, req Request) (Assessment, error) {
+	if req.SessionID == "" {
+		return Assessment{}, nil
+	}
+
+	baseline, err := e.baseline(ctx, req.SessionID)
+	if err != nil {
+		return Assessment{}, err
+	}
+
+	at := req.Fingerprint.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	current := e.lookup(req.Fingerprint.IP)
+	recorded := e.lookup(baseline.IP)
+	if baseline.Location != (iam.Location{}) {
+		recorded.Location = baseline.Location
+	}
+
+	var signals []Signal
+	add := func(name string, score int, detail string) {
+		signals = append(signals, Signal{Name: name, Score: score, Detail: detail})
+	}
+
+	if c, r := current.Location.Country, recorded.Location.Country; c != "" && r != "" && c != r {
+		add("new_country", ScoreNewCountry, fmt.Sprintf("%s -> %s", r, c))
+	}
+	if c, r := current.ASN, recorded.ASN; c != 0 && r != 0 && c != r {
+		add("new_asn", ScoreNewASN, fmt.Sprintf("AS%d -> AS%d", r, c))
+	}
+	if changed, detail := userAgentChanged(baseline.UserAgent, req.Fingerprint.UserAgent); changed {
+		add("user_agent_changed", ScoreUserAgentChanged, detail)
+	}
+	if len(signals) == 0 && baseline.IP != "" && req.Fingerprint.IP != "" && baseline.IP != req.Fingerprint.IP {
+		add("ip_changed", ScoreIPChanged, fmt.Sprintf("%s -> %s", baseline.IP, req.Fingerprint.IP))
+	}
+
+	// Impossible travel: compare with the last place this session was seen,
+	// falling back to where it was recorded.
+	prev, ok := e.lastSeen.Get(req.SessionID)
+	if !ok {
+		prev = observation{location: recorded.Location, at: baseline.LastActiveAt}
+		if prev.at.IsZero() {
+			prev.at = baseline.CreatedAt
+		}
+	}
+	if hasCoordinates(current.Location) && hasCoordinates(prev.location) && !prev.at.IsZero() {
+		dist := distanceKm(prev.location, current.Location)
+		hours := at.Sub(prev.at).Hours()
+		if dist > minTravelDistanceKm && (hours <= 0 || dist/hours > e.maxTravelSpeed) {
+			add("impossible_travel", ScoreImpossibleTravel, fmt.Sprintf("%.0f km in %s", dist, at.Sub(prev.at).Round(time.Second)))
+		}
+	}
+	if hasCoordinates(current.Location) {
+		e.lastSeen.Set(req.SessionID, observation{location: current.Location, at: at}, 24*time.Hour)
+	}
+
+	return e.assess(signals), nil
+}
+
+func (e *Engine) assess(signals []Signal) Assessment {
+	a := Assessment{Signals: signals}
+	for _, s := range signals {
+		a.Score += s.Score
+	}
+	a.Score = min(a.Score, 100)
+
+	switch {
+	case e.revokeThreshold > 0 && a.Score >= e.revokeThreshold:
+		a.Action = ActionRevoke
+	case e.stepUpThreshold > 0 && a.Score >= e.stepUpThreshold:
+		a.Action = ActionStepUp
+	case e.alertThreshold > 0 && a.Score >= e.alertThreshold:
+		a.Action = ActionAlert
+	}
+	return a
+}
+
+func (e *Engine) baseline(ctx context.Context, sessionID string) (iam.Session, error) {
+	if s, ok := e.baselines.Get(sessionID); ok {
+		return s, nil
+	}
+	s, err := e.sessions.Validate(ctx, sessionID)
+	if err != nil {
+		return iam.Session{}, fmt.Errorf("iam/risk: load session: %w", err)
+	}
+	e.baselines.Set(sessionID, *s, e.baselineTTL)
+	return *s, nil
+}
+
+func (e *Engine) lookup(ip string) GeoInfo {
+	if e.geoip == nil || ip == "" {
+		return GeoInfo{}
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return GeoInfo{}
+	}
+	info, _ := e.geoip.Lookup(addr)
+	return info
+}
+
+// userAgentChanged reports a change of browser or OS family. Version bumps
+// within a family are expected and ignored.
+func userAgentChanged(recorded, current string) (bool, string) {
+	if recorded == "" || current == "" || recorded == current {
+		return false, ""
+	}
+	r, c := session.ParseUserAgent(recorded), session.ParseUserAgent(current)
+	if r.Browser == c.Browser && r.OS == c.OS {
+		return false, ""
+	}
+	return true, strings.TrimSpace(fmt.Sprintf("%s/%s -> %s/%s", r.Browser, r.OS, c.Browser, c.OS))
+}
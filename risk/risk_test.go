@@ -0,0 +1,168 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+)
+
+const (
+	chromeMac  = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	chromeMac2 = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_3) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36"
+	firefoxWin = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0"
+)
+
+var (
+	taipei = iam.Location{Country: "TW", City: "Taipei", Latitude: 25.03, Longitude: 121.56}
+	tainan = iam.Location{Country: "TW", City: "Tainan", Latitude: 22.99, Longitude: 120.21}
+	london = iam.Location{Country: "GB", City: "London", Latitude: 51.51, Longitude: -0.13}
+)
+
+func testGeoIP(t *testing.T) *PrefixDB {
+	t.Helper()
+	db := NewPrefixDB()
+	for cidr, info := range map[string]GeoInfo{
+		"198.51.100.0/24": {Location: taipei, ASN: 3462},
+		"198.51.101.0/24": {Location: taipei, ASN: 9924},
+		"198.51.102.0/24": {Location: tainan, ASN: 3462},
+		"203.0.113.0/24":  {Location: london, ASN: 2856},
+	} {
+		if err := db.Add(cidr, info); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func newTestEngine(t *testing.T, opts ...Option) *Engine {
+	t.Helper()
+	client := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", []string{"admin"}),
+		fake.WithSessionDetails(iam.Session{
+			ID:           "s1",
+			UserID:       "u1",
+			IP:           "198.51.100.10",
+			UserAgent:    chromeMac,
+			LastActiveAt: time.Now().Add(-time.Hour),
+		}),
+	)
+	return NewEngine(client.Sessions(), append([]Option{WithGeoIP(testGeoIP(t))}, opts...)...)
+}
+
+func evaluate(t *testing.T, e *Engine, ip, ua string) Assessment {
+	t.Helper()
+	a, err := e.Evaluate(context.Background(), Request{
+		SessionID:   "s1",
+		Fingerprint: Fingerprint{IP: ip, UserAgent: ua, At: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	return a
+}
+
+func signalNames(a Assessment) map[string]bool {
+	names := make(map[string]bool)
+	for _, s := range a.Signals {
+		names[s.Name] = true
+	}
+	return names
+}
+
+func TestEngine_SameFingerprint(t *testing.T) {
+	a := evaluate(t, newTestEngine(t), "198.51.100.10", chromeMac)
+
+	if a.Score != 0 || a.Action != ActionAllow {
+		t.Errorf("expected clean assessment, got %+v", a)
+	}
+}
+
+func TestEngine_BrowserUpdateIgnored(t *testing.T) {
+	a := evaluate(t, newTestEngine(t), "198.51.100.10", chromeMac2)
+
+	if a.Score != 0 {
+		t.Errorf("expected version bump to be ignored, got %+v", a)
+	}
+}
+
+func TestEngine_IPChangeOnly(t *testing.T) {
+	a := evaluate(t, newTestEngine(t), "198.51.100.20", chromeMac)
+
+	if !signalNames(a)["ip_changed"] || a.Action != ActionAllow {
+		t.Errorf("expected low-risk ip_changed, got %+v", a)
+	}
+}
+
+func TestEngine_NewASNAndUserAgent(t *testing.T) {
+	a := evaluate(t, newTestEngine(t), "198.51.101.10", firefoxWin)
+
+	names := signalNames(a)
+	if !names["new_asn"] || !names["user_agent_changed"] {
+		t.Errorf("expected new_asn and user_agent_changed, got %+v", a.Signals)
+	}
+	if a.Score != ScoreNewASN+ScoreUserAgentChanged || a.Action != ActionStepUp {
+		t.Errorf("expected score 50 and step-up, got %d/%s", a.Score, a.Action)
+	}
+}
+
+func TestEngine_ImpossibleTravel(t *testing.T) {
+	a := evaluate(t, newTestEngine(t), "203.0.113.10", chromeMac)
+
+	names := signalNames(a)
+	if !names["new_country"] || !names["impossible_travel"] {
+		t.Errorf("expected new_country and impossible_travel, got %+v", a.Signals)
+	}
+	if a.Action != ActionRevoke {
+		t.Errorf("expected revoke, got %s (score %d)", a.Action, a.Score)
+	}
+}
+
+func TestEngine_PlausibleTravel(t *testing.T) {
+	// Taipei -> Tainan (~270 km) an hour after the last activity is fine.
+	a := evaluate(t, newTestEngine(t), "198.51.102.10", chromeMac)
+
+	if signalNames(a)["impossible_travel"] {
+		t.Errorf("unexpected impossible_travel: %+v", a.Signals)
+	}
+}
+
+func TestEngine_TravelBetweenRequests(t *testing.T) {
+	e := newTestEngine(t)
+
+	evaluate(t, e, "198.51.102.10", chromeMac) // Tainan, now
+	a := evaluate(t, e, "198.51.100.10", chromeMac)
+
+	// Back to Taipei seconds later: matches the recorded session but not the last use.
+	if !signalNames(a)["impossible_travel"] {
+		t.Errorf("expected impossible_travel against last observed location, got %+v", a.Signals)
+	}
+}
+
+func TestEngine_NoSession(t *testing.T) {
+	a, err := newTestEngine(t).Evaluate(context.Background(), Request{Fingerprint: Fingerprint{IP: "203.0.113.10"}})
+
+	if err != nil || a.Score != 0 {
+		t.Errorf("expected requests without session to pass, got %+v, %v", a, err)
+	}
+}
+
+func TestEngine_UnknownSession(t *testing.T) {
+	_, err := newTestEngine(t).Evaluate(context.Background(), Request{SessionID: "missing"})
+
+	if err == nil {
+		t.Fatal("expected error for unknown session")
+	}
+}
+
+func TestEngine_Thresholds(t *testing.T) {
+	e := newTestEngine(t, WithThresholds(10, 0, 0))
+
+	a := evaluate(t, e, "203.0.113.10", chromeMac)
+
+	if a.Action != ActionAlert {
+		t.Errorf("expected alert with step-up and revoke disabled, got %s", a.Action)
+	}
+}
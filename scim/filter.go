@@ -0,0 +1,224 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// filter is a parsed SCIM filter (RFC 7644 §3.4.2.2) in disjunctive normal
+// form: it matches when every comparison of any one clause matches.
+//
+// Supported: the comparison operators eq, ne, co, sw, ew, pr, gt, ge, lt, le
+// combined with "and" and "or" ("and" binds tighter). Parentheses, "not" and
+// value-path filters such as emails[type eq "work"] are rejected.
+type filter struct {
+	clauses [][]comparison
+}
+
+type comparison struct {
+	attr  string // lower-case, schema URN prefix removed
+	op    string // lower-case
+	value string
+}
+
+// caseExactAttrs compare case-sensitively; all other attributes are
+// case-insensitive, as userName and displayName are in the core schema.
+var caseExactAttrs = map[string]bool{
+	"value":         true, // members[value eq "..."] in PATCH paths
+	"id":            true,
+	"externalid":    true,
+	"groups":        true,
+	"groups.value":  true,
+	"members":       true,
+	"members.value": true,
+}
+
+func parseFilter(s string) (*filter, error) {
+	tokens, err := tokenizeFilter(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter")
+	}
+
+	f := &filter{}
+	var clause []comparison
+	for i := 0; i < len(tokens); {
+		if tokens[i].quoted {
+			return nil, fmt.Errorf("expected attribute, got %q", tokens[i].text)
+		}
+		attr := normalizeAttr(tokens[i].text)
+		if strings.ContainsAny(attr, "[]()") || attr == "not" {
+			return nil, fmt.Errorf("unsupported filter expression %q", tokens[i].text)
+		}
+		if i+1 >= len(tokens) {
+			return nil, fmt.Errorf("missing operator after %q", tokens[i].text)
+		}
+		op := strings.ToLower(tokens[i+1].text)
+		c := comparison{attr: attr, op: op}
+		switch op {
+		case "pr":
+			i += 2
+		case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+			if i+2 >= len(tokens) {
+				return nil, fmt.Errorf("missing value after %q %q", tokens[i].text, op)
+			}
+			v := tokens[i+2]
+			if !v.quoted {
+				// true, false, null and numbers
+				v.text = strings.ToLower(v.text)
+			}
+			c.value = v.text
+			i += 3
+		default:
+			return nil, fmt.Errorf("unsupported operator %q", tokens[i+1].text)
+		}
+		clause = append(clause, c)
+
+		if i == len(tokens) {
+			break
+		}
+		switch strings.ToLower(tokens[i].text) {
+		case "and":
+		case "or":
+			f.clauses = append(f.clauses, clause)
+			clause = nil
+		default:
+			return nil, fmt.Errorf("expected \"and\" or \"or\", got %q", tokens[i].text)
+		}
+		i++
+		if i == len(tokens) {
+			return nil, fmt.Errorf("filter ends with a logical operator")
+		}
+	}
+	f.clauses = append(f.clauses, clause)
+	return f, nil
+}
+
+// match evaluates the filter against a resource. get returns the values of a
+// (normalized) attribute; multi-valued attributes match if any value does.
+func (f *filter) match(get func(attr string) []string) bool {
+	for _, clause := range f.clauses {
+		ok := true
+		for _, c := range clause {
+			if !c.match(get(c.attr)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparison) match(values []string) bool {
+	if c.op == "pr" {
+		for _, v := range values {
+			if v != "" {
+				return true
+			}
+		}
+		return false
+	}
+	if c.op == "ne" {
+		for _, v := range values {
+			if c.equal(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	want := c.value
+	for _, v := range values {
+		if !caseExactAttrs[c.attr] {
+			v, want = strings.ToLower(v), strings.ToLower(c.value)
+		}
+		var ok bool
+		switch c.op {
+		case "eq":
+			ok = v == want
+		case "co":
+			ok = strings.Contains(v, want)
+		case "sw":
+			ok = strings.HasPrefix(v, want)
+		case "ew":
+			ok = strings.HasSuffix(v, want)
+		case "gt":
+			ok = v > want
+		case "ge":
+			ok = v >= want
+		case "lt":
+			ok = v < want
+		case "le":
+			ok = v <= want
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparison) equal(v string) bool {
+	if caseExactAttrs[c.attr] {
+		return v == c.value
+	}
+	return strings.EqualFold(v, c.value)
+}
+
+// normalizeAttr lower-cases an attribute path and strips a schema URN prefix
+// ("urn:ietf:params:scim:schemas:core:2.0:User:userName" -> "username").
+func normalizeAttr(attr string) string {
+	attr = strings.ToLower(attr)
+	if strings.HasPrefix(attr, "urn:") {
+		attr = attr[strings.LastIndex(attr, ":")+1:]
+	}
+	return attr
+}
+
+type filterToken struct {
+	text   string
+	quoted bool
+}
+
+// tokenizeFilter splits a filter on whitespace, keeping JSON string literals
+// (which may contain spaces and escapes) as single tokens.
+func tokenizeFilter(s string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == ' ' || s[i] == '\t':
+			i++
+		case s[i] == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			var text string
+			if err := json.Unmarshal([]byte(s[i:end+1]), &text); err != nil {
+				return nil, fmt.Errorf("invalid string %s", s[i:end+1])
+			}
+			tokens = append(tokens, filterToken{text: text, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && s[end] != ' ' && s[end] != '\t' {
+				end++
+			}
+			tokens = append(tokens, filterToken{text: s[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
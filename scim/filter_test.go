@@ -0,0 +1,63 @@
+package scim
+
+import "testing"
+
+func TestParseFilter(t *testing.T) {
+	attrs := func(values map[string][]string) func(string) []string {
+		return func(attr string) []string { return values[attr] }
+	}
+	user := attrs(map[string][]string{
+		"username":     {"Alice@Example.com"},
+		"externalid":   {"ABC"},
+		"active":       {"true"},
+		"groups":       {"admin", "editor"},
+		"groups.value": {"admin", "editor"},
+		"displayname":  {"Alice Liddell"},
+	})
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`userName eq "alice@example.com"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice@example.com"`, true},
+		{`externalId eq "abc"`, false},
+		{`externalId eq "ABC"`, true},
+		{`displayName co "lid"`, true},
+		{`userName sw "bob" or groups.value eq "editor"`, true},
+		{`userName sw "alice" and active eq false`, false},
+		{`active eq true and groups eq "admin"`, true},
+		{`title pr`, false},
+		{`userName pr`, true},
+		{`groups ne "viewer"`, true},
+		{`displayName eq "Alice \"Liddell\""`, false},
+	}
+	for _, tt := range tests {
+		f, err := parseFilter(tt.filter)
+		if err != nil {
+			t.Errorf("parseFilter(%s) error: %v", tt.filter, err)
+			continue
+		}
+		if got := f.match(user); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, s := range []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName foo "x"`,
+		`userName eq "x" and`,
+		`userName eq "x" xor active eq true`,
+		`emails[type eq "work"]`,
+		`not (userName eq "x")`,
+		`userName eq "unterminated`,
+	} {
+		if _, err := parseFilter(s); err == nil {
+			t.Errorf("parseFilter(%q) expected error", s)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// applyUserPatch applies PATCH operations to a SCIM user in place.
+//
+// displayName and name map to the same IAM attribute, so changing either
+// keeps the other in step; likewise userName and the primary email.
+func applyUserPatch(u *User, ops []PatchOperation) error {
+	for _, op := range ops {
+		kind := strings.ToLower(op.Op)
+		switch {
+		case kind != "add" && kind != "replace" && kind != "remove":
+			return badRequest("invalidSyntax", "unsupported patch op %q", op.Op)
+		case op.Path == "" && kind == "remove":
+			return badRequest("noTarget", "remove requires a path")
+		case op.Path == "":
+			var attrs map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &attrs); err != nil {
+				return badRequest("invalidValue", "patch value without a path must be an object")
+			}
+			for attr, value := range attrs {
+				if attr == "schemas" {
+					continue
+				}
+				if err := setUserAttr(u, attr, value); err != nil {
+					return err
+				}
+			}
+		case kind == "remove":
+			if err := removeUserAttr(u, op.Path); err != nil {
+				return err
+			}
+		default:
+			if err := setUserAttr(u, op.Path, op.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setUserAttr(u *User, path string, value json.RawMessage) error {
+	attr := normalizeAttr(path)
+	switch {
+	case attr == "username":
+		s, err := decodeString(path, value)
+		if err != nil {
+			return err
+		}
+		u.UserName = s
+	case attr == "displayname", attr == "name.formatted":
+		s, err := decodeString(path, value)
+		if err != nil {
+			return err
+		}
+		setFullName(u, &Name{Formatted: s}, s)
+	case attr == "name":
+		var n Name
+		if err := json.Unmarshal(value, &n); err != nil {
+			return badRequest("invalidValue", "%s must be an object", path)
+		}
+		setFullName(u, &n, fullName(&User{Name: &n}))
+	case attr == "name.givenname", attr == "name.familyname":
+		s, err := decodeString(path, value)
+		if err != nil {
+			return err
+		}
+		n := Name{}
+		if u.Name != nil {
+			n = *u.Name
+		}
+		if attr == "name.givenname" {
+			n.GivenName = s
+		} else {
+			n.FamilyName = s
+		}
+		n.Formatted = ""
+		full := fullName(&User{Name: &n})
+		n.Formatted = full
+		setFullName(u, &n, full)
+	case attr == "externalid":
+		s, err := decodeString(path, value)
+		if err != nil {
+			return err
+		}
+		u.ExternalID = s
+	case attr == "active":
+		b, err := decodeBool(path, value)
+		if err != nil {
+			return err
+		}
+		u.Active = &b
+	case attr == "emails":
+		var emails []Email
+		if err := json.Unmarshal(value, &emails); err != nil || len(emails) == 0 {
+			return badRequest("invalidValue", "emails must be a non-empty array")
+		}
+		u.Emails = emails
+		u.UserName = email(&User{Emails: emails})
+	case attr == "emails.value", strings.HasPrefix(attr, "emails[") && strings.HasSuffix(attr, "].value"):
+		s, err := decodeString(path, value)
+		if err != nil {
+			return err
+		}
+		u.Emails = []Email{{Value: s, Type: "work", Primary: true}}
+		u.UserName = s
+	case attr == "id", attr == "groups", strings.HasPrefix(attr, "meta"):
+		return badRequest("mutability", "%s is read-only", path)
+	default:
+		return badRequest("invalidPath", "unsupported attribute %q", path)
+	}
+	return nil
+}
+
+func removeUserAttr(u *User, path string) error {
+	switch attr := normalizeAttr(path); {
+	case attr == "externalid":
+		u.ExternalID = ""
+	case attr == "displayname", attr == "name", strings.HasPrefix(attr, "name."):
+		setFullName(u, nil, "")
+	case attr == "username", attr == "active", strings.HasPrefix(attr, "emails"):
+		return badRequest("mutability", "%s is required", path)
+	case attr == "id", attr == "groups", strings.HasPrefix(attr, "meta"):
+		return badRequest("mutability", "%s is read-only", path)
+	default:
+		return badRequest("invalidPath", "unsupported attribute %q", path)
+	}
+	return nil
+}
+
+func setFullName(u *User, n *Name, display string) {
+	u.Name = n
+	u.DisplayName = display
+}
+
+// applyGroupPatch applies PATCH operations to a group's member list in place.
+// The display name is the role name and cannot change.
+func applyGroupPatch(g *Group, ops []PatchOperation) error {
+	for _, op := range ops {
+		kind := strings.ToLower(op.Op)
+		if kind != "add" && kind != "replace" && kind != "remove" {
+			return badRequest("invalidSyntax", "unsupported patch op %q", op.Op)
+		}
+
+		attr := normalizeAttr(op.Path)
+		switch {
+		case attr == "":
+			if kind == "remove" {
+				return badRequest("noTarget", "remove requires a path")
+			}
+			var attrs map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &attrs); err != nil {
+				return badRequest("invalidValue", "patch value without a path must be an object")
+			}
+			for name, value := range attrs {
+				switch normalizeAttr(name) {
+				case "schemas", "id":
+				case "displayname":
+					if err := checkDisplayName(g, value); err != nil {
+						return err
+					}
+				case "members":
+					if err := patchMembers(g, kind, value); err != nil {
+						return err
+					}
+				default:
+					return badRequest("invalidPath", "unsupported attribute %q", name)
+				}
+			}
+		case attr == "members":
+			if err := patchMembers(g, kind, op.Value); err != nil {
+				return err
+			}
+		case strings.HasPrefix(attr, "members[") && strings.HasSuffix(attr, "]"):
+			if kind != "remove" {
+				return badRequest("invalidPath", "%s is only supported with remove", op.Path)
+			}
+			// Keep the original case of the value inside the brackets.
+			inner := op.Path[strings.Index(op.Path, "[")+1 : len(op.Path)-1]
+			f, err := parseFilter(inner)
+			if err != nil {
+				return badRequest("invalidFilter", "%v", err)
+			}
+			kept := g.Members[:0]
+			for _, m := range g.Members {
+				m := m
+				if !f.match(func(a string) []string { return memberAttr(&m, a) }) {
+					kept = append(kept, m)
+				}
+			}
+			g.Members = kept
+		case attr == "displayname":
+			if kind == "remove" {
+				return badRequest("mutability", "displayName is required")
+			}
+			if err := checkDisplayName(g, op.Value); err != nil {
+				return err
+			}
+		default:
+			return badRequest("invalidPath", "unsupported attribute %q", op.Path)
+		}
+	}
+	return nil
+}
+
+func patchMembers(g *Group, kind string, value json.RawMessage) error {
+	var refs []Ref
+	if len(value) > 0 {
+		if err := json.Unmarshal(value, &refs); err != nil {
+			return badRequest("invalidValue", "members must be an array of {\"value\": id}")
+		}
+	}
+
+	switch kind {
+	case "replace":
+		g.Members = refs
+	case "add":
+		g.Members = append(g.Members, refs...)
+	case "remove":
+		if len(value) == 0 {
+			g.Members = nil
+			return nil
+		}
+		drop := make(map[string]bool, len(refs))
+		for _, r := range refs {
+			drop[r.Value] = true
+		}
+		kept := g.Members[:0]
+		for _, m := range g.Members {
+			if !drop[m.Value] {
+				kept = append(kept, m)
+			}
+		}
+		g.Members = kept
+	}
+	return nil
+}
+
+func checkDisplayName(g *Group, value json.RawMessage) error {
+	name, err := decodeString("displayName", value)
+	if err != nil {
+		return err
+	}
+	if name != g.DisplayName {
+		return badRequest("mutability", "groups are IAM roles and cannot be renamed")
+	}
+	return nil
+}
+
+func memberAttr(m *Ref, attr string) []string {
+	switch attr {
+	case "value":
+		return []string{m.Value}
+	case "display":
+		return []string{m.Display}
+	}
+	return nil
+}
+
+func decodeString(path string, value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", badRequest("invalidValue", "%s must be a string", path)
+	}
+	return s, nil
+}
+
+// decodeBool accepts JSON booleans and, as some identity providers send,
+// the strings "true" and "false" in any case.
+func decodeBool(path string, value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+	return false, badRequest("invalidValue", "%s must be a boolean", path)
+}
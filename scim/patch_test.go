@@ -0,0 +1,83 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func ops(t *testing.T, s string) []PatchOperation {
+	t.Helper()
+	var p PatchRequest
+	if err := json.Unmarshal([]byte(s), &p); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return p.Operations
+}
+
+func TestApplyUserPatch(t *testing.T) {
+	active := true
+	u := &User{UserName: "alice@example.com", DisplayName: "Alice", Name: &Name{Formatted: "Alice"}, Active: &active}
+
+	err := applyUserPatch(u, ops(t, `{"Operations": [
+		{"op": "replace", "value": {"active": false, "externalId": "ext-1"}},
+		{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "alice@corp.example.com"},
+		{"op": "add", "path": "name.givenName", "value": "Alicia"},
+		{"op": "add", "path": "name.familyName", "value": "Liddell"}
+	]}`))
+	if err != nil {
+		t.Fatalf("applyUserPatch: %v", err)
+	}
+	if *u.Active || u.ExternalID != "ext-1" || u.UserName != "alice@corp.example.com" {
+		t.Errorf("unexpected user: %+v", u)
+	}
+	if fullName(u) != "Alicia Liddell" {
+		t.Errorf("fullName = %q, want %q", fullName(u), "Alicia Liddell")
+	}
+
+	if err := applyUserPatch(u, ops(t, `{"Operations": [{"op": "remove", "path": "externalId"}]}`)); err != nil || u.ExternalID != "" {
+		t.Errorf("remove externalId: %v, %+v", err, u)
+	}
+}
+
+func TestApplyUserPatch_Errors(t *testing.T) {
+	tests := []struct {
+		patch    string
+		scimType string
+	}{
+		{`{"Operations": [{"op": "move", "path": "active", "value": true}]}`, "invalidSyntax"},
+		{`{"Operations": [{"op": "remove"}]}`, "noTarget"},
+		{`{"Operations": [{"op": "replace", "path": "nickName", "value": "Al"}]}`, "invalidPath"},
+		{`{"Operations": [{"op": "replace", "path": "active", "value": "maybe"}]}`, "invalidValue"},
+		{`{"Operations": [{"op": "add", "path": "groups", "value": [{"value": "admin"}]}]}`, "mutability"},
+		{`{"Operations": [{"op": "remove", "path": "userName"}]}`, "mutability"},
+	}
+	for _, tt := range tests {
+		err := applyUserPatch(&User{UserName: "a@example.com"}, ops(t, tt.patch))
+		var se *scimError
+		if !errors.As(err, &se) || se.scimType != tt.scimType {
+			t.Errorf("%s: error = %v, want scimType %s", tt.patch, err, tt.scimType)
+		}
+	}
+}
+
+func TestApplyGroupPatch(t *testing.T) {
+	g := &Group{DisplayName: "eng", Members: []Ref{{Value: "u1"}, {Value: "u2"}, {Value: "u3"}}}
+
+	err := applyGroupPatch(g, ops(t, `{"Operations": [
+		{"op": "remove", "path": "members", "value": [{"value": "u1"}]},
+		{"op": "add", "path": "members", "value": [{"value": "u4"}]},
+		{"op": "remove", "path": "members[value eq \"u3\"]"},
+		{"op": "replace", "value": {"id": "eng", "displayName": "eng"}}
+	]}`))
+	if err != nil {
+		t.Fatalf("applyGroupPatch: %v", err)
+	}
+	if got := refValues(g.Members); len(got) != 2 || got[0] != "u2" || got[1] != "u4" {
+		t.Errorf("members = %v, want [u2 u4]", got)
+	}
+
+	if err := applyGroupPatch(g, ops(t, `{"Operations": [{"op": "remove", "path": "members"}]}`)); err != nil || len(g.Members) != 0 {
+		t.Errorf("remove all members: %v, %v", err, g.Members)
+	}
+}
@@ -0,0 +1,287 @@
+package scim
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// Schema URNs (RFC 7643, RFC 7644).
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// MetadataExternalID is the iam.User metadata key holding the SCIM externalId
+// set by the provisioning client.
+const MetadataExternalID = "scim_external_id"
+
+// User is the SCIM representation of an iam.User. userName and the primary
+// email are both the user's email address; displayName and name.formatted
+// are both iam.User.Name; groups are the user's roles (read-only).
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Groups      []Ref    `json:"groups,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Name is the SCIM name complex attribute.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is one entry of the SCIM emails attribute.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Ref references another resource (a group member or a user's group).
+type Ref struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// Meta is the SCIM resource metadata.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+// Group is the SCIM representation of an IAM role: the group ID is the role
+// ID and its members are the users holding the role.
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Ref    `json:"members,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ListResponse is a page of query results.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// PatchRequest is the body of a PATCH request.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is one operation of a PatchRequest.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error is a SCIM error response.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func userResource(u *iam.User, baseURL string) *User {
+	active := u.Status != iam.UserStatusDisabled
+	r := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          u.ID,
+		UserName:    u.Email,
+		DisplayName: u.Name,
+		ExternalID:  externalID(u),
+		Active:      &active,
+	}
+	if u.Name != "" {
+		r.Name = &Name{Formatted: u.Name}
+	}
+	if u.Email != "" {
+		r.Emails = []Email{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	for _, role := range u.Roles {
+		r.Groups = append(r.Groups, Ref{Value: role.ID, Display: role.Name, Ref: baseURL + "/Groups/" + role.ID})
+	}
+	r.Meta = &Meta{ResourceType: "User", Location: baseURL + "/Users/" + u.ID}
+	r.Meta.Version = etag(r)
+	return r
+}
+
+func externalID(u *iam.User) string {
+	if v, ok := u.Metadata[MetadataExternalID]; ok {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// group is a role and the users holding it, collected from a user listing.
+type group struct {
+	role    iam.Role
+	members []*iam.User
+}
+
+func groupResource(g *group, baseURL string, withMembers bool) *Group {
+	r := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          g.role.ID,
+		DisplayName: g.role.Name,
+	}
+	members := make([]Ref, len(g.members))
+	for i, u := range g.members {
+		members[i] = Ref{Value: u.ID, Display: u.Email, Ref: baseURL + "/Users/" + u.ID}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Value < members[j].Value })
+
+	// The version always covers the members, even when they are excluded
+	// from the response.
+	r.Members = members
+	r.Meta = &Meta{ResourceType: "Group", Location: baseURL + "/Groups/" + g.role.ID}
+	r.Meta.Version = etag(r)
+	if !withMembers {
+		r.Members = nil
+	}
+	return r
+}
+
+// etag returns a weak entity tag over a resource's attributes (excluding
+// meta, which holds the tag itself).
+func etag(resource any) string {
+	var b []byte
+	switch r := resource.(type) {
+	case *User:
+		c := *r
+		c.Meta = nil
+		b, _ = json.Marshal(c)
+	case *Group:
+		c := *r
+		c.Meta = nil
+		b, _ = json.Marshal(c)
+	}
+	sum := sha256.Sum256(b)
+	return `W/"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// userAttrs returns the attribute getter used to evaluate filters on users.
+func userAttrs(u *User) func(string) []string {
+	return func(attr string) []string {
+		switch attr {
+		case "id":
+			return []string{u.ID}
+		case "externalid":
+			return []string{u.ExternalID}
+		case "username":
+			return []string{u.UserName}
+		case "displayname":
+			return []string{u.DisplayName}
+		case "name.formatted":
+			if u.Name != nil {
+				return []string{u.Name.Formatted}
+			}
+		case "emails", "emails.value":
+			values := make([]string, len(u.Emails))
+			for i, e := range u.Emails {
+				values[i] = e.Value
+			}
+			return values
+		case "active":
+			return []string{fmt.Sprint(u.Active != nil && *u.Active)}
+		case "groups", "groups.value":
+			return refValues(u.Groups)
+		case "groups.display":
+			return refDisplays(u.Groups)
+		case "meta.resourcetype":
+			return []string{"User"}
+		}
+		return nil
+	}
+}
+
+// groupAttrs returns the attribute getter used to evaluate filters on groups.
+func groupAttrs(g *Group) func(string) []string {
+	return func(attr string) []string {
+		switch attr {
+		case "id":
+			return []string{g.ID}
+		case "displayname":
+			return []string{g.DisplayName}
+		case "members", "members.value":
+			return refValues(g.Members)
+		case "members.display":
+			return refDisplays(g.Members)
+		case "meta.resourcetype":
+			return []string{"Group"}
+		}
+		return nil
+	}
+}
+
+func refValues(refs []Ref) []string {
+	values := make([]string, len(refs))
+	for i, r := range refs {
+		values[i] = r.Value
+	}
+	return values
+}
+
+func refDisplays(refs []Ref) []string {
+	values := make([]string, len(refs))
+	for i, r := range refs {
+		values[i] = r.Display
+	}
+	return values
+}
+
+// fullName picks the user's name from a SCIM User: displayName, then
+// name.formatted, then the given and family names.
+func fullName(u *User) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name == nil {
+		return ""
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+// email picks the user's email from a SCIM User: userName if it is an
+// address, otherwise the primary (or first) email.
+func email(u *User) string {
+	if strings.Contains(u.UserName, "@") || len(u.Emails) == 0 {
+		return u.UserName
+	}
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	return u.Emails[0].Value
+}
@@ -0,0 +1,993 @@
+// Package scim serves SCIM 2.0 provisioning (RFC 7643, RFC 7644) on top of
+// the iam interfaces, so identity providers such as Okta or Microsoft Entra ID
+// can create, update and deactivate users.
+//
+// /Users maps onto iam.UserService for reads and iam.UserAdminService for
+// writes. /Groups exposes the tenant's IAM roles, as listed by
+// iam.UserAdminService.ListRoles: a group's ID is the role ID and its members
+// are the users holding the role, if any. Membership changes become
+// AssignRole/RemoveRole calls; roles themselves are managed in the IAM server,
+// so creating, renaming and deleting groups is not supported.
+//
+// Requests authenticate and are authorized by the same pipeline as the
+// middleware packages (see httpmw.Auth and httpmw.Require), and must carry a
+// tenant: the handler only sees and creates users in the caller's tenant.
+// Writes go through user.Admin, so they are validated and audited.
+//
+//	mux.Handle("/scim/v2/", http.StripPrefix("/scim/v2",
+//		scim.NewHandler(client, "scim:provision"),
+//	))
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/apikey"
+	"github.com/chimerakang/iam-go/audit"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/internal/core"
+	"github.com/chimerakang/iam-go/risk"
+	"github.com/chimerakang/iam-go/serviceaccount"
+	"github.com/chimerakang/iam-go/user"
+)
+
+// DefaultMaxResults is the default page size limit for list requests.
+const DefaultMaxResults = 100
+
+// Handler is an http.Handler serving the SCIM 2.0 /Users, /Groups and
+// /ServiceProviderConfig endpoints.
+type Handler struct {
+	client      *iam.Client
+	admin       iam.UserAdminService
+	auditLogger *audit.Logger
+	auth        core.Config
+	baseURL     string
+	permission  string
+	maxResults  int
+	mux         *http.ServeMux
+}
+
+// Option configures Handler behavior.
+type Option func(*Handler)
+
+// WithBaseURL sets the absolute URL the handler is served under (e.g.
+// "https://idp.example.com/scim/v2"), used for meta.location and Location
+// headers. By default it is derived from each request.
+func WithBaseURL(u string) Option {
+	return func(h *Handler) {
+		h.baseURL = strings.TrimSuffix(u, "/")
+	}
+}
+
+// WithImpersonation accepts impersonation tokens (tokens with an RFC 8693
+// "act" claim) whose actor is allowed by p; the handler's permission is then
+// checked as by impersonate.Check. Without it such tokens are rejected with
+// 403.
+func WithImpersonation(p *impersonate.Policy) Option {
+	return func(h *Handler) {
+		h.auth.Impersonation = p
+	}
+}
+
+// WithAPIKeys also accepts API keys, as httpmw.WithAPIKeys does. The key's
+// scopes must include the handler's permission.
+func WithAPIKeys(v iam.TokenVerifier) Option {
+	return func(h *Handler) {
+		h.auth.APIKeys = v
+	}
+}
+
+// WithServiceAccounts resolves service-account callers with r, as
+// httpmw.WithServiceAccounts does.
+func WithServiceAccounts(r *serviceaccount.Resolver) Option {
+	return func(h *Handler) {
+		h.auth.ServiceAccounts = r
+	}
+}
+
+// WithPrincipalTypes admits only the given principal types; other callers
+// are rejected with 403.
+func WithPrincipalTypes(allowed ...iam.PrincipalType) Option {
+	return func(h *Handler) {
+		h.auth.Principals = append(h.auth.Principals, core.PrincipalRule{Pattern: "*", Types: allowed, Allow: true})
+	}
+}
+
+// WithRiskHook evaluates every authenticated request with rh, as
+// httpmw.WithRiskHook does.
+func WithRiskHook(rh *risk.Hook) Option {
+	return func(h *Handler) {
+		h.auth.RiskHook = rh
+	}
+}
+
+// WithTrustedProxies names the reverse proxies in front of the handler, for
+// the risk hook; see httpmw.WithTrustedProxies.
+func WithTrustedProxies(proxies ...netip.Prefix) Option {
+	return func(h *Handler) {
+		h.auth.TrustedProxies = append(h.auth.TrustedProxies, proxies...)
+	}
+}
+
+// WithMaxResults caps the count of a list request (default: 100).
+func WithMaxResults(n int) Option {
+	return func(h *Handler) {
+		h.maxResults = n
+	}
+}
+
+// WithAuditLogger sets the audit logger of the default user.Admin used for
+// writes. Defaults to audit.FromContext.
+func WithAuditLogger(l *audit.Logger) Option {
+	return func(h *Handler) {
+		h.auditLogger = l
+	}
+}
+
+// WithAdmin sets the service used for writes, replacing the default
+// user.Admin over client.UserAdmin(). admin is responsible for validating
+// and auditing the changes.
+func WithAdmin(admin iam.UserAdminService) Option {
+	return func(h *Handler) {
+		h.admin = admin
+	}
+}
+
+// NewHandler creates a SCIM handler backed by client. Callers must hold
+// permission, checked with client.Authz(); an empty permission denies every
+// request.
+func NewHandler(client *iam.Client, permission string, opts ...Option) *Handler {
+	h := &Handler{
+		client:     client,
+		permission: permission,
+		maxResults: DefaultMaxResults,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.admin == nil {
+		if backend := client.UserAdmin(); backend != nil {
+			h.admin = user.NewAdmin(backend, user.WithAuditLogger(h.auditLogger))
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ServiceProviderConfig", h.serviceProviderConfig)
+	mux.HandleFunc("GET /Users", h.listUsers)
+	mux.HandleFunc("POST /Users", h.createUser)
+	mux.HandleFunc("GET /Users/{id}", h.getUser)
+	mux.HandleFunc("PUT /Users/{id}", h.replaceUser)
+	mux.HandleFunc("PATCH /Users/{id}", h.patchUser)
+	mux.HandleFunc("DELETE /Users/{id}", h.deleteUser)
+	mux.HandleFunc("GET /Groups", h.listGroups)
+	mux.HandleFunc("GET /Groups/{id}", h.getGroup)
+	mux.HandleFunc("PUT /Groups/{id}", h.replaceGroup)
+	mux.HandleFunc("PATCH /Groups/{id}", h.patchGroup)
+	mux.HandleFunc("POST /Groups", h.unsupportedGroupChange)
+	mux.HandleFunc("DELETE /Groups/{id}", h.unsupportedGroupChange)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &scimError{status: http.StatusNotFound, detail: "unknown endpoint " + r.Method + " " + r.URL.Path})
+	})
+	h.mux = mux
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, err := h.authenticate(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	h.mux.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// authenticate runs the middleware pipeline on r, then requires the
+// handler's permission and a tenant.
+func (h *Handler) authenticate(r *http.Request) (context.Context, error) {
+	ctx, err := h.auth.Authenticate(r.Context(), h.client, core.Request{
+		Operation:     r.URL.Path,
+		Authorization: r.Header.Get("Authorization"),
+		APIKey:        r.Header.Get(apikey.HeaderName),
+		Tenant:        r.Header.Get(serviceaccount.TenantHeader),
+		Peer: func(context.Context) core.Peer {
+			return core.Peer{
+				RemoteAddr:   r.RemoteAddr,
+				ForwardedFor: r.Header.Values("X-Forwarded-For"),
+				RealIP:       r.Header.Get("X-Real-IP"),
+				UserAgent:    r.UserAgent(),
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if h.permission == "" {
+		return nil, &scimError{status: http.StatusForbidden, detail: "no permission configured"}
+	}
+	if err := core.RequireAll(ctx, h.client, h.permission); err != nil {
+		return nil, err
+	}
+	// Without a tenant the listings and lookups below would span every
+	// tenant, and created users would belong to none.
+	if iam.TenantIDFromContext(ctx) == "" {
+		return nil, &scimError{status: http.StatusForbidden, detail: "token has no tenant"}
+	}
+	return ctx, nil
+}
+
+// --- Users ---
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f, err := queryFilter(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	startIndex, count := h.window(q)
+	users, err := h.users()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	hint, exact := userFilterHint(f)
+	opts := iam.ListOptions{PageSize: h.maxResults, Filter: hint}
+	opts.Filter.TenantID = iam.TenantIDFromContext(r.Context())
+	resp := ListResponse{Schemas: []string{SchemaListResponse}, StartIndex: startIndex, Resources: []any{}}
+	if exact {
+		err = h.pageUsers(r, users, opts, &resp, count)
+	} else {
+		err = h.filterUsers(r, users, opts, f, &resp, count)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp.ItemsPerPage = len(resp.Resources)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// pageUsers fills resp from a listing whose filter the backend evaluates
+// exactly: the backend's Total is the result count, and pages are only
+// fetched up to the end of the requested window.
+func (h *Handler) pageUsers(r *http.Request, users iam.UserService, opts iam.ListOptions, resp *ListResponse, count int) error {
+	base := h.base(r)
+	skip := resp.StartIndex - 1
+	for {
+		list, err := users.List(r.Context(), opts)
+		if err != nil {
+			return err
+		}
+		if opts.PageToken == "" {
+			resp.TotalResults = list.Total
+		}
+		for _, u := range list.Users {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if len(resp.Resources) < count {
+				resp.Resources = append(resp.Resources, userResource(u, base))
+			}
+		}
+		if len(resp.Resources) == count || list.NextPageToken == "" {
+			return nil
+		}
+		opts.PageToken = list.NextPageToken
+	}
+}
+
+// filterUsers fills resp by evaluating f on every user of the listing,
+// which is narrowed by whatever part of f the backend can express.
+func (h *Handler) filterUsers(r *http.Request, users iam.UserService, opts iam.ListOptions, f *filter, resp *ListResponse, count int) error {
+	base := h.base(r)
+	for u, err := range user.All(r.Context(), users, opts) {
+		if err != nil {
+			return err
+		}
+		res := userResource(u, base)
+		if !f.match(userAttrs(res)) {
+			continue
+		}
+		resp.TotalResults++
+		if resp.TotalResults >= resp.StartIndex && len(resp.Resources) < count {
+			resp.Resources = append(resp.Resources, res)
+		}
+	}
+	return nil
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	u, err := h.loadUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := userResource(u, h.base(r))
+	if r.Header.Get("If-None-Match") == res.Meta.Version {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeResource(w, http.StatusOK, res, res.Meta)
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var in User
+	if err := decodeBody(r, &in); err != nil {
+		writeError(w, err)
+		return
+	}
+	admin, err := h.adminService()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	input := iam.CreateUserInput{
+		Email:    email(&in),
+		Name:     fullName(&in),
+		TenantID: iam.TenantIDFromContext(ctx),
+	}
+	if input.Email == "" {
+		writeError(w, badRequest("invalidValue", "userName is required"))
+		return
+	}
+	if in.ExternalID != "" {
+		input.Metadata = map[string]string{MetadataExternalID: in.ExternalID}
+	}
+	created, err := admin.Create(ctx, input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if in.Active != nil && !*in.Active {
+		if err := admin.Disable(ctx, created.ID, "provisioned inactive"); err != nil {
+			writeError(w, err)
+			return
+		}
+		created.Status = iam.UserStatusDisabled
+	}
+
+	res := userResource(created, h.base(r))
+	w.Header().Set("Location", res.Meta.Location)
+	writeResource(w, http.StatusCreated, res, res.Meta)
+}
+
+func (h *Handler) replaceUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.loadUser(ctx, r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := checkIfMatch(r, userResource(current, h.base(r)).Meta.Version); err != nil {
+		writeError(w, err)
+		return
+	}
+	var in User
+	if err := decodeBody(r, &in); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.applyUser(w, r, current, &in)
+}
+
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.loadUser(ctx, r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := userResource(current, h.base(r))
+	if err := checkIfMatch(r, res.Meta.Version); err != nil {
+		writeError(w, err)
+		return
+	}
+	var patch PatchRequest
+	if err := decodeBody(r, &patch); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := applyUserPatch(res, patch.Operations); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.applyUser(w, r, current, res)
+}
+
+// applyUser makes current look like desired and writes the result.
+func (h *Handler) applyUser(w http.ResponseWriter, r *http.Request, current *iam.User, desired *User) {
+	ctx := r.Context()
+	admin, err := h.adminService()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	addr := email(desired)
+	if addr == "" {
+		writeError(w, badRequest("invalidValue", "userName is required"))
+		return
+	}
+	var update iam.UserUpdate
+	if addr != current.Email {
+		update.Email = &addr
+	}
+	// IAM users cannot have a blank name, so an omitted name is kept.
+	if name := fullName(desired); name != "" && name != current.Name {
+		update.Name = &name
+	}
+	if ext := desired.ExternalID; ext != externalID(current) {
+		update.Metadata = metadataStrings(current.Metadata)
+		if ext != "" {
+			update.Metadata[MetadataExternalID] = ext
+		} else {
+			delete(update.Metadata, MetadataExternalID)
+		}
+	}
+	if update.Email != nil || update.Name != nil || update.Metadata != nil {
+		if _, err := admin.Update(ctx, current.ID, update); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	if desired.Active != nil {
+		disabled := current.Status == iam.UserStatusDisabled
+		switch {
+		case *desired.Active && disabled:
+			err = admin.Enable(ctx, current.ID)
+		case !*desired.Active && !disabled:
+			err = admin.Disable(ctx, current.ID, "deactivated via SCIM")
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	updated, err := h.loadUser(ctx, current.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := userResource(updated, h.base(r))
+	writeResource(w, http.StatusOK, res, res.Meta)
+}
+
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	current, err := h.loadUser(ctx, r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := checkIfMatch(r, userResource(current, h.base(r)).Meta.Version); err != nil {
+		writeError(w, err)
+		return
+	}
+	admin, err := h.adminService()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := admin.Delete(ctx, current.ID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadUser returns the user, hiding users of other tenants.
+func (h *Handler) loadUser(ctx context.Context, id string) (*iam.User, error) {
+	users, err := h.users()
+	if err != nil {
+		return nil, err
+	}
+	u, err := users.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenantID := iam.TenantIDFromContext(ctx); tenantID != "" && u.TenantID != tenantID {
+		return nil, &scimError{status: http.StatusNotFound, detail: "user " + id + " not found"}
+	}
+	return u, nil
+}
+
+// userFilterHint extracts the parts of a filter iam.UserFilter can express,
+// and reports whether it expresses all of f. Only single-clause (and-only)
+// filters are pushed down.
+func userFilterHint(f *filter) (iam.UserFilter, bool) {
+	var uf iam.UserFilter
+	if f == nil {
+		return uf, true
+	}
+	if len(f.clauses) != 1 {
+		return uf, false
+	}
+	exact := true
+	for _, c := range f.clauses[0] {
+		switch {
+		case (c.attr == "username" || c.attr == "emails" || c.attr == "emails.value") && c.op == "sw" && uf.EmailPrefix == "":
+			uf.EmailPrefix = c.value
+		case (c.attr == "username" || c.attr == "emails" || c.attr == "emails.value") && c.op == "eq":
+			// A prefix narrows the listing; equality is checked here.
+			uf.EmailPrefix = c.value
+			exact = false
+		case c.attr == "active" && c.op == "eq" && c.value == "true" && uf.Status == "":
+			uf.Status = iam.UserStatusActive
+		case c.attr == "active" && c.op == "eq" && c.value == "false":
+			// Users that are neither active nor disabled are inactive too.
+			uf.Status = iam.UserStatusDisabled
+			exact = false
+		case (c.attr == "groups" || c.attr == "groups.value") && c.op == "eq":
+			// Role also matches role names; membership is checked here.
+			uf.Role = c.value
+			exact = false
+		default:
+			exact = false
+		}
+	}
+	return uf, exact
+}
+
+// --- Groups ---
+
+func (h *Handler) listGroups(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	f, err := queryFilter(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	startIndex, count := h.window(q)
+	withMembers := !excluded(q, "members")
+	base := h.base(r)
+	resp := ListResponse{Schemas: []string{SchemaListResponse}, StartIndex: startIndex, Resources: []any{}}
+
+	if f == nil {
+		// Without a filter the role listing selects the page itself.
+		roles, err := h.roles(ctx, startIndex-1, count)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp.TotalResults = roles.Total
+		for _, role := range roles.Roles {
+			g, err := h.loadGroup(ctx, role)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			resp.Resources = append(resp.Resources, groupResource(g, base, withMembers))
+		}
+		resp.ItemsPerPage = len(resp.Resources)
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	// Filters may refer to members, so every group is loaded and matched.
+	for offset := 0; ; {
+		roles, err := h.roles(ctx, offset, h.maxResults)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		for _, role := range roles.Roles {
+			g, err := h.loadGroup(ctx, role)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			res := groupResource(g, base, true)
+			if !f.match(groupAttrs(res)) {
+				continue
+			}
+			resp.TotalResults++
+			if resp.TotalResults >= startIndex && len(resp.Resources) < count {
+				if !withMembers {
+					res.Members = nil
+				}
+				resp.Resources = append(resp.Resources, res)
+			}
+		}
+		offset += len(roles.Roles)
+		if len(roles.Roles) == 0 || offset >= roles.Total {
+			break
+		}
+	}
+	resp.ItemsPerPage = len(resp.Resources)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) getGroup(w http.ResponseWriter, r *http.Request) {
+	g, err := h.group(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := groupResource(g, h.base(r), !excluded(r.URL.Query(), "members"))
+	if r.Header.Get("If-None-Match") == res.Meta.Version {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeResource(w, http.StatusOK, res, res.Meta)
+}
+
+func (h *Handler) replaceGroup(w http.ResponseWriter, r *http.Request) {
+	g, err := h.group(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := groupResource(g, "", true)
+	if err := checkIfMatch(r, res.Meta.Version); err != nil {
+		writeError(w, err)
+		return
+	}
+	var in Group
+	if err := decodeBody(r, &in); err != nil {
+		writeError(w, err)
+		return
+	}
+	if in.DisplayName != "" && in.DisplayName != res.DisplayName {
+		writeError(w, badRequest("mutability", "groups are IAM roles and cannot be renamed"))
+		return
+	}
+	h.applyMembers(w, r, g, in.Members)
+}
+
+func (h *Handler) patchGroup(w http.ResponseWriter, r *http.Request) {
+	g, err := h.group(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := groupResource(g, "", true)
+	if err := checkIfMatch(r, res.Meta.Version); err != nil {
+		writeError(w, err)
+		return
+	}
+	var patch PatchRequest
+	if err := decodeBody(r, &patch); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := applyGroupPatch(res, patch.Operations); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.applyMembers(w, r, g, res.Members)
+}
+
+// applyMembers assigns and removes the group's role so that exactly the
+// users in members hold it, then writes the group.
+func (h *Handler) applyMembers(w http.ResponseWriter, r *http.Request, g *group, members []Ref) {
+	ctx := r.Context()
+	admin, err := h.adminService()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	want := make(map[string]bool, len(members))
+	for _, m := range members {
+		want[m.Value] = true
+	}
+	have := make(map[string]bool, len(g.members))
+	for _, u := range g.members {
+		have[u.ID] = true
+	}
+
+	for id := range want {
+		if have[id] {
+			continue
+		}
+		if _, err := h.loadUser(ctx, id); err != nil {
+			if errors.Is(err, iam.ErrNotFound) || isStatus(err, http.StatusNotFound) {
+				err = badRequest("invalidValue", "member %s does not exist", id)
+			}
+			writeError(w, err)
+			return
+		}
+		if err := admin.AssignRole(ctx, id, g.role.ID); err != nil && !errors.Is(err, iam.ErrAlreadyExists) {
+			writeError(w, err)
+			return
+		}
+	}
+	for id := range have {
+		if want[id] {
+			continue
+		}
+		if err := admin.RemoveRole(ctx, id, g.role.ID); err != nil && !errors.Is(err, iam.ErrNotFound) {
+			writeError(w, err)
+			return
+		}
+	}
+
+	updated, err := h.loadGroup(ctx, g.role)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := groupResource(updated, h.base(r), true)
+	writeResource(w, http.StatusOK, res, res.Meta)
+}
+
+func (h *Handler) unsupportedGroupChange(w http.ResponseWriter, _ *http.Request) {
+	writeError(w, &scimError{
+		status: http.StatusNotImplemented,
+		detail: "groups are IAM roles; create and delete roles in the IAM server",
+	})
+}
+
+// group returns the group for the role with ID id in the caller's tenant.
+func (h *Handler) group(ctx context.Context, id string) (*group, error) {
+	admin, err := h.adminService()
+	if err != nil {
+		return nil, err
+	}
+	roles, err := admin.ListRoles(ctx, iam.ListRolesOptions{TenantID: iam.TenantIDFromContext(ctx), Role: id})
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles.Roles {
+		if role.ID == id {
+			return h.loadGroup(ctx, role)
+		}
+	}
+	return nil, &scimError{status: http.StatusNotFound, detail: "group " + id + " not found"}
+}
+
+// roles returns at most limit roles of the caller's tenant, starting at
+// offset, and the number of roles in the tenant.
+func (h *Handler) roles(ctx context.Context, offset, limit int) (*iam.RoleList, error) {
+	admin, err := h.adminService()
+	if err != nil {
+		return nil, err
+	}
+	// Limit 0 would ask for the backend's default page; with SCIM count=0
+	// only the total is wanted.
+	roles, err := admin.ListRoles(ctx, iam.ListRolesOptions{
+		TenantID: iam.TenantIDFromContext(ctx),
+		Offset:   offset,
+		Limit:    max(limit, 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles.Roles) > limit {
+		roles.Roles = roles.Roles[:limit]
+	}
+	return roles, nil
+}
+
+// loadGroup returns the group for role, with the users in the caller's
+// tenant who hold it.
+func (h *Handler) loadGroup(ctx context.Context, role iam.Role) (*group, error) {
+	users, err := h.users()
+	if err != nil {
+		return nil, err
+	}
+	opts := iam.ListOptions{
+		PageSize: h.maxResults,
+		Filter:   iam.UserFilter{TenantID: iam.TenantIDFromContext(ctx), Role: role.ID},
+	}
+	g := &group{role: role}
+	for u, err := range user.All(ctx, users, opts) {
+		if err != nil {
+			return nil, err
+		}
+		// The filter also matches roles named role.ID.
+		if slices.ContainsFunc(u.Roles, func(r iam.Role) bool { return r.ID == role.ID }) {
+			g.members = append(g.members, u)
+		}
+	}
+	return g, nil
+}
+
+// --- Service provider configuration ---
+
+func (h *Handler) serviceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	supported := func(ok bool) map[string]any { return map[string]any{"supported": ok} }
+	writeJSON(w, http.StatusOK, map[string]any{
+		"schemas":        []string{SchemaServiceProviderConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": h.maxResults},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(true),
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with a bearer token issued by the IAM server",
+		}},
+		"meta": map[string]any{"resourceType": "ServiceProviderConfig", "location": h.base(r) + "/ServiceProviderConfig"},
+	})
+}
+
+// --- helpers ---
+
+func (h *Handler) users() (iam.UserService, error) {
+	if users := h.client.Users(); users != nil {
+		return users, nil
+	}
+	return nil, &scimError{status: http.StatusInternalServerError, detail: "user service not configured"}
+}
+
+func (h *Handler) adminService() (iam.UserAdminService, error) {
+	if h.admin != nil {
+		return h.admin, nil
+	}
+	return nil, &scimError{status: http.StatusInternalServerError, detail: "user admin service not configured"}
+}
+
+// window returns the 1-based startIndex and the count of a list request.
+func (h *Handler) window(q url.Values) (int, int) {
+	startIndex, err := strconv.Atoi(q.Get("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(q.Get("count"))
+	if err != nil || count > h.maxResults {
+		count = h.maxResults
+	}
+	return startIndex, max(count, 0)
+}
+
+// base returns the URL the handler is served under, for resource locations.
+func (h *Handler) base(r *http.Request) string {
+	if h.baseURL != "" {
+		return h.baseURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	// With http.StripPrefix, RequestURI still holds the full original path.
+	prefix := ""
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		prefix = strings.TrimSuffix(u.Path, r.URL.Path)
+	}
+	return scheme + "://" + r.Host + prefix
+}
+
+func queryFilter(q url.Values) (*filter, error) {
+	s := q.Get("filter")
+	if s == "" {
+		return nil, nil
+	}
+	f, err := parseFilter(s)
+	if err != nil {
+		return nil, badRequest("invalidFilter", "%v", err)
+	}
+	return f, nil
+}
+
+// excluded reports whether attr is listed in the excludedAttributes parameter.
+func excluded(q url.Values, attr string) bool {
+	for _, a := range strings.Split(q.Get("excludedAttributes"), ",") {
+		if normalizeAttr(strings.TrimSpace(a)) == attr {
+			return true
+		}
+	}
+	return false
+}
+
+func checkIfMatch(r *http.Request, version string) error {
+	header := r.Header.Get("If-Match")
+	if header == "" || header == "*" {
+		return nil
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == version {
+			return nil
+		}
+	}
+	return &scimError{status: http.StatusPreconditionFailed, detail: "resource has changed; version does not match If-Match"}
+}
+
+func metadataStrings(m map[string]any) map[string]string {
+	out := make(map[string]string, len(m)+1)
+	for k, v := range m {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest("invalidSyntax", "malformed JSON body: %v", err)
+	}
+	return nil
+}
+
+func writeResource(w http.ResponseWriter, status int, v any, meta *Meta) {
+	w.Header().Set("ETag", meta.Version)
+	writeJSON(w, status, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// scimError is an error with a SCIM status and scimType.
+type scimError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (e *scimError) Error() string {
+	return fmt.Sprintf("iam/scim: %d %s: %s", e.status, e.scimType, e.detail)
+}
+
+func badRequest(scimType, format string, args ...any) *scimError {
+	return &scimError{status: http.StatusBadRequest, scimType: scimType, detail: fmt.Sprintf(format, args...)}
+}
+
+func isStatus(err error, status int) bool {
+	var se *scimError
+	return errors.As(err, &se) && se.status == status
+}
+
+// writeError writes err as a SCIM error response, mapping the iam sentinel
+// errors to their HTTP statuses. Other errors are not disclosed.
+func writeError(w http.ResponseWriter, err error) {
+	var se *scimError
+	var ce *core.Error
+	switch {
+	case errors.As(err, &se):
+	case errors.As(err, &ce):
+		writePipelineError(w, ce)
+		return
+	case errors.Is(err, iam.ErrNotFound):
+		se = &scimError{status: http.StatusNotFound, detail: "resource not found"}
+	case errors.Is(err, iam.ErrInvalidArgument):
+		se = badRequest("invalidValue", "%v", err)
+	case errors.Is(err, iam.ErrAlreadyExists):
+		se = &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "a user with this userName already exists"}
+	default:
+		se = &scimError{status: http.StatusInternalServerError, detail: "internal error"}
+	}
+	writeJSON(w, se.status, Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(se.status),
+		ScimType: se.scimType,
+		Detail:   se.detail,
+	})
+}
+
+// writePipelineError writes a failure of the authentication pipeline: 401
+// or 403 with its WWW-Authenticate challenge, or 500.
+func writePipelineError(w http.ResponseWriter, e *core.Error) {
+	se := &scimError{status: http.StatusInternalServerError, detail: e.Message}
+	switch e.Kind {
+	case core.Unauthenticated:
+		se.status = http.StatusUnauthorized
+	case core.PermissionDenied:
+		se.status = http.StatusForbidden
+	}
+	if c := e.Challenge(); c != "" && se.status != http.StatusInternalServerError {
+		w.Header().Set("WWW-Authenticate", c)
+	}
+	writeError(w, se)
+}
@@ -0,0 +1,452 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/audit/audittest"
+	"github.com/chimerakang/iam-go/fake"
+	"github.com/chimerakang/iam-go/impersonate"
+	"github.com/chimerakang/iam-go/user"
+)
+
+func newTestHandler(t *testing.T, opts ...Option) (http.Handler, *iam.Client) {
+	t.Helper()
+	client := fake.NewClient(
+		fake.WithUser("admin", "t1", "admin@acme.com", []string{"it"}),
+		fake.WithUser("u1", "t1", "alice@acme.com", []string{"engineering"}),
+		fake.WithUser("u2", "t1", "bob@acme.com", []string{"engineering", "oncall"}),
+		fake.WithUser("u9", "t2", "mallory@globex.com", []string{"engineering"}),
+		fake.WithTenant("t1", "acme", "active"),
+		fake.WithTenant("t2", "globex", "active"),
+		fake.WithRole("t1", "auditors"),
+		fake.WithPermissions("admin", []string{"scim:provision"}),
+	)
+	opts = append([]Option{WithBaseURL("https://idp.example.com/scim/v2")}, opts...)
+	return NewHandler(client, "scim:provision", opts...), client
+}
+
+// do sends a request as the "admin" user (the fake verifier treats the
+// token as the user ID) and decodes the JSON response into out, if non-nil.
+func do(t *testing.T, h http.Handler, method, path, body string, header http.Header, out any) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer admin")
+	req.Header.Set("Content-Type", "application/scim+json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if out != nil && rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"invalid", "Bearer nobody", http.StatusUnauthorized},
+		{"no permission", "Bearer u1", http.StatusForbidden},
+		{"allowed", "Bearer admin", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/Users", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate header")
+			}
+			if tt.want != http.StatusOK {
+				var e Error
+				_ = json.Unmarshal(rec.Body.Bytes(), &e)
+				if len(e.Schemas) != 1 || e.Schemas[0] != SchemaError {
+					t.Errorf("expected SCIM error body, got %s", rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestAuthentication_Impersonation(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("admin", "t1", "admin@acme.com", nil),
+		fake.WithUser("support", "t1", "support@acme.com", nil),
+		fake.WithUser("intern", "t1", "intern@acme.com", nil),
+		fake.WithImpersonation("support-token", "support", "admin"),
+		fake.WithImpersonation("intern-token", "intern", "admin"),
+		fake.WithPermissions("admin", []string{"scim:provision"}),
+		fake.WithPermissions("support", []string{impersonate.DefaultPermission}),
+	)
+	policy := impersonate.NewPolicy(client.Authz())
+
+	tests := []struct {
+		name  string
+		token string
+		opts  []Option
+		want  int
+	}{
+		{"not accepted", "support-token", nil, http.StatusForbidden},
+		{"actor not permitted", "intern-token", []Option{WithImpersonation(policy)}, http.StatusForbidden},
+		{"admitted", "support-token", []Option{WithImpersonation(policy)}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(client, "scim:provision", tt.opts...)
+			req := httptest.NewRequest(http.MethodGet, "/Users", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthentication_Required(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("admin", "t1", "admin@acme.com", nil),
+		fake.WithUser("global", "", "global@acme.com", nil),
+		fake.WithPermissions("admin", []string{"scim:provision"}),
+		fake.WithPermissions("global", []string{"scim:provision"}),
+	)
+
+	tests := []struct {
+		name       string
+		permission string
+		token      string
+	}{
+		{"no permission configured", "", "admin"},
+		{"no tenant", "scim:provision", "global"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(client, tt.permission)
+			req := httptest.NewRequest(http.MethodGet, "/Users", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want 403", rec.Code)
+			}
+		})
+	}
+}
+
+func TestListUsers_TenantScopedAndFiltered(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	var all ListResponse
+	do(t, h, http.MethodGet, "/Users", "", nil, &all)
+	if all.TotalResults != 3 {
+		t.Errorf("totalResults = %d, want 3 (tenant t1 only)", all.TotalResults)
+	}
+
+	var filtered struct {
+		ListResponse
+		Resources []User `json:"Resources"`
+	}
+	do(t, h, http.MethodGet, `/Users?filter=`+urlEscape(`userName eq "BOB@acme.com"`), "", nil, &filtered)
+	if filtered.TotalResults != 1 || filtered.Resources[0].ID != "u2" {
+		t.Fatalf("filter by userName = %+v", filtered)
+	}
+	if u := filtered.Resources[0]; len(u.Groups) != 2 || u.Meta.Location != "https://idp.example.com/scim/v2/Users/u2" {
+		t.Errorf("unexpected resource: %+v", u)
+	}
+
+	do(t, h, http.MethodGet, `/Users?filter=`+urlEscape(`groups.value eq "engineering" and userName sw "a"`), "", nil, &filtered)
+	if filtered.TotalResults != 1 || filtered.Resources[0].ID != "u1" {
+		t.Errorf("filter by group = %+v", filtered)
+	}
+
+	rec := do(t, h, http.MethodGet, `/Users?filter=`+urlEscape(`emails[type eq "work"]`), "", nil, nil)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalidFilter") {
+		t.Errorf("unsupported filter: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestListUsers_Paging(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	var page ListResponse
+	do(t, h, http.MethodGet, "/Users?startIndex=2&count=1", "", nil, &page)
+	if page.TotalResults != 3 || page.StartIndex != 2 || page.ItemsPerPage != 1 || len(page.Resources) != 1 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	// Fully expressible filters are paged by the backend, here one user
+	// per page.
+	h, _ = newTestHandler(t, WithMaxResults(1))
+	var paged struct {
+		ListResponse
+		Resources []User `json:"Resources"`
+	}
+	do(t, h, http.MethodGet, "/Users?startIndex=3&count=1&filter="+urlEscape(`active eq true`), "", nil, &paged)
+	if paged.TotalResults != 3 || len(paged.Resources) != 1 || paged.Resources[0].ID != "u2" {
+		t.Errorf("paged = %+v, want u2 of 3", paged)
+	}
+}
+
+func TestUserLifecycle(t *testing.T) {
+	h, client := newTestHandler(t)
+
+	var created User
+	rec := do(t, h, http.MethodPost, "/Users", `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "carol@acme.com",
+		"externalId": "okta-123",
+		"name": {"givenName": "Carol", "familyName": "Danvers"},
+		"active": true
+	}`, nil, &created)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Location") != "https://idp.example.com/scim/v2/Users/"+created.ID {
+		t.Errorf("Location = %q", rec.Header().Get("Location"))
+	}
+	if created.DisplayName != "Carol Danvers" || created.ExternalID != "okta-123" || !*created.Active {
+		t.Errorf("unexpected created user: %+v", created)
+	}
+	u, err := client.Users().Get(t.Context(), created.ID)
+	if err != nil || u.TenantID != "t1" {
+		t.Fatalf("created user = %+v, %v; want tenant t1", u, err)
+	}
+
+	rec = do(t, h, http.MethodPost, "/Users", `{"userName": "carol@acme.com"}`, nil, nil)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "uniqueness") {
+		t.Errorf("duplicate create: %d %s", rec.Code, rec.Body.String())
+	}
+
+	// Azure AD style deactivation: string boolean, capitalized op.
+	var patched User
+	rec = do(t, h, http.MethodPatch, "/Users/"+created.ID, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "Replace", "path": "active", "value": "False"},
+			{"op": "replace", "path": "displayName", "value": "Captain Marvel"}
+		]
+	}`, nil, &patched)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("patch status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if *patched.Active || patched.DisplayName != "Captain Marvel" || patched.ExternalID != "okta-123" {
+		t.Errorf("unexpected patched user: %+v", patched)
+	}
+	if _, err := client.Verifier().Verify(t.Context(), created.ID); err == nil {
+		t.Error("expected deactivated user to be unable to sign in")
+	}
+
+	var replaced User
+	rec = do(t, h, http.MethodPut, "/Users/"+created.ID, `{"userName": "carol.danvers@acme.com", "active": true}`, nil, &replaced)
+	if rec.Code != http.StatusOK || replaced.UserName != "carol.danvers@acme.com" || !*replaced.Active || replaced.ExternalID != "" {
+		t.Errorf("put: %d %+v", rec.Code, replaced)
+	}
+
+	rec = do(t, h, http.MethodDelete, "/Users/"+created.ID, "", nil, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+	rec = do(t, h, http.MethodGet, "/Users/"+created.ID, "", nil, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("get after delete = %d, want 404", rec.Code)
+	}
+}
+
+func TestUser_WritesAudited(t *testing.T) {
+	rec := audittest.NewRecorder()
+	h, _ := newTestHandler(t, WithAuditLogger(rec.Logger))
+
+	if res := do(t, h, http.MethodPost, "/Users", `{"userName": "carol@acme.com"}`, nil, nil); res.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", res.Code, res.Body.String())
+	}
+	events := rec.Events()
+	if len(events) != 1 || events[0].Action != user.AuditActionCreate || events[0].UserID != "admin" || events[0].TenantID != "t1" {
+		t.Errorf("events = %+v, want one user_create by admin in t1", events)
+	}
+}
+
+func TestUser_OtherTenantHidden(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if rec := do(t, h, method, "/Users/u9", "", nil, nil); rec.Code != http.StatusNotFound {
+			t.Errorf("%s other tenant's user = %d, want 404", method, rec.Code)
+		}
+	}
+}
+
+func TestUser_ETags(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	rec := do(t, h, http.MethodGet, "/Users/u1", "", nil, nil)
+	tag := rec.Header().Get("ETag")
+	if !strings.HasPrefix(tag, `W/"`) {
+		t.Fatalf("ETag = %q", tag)
+	}
+
+	rec = do(t, h, http.MethodGet, "/Users/u1", "", http.Header{"If-None-Match": {tag}}, nil)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("conditional GET = %d, want 304", rec.Code)
+	}
+
+	patch := `{"Operations": [{"op": "replace", "path": "displayName", "value": "Alice"}]}`
+	rec = do(t, h, http.MethodPatch, "/Users/u1", patch, http.Header{"If-Match": {tag}}, nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == tag {
+		t.Fatalf("PATCH with current ETag = %d, ETag %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	rec = do(t, h, http.MethodPatch, "/Users/u1", patch, http.Header{"If-Match": {tag}}, nil)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("PATCH with stale ETag = %d, want 412", rec.Code)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	h, client := newTestHandler(t)
+
+	var list struct {
+		ListResponse
+		Resources []Group `json:"Resources"`
+	}
+	do(t, h, http.MethodGet, "/Groups?excludedAttributes=members", "", nil, &list)
+	if list.TotalResults != 4 {
+		t.Fatalf("totalResults = %d, want 4 (auditors, engineering, it, oncall)", list.TotalResults)
+	}
+	for _, g := range list.Resources {
+		if len(g.Members) != 0 {
+			t.Errorf("members not excluded from %s", g.ID)
+		}
+	}
+
+	var eng Group
+	do(t, h, http.MethodGet, "/Groups/engineering", "", nil, &eng)
+	if len(eng.Members) != 2 {
+		t.Errorf("engineering members = %+v, want u1 and u2 (not other tenant's u9)", eng.Members)
+	}
+
+	// Okta style: add members, remove with a value filter.
+	rec := do(t, h, http.MethodPatch, "/Groups/oncall", `{"Operations": [
+		{"op": "add", "path": "members", "value": [{"value": "u1"}]},
+		{"op": "remove", "path": "members[value eq \"u2\"]"}
+	]}`, nil, &eng)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("patch group = %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(eng.Members) != 1 || eng.Members[0].Value != "u1" {
+		t.Errorf("oncall members = %+v, want [u1]", eng.Members)
+	}
+	roles, _ := client.Users().GetRoles(t.Context(), "u2")
+	if len(roles) != 1 {
+		t.Errorf("u2 roles = %v, want only engineering", roles)
+	}
+
+	rec = do(t, h, http.MethodPatch, "/Groups/oncall", `{"Operations": [{"op": "add", "path": "members", "value": [{"value": "u9"}]}]}`, nil, nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("adding other tenant's user = %d, want 400", rec.Code)
+	}
+
+	rec = do(t, h, http.MethodPatch, "/Groups/oncall", `{"Operations": [{"op": "replace", "value": {"displayName": "pager"}}]}`, nil, nil)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "mutability") {
+		t.Errorf("rename group = %d %s", rec.Code, rec.Body.String())
+	}
+
+	// Emptying a group removes the role from everyone; the group remains.
+	eng = Group{}
+	rec = do(t, h, http.MethodPut, "/Groups/oncall", `{"displayName": "oncall", "members": []}`, nil, &eng)
+	if rec.Code != http.StatusOK || len(eng.Members) != 0 {
+		t.Errorf("empty group = %d %+v, want 200 without members", rec.Code, eng.Members)
+	}
+	if rec := do(t, h, http.MethodGet, "/Groups/oncall", "", nil, nil); rec.Code != http.StatusOK {
+		t.Errorf("get emptied group = %d, want 200", rec.Code)
+	}
+	if rec := do(t, h, http.MethodGet, "/Groups/ghost", "", nil, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("get unknown group = %d, want 404", rec.Code)
+	}
+
+	if rec := do(t, h, http.MethodPost, "/Groups", `{"displayName": "new"}`, nil, nil); rec.Code != http.StatusNotImplemented {
+		t.Errorf("create group = %d, want 501", rec.Code)
+	}
+}
+
+func TestGroups_EmptyAndPaging(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	var auditors Group
+	if rec := do(t, h, http.MethodGet, "/Groups/auditors", "", nil, &auditors); rec.Code != http.StatusOK || len(auditors.Members) != 0 {
+		t.Errorf("group without members = %d %+v, want 200", rec.Code, auditors)
+	}
+
+	var list struct {
+		ListResponse
+		Resources []Group `json:"Resources"`
+	}
+	do(t, h, http.MethodGet, "/Groups?startIndex=2&count=2", "", nil, &list)
+	if list.TotalResults != 4 || len(list.Resources) != 2 || list.Resources[0].ID != "engineering" || list.Resources[1].ID != "it" {
+		t.Errorf("page = %+v, want engineering and it of 4", list)
+	}
+
+	list.Resources = nil
+	do(t, h, http.MethodGet, "/Groups?count=0", "", nil, &list)
+	if list.TotalResults != 4 || len(list.Resources) != 0 {
+		t.Errorf("count=0 = %+v, want only the total", list)
+	}
+
+	list.Resources = nil
+	do(t, h, http.MethodGet, `/Groups?filter=displayName+eq+"auditors"`, "", nil, &list)
+	if list.TotalResults != 1 || len(list.Resources) != 1 || list.Resources[0].ID != "auditors" {
+		t.Errorf("filtered = %+v, want auditors", list)
+	}
+}
+
+func TestServiceProviderConfig(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	var cfg map[string]any
+	rec := do(t, h, http.MethodGet, "/ServiceProviderConfig", "", nil, &cfg)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/scim+json" {
+		t.Fatalf("status = %d, Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if patch, _ := cfg["patch"].(map[string]any); patch["supported"] != true {
+		t.Errorf("patch not advertised: %v", cfg)
+	}
+}
+
+func TestBaseURLFromRequest(t *testing.T) {
+	client := fake.NewClient(
+		fake.WithUser("admin", "t1", "admin@acme.com", nil),
+		fake.WithPermissions("admin", []string{"scim:provision"}),
+	)
+	mux := http.NewServeMux()
+	mux.Handle("/scim/v2/", http.StripPrefix("/scim/v2", NewHandler(client, "scim:provision")))
+
+	var u User
+	do(t, mux, http.MethodGet, "http://idp.example.com/scim/v2/Users/admin", "", nil, &u)
+	if u.Meta == nil || u.Meta.Location != "http://idp.example.com/scim/v2/Users/admin" {
+		t.Errorf("meta = %+v", u.Meta)
+	}
+}
+
+func urlEscape(s string) string {
+	return strings.NewReplacer(" ", "%20", `"`, "%22", "[", "%5B", "]", "%5D").Replace(s)
+}
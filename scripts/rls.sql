@@ -0,0 +1,78 @@
+-- PostgreSQL row-level security policies for tenant isolation
+-- Run after init.sql. Pairs with the tenant/tenantsql driver wrapper, which sets
+-- the iam.tenant_id session setting from iam.TenantIDFromContext before every
+-- statement. Statements issued without a tenant see no tenant-owned rows.
+--
+-- RLS does not apply to the table owner or superusers: connect the application
+-- as a separate role (see iam_app below) or use FORCE ROW LEVEL SECURITY.
+
+-- Current tenant from the session setting; NULL when unset or empty
+CREATE OR REPLACE FUNCTION iam_current_tenant() RETURNS UUID
+    LANGUAGE sql STABLE
+    AS $$ SELECT NULLIF(current_setting('iam.tenant_id', true), '')::uuid $$;
+
+-- Tenants: a tenant can only see itself
+ALTER TABLE tenants ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON tenants
+    USING (id = iam_current_tenant());
+
+-- Users: visible when they belong to the current tenant via user_tenants
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON users
+    USING (EXISTS (
+        SELECT 1 FROM user_tenants ut
+        WHERE ut.user_id = users.id AND ut.tenant_id = iam_current_tenant()
+    ));
+
+-- Tenant-owned tables: filter and check on tenant_id
+ALTER TABLE user_tenants ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_tenants
+    USING (tenant_id = iam_current_tenant())
+    WITH CHECK (tenant_id = iam_current_tenant());
+
+ALTER TABLE roles ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON roles
+    USING (tenant_id = iam_current_tenant())
+    WITH CHECK (tenant_id = iam_current_tenant());
+
+ALTER TABLE permissions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON permissions
+    USING (tenant_id = iam_current_tenant())
+    WITH CHECK (tenant_id = iam_current_tenant());
+
+ALTER TABLE user_roles ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_roles
+    USING (tenant_id = iam_current_tenant())
+    WITH CHECK (tenant_id = iam_current_tenant());
+
+ALTER TABLE api_secrets ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_secrets
+    USING (tenant_id = iam_current_tenant())
+    WITH CHECK (tenant_id = iam_current_tenant());
+
+ALTER TABLE sessions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sessions
+    USING (tenant_id = iam_current_tenant())
+    WITH CHECK (tenant_id = iam_current_tenant());
+
+ALTER TABLE audit_logs ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_logs
+    USING (tenant_id = iam_current_tenant())
+    WITH CHECK (tenant_id = iam_current_tenant());
+
+-- Application role subject to the policies above. It is created without a
+-- password; pass one as a psql variable:
+--   psql -v app_password="$IAM_APP_PASSWORD" -f scripts/rls.sql
+-- or set it afterwards with ALTER ROLE iam_app PASSWORD '...'.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'iam_app') THEN
+        CREATE ROLE iam_app LOGIN;
+    END IF;
+END $$;
+
+\if :{?app_password}
+ALTER ROLE iam_app PASSWORD :'app_password';
+\endif
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO iam_app;
@@ -0,0 +1,141 @@
+// Package serviceaccount resolves service-account principals to the tenant
+// and roles they act with.
+//
+// Service accounts authenticate with OAuth2 client credentials (see the
+// oauth2 package) and receive tokens whose subject is the account ID, but
+// usually no tenant or roles: an account may hold roles in several tenants
+// and names the tenant of each call in the X-Tenant-ID header. A Resolver
+// looks up the account's role bindings and completes the claims; the auth
+// middleware uses one via kratosmw.WithServiceAccounts and
+// grpcmw.WithServiceAccounts.
+package serviceaccount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/cache"
+	"github.com/chimerakang/iam-go/internal/flight"
+)
+
+// TenantHeader is the request header in which service accounts name the
+// tenant they act in.
+const TenantHeader = "X-Tenant-ID"
+
+// ErrNoBinding is returned by Resolver.Resolve when the service account holds
+// no role in the requested tenant.
+var ErrNoBinding = errors.New("iam/serviceaccount: no role binding in tenant")
+
+// Default cache settings.
+const (
+	DefaultCacheTTL   = time.Minute
+	DefaultMaxEntries = 1000
+)
+
+// Resolver completes service-account claims with the tenant and roles of the
+// call. Accounts are cached by ID, so binding changes take effect within the
+// cache TTL (or at once after Invalidate).
+type Resolver struct {
+	accounts   iam.ServiceAccountService
+	ttl        time.Duration
+	maxEntries int
+
+	cache *cache.LRU[string, *iam.ServiceAccount]
+	sf    flight.Group
+}
+
+// Option configures the Resolver.
+type Option func(*Resolver)
+
+// WithCacheTTL sets how long accounts are cached (default: 1 minute).
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(r *Resolver) {
+		r.ttl = ttl
+	}
+}
+
+// WithMaxEntries bounds the cache size (default: 1000). Zero or negative
+// means unbounded.
+func WithMaxEntries(n int) Option {
+	return func(r *Resolver) {
+		r.maxEntries = n
+	}
+}
+
+// NewResolver creates a Resolver that looks accounts up in accounts.
+func NewResolver(accounts iam.ServiceAccountService, opts ...Option) *Resolver {
+	r := &Resolver{
+		accounts:   accounts,
+		ttl:        DefaultCacheTTL,
+		maxEntries: DefaultMaxEntries,
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	r.cache = cache.New[string, *iam.ServiceAccount](r.maxEntries)
+	return r
+}
+
+// Resolve returns claims for a call made with tenantID (normally the
+// TenantHeader value). Claims of other principal types are returned as they
+// are. For service accounts a tenant in the token takes precedence over
+// tenantID; the returned copy carries that tenant and the names of the roles
+// bound in it, or ErrNoBinding if there are none. Without any tenant the
+// account acts tenant-less, with no roles.
+//
+// Unknown accounts yield an error wrapping iam.ErrNotFound.
+func (r *Resolver) Resolve(ctx context.Context, claims *iam.Claims, tenantID string) (*iam.Claims, error) {
+	if claims.Principal() != iam.PrincipalServiceAccount {
+		return claims, nil
+	}
+	if claims.TenantID != "" {
+		tenantID = claims.TenantID
+	}
+
+	sa, err := r.lookup(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *claims
+	resolved.TenantID = tenantID
+	resolved.Roles = nil
+	if tenantID == "" {
+		return &resolved, nil
+	}
+	roles := sa.RolesIn(tenantID)
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("%w: account %q, tenant %q", ErrNoBinding, sa.ID, tenantID)
+	}
+	for _, role := range roles {
+		resolved.Roles = append(resolved.Roles, role.Name)
+	}
+	return &resolved, nil
+}
+
+// Invalidate drops the cached account, e.g. right after changing its bindings.
+func (r *Resolver) Invalidate(id string) {
+	r.cache.Delete(id)
+}
+
+func (r *Resolver) lookup(ctx context.Context, id string) (*iam.ServiceAccount, error) {
+	if sa, ok := r.cache.Get(id); ok {
+		return sa, nil
+	}
+
+	res, err := r.sf.Do(ctx, id, func(ctx context.Context) (interface{}, error) {
+		sa, err := r.accounts.Get(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("iam/serviceaccount: looking up account %q: %w", id, err)
+		}
+		r.cache.Set(id, sa, r.ttl)
+		return sa, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*iam.ServiceAccount), nil
+}
@@ -0,0 +1,149 @@
+package serviceaccount
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// countingAccounts is a ServiceAccountService holding one account, counting
+// lookups.
+type countingAccounts struct {
+	iam.ServiceAccountService
+	account iam.ServiceAccount
+	gets    atomic.Int32
+}
+
+func (c *countingAccounts) Get(_ context.Context, id string) (*iam.ServiceAccount, error) {
+	c.gets.Add(1)
+	if id != c.account.ID {
+		return nil, iam.ErrNotFound
+	}
+	sa := c.account
+	return &sa, nil
+}
+
+func newAccounts() *countingAccounts {
+	return &countingAccounts{account: iam.ServiceAccount{
+		ID:   "sa-billing",
+		Name: "billing-worker",
+		Bindings: []iam.RoleBinding{
+			{TenantID: "t1", Role: iam.Role{ID: "r-reader", Name: "reader"}},
+			{TenantID: "t1", Role: iam.Role{ID: "r-writer", Name: "writer"}},
+			{TenantID: "t2", Role: iam.Role{ID: "r-reader", Name: "reader"}},
+		},
+	}}
+}
+
+func saClaims() *iam.Claims {
+	return &iam.Claims{Subject: "sa-billing", PrincipalType: iam.PrincipalServiceAccount}
+}
+
+func TestResolve_TenantFromHeader(t *testing.T) {
+	accounts := newAccounts()
+	r := NewResolver(accounts)
+
+	claims, err := r.Resolve(context.Background(), saClaims(), "t1")
+
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if claims.TenantID != "t1" || len(claims.Roles) != 2 || claims.Roles[0] != "reader" || claims.Roles[1] != "writer" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	// Served from the cache
+	for range 3 {
+		if _, err := r.Resolve(context.Background(), saClaims(), "t2"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := accounts.gets.Load(); n != 1 {
+		t.Errorf("expected 1 lookup, got %d", n)
+	}
+}
+
+func TestResolve_TokenTenantWins(t *testing.T) {
+	r := NewResolver(newAccounts())
+	in := saClaims()
+	in.TenantID = "t2"
+
+	claims, err := r.Resolve(context.Background(), in, "t1")
+
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if claims.TenantID != "t2" || len(claims.Roles) != 1 || claims.Roles[0] != "reader" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	if in.Roles != nil {
+		t.Error("Resolve must not modify its input")
+	}
+}
+
+func TestResolve_NoBinding(t *testing.T) {
+	r := NewResolver(newAccounts())
+
+	if _, err := r.Resolve(context.Background(), saClaims(), "t3"); !errors.Is(err, ErrNoBinding) {
+		t.Errorf("expected ErrNoBinding, got %v", err)
+	}
+}
+
+func TestResolve_NoTenant(t *testing.T) {
+	r := NewResolver(newAccounts())
+
+	claims, err := r.Resolve(context.Background(), saClaims(), "")
+
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if claims.TenantID != "" || claims.Roles != nil {
+		t.Errorf("expected tenant-less claims, got %+v", claims)
+	}
+}
+
+func TestResolve_UnknownAccount(t *testing.T) {
+	r := NewResolver(newAccounts())
+	claims := saClaims()
+	claims.Subject = "sa-unknown"
+
+	if _, err := r.Resolve(context.Background(), claims, "t1"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestResolve_OtherPrincipalsUnchanged(t *testing.T) {
+	accounts := newAccounts()
+	r := NewResolver(accounts)
+	user := &iam.Claims{Subject: "u1", TenantID: "t1", Roles: []string{"admin"}}
+
+	claims, err := r.Resolve(context.Background(), user, "t2")
+
+	if err != nil || claims != user {
+		t.Errorf("expected user claims unchanged, got %+v, %v", claims, err)
+	}
+	if n := accounts.gets.Load(); n != 0 {
+		t.Errorf("expected no lookups, got %d", n)
+	}
+}
+
+func TestResolver_Invalidate(t *testing.T) {
+	accounts := newAccounts()
+	r := NewResolver(accounts, WithCacheTTL(time.Hour))
+	if _, err := r.Resolve(context.Background(), saClaims(), "t2"); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts.account.Bindings = accounts.account.Bindings[:2]
+	if _, err := r.Resolve(context.Background(), saClaims(), "t2"); err != nil {
+		t.Fatalf("expected cached bindings until invalidated, got %v", err)
+	}
+	r.Invalidate("sa-billing")
+	if _, err := r.Resolve(context.Background(), saClaims(), "t2"); !errors.Is(err, ErrNoBinding) {
+		t.Errorf("expected ErrNoBinding after unbinding, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// ErrSessionLimit is returned by Policy.Enforce when the user already has the
+// maximum number of sessions and the policy rejects new ones.
+var ErrSessionLimit = errors.New("iam/session: concurrent session limit reached")
+
+// LimitAction selects what Policy does when a user exceeds the session limit.
+type LimitAction int
+
+const (
+	// RevokeOldest revokes the user's oldest sessions to make room for the new one.
+	RevokeOldest LimitAction = iota
+	// RejectNew revokes the new session and returns ErrSessionLimit.
+	RejectNew
+)
+
+// Policy enforces a maximum number of concurrent sessions per user, with
+// optional per-tenant overrides.
+//
+// Call Enforce right after a session is established, with a context carrying
+// the user and the new session ID (iam.WithUserID, iam.WithSessionID).
+type Policy struct {
+	sessions    iam.SessionService
+	maxSessions int
+	tenantMax   map[string]int
+	action      LimitAction
+}
+
+// PolicyOption configures Policy behavior.
+type PolicyOption func(*Policy)
+
+// WithMaxSessions sets the default concurrent session limit. Zero or negative
+// means unlimited (the default).
+func WithMaxSessions(n int) PolicyOption {
+	return func(p *Policy) {
+		p.maxSessions = n
+	}
+}
+
+// WithTenantMaxSessions overrides the limit for users acting in tenantID.
+func WithTenantMaxSessions(tenantID string, n int) PolicyOption {
+	return func(p *Policy) {
+		p.tenantMax[tenantID] = n
+	}
+}
+
+// WithLimitAction sets what happens when the limit is exceeded (default: RevokeOldest).
+func WithLimitAction(a LimitAction) PolicyOption {
+	return func(p *Policy) {
+		p.action = a
+	}
+}
+
+// NewPolicy creates a Policy backed by sessions.
+func NewPolicy(sessions iam.SessionService, opts ...PolicyOption) *Policy {
+	p := &Policy{
+		sessions:  sessions,
+		tenantMax: make(map[string]int),
+		action:    RevokeOldest,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Limit returns the session limit that applies in tenantID (0 means unlimited).
+func (p *Policy) Limit(tenantID string) int {
+	if n, ok := p.tenantMax[tenantID]; ok {
+		return n
+	}
+	return p.maxSessions
+}
+
+// Enforce applies the session limit to the user in ctx. The session
+// identified by iam.SessionIDFromContext is treated as the new session and is
+// never revoked under RevokeOldest.
+func (p *Policy) Enforce(ctx context.Context) error {
+	limit := p.Limit(iam.TenantIDFromContext(ctx))
+	if limit <= 0 {
+		return nil
+	}
+	current := iam.SessionIDFromContext(ctx)
+	if current == "" {
+		return ErrNoCurrentSession
+	}
+
+	sessions, err := p.sessions.List(ctx)
+	if err != nil {
+		return fmt.Errorf("iam/session: list sessions: %w", err)
+	}
+	excess := len(sessions) - limit
+	if excess <= 0 {
+		return nil
+	}
+
+	if p.action == RejectNew {
+		if err := p.sessions.Revoke(ctx, current); err != nil {
+			return fmt.Errorf("iam/session: revoke new session: %w", err)
+		}
+		return ErrSessionLimit
+	}
+
+	others := make([]iam.Session, 0, len(sessions))
+	for _, s := range sessions {
+		if s.ID != current {
+			others = append(others, s)
+		}
+	}
+	sort.SliceStable(others, func(i, j int) bool {
+		return others[i].CreatedAt.Before(others[j].CreatedAt)
+	})
+	for _, s := range others[:min(excess, len(others))] {
+		if err := p.sessions.Revoke(ctx, s.ID); err != nil {
+			return fmt.Errorf("iam/session: revoke session %s: %w", s.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// listingSessions extends mockSessions with List for Policy tests
+type listingSessions struct {
+	*mockSessions
+}
+
+func (m listingSessions) List(context.Context) ([]iam.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]iam.Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		result = append(result, *s)
+	}
+	return result, nil
+}
+
+func (m listingSessions) ids() []string {
+	sessions, _ := m.List(context.Background())
+	ids := make([]string, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func newListingSessions() listingSessions {
+	base := time.Now().Add(-time.Hour)
+	return listingSessions{newMockSessions(
+		iam.Session{ID: "s1", CreatedAt: base},
+		iam.Session{ID: "s2", CreatedAt: base.Add(time.Minute)},
+		iam.Session{ID: "s3", CreatedAt: base.Add(2 * time.Minute)},
+		iam.Session{ID: "s4", CreatedAt: base.Add(3 * time.Minute)},
+	)}
+}
+
+func policyCtx(tenantID, sessionID string) context.Context {
+	ctx := iam.WithUserID(context.Background(), "user1")
+	ctx = iam.WithTenantID(ctx, tenantID)
+	return iam.WithSessionID(ctx, sessionID)
+}
+
+func TestPolicy_RevokeOldest(t *testing.T) {
+	sessions := newListingSessions()
+	p := NewPolicy(sessions, WithMaxSessions(2))
+
+	if err := p.Enforce(policyCtx("tenant1", "s1")); err != nil {
+		t.Fatalf("Enforce returned error: %v", err)
+	}
+
+	// s1 is the oldest but is the new (current) session, so s2 and s3 go.
+	if got := sessions.ids(); len(got) != 2 || got[0] != "s1" || got[1] != "s4" {
+		t.Errorf("remaining sessions = %v, want [s1 s4]", got)
+	}
+}
+
+func TestPolicy_RejectNew(t *testing.T) {
+	sessions := newListingSessions()
+	p := NewPolicy(sessions, WithMaxSessions(3), WithLimitAction(RejectNew))
+
+	err := p.Enforce(policyCtx("tenant1", "s4"))
+
+	if !errors.Is(err, ErrSessionLimit) {
+		t.Fatalf("expected ErrSessionLimit, got %v", err)
+	}
+	if got := sessions.ids(); len(got) != 3 || got[2] != "s3" {
+		t.Errorf("remaining sessions = %v, want [s1 s2 s3]", got)
+	}
+}
+
+func TestPolicy_WithinLimit(t *testing.T) {
+	sessions := newListingSessions()
+	p := NewPolicy(sessions, WithMaxSessions(4))
+
+	if err := p.Enforce(policyCtx("tenant1", "s4")); err != nil {
+		t.Fatalf("Enforce returned error: %v", err)
+	}
+	if len(sessions.ids()) != 4 {
+		t.Errorf("expected no sessions revoked, got %v", sessions.ids())
+	}
+}
+
+func TestPolicy_TenantOverride(t *testing.T) {
+	p := NewPolicy(newListingSessions(), WithMaxSessions(2), WithTenantMaxSessions("strict", 1), WithTenantMaxSessions("open", 0))
+
+	if p.Limit("other") != 2 || p.Limit("strict") != 1 || p.Limit("open") != 0 {
+		t.Errorf("unexpected limits: other=%d strict=%d open=%d", p.Limit("other"), p.Limit("strict"), p.Limit("open"))
+	}
+
+	sessions := newListingSessions()
+	p = NewPolicy(sessions, WithMaxSessions(2), WithTenantMaxSessions("open", 0))
+	if err := p.Enforce(policyCtx("open", "s4")); err != nil {
+		t.Fatalf("Enforce returned error: %v", err)
+	}
+	if len(sessions.ids()) != 4 {
+		t.Errorf("expected unlimited tenant to keep all sessions, got %v", sessions.ids())
+	}
+}
+
+func TestPolicy_NoCurrentSession(t *testing.T) {
+	p := NewPolicy(newListingSessions(), WithMaxSessions(1))
+
+	if err := p.Enforce(policyCtx("tenant1", "")); !errors.Is(err, ErrNoCurrentSession) {
+		t.Errorf("expected ErrNoCurrentSession, got %v", err)
+	}
+}
@@ -3,11 +3,16 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	iam "github.com/chimerakang/iam-go"
 )
 
+// ErrNoCurrentSession is returned by RevokeAllOthers when the context carries no
+// session ID. Without it the backend cannot tell which session to keep.
+var ErrNoCurrentSession = errors.New("iam/session: no current session in context")
+
 // Backend defines the contract for pluggable session service backends (gRPC, REST, etc.).
 type Backend interface {
 	// List returns all active sessions for the current user.
@@ -16,26 +21,58 @@ type Backend interface {
 	// Revoke terminates a specific session.
 	Revoke(ctx context.Context, sessionID string) error
 
-	// RevokeAllOthers terminates all sessions except the current one.
+	// RevokeAllOthers terminates all sessions except the current one,
+	// identified by iam.SessionIDFromContext.
 	RevokeAllOthers(ctx context.Context) error
+
+	// Validate returns the session if it is still active, or an error wrapping
+	// iam.ErrSessionInvalid if it was revoked, expired or is unknown.
+	Validate(ctx context.Context, sessionID string) (*iam.Session, error)
+
+	// Touch records activity on the session.
+	Touch(ctx context.Context, sessionID string) error
 }
 
+// DeviceBackend is implemented by backends that keep a device inventory.
+type DeviceBackend interface {
+	ListDevices(ctx context.Context) ([]iam.Device, error)
+	RenameDevice(ctx context.Context, deviceID, name string) error
+	TrustDevice(ctx context.Context, deviceID string, trusted bool) error
+}
+
+// ErrDevicesUnsupported is returned by the device methods when the backend
+// does not implement DeviceBackend.
+var ErrDevicesUnsupported = errors.New("iam/session: backend does not support device management")
+
 // Service implements iam.SessionService with a configurable backend.
 type Service struct {
 	backend Backend
 }
 
+var (
+	_ iam.SessionService = (*Service)(nil)
+	_ iam.DeviceManager  = (*Service)(nil)
+)
+
 // New creates a new SessionService with the given backend.
 func New(backend Backend) *Service {
 	return &Service{backend: backend}
 }
 
-// List returns all active sessions for the current user.
+// List returns all active sessions for the current user. The session matching
+// iam.SessionIDFromContext is marked Current.
 func (s *Service) List(ctx context.Context) ([]iam.Session, error) {
 	sessions, err := s.backend.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("iam/session: %w", err)
 	}
+	if current := iam.SessionIDFromContext(ctx); current != "" {
+		for i := range sessions {
+			if sessions[i].ID == current {
+				sessions[i].Current = true
+			}
+		}
+	}
 	return sessions, nil
 }
 
@@ -53,10 +90,88 @@ func (s *Service) Revoke(ctx context.Context, sessionID string) error {
 }
 
 // RevokeAllOthers terminates all sessions except the current one.
+// Returns ErrNoCurrentSession if the context has no session ID, rather than
+// risk revoking the caller's own session.
 func (s *Service) RevokeAllOthers(ctx context.Context) error {
+	if iam.SessionIDFromContext(ctx) == "" {
+		return ErrNoCurrentSession
+	}
+
 	err := s.backend.RevokeAllOthers(ctx)
 	if err != nil {
 		return fmt.Errorf("iam/session: %w", err)
 	}
 	return nil
 }
+
+// Validate returns the session if it is still active.
+func (s *Service) Validate(ctx context.Context, sessionID string) (*iam.Session, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("iam/session: sessionID cannot be empty")
+	}
+
+	sess, err := s.backend.Validate(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("iam/session: %w", err)
+	}
+	sess.Current = sess.ID == iam.SessionIDFromContext(ctx)
+	return sess, nil
+}
+
+// Touch records activity on the session.
+func (s *Service) Touch(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return fmt.Errorf("iam/session: sessionID cannot be empty")
+	}
+
+	if err := s.backend.Touch(ctx, sessionID); err != nil {
+		return fmt.Errorf("iam/session: %w", err)
+	}
+	return nil
+}
+
+// ListDevices returns the current user's devices.
+func (s *Service) ListDevices(ctx context.Context) ([]iam.Device, error) {
+	db, ok := s.backend.(DeviceBackend)
+	if !ok {
+		return nil, ErrDevicesUnsupported
+	}
+
+	devices, err := db.ListDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("iam/session: %w", err)
+	}
+	return devices, nil
+}
+
+// RenameDevice sets a display name for one of the current user's devices.
+func (s *Service) RenameDevice(ctx context.Context, deviceID, name string) error {
+	if deviceID == "" {
+		return fmt.Errorf("iam/session: deviceID cannot be empty")
+	}
+	db, ok := s.backend.(DeviceBackend)
+	if !ok {
+		return ErrDevicesUnsupported
+	}
+
+	if err := db.RenameDevice(ctx, deviceID, name); err != nil {
+		return fmt.Errorf("iam/session: %w", err)
+	}
+	return nil
+}
+
+// TrustDevice marks one of the current user's devices as trusted or untrusted.
+func (s *Service) TrustDevice(ctx context.Context, deviceID string, trusted bool) error {
+	if deviceID == "" {
+		return fmt.Errorf("iam/session: deviceID cannot be empty")
+	}
+	db, ok := s.backend.(DeviceBackend)
+	if !ok {
+		return ErrDevicesUnsupported
+	}
+
+	if err := db.TrustDevice(ctx, deviceID, trusted); err != nil {
+		return fmt.Errorf("iam/session: %w", err)
+	}
+	return nil
+}
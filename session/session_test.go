@@ -36,9 +36,29 @@ func (m *mockBackend) RevokeAllOthers(ctx context.Context) error {
 	if m.shouldFailRevoke {
 		return errors.New("revoke all others failed")
 	}
-	// Mark all sessions as revoked (simulating revoke all except current)
+	// Mark all sessions except the current one as revoked
+	current := iam.SessionIDFromContext(ctx)
 	for _, session := range m.sessions {
-		m.revokedSessions[session.ID] = true
+		if session.ID != current {
+			m.revokedSessions[session.ID] = true
+		}
+	}
+	return nil
+}
+
+func (m *mockBackend) Validate(ctx context.Context, sessionID string) (*iam.Session, error) {
+	for _, session := range m.sessions {
+		if session.ID == sessionID && !m.revokedSessions[sessionID] {
+			s := session
+			return &s, nil
+		}
+	}
+	return nil, iam.ErrSessionInvalid
+}
+
+func (m *mockBackend) Touch(ctx context.Context, sessionID string) error {
+	if m.shouldFailRevoke {
+		return errors.New("touch failed")
 	}
 	return nil
 }
@@ -149,19 +169,53 @@ func TestRevokeAllOthers_Success(t *testing.T) {
 	}
 	svc := New(backend)
 
-	err := svc.RevokeAllOthers(context.Background())
+	err := svc.RevokeAllOthers(iam.WithSessionID(context.Background(), "sess2"))
 
 	if err != nil {
 		t.Fatalf("RevokeAllOthers returned error: %v", err)
 	}
-	// All sessions should be marked as revoked
+	// All sessions except the current one should be marked as revoked
 	for _, session := range sessions {
-		if !backend.revokedSessions[session.ID] {
-			t.Errorf("session %s should be revoked", session.ID)
+		if backend.revokedSessions[session.ID] == (session.ID == "sess2") {
+			t.Errorf("session %s: revoked = %v", session.ID, backend.revokedSessions[session.ID])
 		}
 	}
 }
 
+func TestRevokeAllOthers_NoCurrentSession(t *testing.T) {
+	backend := &mockBackend{
+		sessions:        []iam.Session{{ID: "sess1"}},
+		revokedSessions: make(map[string]bool),
+	}
+	svc := New(backend)
+
+	err := svc.RevokeAllOthers(context.Background())
+
+	if !errors.Is(err, ErrNoCurrentSession) {
+		t.Fatalf("expected ErrNoCurrentSession, got %v", err)
+	}
+	if len(backend.revokedSessions) != 0 {
+		t.Errorf("expected no sessions revoked, got %v", backend.revokedSessions)
+	}
+}
+
+func TestList_MarksCurrent(t *testing.T) {
+	backend := &mockBackend{
+		sessions:        []iam.Session{{ID: "sess1"}, {ID: "sess2"}},
+		revokedSessions: make(map[string]bool),
+	}
+	svc := New(backend)
+
+	result, err := svc.List(iam.WithSessionID(context.Background(), "sess2"))
+
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if result[0].Current || !result[1].Current {
+		t.Errorf("expected only sess2 current, got %+v", result)
+	}
+}
+
 func TestRevokeAllOthers_Failed(t *testing.T) {
 	backend := &mockBackend{
 		shouldFailRevoke: true,
@@ -169,7 +223,7 @@ func TestRevokeAllOthers_Failed(t *testing.T) {
 	}
 	svc := New(backend)
 
-	err := svc.RevokeAllOthers(context.Background())
+	err := svc.RevokeAllOthers(iam.WithSessionID(context.Background(), "sess1"))
 
 	if err == nil {
 		t.Fatal("expected error")
@@ -218,14 +272,88 @@ func TestCRUDLifecycle(t *testing.T) {
 		t.Fatal("failed to revoke session")
 	}
 
-	// RevokeAllOthers
-	err = svc.RevokeAllOthers(context.Background())
+	// RevokeAllOthers from sess2
+	err = svc.RevokeAllOthers(iam.WithSessionID(context.Background(), "sess2"))
 	if err != nil {
 		t.Fatal("failed to revoke all others")
 	}
 
-	// Verify all sessions are revoked
-	if !backend.revokedSessions["sess1"] || !backend.revokedSessions["sess2"] {
-		t.Error("all sessions should be revoked")
+	// Verify sess1 is revoked and the current session survives
+	if !backend.revokedSessions["sess1"] || backend.revokedSessions["sess2"] {
+		t.Error("only the non-current session should be revoked")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	backend := &mockBackend{
+		sessions:        []iam.Session{{ID: "sess1"}, {ID: "sess2"}},
+		revokedSessions: map[string]bool{"sess2": true},
+	}
+	svc := New(backend)
+	ctx := iam.WithSessionID(context.Background(), "sess1")
+
+	sess, err := svc.Validate(ctx, "sess1")
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if !sess.Current {
+		t.Error("expected sess1 to be marked current")
+	}
+
+	if _, err := svc.Validate(ctx, "sess2"); !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("expected ErrSessionInvalid for revoked session, got %v", err)
+	}
+	if _, err := svc.Validate(ctx, ""); err == nil {
+		t.Error("expected error for empty sessionID")
+	}
+}
+
+// mockDeviceBackend adds DeviceBackend to mockBackend
+type mockDeviceBackend struct {
+	*mockBackend
+	names map[string]string
+}
+
+func (m *mockDeviceBackend) ListDevices(ctx context.Context) ([]iam.Device, error) {
+	var devices []iam.Device
+	for id, name := range m.names {
+		devices = append(devices, iam.Device{ID: id, Name: name})
+	}
+	return devices, nil
+}
+
+func (m *mockDeviceBackend) RenameDevice(ctx context.Context, deviceID, name string) error {
+	m.names[deviceID] = name
+	return nil
+}
+
+func (m *mockDeviceBackend) TrustDevice(ctx context.Context, deviceID string, trusted bool) error {
+	return nil
+}
+
+func TestDevices(t *testing.T) {
+	backend := &mockDeviceBackend{mockBackend: &mockBackend{}, names: map[string]string{"dev1": ""}}
+	svc := New(backend)
+
+	if err := svc.RenameDevice(context.Background(), "dev1", "Work laptop"); err != nil {
+		t.Fatalf("RenameDevice returned error: %v", err)
+	}
+	devices, err := svc.ListDevices(context.Background())
+	if err != nil {
+		t.Fatalf("ListDevices returned error: %v", err)
+	}
+	if len(devices) != 1 || devices[0].Name != "Work laptop" {
+		t.Errorf("unexpected devices: %+v", devices)
+	}
+	if err := svc.TrustDevice(context.Background(), "", true); err == nil {
+		t.Error("expected error for empty deviceID")
+	}
+}
+
+func TestDevices_Unsupported(t *testing.T) {
+	svc := New(&mockBackend{})
+
+	if _, err := svc.ListDevices(context.Background()); !errors.Is(err, ErrDevicesUnsupported) {
+		t.Errorf("expected ErrDevicesUnsupported, got %v", err)
 	}
 }
@@ -0,0 +1,84 @@
+package session
+
+import "strings"
+
+// UserAgentInfo is the device, OS and browser parsed from a User-Agent header.
+type UserAgentInfo struct {
+	Device  string // "desktop", "mobile", "tablet", "bot" or ""
+	OS      string
+	Browser string
+}
+
+// ParseUserAgent extracts coarse device, OS and browser names from a
+// User-Agent header. It recognizes the common families only and leaves a
+// field empty when unsure; versions are not reported.
+func ParseUserAgent(ua string) UserAgentInfo {
+	if ua == "" {
+		return UserAgentInfo{}
+	}
+	lower := strings.ToLower(ua)
+
+	info := UserAgentInfo{
+		OS:      parseOS(ua),
+		Browser: parseBrowser(ua),
+	}
+
+	switch {
+	case containsAny(lower, "bot", "crawler", "spider"):
+		info.Device = "bot"
+	case containsAny(ua, "iPad", "Tablet") || (info.OS == "Android" && !strings.Contains(ua, "Mobile")):
+		info.Device = "tablet"
+	case containsAny(ua, "Mobi", "iPhone", "iPod"):
+		info.Device = "mobile"
+	case info.OS == "Windows" || info.OS == "macOS" || info.OS == "Linux" || info.OS == "ChromeOS":
+		info.Device = "desktop"
+	}
+	return info
+}
+
+func parseOS(ua string) string {
+	switch {
+	case strings.Contains(ua, "Windows"):
+		return "Windows"
+	case containsAny(ua, "iPhone", "iPad", "iPod"):
+		return "iOS"
+	case strings.Contains(ua, "Android"):
+		return "Android"
+	case strings.Contains(ua, "CrOS"):
+		return "ChromeOS"
+	case containsAny(ua, "Mac OS X", "Macintosh"):
+		return "macOS"
+	case strings.Contains(ua, "Linux"):
+		return "Linux"
+	}
+	return ""
+}
+
+// parseBrowser checks tokens in order: most browsers also claim to be
+// Chrome and Safari, so the specific ones must match first.
+func parseBrowser(ua string) string {
+	switch {
+	case strings.Contains(ua, "Edg"):
+		return "Edge"
+	case containsAny(ua, "OPR/", "Opera"):
+		return "Opera"
+	case strings.Contains(ua, "SamsungBrowser"):
+		return "Samsung Internet"
+	case containsAny(ua, "Firefox/", "FxiOS"):
+		return "Firefox"
+	case containsAny(ua, "Chrome/", "CriOS"):
+		return "Chrome"
+	case strings.Contains(ua, "Safari/"):
+		return "Safari"
+	}
+	return ""
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
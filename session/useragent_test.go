@@ -0,0 +1,50 @@
+package session
+
+import "testing"
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want UserAgentInfo
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			UserAgentInfo{Device: "desktop", OS: "Windows", Browser: "Chrome"},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			UserAgentInfo{Device: "desktop", OS: "Windows", Browser: "Edge"},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			UserAgentInfo{Device: "desktop", OS: "macOS", Browser: "Safari"},
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			UserAgentInfo{Device: "mobile", OS: "iOS", Browser: "Safari"},
+		},
+		{
+			"Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1",
+			UserAgentInfo{Device: "tablet", OS: "iOS", Browser: "Chrome"},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			UserAgentInfo{Device: "mobile", OS: "Android", Browser: "Chrome"},
+		},
+		{
+			"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			UserAgentInfo{Device: "desktop", OS: "Linux", Browser: "Firefox"},
+		},
+		{
+			"Googlebot/2.1 (+http://www.google.com/bot.html)",
+			UserAgentInfo{Device: "bot"},
+		},
+		{"", UserAgentInfo{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseUserAgent(tt.ua); got != tt.want {
+			t.Errorf("ParseUserAgent(%q) = %+v, want %+v", tt.ua, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/cache"
+	"github.com/chimerakang/iam-go/internal/flight"
+)
+
+// Session policy violations. Both wrap iam.ErrSessionInvalid.
+var (
+	ErrIdleTimeout      = fmt.Errorf("iam/session: idle timeout exceeded: %w", iam.ErrSessionInvalid)
+	ErrLifetimeExceeded = fmt.Errorf("iam/session: absolute lifetime exceeded: %w", iam.ErrSessionInvalid)
+)
+
+// Defaults for Validator options.
+const (
+	DefaultValidationTTL = 10 * time.Second
+	DefaultTouchInterval = time.Minute
+	DefaultTouchTimeout  = 5 * time.Second
+	DefaultMaxEntries    = 10000
+)
+
+// Validator checks that the session behind a token is still active and within
+// the configured idle-timeout and absolute-lifetime policies.
+//
+// Backend answers are cached for a short TTL, so a revoked session stops
+// working within that window without a backend call on every request. Activity
+// is reported with SessionService.Touch in the background, at most once per
+// touch interval per session; idle timeouts are therefore only as precise as
+// touch interval plus cache TTL.
+type Validator struct {
+	sessions         iam.SessionService
+	cacheTTL         time.Duration
+	idleTimeout      time.Duration
+	absoluteLifetime time.Duration
+	touchInterval    time.Duration
+	touchTimeout     time.Duration
+	requireSession   bool
+	maxEntries       int
+
+	cache   *cache.LRU[string, validation]
+	sf      flight.Group
+	mu      sync.Mutex // serializes touch scheduling
+	touched *cache.LRU[string, struct{}]
+	now     func() time.Time
+}
+
+type validation struct {
+	session *iam.Session
+	err     error // non-nil when the backend reported the session invalid
+}
+
+// ValidatorOption configures Validator behavior.
+type ValidatorOption func(*Validator)
+
+// WithValidationTTL sets how long backend answers are cached (default: 10 seconds).
+// This bounds how long a revoked session keeps working.
+func WithValidationTTL(ttl time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.cacheTTL = ttl
+	}
+}
+
+// WithIdleTimeout rejects sessions with no activity for longer than d.
+// Zero (the default) disables the check.
+func WithIdleTimeout(d time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.idleTimeout = d
+	}
+}
+
+// WithAbsoluteLifetime rejects sessions created more than d ago, regardless of
+// activity. Zero (the default) disables the check.
+func WithAbsoluteLifetime(d time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.absoluteLifetime = d
+	}
+}
+
+// WithTouchInterval sets the minimum time between Touch calls for a session
+// (default: 1 minute). Zero disables touching.
+func WithTouchInterval(d time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.touchInterval = d
+	}
+}
+
+// WithMaxEntries bounds the number of sessions whose validation and last
+// touch are remembered (default: 10000). When full, the least recently used
+// entry is evicted. Zero or negative means unbounded.
+func WithMaxEntries(n int) ValidatorOption {
+	return func(v *Validator) {
+		v.maxEntries = n
+	}
+}
+
+// WithRequireSession rejects tokens without a "sid" claim with
+// ErrNoCurrentSession. By default such tokens (e.g. client credentials) pass.
+func WithRequireSession() ValidatorOption {
+	return func(v *Validator) {
+		v.requireSession = true
+	}
+}
+
+// NewValidator creates a Validator backed by sessions.
+func NewValidator(sessions iam.SessionService, opts ...ValidatorOption) *Validator {
+	v := &Validator{
+		sessions:      sessions,
+		cacheTTL:      DefaultValidationTTL,
+		touchInterval: DefaultTouchInterval,
+		touchTimeout:  DefaultTouchTimeout,
+		maxEntries:    DefaultMaxEntries,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	v.cache = cache.New[string, validation](v.maxEntries)
+	v.touched = cache.New[string, struct{}](v.maxEntries)
+	return v
+}
+
+// Check validates the session identified by sessionID. Errors wrapping
+// iam.ErrSessionInvalid (or ErrNoCurrentSession) mean the caller must
+// re-authenticate; any other error is a backend failure.
+func (v *Validator) Check(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		if v.requireSession {
+			return ErrNoCurrentSession
+		}
+		return nil
+	}
+
+	res, err := v.load(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if res.err != nil {
+		return res.err
+	}
+
+	now := v.now()
+	sess := res.session
+	if !sess.ExpiresAt.IsZero() && !now.Before(sess.ExpiresAt) {
+		return fmt.Errorf("iam/session: session expired: %w", iam.ErrSessionInvalid)
+	}
+	if v.absoluteLifetime > 0 && !sess.CreatedAt.IsZero() && now.Sub(sess.CreatedAt) > v.absoluteLifetime {
+		return ErrLifetimeExceeded
+	}
+	if v.idleTimeout > 0 && !sess.LastActiveAt.IsZero() && now.Sub(sess.LastActiveAt) > v.idleTimeout {
+		return ErrIdleTimeout
+	}
+
+	v.touch(ctx, sessionID)
+	return nil
+}
+
+// Invalidate drops the cached answer for sessionID, e.g. after revoking it locally.
+func (v *Validator) Invalidate(sessionID string) {
+	v.cache.Delete(sessionID)
+}
+
+// load returns the cached validation for sessionID, or asks the backend once
+// for all concurrent callers (see flight.Group). Transient backend errors are
+// not cached.
+func (v *Validator) load(ctx context.Context, sessionID string) (validation, error) {
+	if res, ok := v.cache.Get(sessionID); ok {
+		return res, nil
+	}
+
+	r, err := v.sf.Do(ctx, sessionID, func(ctx context.Context) (interface{}, error) {
+		sess, err := v.sessions.Validate(ctx, sessionID)
+		switch {
+		case err == nil:
+		case errors.Is(err, iam.ErrSessionInvalid):
+			sess = nil
+		default:
+			return nil, fmt.Errorf("iam/session: validate: %w", err)
+		}
+		res := validation{session: sess, err: err}
+		v.cache.Set(sessionID, res, v.cacheTTL)
+		return res, nil
+	})
+	if err != nil {
+		return validation{}, err
+	}
+	return r.(validation), nil
+}
+
+// touch reports activity in the background, at most once per touch interval.
+// A failed touch is retried on the next request.
+func (v *Validator) touch(ctx context.Context, sessionID string) {
+	if v.touchInterval <= 0 {
+		return
+	}
+
+	v.mu.Lock()
+	if _, ok := v.touched.Get(sessionID); ok {
+		v.mu.Unlock()
+		return
+	}
+	v.touched.Set(sessionID, struct{}{}, v.touchInterval)
+	v.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, v.touchTimeout)
+		defer cancel()
+		if err := v.sessions.Touch(ctx, sessionID); err != nil {
+			v.touched.Delete(sessionID)
+		}
+	}()
+}
@@ -0,0 +1,237 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// mockSessions implements iam.SessionService for Validator tests
+type mockSessions struct {
+	mu            sync.Mutex
+	sessions      map[string]*iam.Session
+	validateCalls int
+	failValidate  bool
+	touched       chan string
+	release       chan struct{} // if set, Validate waits for it
+}
+
+func newMockSessions(sessions ...iam.Session) *mockSessions {
+	m := &mockSessions{sessions: make(map[string]*iam.Session), touched: make(chan string, 10)}
+	for i := range sessions {
+		m.sessions[sessions[i].ID] = &sessions[i]
+	}
+	return m
+}
+
+func (m *mockSessions) List(context.Context) ([]iam.Session, error) { return nil, nil }
+func (m *mockSessions) Revoke(_ context.Context, sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, sessionID)
+	return nil
+}
+func (m *mockSessions) RevokeAllOthers(context.Context) error { return nil }
+
+func (m *mockSessions) Validate(ctx context.Context, sessionID string) (*iam.Session, error) {
+	if m.release != nil {
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.validateCalls++
+	if m.failValidate {
+		return nil, errors.New("backend unavailable")
+	}
+	sess, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, iam.ErrSessionInvalid
+	}
+	cp := *sess
+	return &cp, nil
+}
+
+func (m *mockSessions) Touch(_ context.Context, sessionID string) error {
+	m.touched <- sessionID
+	return nil
+}
+
+func (m *mockSessions) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.validateCalls
+}
+
+func TestValidator_Active(t *testing.T) {
+	now := time.Now()
+	sessions := newMockSessions(iam.Session{ID: "sess1", CreatedAt: now, LastActiveAt: now})
+	v := NewValidator(sessions)
+
+	if err := v.Check(context.Background(), "sess1"); err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+}
+
+func TestValidator_CachesResult(t *testing.T) {
+	sessions := newMockSessions(iam.Session{ID: "sess1"})
+	v := NewValidator(sessions)
+
+	_ = v.Check(context.Background(), "sess1")
+	_ = v.Check(context.Background(), "sess1")
+
+	if sessions.calls() != 1 {
+		t.Errorf("expected 1 backend call (cached), got %d", sessions.calls())
+	}
+}
+
+func TestValidator_RevokedAfterTTL(t *testing.T) {
+	sessions := newMockSessions(iam.Session{ID: "sess1"})
+	v := NewValidator(sessions, WithValidationTTL(50*time.Millisecond), WithTouchInterval(0))
+
+	if err := v.Check(context.Background(), "sess1"); err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	_ = sessions.Revoke(context.Background(), "sess1")
+	time.Sleep(80 * time.Millisecond)
+
+	if err := v.Check(context.Background(), "sess1"); !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("expected ErrSessionInvalid after revocation, got %v", err)
+	}
+}
+
+func TestValidator_TransientErrorNotCached(t *testing.T) {
+	sessions := newMockSessions(iam.Session{ID: "sess1"})
+	sessions.failValidate = true
+	v := NewValidator(sessions)
+
+	err := v.Check(context.Background(), "sess1")
+	if err == nil || errors.Is(err, iam.ErrSessionInvalid) {
+		t.Fatalf("expected transient error, got %v", err)
+	}
+	_ = v.Check(context.Background(), "sess1")
+
+	if sessions.calls() != 2 {
+		t.Errorf("expected 2 backend calls (transient error not cached), got %d", sessions.calls())
+	}
+}
+
+func TestValidator_IdleTimeout(t *testing.T) {
+	now := time.Now()
+	sessions := newMockSessions(iam.Session{ID: "sess1", CreatedAt: now.Add(-time.Hour), LastActiveAt: now.Add(-31 * time.Minute)})
+	v := NewValidator(sessions, WithIdleTimeout(30*time.Minute))
+
+	if err := v.Check(context.Background(), "sess1"); !errors.Is(err, ErrIdleTimeout) {
+		t.Errorf("expected ErrIdleTimeout, got %v", err)
+	}
+}
+
+func TestValidator_AbsoluteLifetime(t *testing.T) {
+	now := time.Now()
+	sessions := newMockSessions(iam.Session{ID: "sess1", CreatedAt: now.Add(-25 * time.Hour), LastActiveAt: now})
+	v := NewValidator(sessions, WithAbsoluteLifetime(24*time.Hour))
+
+	err := v.Check(context.Background(), "sess1")
+	if !errors.Is(err, ErrLifetimeExceeded) || !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("expected ErrLifetimeExceeded wrapping ErrSessionInvalid, got %v", err)
+	}
+}
+
+func TestValidator_Expired(t *testing.T) {
+	sessions := newMockSessions(iam.Session{ID: "sess1", ExpiresAt: time.Now().Add(-time.Minute)})
+	v := NewValidator(sessions)
+
+	if err := v.Check(context.Background(), "sess1"); !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("expected ErrSessionInvalid, got %v", err)
+	}
+}
+
+func TestValidator_MissingSessionID(t *testing.T) {
+	v := NewValidator(newMockSessions())
+
+	if err := v.Check(context.Background(), ""); err != nil {
+		t.Errorf("expected tokens without sid to pass by default, got %v", err)
+	}
+
+	v = NewValidator(newMockSessions(), WithRequireSession())
+	if err := v.Check(context.Background(), ""); !errors.Is(err, ErrNoCurrentSession) {
+		t.Errorf("expected ErrNoCurrentSession, got %v", err)
+	}
+}
+
+func TestValidator_TouchThrottled(t *testing.T) {
+	sessions := newMockSessions(iam.Session{ID: "sess1"})
+	v := NewValidator(sessions, WithTouchInterval(time.Hour))
+
+	_ = v.Check(context.Background(), "sess1")
+	_ = v.Check(context.Background(), "sess1")
+
+	select {
+	case id := <-sessions.touched:
+		if id != "sess1" {
+			t.Errorf("touched %s, want sess1", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected session to be touched")
+	}
+	select {
+	case <-sessions.touched:
+		t.Error("expected a single touch within the interval")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestValidator_Invalidate(t *testing.T) {
+	sessions := newMockSessions(iam.Session{ID: "sess1"})
+	v := NewValidator(sessions, WithTouchInterval(0))
+
+	_ = v.Check(context.Background(), "sess1")
+	_ = sessions.Revoke(context.Background(), "sess1")
+	v.Invalidate("sess1")
+
+	if err := v.Check(context.Background(), "sess1"); !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("expected ErrSessionInvalid after Invalidate, got %v", err)
+	}
+}
+
+func TestValidator_CanceledCallerDoesNotFailOthers(t *testing.T) {
+	sessions := newMockSessions(iam.Session{ID: "sess1"})
+	sessions.release = make(chan struct{})
+	v := NewValidator(sessions, WithTouchInterval(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() { first <- v.Check(ctx, "sess1") }()
+	second := make(chan error, 1)
+	go func() { second <- v.Check(context.Background(), "sess1") }()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled caller: got %v, want context.Canceled", err)
+	}
+	close(sessions.release)
+	if err := <-second; err != nil {
+		t.Errorf("second caller: %v", err)
+	}
+}
+
+func TestValidator_MaxEntries(t *testing.T) {
+	sessions := newMockSessions(iam.Session{ID: "sess1"}, iam.Session{ID: "sess2"})
+	v := NewValidator(sessions, WithMaxEntries(1), WithTouchInterval(0))
+
+	for _, id := range []string{"sess1", "sess2", "sess1"} {
+		if err := v.Check(context.Background(), id); err != nil {
+			t.Fatalf("Check(%s): %v", id, err)
+		}
+	}
+	if sessions.calls() != 3 {
+		t.Errorf("expected sess1 to be evicted (3 backend calls), got %d", sessions.calls())
+	}
+}
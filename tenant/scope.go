@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// ErrNoTenant is returned when code that must run in a tenant scope is
+// reached with a context that carries no tenant ID.
+var ErrNoTenant = errors.New("iam/tenant: no tenant in context")
+
+// FromContext returns the tenant ID stored by iam.WithTenantID (normally set
+// by the Auth middleware), or ErrNoTenant if the context is not tenant-scoped.
+//
+// Repositories should call it at the top of every query path instead of
+// reading iam.TenantIDFromContext directly, so a missing tenant surfaces as an
+// error rather than as an unfiltered query.
+func FromContext(ctx context.Context) (string, error) {
+	tenantID := iam.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		return "", ErrNoTenant
+	}
+	return tenantID, nil
+}
+
+// MustTenant returns the tenant ID from the context and panics with ErrNoTenant
+// if there is none. Use it in handlers that are only reachable behind the Auth
+// and Tenant middleware, where a missing tenant is a wiring bug.
+func MustTenant(ctx context.Context) string {
+	tenantID, err := FromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return tenantID
+}
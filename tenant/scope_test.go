@@ -0,0 +1,52 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+func TestFromContext(t *testing.T) {
+	ctx := iam.WithTenantID(context.Background(), "tenant123")
+
+	tenantID, err := FromContext(ctx)
+
+	if err != nil {
+		t.Fatalf("FromContext returned error: %v", err)
+	}
+	if tenantID != "tenant123" {
+		t.Errorf("expected tenant123, got %s", tenantID)
+	}
+}
+
+func TestFromContext_Missing(t *testing.T) {
+	_, err := FromContext(context.Background())
+
+	if !errors.Is(err, ErrNoTenant) {
+		t.Fatalf("expected ErrNoTenant, got %v", err)
+	}
+}
+
+func TestMustTenant(t *testing.T) {
+	ctx := iam.WithTenantID(context.Background(), "tenant123")
+
+	if tenantID := MustTenant(ctx); tenantID != "tenant123" {
+		t.Errorf("expected tenant123, got %s", tenantID)
+	}
+}
+
+func TestMustTenant_Panics(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic for missing tenant")
+		}
+		if err, ok := r.(error); !ok || !errors.Is(err, ErrNoTenant) {
+			t.Errorf("expected ErrNoTenant panic, got %v", r)
+		}
+	}()
+
+	MustTenant(context.Background())
+}
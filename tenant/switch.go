@@ -0,0 +1,46 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// Switch changes the active tenant of the authenticated user without
+// re-authentication. It checks membership via client.Tenants(), then exchanges
+// subjectToken (the user's current access token) for a token scoped to tenantID
+// via client.OAuth2(), which must implement iam.TenantSwitcher.
+//
+// A tenant picker typically lists client.Tenants().ListMemberships for the
+// current user and calls Switch with the selected tenant ID.
+func Switch(ctx context.Context, client *iam.Client, subjectToken, tenantID string) (*iam.OAuth2Token, error) {
+	userID := iam.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("iam/tenant: no authenticated user in context")
+	}
+	if tenantID == "" {
+		return nil, fmt.Errorf("iam/tenant: tenantID cannot be empty")
+	}
+
+	switcher, ok := client.OAuth2().(iam.TenantSwitcher)
+	if !ok {
+		return nil, fmt.Errorf("iam/tenant: oauth2 exchanger does not support tenant switching")
+	}
+
+	if svc := client.Tenants(); svc != nil {
+		member, err := svc.ValidateMembership(ctx, userID, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("iam/tenant: %w", err)
+		}
+		if !member {
+			return nil, fmt.Errorf("iam/tenant: user %q is not a member of tenant %q", userID, tenantID)
+		}
+	}
+
+	token, err := switcher.SwitchTenant(ctx, subjectToken, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("iam/tenant: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,68 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/fake"
+	"github.com/chimerakang/iam-go/tenant"
+)
+
+func newSwitchClient() *iam.Client {
+	return fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", []string{"admin"}),
+		fake.WithMembership("u1", "t2", "viewer"),
+		fake.WithOAuth2App("app_test", "secret_test", nil),
+	)
+}
+
+func TestSwitch_Success(t *testing.T) {
+	client := newSwitchClient()
+	ctx := iam.WithUserID(context.Background(), "u1")
+
+	token, err := tenant.Switch(ctx, client, "u1", "t2")
+	if err != nil {
+		t.Fatalf("Switch returned error: %v", err)
+	}
+
+	claims, err := client.Verifier().Verify(ctx, token.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if claims.TenantID != "t2" {
+		t.Errorf("expected switched token for t2, got %s", claims.TenantID)
+	}
+}
+
+func TestSwitch_NotMember(t *testing.T) {
+	client := newSwitchClient()
+	ctx := iam.WithUserID(context.Background(), "u1")
+
+	_, err := tenant.Switch(ctx, client, "u1", "t3")
+
+	if err == nil {
+		t.Fatal("expected error for non-member tenant")
+	}
+}
+
+func TestSwitch_NoUser(t *testing.T) {
+	client := newSwitchClient()
+
+	_, err := tenant.Switch(context.Background(), client, "u1", "t2")
+
+	if err == nil {
+		t.Fatal("expected error without authenticated user")
+	}
+}
+
+func TestSwitch_Unsupported(t *testing.T) {
+	client := fake.NewClient(fake.WithUser("u1", "t1", "alice@example.com", nil))
+	ctx := iam.WithUserID(context.Background(), "u1")
+
+	_, err := tenant.Switch(ctx, client, "u1", "t1")
+
+	if err == nil {
+		t.Fatal("expected error when no tenant switcher is configured")
+	}
+}
@@ -1,55 +1,120 @@
 // Package tenant provides TenantService implementation with local caching.
+//
+// The cache is bounded (LRU), coalesces concurrent misses for the same key
+// into a single backend call, and only caches negative results for errors
+// that wrap iam.ErrNotFound — transient backend failures are never cached.
 package tenant
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
 	"time"
 
 	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/cache"
+	"github.com/chimerakang/iam-go/internal/flight"
 )
 
 // Backend defines the contract for pluggable tenant resolution backends (gRPC, REST, etc.).
+//
+// Resolve must return an error wrapping iam.ErrNotFound when the tenant does
+// not exist; any other error is treated as transient and is not cached.
 type Backend interface {
 	// Resolve looks up a tenant by slug or subdomain.
 	Resolve(ctx context.Context, identifier string) (*iam.Tenant, error)
 
 	// ValidateMembership checks if a user belongs to a tenant.
 	ValidateMembership(ctx context.Context, userID, tenantID string) (bool, error)
+
+	// ListMemberships returns all tenants a user belongs to.
+	ListMemberships(ctx context.Context, userID string) ([]iam.Membership, error)
+}
+
+// Metrics receives cache instrumentation. *metrics.Metrics satisfies it.
+type Metrics interface {
+	RecordCacheHit(cacheType string)
+	RecordCacheMiss(cacheType string)
+	SetCacheSize(cacheType string, size float64)
 }
 
+// Cache type labels reported to Metrics.
+const (
+	CacheTypeResolve     = "tenant_resolve"
+	CacheTypeMembership  = "tenant_membership"
+	CacheTypeMemberships = "tenant_memberships"
+	cacheTypeSize        = "tenant"
+)
+
+// Defaults for Service options.
+const (
+	DefaultTTL         = 5 * time.Minute
+	DefaultNegativeTTL = 30 * time.Second
+	DefaultMaxEntries  = 10000
+)
+
 // Service implements iam.TenantService with local caching and configurable backend.
 type Service struct {
-	backend Backend
-	ttl     time.Duration
-	cache   sync.Map // key: "resolve:<identifier>" | "member:<userID>:<tenantID>", value: cacheEntry
+	backend     Backend
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxEntries  int
+	metrics     Metrics
+
+	cache *cache.LRU[string, cacheEntry] // key: "resolve:<identifier>" | "member:<userID>:<tenantID>" | "memberships:<userID>"
+	sf    flight.Group
 }
 
 type cacheEntry struct {
-	value     interface{}
-	expiresAt time.Time
+	value    interface{}
+	notFound bool
 }
 
 // Option configures Service behavior.
 type Option func(*Service)
 
-// WithTTL sets cache TTL (default: 5 minutes).
+// WithTTL sets cache TTL for successful lookups (default: 5 minutes).
 func WithTTL(ttl time.Duration) Option {
 	return func(s *Service) {
 		s.ttl = ttl
 	}
 }
 
+// WithNegativeTTL sets how long a not-found tenant is cached (default: 30 seconds).
+// Zero disables negative caching.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.negativeTTL = ttl
+	}
+}
+
+// WithMaxEntries bounds the cache size (default: 10000). When full, the least
+// recently used entry is evicted. Zero or negative means unbounded.
+func WithMaxEntries(n int) Option {
+	return func(s *Service) {
+		s.maxEntries = n
+	}
+}
+
+// WithMetrics reports cache hits, misses and size to m.
+func WithMetrics(m Metrics) Option {
+	return func(s *Service) {
+		s.metrics = m
+	}
+}
+
 // New creates a new TenantService with the given backend and options.
 func New(backend Backend, opts ...Option) *Service {
 	s := &Service{
-		backend: backend,
-		ttl:     5 * time.Minute,
+		backend:     backend,
+		ttl:         DefaultTTL,
+		negativeTTL: DefaultNegativeTTL,
+		maxEntries:  DefaultMaxEntries,
 	}
 	for _, opt := range opts {
 		opt(s)
 	}
+	s.cache = cache.New[string, cacheEntry](s.maxEntries)
 	return s
 }
 
@@ -59,39 +124,13 @@ func (s *Service) Resolve(ctx context.Context, identifier string) (*iam.Tenant,
 		return nil, fmt.Errorf("iam/tenant: identifier cannot be empty")
 	}
 
-	cacheKey := fmt.Sprintf("resolve:%s", identifier)
-
-	// Try cache first
-	if cached, ok := s.cache.Load(cacheKey); ok {
-		entry := cached.(cacheEntry)
-		if time.Now().Before(entry.expiresAt) {
-			if entry.value == nil {
-				return nil, fmt.Errorf("iam/tenant: tenant not found (cached)")
-			}
-			return entry.value.(*iam.Tenant), nil
-		}
-		// Expired entry, remove it
-		s.cache.Delete(cacheKey)
-	}
-
-	// Call backend
-	tenant, err := s.backend.Resolve(ctx, identifier)
+	v, err := s.load(ctx, CacheTypeResolve, fmt.Sprintf("resolve:%s", identifier), func(ctx context.Context) (interface{}, error) {
+		return s.backend.Resolve(ctx, identifier)
+	})
 	if err != nil {
-		// Cache negative result to avoid repeated lookups
-		s.cache.Store(cacheKey, cacheEntry{
-			value:     nil,
-			expiresAt: time.Now().Add(s.ttl),
-		})
-		return nil, fmt.Errorf("iam/tenant: %w", err)
+		return nil, err
 	}
-
-	// Cache positive result
-	s.cache.Store(cacheKey, cacheEntry{
-		value:     tenant,
-		expiresAt: time.Now().Add(s.ttl),
-	})
-
-	return tenant, nil
+	return v.(*iam.Tenant), nil
 }
 
 // ValidateMembership checks if a user belongs to a tenant with local caching.
@@ -100,37 +139,83 @@ func (s *Service) ValidateMembership(ctx context.Context, userID, tenantID strin
 		return false, fmt.Errorf("iam/tenant: userID and tenantID cannot be empty")
 	}
 
-	cacheKey := fmt.Sprintf("member:%s:%s", userID, tenantID)
+	v, err := s.load(ctx, CacheTypeMembership, fmt.Sprintf("member:%s:%s", userID, tenantID), func(ctx context.Context) (interface{}, error) {
+		return s.backend.ValidateMembership(ctx, userID, tenantID)
+	})
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
 
-	// Try cache first
-	if cached, ok := s.cache.Load(cacheKey); ok {
-		entry := cached.(cacheEntry)
-		if time.Now().Before(entry.expiresAt) {
-			return entry.value.(bool), nil
-		}
-		// Expired entry, remove it
-		s.cache.Delete(cacheKey)
+// ListMemberships returns all tenants a user belongs to with local caching.
+// The returned slice is the caller's to modify.
+func (s *Service) ListMemberships(ctx context.Context, userID string) ([]iam.Membership, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("iam/tenant: userID cannot be empty")
 	}
 
-	// Call backend
-	ok, err := s.backend.ValidateMembership(ctx, userID, tenantID)
+	v, err := s.load(ctx, CacheTypeMemberships, fmt.Sprintf("memberships:%s", userID), func(ctx context.Context) (interface{}, error) {
+		return s.backend.ListMemberships(ctx, userID)
+	})
 	if err != nil {
-		return false, fmt.Errorf("iam/tenant: %w", err)
+		return nil, err
 	}
+	// Copy so callers cannot modify the cached slice
+	return append([]iam.Membership(nil), v.([]iam.Membership)...), nil
+}
 
-	// Cache result
-	s.cache.Store(cacheKey, cacheEntry{
-		value:     ok,
-		expiresAt: time.Now().Add(s.ttl),
+// load returns the cached value for key, or calls fetch once for all
+// concurrent callers that miss on the same key (see flight.Group).
+func (s *Service) load(ctx context.Context, cacheType, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	// Try cache first
+	if entry, ok := s.cache.Get(key); ok {
+		s.recordHit(cacheType)
+		if entry.notFound {
+			return nil, fmt.Errorf("iam/tenant: %w (cached)", iam.ErrNotFound)
+		}
+		return entry.value, nil
+	}
+	s.recordMiss(cacheType)
+
+	// Call backend, coalescing concurrent misses
+	v, err := s.sf.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		v, err := fetch(ctx)
+		switch {
+		case err == nil:
+			s.cache.Set(key, cacheEntry{value: v}, s.ttl)
+		case errors.Is(err, iam.ErrNotFound) && s.negativeTTL > 0:
+			s.cache.Set(key, cacheEntry{notFound: true}, s.negativeTTL)
+		}
+		s.recordSize()
+		return v, err
 	})
-
-	return ok, nil
+	if err != nil {
+		return nil, fmt.Errorf("iam/tenant: %w", err)
+	}
+	return v, nil
 }
 
 // ClearCache removes all cached entries.
 func (s *Service) ClearCache() {
-	s.cache.Range(func(key, value interface{}) bool {
-		s.cache.Delete(key)
-		return true
-	})
+	s.cache.Clear()
+	s.recordSize()
+}
+
+func (s *Service) recordHit(cacheType string) {
+	if s.metrics != nil {
+		s.metrics.RecordCacheHit(cacheType)
+	}
+}
+
+func (s *Service) recordMiss(cacheType string) {
+	if s.metrics != nil {
+		s.metrics.RecordCacheMiss(cacheType)
+	}
+}
+
+func (s *Service) recordSize() {
+	if s.metrics != nil {
+		s.metrics.SetCacheSize(cacheTypeSize, float64(s.cache.Len()))
+	}
 }
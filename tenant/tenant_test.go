@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,19 +17,37 @@ type mockBackend struct {
 	memberships        map[string]map[string]bool // userID -> tenantID -> bool
 	resolveCalls       int
 	membershipCalls    int
+	listCalls          int
 	shouldFailResolve  bool
 	shouldFailMember   bool
+	resolveDelay       time.Duration
+
+	mu sync.Mutex
 }
 
 func (m *mockBackend) Resolve(ctx context.Context, identifier string) (*iam.Tenant, error) {
+	m.mu.Lock()
 	m.resolveCalls++
+	m.mu.Unlock()
+	time.Sleep(m.resolveDelay)
 	if m.shouldFailResolve {
 		return nil, errors.New("resolve failed")
 	}
 	if tenant, ok := m.tenants[identifier]; ok {
 		return tenant, nil
 	}
-	return nil, fmt.Errorf("tenant not found: %s", identifier)
+	return nil, fmt.Errorf("tenant not found: %s: %w", identifier, iam.ErrNotFound)
+}
+
+func (m *mockBackend) ListMemberships(ctx context.Context, userID string) ([]iam.Membership, error) {
+	m.listCalls++
+	var result []iam.Membership
+	for tenantID, ok := range m.memberships[userID] {
+		if ok {
+			result = append(result, iam.Membership{Tenant: iam.Tenant{ID: tenantID}, Status: "active"})
+		}
+	}
+	return result, nil
 }
 
 func (m *mockBackend) ValidateMembership(ctx context.Context, userID, tenantID string) (bool, error) {
@@ -268,3 +287,202 @@ func TestErrorWrapping(t *testing.T) {
 		}
 	}
 }
+
+func TestListMemberships_Cached(t *testing.T) {
+	backend := &mockBackend{
+		memberships: map[string]map[string]bool{
+			"user123": {"tenant1": true, "tenant2": true},
+		},
+	}
+	svc := New(backend)
+
+	memberships, err := svc.ListMemberships(context.Background(), "user123")
+	if err != nil {
+		t.Fatalf("ListMemberships returned error: %v", err)
+	}
+	if len(memberships) != 2 {
+		t.Errorf("expected 2 memberships, got %d", len(memberships))
+	}
+
+	_, _ = svc.ListMemberships(context.Background(), "user123")
+
+	if backend.listCalls != 1 {
+		t.Errorf("expected 1 backend call (cached), got %d", backend.listCalls)
+	}
+}
+
+func TestListMemberships_ReturnsCopy(t *testing.T) {
+	backend := &mockBackend{
+		memberships: map[string]map[string]bool{
+			"user123": {"tenant1": true},
+		},
+	}
+	svc := New(backend)
+
+	first, _ := svc.ListMemberships(context.Background(), "user123")
+	first[0].Status = "modified"
+
+	second, err := svc.ListMemberships(context.Background(), "user123")
+	if err != nil {
+		t.Fatalf("ListMemberships returned error: %v", err)
+	}
+	if second[0].Status == "modified" {
+		t.Error("modifying the returned slice changed the cached memberships")
+	}
+}
+
+func TestListMemberships_EmptyUserID(t *testing.T) {
+	svc := New(&mockBackend{})
+
+	_, err := svc.ListMemberships(context.Background(), "")
+
+	if err == nil {
+		t.Fatal("expected error for empty userID")
+	}
+}
+
+func TestResolve_NotFound_WrapsErrNotFound(t *testing.T) {
+	svc := New(&mockBackend{tenants: make(map[string]*iam.Tenant)})
+
+	_, err := svc.Resolve(context.Background(), "unknown")
+	if !errors.Is(err, iam.ErrNotFound) {
+		t.Fatalf("expected iam.ErrNotFound, got %v", err)
+	}
+
+	// Cached negative result must keep the sentinel.
+	_, err = svc.Resolve(context.Background(), "unknown")
+	if !errors.Is(err, iam.ErrNotFound) {
+		t.Fatalf("expected cached iam.ErrNotFound, got %v", err)
+	}
+}
+
+func TestResolve_TransientErrorNotCached(t *testing.T) {
+	backend := &mockBackend{
+		tenants:           make(map[string]*iam.Tenant),
+		shouldFailResolve: true,
+	}
+	svc := New(backend)
+
+	_, _ = svc.Resolve(context.Background(), "acme")
+	_, _ = svc.Resolve(context.Background(), "acme")
+
+	if backend.resolveCalls != 2 {
+		t.Errorf("expected 2 backend calls (transient error not cached), got %d", backend.resolveCalls)
+	}
+}
+
+func TestResolve_NegativeTTLExpiration(t *testing.T) {
+	backend := &mockBackend{tenants: make(map[string]*iam.Tenant)}
+	svc := New(backend, WithTTL(time.Hour), WithNegativeTTL(50*time.Millisecond))
+
+	_, _ = svc.Resolve(context.Background(), "unknown")
+	time.Sleep(80 * time.Millisecond)
+	_, _ = svc.Resolve(context.Background(), "unknown")
+
+	if backend.resolveCalls != 2 {
+		t.Errorf("expected 2 backend calls (after negative TTL), got %d", backend.resolveCalls)
+	}
+}
+
+func TestResolve_NegativeCachingDisabled(t *testing.T) {
+	backend := &mockBackend{tenants: make(map[string]*iam.Tenant)}
+	svc := New(backend, WithNegativeTTL(0))
+
+	_, _ = svc.Resolve(context.Background(), "unknown")
+	_, _ = svc.Resolve(context.Background(), "unknown")
+
+	if backend.resolveCalls != 2 {
+		t.Errorf("expected 2 backend calls (negative caching disabled), got %d", backend.resolveCalls)
+	}
+}
+
+func TestResolve_CoalescesConcurrentMisses(t *testing.T) {
+	backend := &mockBackend{
+		tenants: map[string]*iam.Tenant{
+			"acme": {ID: "tenant123", Slug: "acme", Status: "active"},
+		},
+		resolveDelay: 50 * time.Millisecond,
+	}
+	svc := New(backend)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.Resolve(context.Background(), "acme"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Resolve returned error: %v", err)
+	}
+	if backend.resolveCalls != 1 {
+		t.Errorf("expected 1 backend call (coalesced), got %d", backend.resolveCalls)
+	}
+}
+
+func TestResolve_MaxEntries(t *testing.T) {
+	backend := &mockBackend{
+		tenants: map[string]*iam.Tenant{
+			"a": {ID: "1", Slug: "a"},
+			"b": {ID: "2", Slug: "b"},
+			"c": {ID: "3", Slug: "c"},
+		},
+	}
+	svc := New(backend, WithMaxEntries(2))
+
+	_, _ = svc.Resolve(context.Background(), "a")
+	_, _ = svc.Resolve(context.Background(), "b")
+	_, _ = svc.Resolve(context.Background(), "c") // evicts "a"
+	_, _ = svc.Resolve(context.Background(), "a")
+
+	if backend.resolveCalls != 4 {
+		t.Errorf("expected 4 backend calls (a evicted), got %d", backend.resolveCalls)
+	}
+}
+
+// recordingMetrics implements Metrics for testing
+type recordingMetrics struct {
+	hits   map[string]int
+	misses map[string]int
+	size   float64
+}
+
+func (r *recordingMetrics) RecordCacheHit(cacheType string)  { r.hits[cacheType]++ }
+func (r *recordingMetrics) RecordCacheMiss(cacheType string) { r.misses[cacheType]++ }
+func (r *recordingMetrics) SetCacheSize(_ string, size float64) {
+	r.size = size
+}
+
+func TestMetrics(t *testing.T) {
+	backend := &mockBackend{
+		tenants: map[string]*iam.Tenant{
+			"acme": {ID: "tenant123", Slug: "acme", Status: "active"},
+		},
+		memberships: map[string]map[string]bool{
+			"user123": {"tenant123": true},
+		},
+	}
+	m := &recordingMetrics{hits: map[string]int{}, misses: map[string]int{}}
+	svc := New(backend, WithMetrics(m))
+
+	_, _ = svc.Resolve(context.Background(), "acme")
+	_, _ = svc.Resolve(context.Background(), "acme")
+	_, _ = svc.ValidateMembership(context.Background(), "user123", "tenant123")
+
+	if m.misses[CacheTypeResolve] != 1 || m.hits[CacheTypeResolve] != 1 {
+		t.Errorf("unexpected resolve metrics: hits=%v misses=%v", m.hits, m.misses)
+	}
+	if m.misses[CacheTypeMembership] != 1 {
+		t.Errorf("expected 1 membership miss, got %d", m.misses[CacheTypeMembership])
+	}
+	if m.size != 2 {
+		t.Errorf("expected cache size 2, got %v", m.size)
+	}
+}
@@ -0,0 +1,398 @@
+// Package tenantsql wraps a database/sql driver so every statement runs with
+// the caller's tenant exposed to PostgreSQL row-level security.
+//
+// Before a statement is executed, the wrapped connection copies
+// iam.TenantIDFromContext into a session setting (default "iam.tenant_id")
+// with set_config. RLS policies then filter on that setting — see
+// scripts/rls.sql for policies matching the tables in scripts/init.sql.
+//
+// Usage:
+//
+//	connector, _ := pq.NewConnector(dsn)
+//	db := tenantsql.OpenDB(connector, tenantsql.WithStrict())
+//	rows, err := db.QueryContext(ctx, "SELECT id, name FROM roles")
+//
+// The context passed to QueryContext/ExecContext must carry the tenant
+// (normally set by the Auth middleware). Calls without a context-aware API
+// (db.Query, db.Exec) run unscoped.
+package tenantsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/tenant"
+)
+
+// DefaultSetting is the PostgreSQL session setting that carries the tenant ID.
+const DefaultSetting = "iam.tenant_id"
+
+const setConfigQuery = "SELECT set_config($1, $2, false)"
+
+// Option configures the driver wrapper.
+type Option func(*config)
+
+type config struct {
+	setting string
+	strict  bool
+	guard   *Guard
+}
+
+// WithSetting sets the session setting name used by RLS policies (default: "iam.tenant_id").
+func WithSetting(name string) Option {
+	return func(c *config) { c.setting = name }
+}
+
+// WithStrict rejects statements issued without tenant context with tenant.ErrNoTenant
+// instead of running them with an empty tenant setting.
+func WithStrict() Option {
+	return func(c *config) { c.strict = true }
+}
+
+// WithGuard records every statement issued without tenant context in g.
+func WithGuard(g *Guard) Option {
+	return func(c *config) { c.guard = g }
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{setting: DefaultSetting}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return cfg
+}
+
+// Wrap returns a driver that scopes every connection opened by d to the tenant
+// in the statement context. Register the result with sql.Register.
+func Wrap(d driver.Driver, opts ...Option) driver.Driver {
+	return &wrappedDriver{Driver: d, cfg: newConfig(opts)}
+}
+
+// WrapConnector returns a connector whose connections are tenant-scoped.
+func WrapConnector(c driver.Connector, opts ...Option) driver.Connector {
+	return &wrappedConnector{Connector: c, cfg: newConfig(opts)}
+}
+
+// OpenDB opens a *sql.DB on top of a tenant-scoped connector.
+func OpenDB(c driver.Connector, opts ...Option) *sql.DB {
+	return sql.OpenDB(WrapConnector(c, opts...))
+}
+
+// --- driver / connector ---
+
+type wrappedDriver struct {
+	driver.Driver
+	cfg *config
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: c, cfg: d.cfg}, nil
+}
+
+type wrappedConnector struct {
+	driver.Connector
+	cfg *config
+}
+
+func (c *wrappedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dc, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: dc, cfg: c.cfg}, nil
+}
+
+func (c *wrappedConnector) Driver() driver.Driver {
+	return &wrappedDriver{Driver: c.Connector.Driver(), cfg: c.cfg}
+}
+
+// --- connection ---
+
+// conn tracks which tenant the server-side session setting currently holds so
+// set_config only runs when the tenant changes.
+type conn struct {
+	driver.Conn
+	cfg *config
+
+	applied  bool
+	tenantID string
+
+	// inTx is set while a transaction is open; txScoped records that the
+	// setting was changed inside it, which a rollback undoes on the server.
+	inTx     bool
+	txScoped bool
+}
+
+var (
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.SessionResetter    = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+)
+
+// scope makes the session setting match the tenant in ctx.
+func (c *conn) scope(ctx context.Context, query string) error {
+	tenantID := iam.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		if c.cfg.guard != nil {
+			c.cfg.guard.record(query)
+		}
+		if c.cfg.strict {
+			return fmt.Errorf("iam/tenantsql: %w", tenant.ErrNoTenant)
+		}
+	}
+
+	if c.applied && c.tenantID == tenantID {
+		return nil
+	}
+
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: c.cfg.setting},
+		{Ordinal: 2, Value: tenantID},
+	}
+	if err := c.execRaw(ctx, setConfigQuery, args); err != nil {
+		c.applied = false
+		return fmt.Errorf("iam/tenantsql: set %s: %w", c.cfg.setting, err)
+	}
+	c.applied = true
+	c.tenantID = tenantID
+	if c.inTx {
+		c.txScoped = true
+	}
+	return nil
+}
+
+// execRaw runs a statement on the underlying connection without scoping.
+func (c *conn) execRaw(ctx context.Context, query string, args []driver.NamedValue) error {
+	if ex, ok := c.Conn.(driver.ExecerContext); ok {
+		_, err := ex.ExecContext(ctx, query, args)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	ds, err := c.prepareRaw(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ds.Close() }()
+
+	if sx, ok := ds.(driver.StmtExecContext); ok {
+		_, err = sx.ExecContext(ctx, args)
+		return err
+	}
+	_, err = ds.Exec(namedToValues(args)) //nolint:staticcheck // fallback for drivers without StmtExecContext
+	return err
+}
+
+func (c *conn) prepareRaw(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ex, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.scope(ctx, query); err != nil {
+		return nil, err
+	}
+	return ex.ExecContext(ctx, query, args)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.scope(ctx, query); err != nil {
+		return nil, err
+	}
+	return q.QueryContext(ctx, query, args)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	ds, err := c.prepareRaw(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: ds, conn: c, query: query}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.scope(ctx, "BEGIN"); err != nil {
+		return nil, err
+	}
+	var (
+		dt  driver.Tx
+		err error
+	)
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		dt, err = b.BeginTx(ctx, opts)
+	} else {
+		dt, err = c.Conn.Begin() //nolint:staticcheck // fallback for drivers without ConnBeginTx
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.inTx = true
+	c.txScoped = false
+	return &tx{Tx: dt, conn: c}, nil
+}
+
+// --- transaction ---
+
+// tx forgets the tracked tenant when a transaction that changed the setting
+// does not commit: the server reverts set_config on rollback, so the next
+// statement must apply it again.
+type tx struct {
+	driver.Tx
+	conn *conn
+}
+
+func (t *tx) Commit() error {
+	err := t.Tx.Commit()
+	t.conn.endTx(err == nil)
+	return err
+}
+
+func (t *tx) Rollback() error {
+	err := t.Tx.Rollback()
+	t.conn.endTx(false)
+	return err
+}
+
+func (c *conn) endTx(committed bool) {
+	if c.txScoped && !committed {
+		c.applied = false
+	}
+	c.inTx = false
+	c.txScoped = false
+}
+
+// ResetSession is called by database/sql before a pooled connection is
+// reused. The session setting survives on the server, so the tracked tenant
+// stays valid; the next statement re-scopes only if its tenant differs.
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		if err := r.ResetSession(ctx); err != nil {
+			c.applied = false
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if ch, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return ch.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// --- statement ---
+
+// stmt re-scopes on every execution: a statement prepared under one tenant
+// may be executed later under another.
+type stmt struct {
+	driver.Stmt
+	conn  *conn
+	query string
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := s.conn.scope(ctx, s.query); err != nil {
+		return nil, err
+	}
+	if sx, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return sx.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(namedToValues(args)) //nolint:staticcheck // fallback for drivers without StmtExecContext
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := s.conn.scope(ctx, s.query); err != nil {
+		return nil, err
+	}
+	if sq, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return sq.QueryContext(ctx, args)
+	}
+	return s.Stmt.Query(namedToValues(args)) //nolint:staticcheck // fallback for drivers without StmtQueryContext
+}
+
+func namedToValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	return values
+}
+
+// --- guard ---
+
+// TB is the subset of testing.TB used by Guard.AssertScoped.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Guard records statements that ran without tenant context. Install it with
+// WithGuard in tests and call AssertScoped once the code under test has run.
+type Guard struct {
+	mu       sync.Mutex
+	unscoped []string
+}
+
+// NewGuard creates an empty Guard.
+func NewGuard() *Guard {
+	return &Guard{}
+}
+
+func (g *Guard) record(query string) {
+	g.mu.Lock()
+	g.unscoped = append(g.unscoped, query)
+	g.mu.Unlock()
+}
+
+// Unscoped returns the statements recorded so far.
+func (g *Guard) Unscoped() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.unscoped...)
+}
+
+// Reset clears the recorded statements.
+func (g *Guard) Reset() {
+	g.mu.Lock()
+	g.unscoped = nil
+	g.mu.Unlock()
+}
+
+// AssertScoped fails t for every statement that ran without tenant context.
+func (g *Guard) AssertScoped(t TB) {
+	t.Helper()
+	for _, q := range g.Unscoped() {
+		t.Errorf("iam/tenantsql: query ran without tenant scope: %s", q)
+	}
+}
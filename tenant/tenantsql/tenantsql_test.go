@@ -0,0 +1,329 @@
+package tenantsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/tenant"
+)
+
+// mockDriver records every statement executed on its connections.
+type mockDriver struct {
+	mu    sync.Mutex
+	execs []string
+}
+
+func (d *mockDriver) Open(string) (driver.Conn, error) {
+	return &mockConn{d: d}, nil
+}
+
+func (d *mockDriver) log(query string, args []driver.NamedValue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vals := make([]string, len(args))
+	for i, a := range args {
+		vals[i] = fmt.Sprint(a.Value)
+	}
+	d.execs = append(d.execs, query+" "+strings.Join(vals, ","))
+}
+
+func (d *mockDriver) statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.execs...)
+}
+
+type mockConnector struct{ d *mockDriver }
+
+func (c *mockConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c *mockConnector) Driver() driver.Driver                        { return c.d }
+
+type mockConn struct{ d *mockDriver }
+
+func (c *mockConn) Prepare(query string) (driver.Stmt, error) {
+	return &mockStmt{c: c, query: query}, nil
+}
+func (c *mockConn) Close() error              { return nil }
+func (c *mockConn) Begin() (driver.Tx, error) { return mockTx{}, nil }
+
+func (c *mockConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.log(query, args)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *mockConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.log(query, args)
+	return &mockRows{}, nil
+}
+
+type mockStmt struct {
+	c     *mockConn
+	query string
+}
+
+func (s *mockStmt) Close() error  { return nil }
+func (s *mockStmt) NumInput() int { return -1 }
+func (s *mockStmt) Exec(args []driver.Value) (driver.Result, error) {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return s.c.ExecContext(context.Background(), s.query, named)
+}
+func (s *mockStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.c.d.log(s.query, nil)
+	return &mockRows{}, nil
+}
+
+type mockTx struct{}
+
+func (mockTx) Commit() error   { return nil }
+func (mockTx) Rollback() error { return nil }
+
+type mockRows struct{}
+
+func (*mockRows) Columns() []string         { return nil }
+func (*mockRows) Close() error              { return nil }
+func (*mockRows) Next([]driver.Value) error { return io.EOF }
+
+func newDB(t *testing.T, opts ...Option) (*sql.DB, *mockDriver) {
+	t.Helper()
+	d := &mockDriver{}
+	db := OpenDB(&mockConnector{d: d}, opts...)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+func ctxWithTenant(tenantID string) context.Context {
+	return iam.WithTenantID(context.Background(), tenantID)
+}
+
+func TestExec_SetsTenant(t *testing.T) {
+	db, d := newDB(t)
+
+	if _, err := db.ExecContext(ctxWithTenant("tenant1"), "DELETE FROM roles"); err != nil {
+		t.Fatalf("ExecContext returned error: %v", err)
+	}
+
+	got := d.statements()
+	want := []string{
+		setConfigQuery + " iam.tenant_id,tenant1",
+		"DELETE FROM roles ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestExec_SkipsUnchangedTenant(t *testing.T) {
+	db, d := newDB(t)
+	ctx := ctxWithTenant("tenant1")
+
+	_, _ = db.ExecContext(ctx, "SELECT 1")
+	_, _ = db.ExecContext(ctx, "SELECT 2")
+
+	if n := countSetConfig(d); n != 1 {
+		t.Errorf("expected 1 set_config call, got %d", n)
+	}
+}
+
+func TestExec_TenantChange(t *testing.T) {
+	db, d := newDB(t)
+
+	_, _ = db.ExecContext(ctxWithTenant("tenant1"), "SELECT 1")
+	_, _ = db.ExecContext(ctxWithTenant("tenant2"), "SELECT 1")
+
+	got := d.statements()
+	if len(got) != 4 || got[2] != setConfigQuery+" iam.tenant_id,tenant2" {
+		t.Errorf("expected setting switched to tenant2, got %q", got)
+	}
+}
+
+func TestQuery_Unscoped_ClearsSetting(t *testing.T) {
+	db, d := newDB(t)
+
+	_, _ = db.ExecContext(ctxWithTenant("tenant1"), "SELECT 1")
+	rows, err := db.QueryContext(context.Background(), "SELECT * FROM roles")
+	if err != nil {
+		t.Fatalf("QueryContext returned error: %v", err)
+	}
+	_ = rows.Close()
+
+	got := d.statements()
+	if len(got) != 4 || got[2] != setConfigQuery+" iam.tenant_id," {
+		t.Errorf("expected setting cleared for unscoped query, got %q", got)
+	}
+}
+
+func TestStrict_RejectsUnscoped(t *testing.T) {
+	db, d := newDB(t, WithStrict())
+
+	_, err := db.ExecContext(context.Background(), "DELETE FROM roles")
+
+	if !errors.Is(err, tenant.ErrNoTenant) {
+		t.Fatalf("expected ErrNoTenant, got %v", err)
+	}
+	if len(d.statements()) != 0 {
+		t.Errorf("expected no statements executed, got %q", d.statements())
+	}
+}
+
+func TestWithSetting(t *testing.T) {
+	db, d := newDB(t, WithSetting("app.current_tenant"))
+
+	_, _ = db.ExecContext(ctxWithTenant("tenant1"), "SELECT 1")
+
+	if got := d.statements()[0]; got != setConfigQuery+" app.current_tenant,tenant1" {
+		t.Errorf("unexpected set_config statement: %s", got)
+	}
+}
+
+func TestPreparedStmt_RescopesPerExecution(t *testing.T) {
+	db, d := newDB(t)
+
+	stmt, err := db.PrepareContext(ctxWithTenant("tenant1"), "UPDATE roles SET name = $1")
+	if err != nil {
+		t.Fatalf("PrepareContext returned error: %v", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	_, _ = stmt.ExecContext(ctxWithTenant("tenant1"), "a")
+	_, _ = stmt.ExecContext(ctxWithTenant("tenant2"), "b")
+
+	if n := countSetConfig(d); n != 2 {
+		t.Errorf("expected 2 set_config calls, got %d: %q", n, d.statements())
+	}
+}
+
+func TestBeginTx_Scoped(t *testing.T) {
+	db, d := newDB(t)
+
+	tx, err := db.BeginTx(ctxWithTenant("tenant1"), nil)
+	if err != nil {
+		t.Fatalf("BeginTx returned error: %v", err)
+	}
+	_ = tx.Commit()
+
+	if n := countSetConfig(d); n != 1 {
+		t.Errorf("expected 1 set_config call, got %d", n)
+	}
+}
+
+func TestBeginTx_RollbackRescopes(t *testing.T) {
+	db, d := newDB(t)
+
+	// The transaction switches the setting to tenant1; the rollback reverts
+	// the server to tenant2, so the next tenant1 statement must set it again.
+	_, _ = db.ExecContext(ctxWithTenant("tenant2"), "SELECT 1")
+	tx, err := db.BeginTx(ctxWithTenant("tenant2"), nil)
+	if err != nil {
+		t.Fatalf("BeginTx returned error: %v", err)
+	}
+	_, _ = tx.ExecContext(ctxWithTenant("tenant1"), "SELECT 2")
+	_ = tx.Rollback()
+	_, _ = db.ExecContext(ctxWithTenant("tenant1"), "SELECT 3")
+
+	got := d.statements()
+	if n := countSetConfig(d); n != 3 || got[len(got)-2] != setConfigQuery+" iam.tenant_id,tenant1" {
+		t.Errorf("expected tenant1 re-applied after rollback, got %q", got)
+	}
+}
+
+func TestBeginTx_CommitKeepsTenant(t *testing.T) {
+	db, d := newDB(t)
+
+	tx, err := db.BeginTx(ctxWithTenant("tenant2"), nil)
+	if err != nil {
+		t.Fatalf("BeginTx returned error: %v", err)
+	}
+	_, _ = tx.ExecContext(ctxWithTenant("tenant1"), "SELECT 1")
+	_ = tx.Commit()
+	_, _ = db.ExecContext(ctxWithTenant("tenant1"), "SELECT 2")
+
+	if n := countSetConfig(d); n != 2 {
+		t.Errorf("expected 2 set_config calls, got %d: %q", n, d.statements())
+	}
+}
+
+func TestPooledConn_KeepsTenant(t *testing.T) {
+	db, d := newDB(t)
+	ctx := ctxWithTenant("tenant1")
+
+	// The single pooled connection is returned and reused between calls;
+	// the server-side setting still holds tenant1.
+	_, _ = db.ExecContext(ctx, "SELECT 1")
+	_, _ = db.ExecContext(ctx, "SELECT 2")
+	_, _ = db.ExecContext(ctxWithTenant("tenant2"), "SELECT 3")
+
+	if n := countSetConfig(d); n != 2 {
+		t.Errorf("expected 2 set_config calls, got %d: %q", n, d.statements())
+	}
+}
+
+func TestGuard(t *testing.T) {
+	g := NewGuard()
+	db, _ := newDB(t, WithGuard(g))
+
+	_, _ = db.ExecContext(ctxWithTenant("tenant1"), "SELECT scoped")
+	_, _ = db.ExecContext(context.Background(), "SELECT unscoped")
+
+	unscoped := g.Unscoped()
+	if len(unscoped) != 1 || unscoped[0] != "SELECT unscoped" {
+		t.Fatalf("expected one unscoped query, got %q", unscoped)
+	}
+
+	rec := &recordingTB{}
+	g.AssertScoped(rec)
+	if len(rec.errors) != 1 {
+		t.Errorf("expected AssertScoped to report 1 error, got %d", len(rec.errors))
+	}
+
+	g.Reset()
+	rec = &recordingTB{}
+	g.AssertScoped(rec)
+	if len(rec.errors) != 0 {
+		t.Errorf("expected no errors after Reset, got %v", rec.errors)
+	}
+}
+
+func TestWrap_Driver(t *testing.T) {
+	d := &mockDriver{}
+	wrapped := Wrap(d)
+
+	c, err := wrapped.Open("")
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	ex := c.(driver.ExecerContext)
+	_, _ = ex.ExecContext(ctxWithTenant("tenant1"), "SELECT 1", nil)
+
+	if n := countSetConfig(d); n != 1 {
+		t.Errorf("expected 1 set_config call, got %d", n)
+	}
+}
+
+type recordingTB struct{ errors []string }
+
+func (r *recordingTB) Helper() {}
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func countSetConfig(d *mockDriver) int {
+	n := 0
+	for _, s := range d.statements() {
+		if strings.HasPrefix(s, setConfigQuery) {
+			n++
+		}
+	}
+	return n
+}
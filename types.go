@@ -1,6 +1,19 @@
 package iam
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// PrincipalType is the kind of party a credential was issued to.
+type PrincipalType string
+
+// Principal types.
+const (
+	PrincipalUser           PrincipalType = "user"
+	PrincipalServiceAccount PrincipalType = "service_account"
+	PrincipalAPIKey         PrincipalType = "api_key"
+)
 
 // Claims represents the standard claims extracted from a verified token.
 type Claims struct {
@@ -8,10 +21,111 @@ type Claims struct {
 	TenantID  string
 	Roles     []string
 	Email     string
+	SessionID string // "sid" claim
 	ExpiresAt time.Time
 	IssuedAt  time.Time
 	Issuer    string
-	Extra     map[string]any
+	Actor     *Actor // "act" claim; non-nil when Subject is being impersonated
+
+	// PrincipalType is the kind of Subject; empty means PrincipalUser.
+	PrincipalType PrincipalType
+
+	// Scopes limits the permissions of the credential (API keys); nil means
+	// the subject's permissions apply unrestricted.
+	Scopes []string
+
+	// Authentication context: how and when the user authenticated.
+	ACR      string    // "acr" claim, e.g. "mfa"
+	AMR      []string  // "amr" claim, e.g. ["pwd", "otp"] (RFC 8176)
+	AuthTime time.Time // "auth_time" claim; zero if absent
+
+	Extra map[string]any
+}
+
+// Principal returns the kind of the token's subject, PrincipalUser unless
+// the verifier recorded otherwise. It returns "" for nil claims.
+func (c *Claims) Principal() PrincipalType {
+	switch {
+	case c == nil:
+		return ""
+	case c.PrincipalType == "":
+		return PrincipalUser
+	}
+	return c.PrincipalType
+}
+
+// ParsePrincipalType determines the principal type from decoded JWT claims:
+// the "principal_type" claim if present, otherwise PrincipalServiceAccount
+// for client-credentials tokens (a "gty" of "client_credentials", or a
+// "client_id" equal to "sub"), otherwise PrincipalUser.
+func ParsePrincipalType(m map[string]any) PrincipalType {
+	if v, ok := m["principal_type"].(string); ok && v != "" {
+		return PrincipalType(v)
+	}
+	if gty, _ := m["gty"].(string); gty == "client_credentials" {
+		return PrincipalServiceAccount
+	}
+	clientID, _ := m["client_id"].(string)
+	if sub, _ := m["sub"].(string); clientID != "" && clientID == sub {
+		return PrincipalServiceAccount
+	}
+	return PrincipalUser
+}
+
+// SatisfiesACR reports whether the token's authentication context class is
+// one of values.
+func (c *Claims) SatisfiesACR(values ...string) bool {
+	if c == nil || c.ACR == "" {
+		return false
+	}
+	for _, v := range values {
+		if c.ACR == v {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthenticatedWithin reports whether the user authenticated no more than
+// maxAge before now. Tokens without auth_time never qualify.
+func (c *Claims) AuthenticatedWithin(maxAge time.Duration, now time.Time) bool {
+	if c == nil || c.AuthTime.IsZero() {
+		return false
+	}
+	return now.Sub(c.AuthTime) <= maxAge
+}
+
+// Actor is the party acting on behalf of a token's subject (RFC 8693 "act"
+// claim). Actor.Actor holds the previous actor when delegation is chained.
+type Actor struct {
+	Subject string
+	Issuer  string
+	Actor   *Actor
+}
+
+// ParseActor converts a decoded "act" claim into an Actor. It returns nil if
+// the claim is absent, and an error if it, or a nested "act", is not a JSON
+// object with a "sub" member: verifiers must reject such tokens rather than
+// treat them as not impersonated.
+func ParseActor(v any) (*Actor, error) {
+	if v == nil {
+		return nil, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.New("iam: malformed act claim")
+	}
+	sub, _ := m["sub"].(string)
+	if sub == "" {
+		return nil, errors.New("iam: act claim has no sub")
+	}
+	a := &Actor{Subject: sub}
+	a.Issuer, _ = m["iss"].(string)
+	var err error
+	if a.Actor, err = ParseActor(m["act"]); err != nil {
+		return nil, err
+	}
+	return a, nil
 }
 
 // User represents an authenticated user.
@@ -22,6 +136,31 @@ type User struct {
 	TenantID string
 	Roles    []Role
 	Metadata map[string]any
+	Status   string // UserStatusActive, UserStatusDisabled
+}
+
+// User statuses.
+const (
+	UserStatusActive   = "active"
+	UserStatusDisabled = "disabled"
+)
+
+// CreateUserInput describes a user to create.
+type CreateUserInput struct {
+	Email      string
+	Name       string
+	TenantID   string
+	RoleIDs    []string
+	Metadata   map[string]string
+	SendInvite bool // email an invitation to set a password
+}
+
+// UserUpdate lists the user fields to change. Nil fields are left as they
+// are; a non-nil Metadata replaces the user's metadata.
+type UserUpdate struct {
+	Email    *string
+	Name     *string
+	Metadata map[string]string
 }
 
 // Role represents a named role assigned to a user.
@@ -30,6 +169,29 @@ type Role struct {
 	Name string
 }
 
+// ListRolesOptions selects a page of a tenant's roles.
+type ListRolesOptions struct {
+	TenantID string
+
+	// Role restricts the listing to the role with this ID or name.
+	Role string
+
+	// Offset is the number of roles to skip.
+	Offset int
+
+	// Limit is the maximum number of roles to return; backends apply a
+	// default when it is zero.
+	Limit int
+}
+
+// RoleList is one page of a role listing.
+type RoleList struct {
+	Roles []Role
+
+	// Total is the number of roles matching the options across all pages.
+	Total int
+}
+
 // Tenant represents a tenant in a multi-tenant system.
 type Tenant struct {
 	ID     string
@@ -38,27 +200,183 @@ type Tenant struct {
 	Status string
 }
 
-// Session represents an active user session.
-type Session struct {
+// Membership represents a user's membership in a tenant.
+type Membership struct {
+	Tenant   Tenant
+	Role     Role
+	Status   string
+	JoinedAt time.Time
+}
+
+// APIKey describes an API key. The secret part of the key is never stored.
+type APIKey struct {
 	ID        string
+	Prefix    string // public part of the key, e.g. "iam_4f9c1e27"
+	Name      string
 	UserID    string
+	TenantID  string
+	Scopes    []string // permissions the key is limited to; empty means all of the user's
 	CreatedAt time.Time
-	ExpiresAt time.Time
-	UserAgent string
-	IP        string
+	ExpiresAt time.Time // zero if the key does not expire
+	RevokedAt time.Time // zero unless revoked
+
+	// SecretHash is the hex SHA-256 of the full key; only set by Lookup.
+	SecretHash string
+}
+
+// Active reports whether the key is neither revoked nor expired at now.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt.IsZero() && (k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt))
+}
+
+// CreateAPIKeyInput describes an API key to create.
+type CreateAPIKeyInput struct {
+	Name      string
+	UserID    string
+	TenantID  string
+	Scopes    []string
+	ExpiresAt time.Time // zero for a key that does not expire
+}
+
+// NewAPIKey is a freshly created or rotated key. Key is the full secret
+// value; it cannot be retrieved again.
+type NewAPIKey struct {
+	APIKey
+	Key string
+}
+
+// ServiceAccount is a non-human principal, such as a backend service calling
+// with OAuth2 client credentials. Tokens issued to it carry its ID as "sub".
+type ServiceAccount struct {
+	ID          string
+	Name        string
+	Description string
+	ClientID    string // OAuth2 client the account authenticates as
+	CreatedAt   time.Time
+	Bindings    []RoleBinding
+}
+
+// RolesIn returns the roles the account is bound to in tenantID.
+func (sa *ServiceAccount) RolesIn(tenantID string) []Role {
+	var roles []Role
+	for _, b := range sa.Bindings {
+		if b.TenantID == tenantID {
+			roles = append(roles, b.Role)
+		}
+	}
+	return roles
+}
+
+// RoleBinding grants a service account a role in one tenant.
+type RoleBinding struct {
+	TenantID string
+	Role     Role
+}
+
+// CreateServiceAccountInput describes a service account to create.
+type CreateServiceAccountInput struct {
+	Name        string
+	Description string
+	ClientID    string // empty to let the server register a client
+}
+
+// Session represents an active user session.
+type Session struct {
+	ID           string
+	UserID       string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	LastActiveAt time.Time
+	UserAgent    string
+	IP           string
+	DeviceID     string
+	Device       string // "desktop", "mobile", "tablet", "bot" or ""
+	OS           string
+	Browser      string
+	Location     Location
+	Current      bool // true for the session the request was made with
+}
+
+// Location is the approximate geographic origin of a session.
+type Location struct {
+	Country   string
+	Region    string
+	City      string
+	Latitude  float64
+	Longitude float64
+}
+
+// Device is a client a user has signed in from.
+type Device struct {
+	ID          string
+	UserID      string
+	Name        string
+	Trusted     bool
+	Device      string
+	OS          string
+	Browser     string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
 }
 
 // OAuth2Token represents an OAuth2 access token response.
 type OAuth2Token struct {
-	AccessToken string
-	TokenType   string // "Bearer"
-	ExpiresIn   int32
-	ExpiresAt   time.Time
-	Scope       string
+	AccessToken  string
+	TokenType    string // "Bearer"
+	ExpiresIn    int32
+	ExpiresAt    time.Time
+	Scope        string
+	RefreshToken string // empty unless the grant issues one (e.g. user logins)
 }
 
-// ListOptions holds pagination parameters.
+// TokenRequest describes the access token a caller needs for a target: its
+// scopes, its audience and its RFC 8707 resource indicators. Empty fields
+// leave the choice to the exchanger's defaults and the authorization server.
+type TokenRequest struct {
+	Scopes    []string
+	Audience  string
+	Resources []string // absolute URIs of the target resource servers
+}
+
+// ListOptions holds pagination, filter and sort parameters for listing users.
 type ListOptions struct {
-	Page     int
+	// PageSize is the maximum number of users per page; backends apply a
+	// default when it is zero.
 	PageSize int
+
+	// PageToken continues a listing from UserList.NextPageToken. Tokens are
+	// opaque and only valid with the same Filter and OrderBy.
+	PageToken string
+
+	// Page selects a 1-based page for offset pagination and is ignored when
+	// PageToken is set.
+	//
+	// Deprecated: offset paging skips or repeats users under concurrent
+	// writes. Use PageToken.
+	Page int
+
+	Filter UserFilter
+
+	// OrderBy is "id" (default), "email" or "name", optionally followed by
+	// " desc".
+	OrderBy string
+}
+
+// UserFilter restricts a user listing. Empty fields match every user.
+type UserFilter struct {
+	Role        string // role ID or name
+	TenantID    string
+	Status      string
+	EmailPrefix string // case-insensitive
+}
+
+// UserList is one page of a user listing.
+type UserList struct {
+	Users []*User
+
+	// Total is the number of users matching the filter across all pages.
+	Total int
+
+	// NextPageToken fetches the next page; empty on the last page.
+	NextPageToken string
 }
@@ -0,0 +1,286 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/audit"
+)
+
+// AdminBackend defines the contract for pluggable user administration backends.
+type AdminBackend interface {
+	// Create creates a user.
+	Create(ctx context.Context, input iam.CreateUserInput) (*iam.User, error)
+
+	// Update changes the non-nil fields of update.
+	Update(ctx context.Context, userID string, update iam.UserUpdate) (*iam.User, error)
+
+	// Disable blocks the user from signing in and revokes their sessions.
+	Disable(ctx context.Context, userID, reason string) error
+
+	// Enable re-enables a disabled user.
+	Enable(ctx context.Context, userID string) error
+
+	// Delete permanently deletes a user.
+	Delete(ctx context.Context, userID string) error
+
+	// AssignRole grants a role to a user.
+	AssignRole(ctx context.Context, userID, roleID string) error
+
+	// RemoveRole revokes a role from a user.
+	RemoveRole(ctx context.Context, userID, roleID string) error
+
+	// ListRoles returns one page of a tenant's roles.
+	ListRoles(ctx context.Context, opts iam.ListRolesOptions) (*iam.RoleList, error)
+}
+
+// Audit actions recorded by Admin, one per mutation.
+const (
+	AuditActionCreate     = "user_create"
+	AuditActionUpdate     = "user_update"
+	AuditActionDisable    = "user_disable"
+	AuditActionEnable     = "user_enable"
+	AuditActionDelete     = "user_delete"
+	AuditActionAssignRole = "user_role_assign"
+	AuditActionRemoveRole = "user_role_remove"
+)
+
+// Admin implements iam.UserAdminService with a configurable backend. It
+// validates input before calling the backend and writes an audit event for
+// every mutation, successful or not. The acting user and tenant are taken
+// from the context.
+type Admin struct {
+	backend AdminBackend
+	logger  *audit.Logger
+	users   *Service
+}
+
+// AdminOption configures Admin behavior.
+type AdminOption func(*Admin)
+
+// WithAuditLogger sets the audit logger. Defaults to audit.FromContext.
+func WithAuditLogger(l *audit.Logger) AdminOption {
+	return func(a *Admin) {
+		a.logger = l
+	}
+}
+
+// WithCacheInvalidation drops users from the cache of s after they change,
+// so reads through s see the update immediately.
+func WithCacheInvalidation(s *Service) AdminOption {
+	return func(a *Admin) {
+		a.users = s
+	}
+}
+
+// NewAdmin creates a new UserAdminService with the given backend and options.
+func NewAdmin(backend AdminBackend, opts ...AdminOption) *Admin {
+	a := &Admin{backend: backend}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Create validates input and creates a user.
+func (a *Admin) Create(ctx context.Context, input iam.CreateUserInput) (*iam.User, error) {
+	details := fmt.Sprintf("email=%s roles=%s", input.Email, strings.Join(input.RoleIDs, ","))
+
+	if err := validateCreate(input); err != nil {
+		a.audit(ctx, AuditActionCreate, "", details, err)
+		return nil, err
+	}
+
+	user, err := a.backend.Create(ctx, input)
+	if err != nil {
+		err = fmt.Errorf("iam/user: %w", err)
+		a.audit(ctx, AuditActionCreate, "", details, err)
+		return nil, err
+	}
+	a.audit(ctx, AuditActionCreate, user.ID, details, nil)
+	return user, nil
+}
+
+// Update validates update and applies it to the user.
+func (a *Admin) Update(ctx context.Context, userID string, update iam.UserUpdate) (*iam.User, error) {
+	var fields []string
+	if update.Email != nil {
+		fields = append(fields, "email")
+	}
+	if update.Name != nil {
+		fields = append(fields, "name")
+	}
+	if update.Metadata != nil {
+		fields = append(fields, "metadata")
+	}
+	details := "fields=" + strings.Join(fields, ",")
+
+	if err := validateUpdate(userID, update, fields); err != nil {
+		a.audit(ctx, AuditActionUpdate, userID, details, err)
+		return nil, err
+	}
+
+	user, err := a.backend.Update(ctx, userID, update)
+	if err != nil {
+		err = fmt.Errorf("iam/user: %w", err)
+		a.done(ctx, AuditActionUpdate, userID, details, err)
+		return nil, err
+	}
+	a.done(ctx, AuditActionUpdate, userID, details, nil)
+	return user, nil
+}
+
+// Disable blocks the user from signing in and revokes their sessions.
+func (a *Admin) Disable(ctx context.Context, userID, reason string) error {
+	return a.mutate(ctx, AuditActionDisable, userID, "reason="+reason, func() error {
+		return a.backend.Disable(ctx, userID, reason)
+	})
+}
+
+// Enable re-enables a disabled user.
+func (a *Admin) Enable(ctx context.Context, userID string) error {
+	return a.mutate(ctx, AuditActionEnable, userID, "", func() error {
+		return a.backend.Enable(ctx, userID)
+	})
+}
+
+// Delete permanently deletes a user.
+func (a *Admin) Delete(ctx context.Context, userID string) error {
+	return a.mutate(ctx, AuditActionDelete, userID, "", func() error {
+		return a.backend.Delete(ctx, userID)
+	})
+}
+
+// AssignRole grants a role to a user.
+func (a *Admin) AssignRole(ctx context.Context, userID, roleID string) error {
+	if err := validateID("roleID", roleID); err != nil {
+		a.audit(ctx, AuditActionAssignRole, userID, "role="+roleID, err)
+		return err
+	}
+	return a.mutate(ctx, AuditActionAssignRole, userID, "role="+roleID, func() error {
+		return a.backend.AssignRole(ctx, userID, roleID)
+	})
+}
+
+// RemoveRole revokes a role from a user.
+func (a *Admin) RemoveRole(ctx context.Context, userID, roleID string) error {
+	if err := validateID("roleID", roleID); err != nil {
+		a.audit(ctx, AuditActionRemoveRole, userID, "role="+roleID, err)
+		return err
+	}
+	return a.mutate(ctx, AuditActionRemoveRole, userID, "role="+roleID, func() error {
+		return a.backend.RemoveRole(ctx, userID, roleID)
+	})
+}
+
+// ListRoles returns one page of a tenant's roles. It is not audited.
+func (a *Admin) ListRoles(ctx context.Context, opts iam.ListRolesOptions) (*iam.RoleList, error) {
+	roles, err := a.backend.ListRoles(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("iam/user: %w", err)
+	}
+	return roles, nil
+}
+
+// mutate validates userID, runs fn and records the outcome.
+func (a *Admin) mutate(ctx context.Context, action, userID, details string, fn func() error) error {
+	if err := validateID("userID", userID); err != nil {
+		a.audit(ctx, action, userID, details, err)
+		return err
+	}
+	err := fn()
+	if err != nil {
+		err = fmt.Errorf("iam/user: %w", err)
+	}
+	a.done(ctx, action, userID, details, err)
+	return err
+}
+
+// done records a backend call and invalidates the cached user. The cache is
+// dropped on failure too: the backend may have applied part of the change.
+func (a *Admin) done(ctx context.Context, action, userID, details string, err error) {
+	if a.users != nil {
+		a.users.Invalidate(userID)
+	}
+	a.audit(ctx, action, userID, details, err)
+}
+
+func (a *Admin) audit(ctx context.Context, action, userID, details string, err error) {
+	logger := a.logger
+	if logger == nil {
+		logger = audit.FromContext(ctx)
+	}
+	if logger == nil {
+		return
+	}
+
+	event := audit.Event{
+		RequestID:     audit.RequestID(ctx),
+		UserID:        iam.UserIDFromContext(ctx),
+		ActorID:       iam.ActorIDFromContext(ctx),
+		PrincipalType: string(iam.PrincipalTypeFromContext(ctx)),
+		TenantID:      iam.TenantIDFromContext(ctx),
+		Action:        action,
+		Resource:      userID,
+		Result:        "success",
+		Details:       details,
+	}
+	if err != nil {
+		event.Result = "failure"
+		event.Error = err.Error()
+	}
+	logger.Log(event)
+}
+
+func validateCreate(input iam.CreateUserInput) error {
+	if err := validateEmail(input.Email); err != nil {
+		return err
+	}
+	for _, id := range input.RoleIDs {
+		if err := validateID("roleID", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateUpdate(userID string, update iam.UserUpdate, fields []string) error {
+	if err := validateID("userID", userID); err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("iam/user: update changes no fields: %w", iam.ErrInvalidArgument)
+	}
+	if update.Email != nil {
+		if err := validateEmail(*update.Email); err != nil {
+			return err
+		}
+	}
+	if update.Name != nil && strings.TrimSpace(*update.Name) == "" {
+		return fmt.Errorf("iam/user: name cannot be blank: %w", iam.ErrInvalidArgument)
+	}
+	return nil
+}
+
+func validateID(name, id string) error {
+	if id == "" {
+		return fmt.Errorf("iam/user: %s cannot be empty: %w", name, iam.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// validateEmail accepts a bare address ("alice@example.com"), rejecting
+// display names and anything net/mail cannot parse.
+func validateEmail(email string) error {
+	if email == "" {
+		return fmt.Errorf("iam/user: email cannot be empty: %w", iam.ErrInvalidArgument)
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("iam/user: invalid email %q: %w", email, iam.ErrInvalidArgument)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/audit"
+	"github.com/chimerakang/iam-go/audit/audittest"
+	"github.com/chimerakang/iam-go/fake"
+)
+
+func newFakeAdmin(opts ...AdminOption) (*Admin, *iam.Client) {
+	c := fake.NewClient(
+		fake.WithUser("u1", "t1", "alice@example.com", []string{"admin"}),
+		fake.WithTenant("t1", "acme", "active"),
+	)
+	return NewAdmin(c.UserAdmin(), opts...), c
+}
+
+func adminCtx() context.Context {
+	ctx := iam.WithUserID(context.Background(), "admin-1")
+	return iam.WithTenantID(ctx, "t1")
+}
+
+func TestAdmin_AuditsEveryMutation(t *testing.T) {
+	rec := audittest.NewRecorder()
+	admin, _ := newFakeAdmin(WithAuditLogger(rec.Logger))
+	ctx := adminCtx()
+
+	created, err := admin.Create(ctx, iam.CreateUserInput{Email: "bob@example.com", TenantID: "t1"})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	name := "Bob"
+	if _, err := admin.Update(ctx, created.ID, iam.UserUpdate{Name: &name}); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	steps := []func() error{
+		func() error { return admin.AssignRole(ctx, created.ID, "editor") },
+		func() error { return admin.RemoveRole(ctx, created.ID, "editor") },
+		func() error { return admin.Disable(ctx, created.ID, "left company") },
+		func() error { return admin.Enable(ctx, created.ID) },
+		func() error { return admin.Delete(ctx, created.ID) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d returned error: %v", i, err)
+		}
+	}
+
+	want := []string{
+		AuditActionCreate, AuditActionUpdate, AuditActionAssignRole, AuditActionRemoveRole,
+		AuditActionDisable, AuditActionEnable, AuditActionDelete,
+	}
+	got := rec.Events()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(got), got)
+	}
+	for i, e := range got {
+		if e.Action != want[i] || e.Result != "success" || e.Resource != created.ID {
+			t.Errorf("event %d = %+v, want %s success on %s", i, e, want[i], created.ID)
+		}
+		if e.UserID != "admin-1" || e.TenantID != "t1" {
+			t.Errorf("event %d actor = %s/%s, want admin-1/t1", i, e.UserID, e.TenantID)
+		}
+	}
+	if got[4].Details != "reason=left company" {
+		t.Errorf("disable details = %q", got[4].Details)
+	}
+}
+
+func TestAdmin_Validation(t *testing.T) {
+	admin, _ := newFakeAdmin()
+	ctx := context.Background()
+	blank := " "
+	badEmail := "Alice <alice@example.com>"
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"create without email", func() error { _, err := admin.Create(ctx, iam.CreateUserInput{}); return err }},
+		{"create with display name", func() error { _, err := admin.Create(ctx, iam.CreateUserInput{Email: badEmail}); return err }},
+		{"create with empty role", func() error {
+			_, err := admin.Create(ctx, iam.CreateUserInput{Email: "x@example.com", RoleIDs: []string{""}})
+			return err
+		}},
+		{"update nothing", func() error { _, err := admin.Update(ctx, "u1", iam.UserUpdate{}); return err }},
+		{"update blank name", func() error { _, err := admin.Update(ctx, "u1", iam.UserUpdate{Name: &blank}); return err }},
+		{"update bad email", func() error { _, err := admin.Update(ctx, "u1", iam.UserUpdate{Email: &badEmail}); return err }},
+		{"disable without user", func() error { return admin.Disable(ctx, "", "") }},
+		{"assign empty role", func() error { return admin.AssignRole(ctx, "u1", "") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, iam.ErrInvalidArgument) {
+				t.Errorf("expected iam.ErrInvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAdmin_BackendErrorsAudited(t *testing.T) {
+	rec := audittest.NewRecorder()
+	admin, _ := newFakeAdmin(WithAuditLogger(rec.Logger))
+
+	_, err := admin.Create(adminCtx(), iam.CreateUserInput{Email: "alice@example.com"})
+	if !errors.Is(err, iam.ErrAlreadyExists) {
+		t.Fatalf("expected iam.ErrAlreadyExists, got %v", err)
+	}
+	if err := admin.Delete(adminCtx(), "missing"); !errors.Is(err, iam.ErrNotFound) {
+		t.Fatalf("expected iam.ErrNotFound, got %v", err)
+	}
+
+	got := rec.Events()
+	if len(got) != 2 || got[0].Result != "failure" || got[0].Error == "" || got[1].Resource != "missing" {
+		t.Errorf("unexpected events: %+v", got)
+	}
+}
+
+func TestAdmin_AuditLoggerFromContext(t *testing.T) {
+	rec := audittest.NewRecorder()
+	admin, _ := newFakeAdmin()
+
+	if err := admin.Disable(audit.WithContext(adminCtx(), rec.Logger), "u1", ""); err != nil {
+		t.Fatalf("Disable returned error: %v", err)
+	}
+	if got := rec.Events(); len(got) != 1 || got[0].Action != AuditActionDisable {
+		t.Errorf("unexpected events: %+v", got)
+	}
+}
+
+func TestAdmin_AuditsServiceAccounts(t *testing.T) {
+	rec := audittest.NewRecorder()
+	admin, _ := newFakeAdmin(WithAuditLogger(rec.Logger))
+	claims := &iam.Claims{Subject: "sa-provisioner", PrincipalType: iam.PrincipalServiceAccount}
+	ctx := iam.WithClaims(iam.WithUserID(context.Background(), claims.Subject), claims)
+
+	if err := admin.Disable(ctx, "u1", ""); err != nil {
+		t.Fatalf("Disable returned error: %v", err)
+	}
+
+	got := rec.Events()
+	if len(got) != 1 || got[0].UserID != "sa-provisioner" || got[0].PrincipalType != "service_account" {
+		t.Errorf("events = %+v, want one by service account sa-provisioner", got)
+	}
+}
+
+func TestAdmin_InvalidatesCache(t *testing.T) {
+	_, c := newFakeAdmin()
+	users := New(c.Users(), WithCache(time.Minute, 10))
+	admin := NewAdmin(c.UserAdmin(), WithCacheInvalidation(users))
+	ctx := context.Background()
+
+	if _, err := users.Get(ctx, "u1"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	name := "Alice"
+	if _, err := admin.Update(ctx, "u1", iam.UserUpdate{Name: &name}); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+
+	u, err := users.Get(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if u.Name != "Alice" {
+		t.Errorf("expected updated name after invalidation, got %q", u.Name)
+	}
+}
+
+func TestAdmin_ListRolesNotAudited(t *testing.T) {
+	rec := audittest.NewRecorder()
+	admin, _ := newFakeAdmin(WithAuditLogger(rec.Logger))
+
+	roles, err := admin.ListRoles(context.Background(), iam.ListRolesOptions{TenantID: "t1"})
+	if err != nil {
+		t.Fatalf("ListRoles returned error: %v", err)
+	}
+	if roles.Total != 1 || roles.Roles[0].ID != "admin" {
+		t.Errorf("expected the admin role, got %+v", roles)
+	}
+	if got := rec.Events(); len(got) != 0 {
+		t.Errorf("expected no audit events, got %+v", got)
+	}
+}
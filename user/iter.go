@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+// All returns an iterator over every user matching opts, fetching pages from
+// users.List on demand by following NextPageToken. opts.PageToken may resume
+// an earlier listing; opts.Page is ignored.
+//
+// A failed page is yielded once as (nil, err) and ends the iteration:
+//
+//	for u, err := range user.All(ctx, c.Users(), iam.ListOptions{Filter: f}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func All(ctx context.Context, users iam.UserService, opts iam.ListOptions) iter.Seq2[*iam.User, error] {
+	return func(yield func(*iam.User, error) bool) {
+		opts.Page = 0
+		for {
+			list, err := users.List(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, u := range list.Users {
+				if !yield(u, nil) {
+					return
+				}
+			}
+			if list.NextPageToken == "" {
+				return
+			}
+			if list.NextPageToken == opts.PageToken {
+				yield(nil, fmt.Errorf("iam/user: backend returned the same page token twice"))
+				return
+			}
+			opts.PageToken = list.NextPageToken
+		}
+	}
+}
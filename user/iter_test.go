@@ -0,0 +1,62 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+func TestAll_WalksEveryPage(t *testing.T) {
+	users := make(map[string]*iam.User)
+	for i := 0; i < 7; i++ {
+		id := fmt.Sprintf("user%d", i)
+		users[id] = &iam.User{ID: id, TenantID: "t1"}
+	}
+	users["other"] = &iam.User{ID: "other", TenantID: "t2"}
+	svc := New(&mockBackend{users: users})
+
+	var ids []string
+	for u, err := range All(context.Background(), svc, iam.ListOptions{PageSize: 3, Filter: iam.UserFilter{TenantID: "t1"}}) {
+		if err != nil {
+			t.Fatalf("All yielded error: %v", err)
+		}
+		ids = append(ids, u.ID)
+	}
+
+	if len(ids) != 7 || ids[0] != "user0" || ids[6] != "user6" {
+		t.Errorf("unexpected users: %v", ids)
+	}
+}
+
+func TestAll_StopsEarly(t *testing.T) {
+	users := map[string]*iam.User{"a": {ID: "a"}, "b": {ID: "b"}, "c": {ID: "c"}}
+	svc := New(&mockBackend{users: users})
+
+	n := 0
+	for range All(context.Background(), svc, iam.ListOptions{PageSize: 1}) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("expected 2 iterations, got %d", n)
+	}
+}
+
+func TestAll_Error(t *testing.T) {
+	svc := New(&mockBackend{shouldFail: true})
+
+	n := 0
+	for u, err := range All(context.Background(), svc, iam.ListOptions{}) {
+		n++
+		if err == nil || u != nil {
+			t.Errorf("expected (nil, err), got (%v, %v)", u, err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("expected a single error, got %d iterations", n)
+	}
+}
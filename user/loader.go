@@ -0,0 +1,217 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/flight"
+)
+
+// Defaults for Loader options.
+const (
+	DefaultBatchWait = 2 * time.Millisecond
+	DefaultMaxBatch  = 100
+)
+
+// Loader batches the individual user lookups made while handling one request
+// into GetMany calls, dataloader style: Load calls arriving within the batch
+// window are sent together, and each ID is fetched at most once for the life
+// of the Loader. Create one Loader per request (see WithLoader) — results are
+// memoized and never expire. Safe for concurrent use.
+type Loader struct {
+	users    iam.UserService
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	results map[string]*loaderResult
+	pending *loaderBatch
+}
+
+type loaderResult struct {
+	done chan struct{}
+	user *iam.User
+	err  error
+}
+
+type loaderBatch struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	ids        []string
+	results    []*loaderResult
+	dispatched bool
+}
+
+// LoaderOption configures Loader behavior.
+type LoaderOption func(*Loader)
+
+// WithBatchWait sets how long the Loader collects IDs before dispatching a
+// batch (default: 2ms).
+func WithBatchWait(d time.Duration) LoaderOption {
+	return func(l *Loader) {
+		l.wait = d
+	}
+}
+
+// WithMaxBatch dispatches a batch as soon as it holds n IDs (default: 100).
+func WithMaxBatch(n int) LoaderOption {
+	return func(l *Loader) {
+		l.maxBatch = n
+	}
+}
+
+// NewLoader creates a Loader that fetches users through users.GetMany.
+func NewLoader(users iam.UserService, opts ...LoaderOption) *Loader {
+	l := &Loader{
+		users:    users,
+		wait:     DefaultBatchWait,
+		maxBatch: DefaultMaxBatch,
+		results:  make(map[string]*loaderResult),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.maxBatch < 1 {
+		l.maxBatch = 1
+	}
+	return l
+}
+
+// Load returns the user with the given ID. A missing user is reported as an
+// error wrapping iam.ErrNotFound.
+func (l *Loader) Load(ctx context.Context, userID string) (*iam.User, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("iam/user: userID cannot be empty")
+	}
+	return l.await(ctx, l.enqueue(ctx, userID))
+}
+
+// LoadMany returns the users with the given IDs, keyed by ID. Unknown IDs are
+// absent from the result; any other failure is returned as an error.
+func (l *Loader) LoadMany(ctx context.Context, userIDs []string) (map[string]*iam.User, error) {
+	pending := make(map[string]*loaderResult, len(userIDs))
+	for _, id := range userIDs {
+		if id != "" && pending[id] == nil {
+			pending[id] = l.enqueue(ctx, id)
+		}
+	}
+
+	users := make(map[string]*iam.User, len(pending))
+	for id, r := range pending {
+		user, err := l.await(ctx, r)
+		switch {
+		case err == nil:
+			users[id] = user
+		case !errors.Is(err, iam.ErrNotFound):
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+func (l *Loader) await(ctx context.Context, r *loaderResult) (*iam.User, error) {
+	select {
+	case <-r.done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		// Results are shared by every Load of the ID.
+		return cloneUser(r.user), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// enqueue returns the result for userID, adding it to the pending batch if it
+// has not been requested before.
+func (l *Loader) enqueue(ctx context.Context, userID string) *loaderResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r, ok := l.results[userID]; ok {
+		return r
+	}
+	r := &loaderResult{done: make(chan struct{})}
+	l.results[userID] = r
+
+	b := l.pending
+	if b == nil {
+		// The batch outlives any single caller: one canceled request must not
+		// fail the lookups of the others. It needs a deadline of its own.
+		b = &loaderBatch{}
+		b.ctx, b.cancel = context.WithTimeout(context.WithoutCancel(ctx), flight.Timeout)
+		l.pending = b
+		time.AfterFunc(l.wait, func() { l.flush(b) })
+	}
+	b.ids = append(b.ids, userID)
+	b.results = append(b.results, r)
+
+	if len(b.ids) >= l.maxBatch {
+		b.dispatched = true
+		l.pending = nil
+		go l.run(b)
+	}
+	return r
+}
+
+func (l *Loader) flush(b *loaderBatch) {
+	l.mu.Lock()
+	if b.dispatched {
+		l.mu.Unlock()
+		return
+	}
+	b.dispatched = true
+	if l.pending == b {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+	l.run(b)
+}
+
+func (l *Loader) run(b *loaderBatch) {
+	users, err := l.users.GetMany(b.ctx, b.ids)
+	b.cancel()
+	if err != nil {
+		err = fmt.Errorf("iam/user: batch load: %w", err)
+
+		// Forget failed IDs so a later Load can retry them.
+		l.mu.Lock()
+		for _, id := range b.ids {
+			delete(l.results, id)
+		}
+		l.mu.Unlock()
+	}
+
+	for i, id := range b.ids {
+		r := b.results[i]
+		switch {
+		case err != nil:
+			r.err = err
+		case users[id] != nil:
+			r.user = users[id]
+		default:
+			r.err = fmt.Errorf("iam/user: user %q: %w", id, iam.ErrNotFound)
+		}
+		close(r.done)
+	}
+}
+
+type contextKey string
+
+const contextKeyLoader contextKey = "user.loader"
+
+// WithLoader returns a context carrying a new Loader for users. Call it once
+// per request, typically in middleware, so that handlers share one batch.
+func WithLoader(ctx context.Context, users iam.UserService, opts ...LoaderOption) context.Context {
+	return context.WithValue(ctx, contextKeyLoader, NewLoader(users, opts...))
+}
+
+// LoaderFromContext returns the request's Loader, or nil if none was attached.
+func LoaderFromContext(ctx context.Context) *Loader {
+	l, _ := ctx.Value(contextKeyLoader).(*Loader)
+	return l
+}
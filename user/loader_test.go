@@ -0,0 +1,130 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	iam "github.com/chimerakang/iam-go"
+)
+
+func TestLoader_BatchesConcurrentLoads(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{
+		"user1": {ID: "user1"},
+		"user2": {ID: "user2"},
+		"user3": {ID: "user3"},
+	}}
+	loader := NewLoader(New(backend), WithBatchWait(20*time.Millisecond))
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"user1", "user2", "user3", "user1"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u, err := loader.Load(context.Background(), id)
+			if err != nil || u.ID != id {
+				t.Errorf("Load(%s) = %v, %v", id, u, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := backend.getManyCalls.Load(); n != 1 {
+		t.Fatalf("expected 1 GetMany call, got %d", n)
+	}
+	if got := backend.batches[0]; len(got) != 3 {
+		t.Errorf("expected batch of 3 distinct IDs, got %v", got)
+	}
+
+	// Memoized for the life of the loader.
+	if _, err := loader.Load(context.Background(), "user2"); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if n := backend.getManyCalls.Load(); n != 1 {
+		t.Errorf("expected memoized result, got %d GetMany calls", n)
+	}
+}
+
+func TestLoader_MaxBatch(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{}}
+	loader := NewLoader(New(backend), WithBatchWait(time.Hour), WithMaxBatch(2))
+
+	users, err := loader.LoadMany(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("LoadMany returned error: %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("expected no users, got %v", users)
+	}
+	if n := backend.getManyCalls.Load(); n != 1 {
+		t.Errorf("expected full batch dispatched immediately, got %d calls", n)
+	}
+}
+
+func TestLoader_NotFound(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{}}
+	loader := NewLoader(New(backend), WithBatchWait(time.Millisecond))
+
+	_, err := loader.Load(context.Background(), "unknown")
+	if !errors.Is(err, iam.ErrNotFound) {
+		t.Fatalf("expected iam.ErrNotFound, got %v", err)
+	}
+}
+
+func TestLoader_ErrorsAreRetried(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{"user1": {ID: "user1"}}, shouldFail: true}
+	loader := NewLoader(New(backend), WithBatchWait(time.Millisecond))
+
+	if _, err := loader.Load(context.Background(), "user1"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	backend.shouldFail = false
+	if _, err := loader.Load(context.Background(), "user1"); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+}
+
+func TestLoader_ReturnsCopies(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{"user1": {ID: "user1", Name: "Alice"}}}
+	loader := NewLoader(New(backend))
+
+	u, err := loader.Load(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	u.Name = "changed"
+	if u, _ := loader.Load(context.Background(), "user1"); u.Name != "Alice" {
+		t.Errorf("memoized user was modified through a returned copy: %+v", u)
+	}
+}
+
+func TestLoader_BatchHasDeadline(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{"user1": {ID: "user1"}}}
+	loader := NewLoader(New(backend))
+
+	if _, err := loader.Load(context.Background(), "user1"); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(backend.deadlines) != 1 || !backend.deadlines[0] {
+		t.Errorf("expected the batch context to have a deadline, got %v", backend.deadlines)
+	}
+}
+
+func TestLoader_Context(t *testing.T) {
+	if LoaderFromContext(context.Background()) != nil {
+		t.Fatal("expected no loader in empty context")
+	}
+
+	backend := &mockBackend{users: map[string]*iam.User{"user1": {ID: "user1"}}}
+	ctx := WithLoader(context.Background(), New(backend))
+	loader := LoaderFromContext(ctx)
+	if loader == nil {
+		t.Fatal("expected loader in context")
+	}
+	if u, err := loader.Load(ctx, "user1"); err != nil || u.ID != "user1" {
+		t.Errorf("Load = %v, %v", u, err)
+	}
+}
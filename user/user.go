@@ -1,11 +1,22 @@
 // Package user provides UserService implementation.
+//
+// The Service can optionally cache users and their roles in a bounded LRU
+// (see WithCache). Concurrent misses for the same user are coalesced into a
+// single backend call, and GetMany only asks the backend for users that are
+// not cached. Cached users and roles are returned as copies, so callers may
+// modify them. For request-scoped batching of individual lookups, see Loader.
 package user
 
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"time"
 
 	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/internal/cache"
+	"github.com/chimerakang/iam-go/internal/flight"
 )
 
 // Backend defines the contract for pluggable user service backends (gRPC, REST, etc.).
@@ -16,24 +27,59 @@ type Backend interface {
 	// Get returns a user by ID.
 	Get(ctx context.Context, userID string) (*iam.User, error)
 
-	// List returns users with pagination.
-	List(ctx context.Context, opts iam.ListOptions) ([]*iam.User, int, error)
+	// List returns one page of users matching opts.Filter. Backends should
+	// support PageToken cursors; Page is only kept for older servers.
+	List(ctx context.Context, opts iam.ListOptions) (*iam.UserList, error)
 
 	// GetRoles returns the roles assigned to a user.
 	GetRoles(ctx context.Context, userID string) ([]iam.Role, error)
+
+	// GetMany returns the users with the given IDs, keyed by ID. Unknown IDs
+	// must be omitted rather than reported as an error.
+	GetMany(ctx context.Context, userIDs []string) (map[string]*iam.User, error)
 }
 
+// Default cache size used by WithCache when maxEntries is not positive.
+const DefaultMaxEntries = 10000
+
 // Service implements iam.UserService with a configurable backend.
 type Service struct {
 	backend Backend
+
+	ttl   time.Duration
+	users *cache.LRU[string, *iam.User]  // nil when caching is disabled
+	roles *cache.LRU[string, []iam.Role] // nil when caching is disabled
+	sf    flight.Group
 }
 
-// New creates a new UserService with the given backend.
-func New(backend Backend) *Service {
-	return &Service{backend: backend}
+// Option configures Service behavior.
+type Option func(*Service)
+
+// WithCache enables a read-through cache of users and roles. Entries expire
+// after ttl; at most maxEntries users (and as many role lists) are kept, least
+// recently used first out. A non-positive maxEntries uses DefaultMaxEntries.
+// Caching is disabled by default.
+func WithCache(ttl time.Duration, maxEntries int) Option {
+	return func(s *Service) {
+		if maxEntries <= 0 {
+			maxEntries = DefaultMaxEntries
+		}
+		s.ttl = ttl
+		s.users = cache.New[string, *iam.User](maxEntries)
+		s.roles = cache.New[string, []iam.Role](maxEntries)
+	}
 }
 
-// GetCurrent returns the currently authenticated user.
+// New creates a new UserService with the given backend and options.
+func New(backend Backend, opts ...Option) *Service {
+	s := &Service{backend: backend}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetCurrent returns the currently authenticated user. It is never cached.
 func (s *Service) GetCurrent(ctx context.Context) (*iam.User, error) {
 	user, err := s.backend.GetCurrent(ctx)
 	if err != nil {
@@ -47,21 +93,85 @@ func (s *Service) Get(ctx context.Context, userID string) (*iam.User, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("iam/user: userID cannot be empty")
 	}
+	if s.users == nil {
+		user, err := s.backend.Get(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("iam/user: %w", err)
+		}
+		return user, nil
+	}
 
-	user, err := s.backend.Get(ctx, userID)
+	if user, ok := s.users.Get(userID); ok {
+		return cloneUser(user), nil
+	}
+	v, err := s.sf.Do(ctx, "user:"+userID, func(ctx context.Context) (interface{}, error) {
+		user, err := s.backend.Get(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		s.users.Set(userID, user, s.ttl)
+		return user, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("iam/user: %w", err)
 	}
-	return user, nil
+	return cloneUser(v.(*iam.User)), nil
 }
 
-// List returns users with pagination.
-func (s *Service) List(ctx context.Context, opts iam.ListOptions) ([]*iam.User, int, error) {
-	users, total, err := s.backend.List(ctx, opts)
+// GetMany returns the users with the given IDs, keyed by ID. Unknown IDs are
+// absent from the result. Duplicate and empty IDs are ignored; with caching
+// enabled, only uncached users are requested from the backend.
+func (s *Service) GetMany(ctx context.Context, userIDs []string) (map[string]*iam.User, error) {
+	result := make(map[string]*iam.User, len(userIDs))
+	missing := make([]string, 0, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if s.users != nil {
+			if user, ok := s.users.Get(id); ok {
+				result[id] = cloneUser(user)
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	fetched, err := s.backend.GetMany(ctx, missing)
 	if err != nil {
-		return nil, 0, fmt.Errorf("iam/user: %w", err)
+		return nil, fmt.Errorf("iam/user: %w", err)
 	}
-	return users, total, nil
+	for _, id := range missing {
+		user, ok := fetched[id]
+		if !ok {
+			continue
+		}
+		result[id] = user
+		if s.users != nil {
+			s.users.Set(id, user, s.ttl)
+			result[id] = cloneUser(user)
+		}
+	}
+	return result, nil
+}
+
+// List returns one page of users matching opts.Filter. Pages are not cached.
+// To walk every page, use All.
+func (s *Service) List(ctx context.Context, opts iam.ListOptions) (*iam.UserList, error) {
+	if opts.PageSize < 0 {
+		return nil, fmt.Errorf("iam/user: page size cannot be negative: %w", iam.ErrInvalidArgument)
+	}
+
+	list, err := s.backend.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("iam/user: %w", err)
+	}
+	return list, nil
 }
 
 // GetRoles returns the roles assigned to a user.
@@ -69,10 +179,55 @@ func (s *Service) GetRoles(ctx context.Context, userID string) ([]iam.Role, erro
 	if userID == "" {
 		return nil, fmt.Errorf("iam/user: userID cannot be empty")
 	}
+	if s.roles == nil {
+		roles, err := s.backend.GetRoles(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("iam/user: %w", err)
+		}
+		return roles, nil
+	}
 
-	roles, err := s.backend.GetRoles(ctx, userID)
+	if roles, ok := s.roles.Get(userID); ok {
+		return slices.Clone(roles), nil
+	}
+	v, err := s.sf.Do(ctx, "roles:"+userID, func(ctx context.Context) (interface{}, error) {
+		roles, err := s.backend.GetRoles(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		s.roles.Set(userID, roles, s.ttl)
+		return roles, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("iam/user: %w", err)
 	}
-	return roles, nil
+	return slices.Clone(v.([]iam.Role)), nil
+}
+
+// Invalidate drops the cached user and roles for userID. Call it after
+// changing a user so the next read goes to the backend.
+func (s *Service) Invalidate(userID string) {
+	if s.users == nil {
+		return
+	}
+	s.users.Delete(userID)
+	s.roles.Delete(userID)
+}
+
+// ClearCache removes all cached entries.
+func (s *Service) ClearCache() {
+	if s.users == nil {
+		return
+	}
+	s.users.Clear()
+	s.roles.Clear()
+}
+
+// cloneUser returns a copy of a cached user, so that callers may modify it
+// without affecting the cache or other callers.
+func cloneUser(u *iam.User) *iam.User {
+	c := *u
+	c.Roles = slices.Clone(u.Roles)
+	c.Metadata = maps.Clone(u.Metadata)
+	return &c
 }
@@ -3,7 +3,12 @@ package user
 import (
 	"context"
 	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	iam "github.com/chimerakang/iam-go"
 )
@@ -14,6 +19,13 @@ type mockBackend struct {
 	users       map[string]*iam.User
 	userRoles   map[string][]iam.Role
 	shouldFail  bool
+	getDelay    time.Duration
+
+	getCalls     atomic.Int32
+	getManyCalls atomic.Int32
+	mu           sync.Mutex
+	batches      [][]string
+	deadlines    []bool // whether each GetMany call's ctx had a deadline
 }
 
 func (m *mockBackend) GetCurrent(ctx context.Context) (*iam.User, error) {
@@ -27,6 +39,8 @@ func (m *mockBackend) GetCurrent(ctx context.Context) (*iam.User, error) {
 }
 
 func (m *mockBackend) Get(ctx context.Context, userID string) (*iam.User, error) {
+	m.getCalls.Add(1)
+	time.Sleep(m.getDelay)
 	if m.shouldFail {
 		return nil, errors.New("get user failed")
 	}
@@ -37,15 +51,29 @@ func (m *mockBackend) Get(ctx context.Context, userID string) (*iam.User, error)
 	return user, nil
 }
 
-func (m *mockBackend) List(ctx context.Context, opts iam.ListOptions) ([]*iam.User, int, error) {
+func (m *mockBackend) List(ctx context.Context, opts iam.ListOptions) (*iam.UserList, error) {
 	if m.shouldFail {
-		return nil, 0, errors.New("list users failed")
+		return nil, errors.New("list users failed")
 	}
 	users := make([]*iam.User, 0, len(m.users))
 	for _, u := range m.users {
-		users = append(users, u)
+		if opts.Filter.TenantID == "" || u.TenantID == opts.Filter.TenantID {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	// Page tokens are plain offsets.
+	start, _ := strconv.Atoi(opts.PageToken)
+	end := len(users)
+	if opts.PageSize > 0 {
+		end = min(start+opts.PageSize, len(users))
 	}
-	return users, len(users), nil
+	list := &iam.UserList{Users: users[start:end], Total: len(users)}
+	if end < len(users) {
+		list.NextPageToken = strconv.Itoa(end)
+	}
+	return list, nil
 }
 
 func (m *mockBackend) GetRoles(ctx context.Context, userID string) ([]iam.Role, error) {
@@ -59,6 +87,25 @@ func (m *mockBackend) GetRoles(ctx context.Context, userID string) ([]iam.Role,
 	return roles, nil
 }
 
+func (m *mockBackend) GetMany(ctx context.Context, userIDs []string) (map[string]*iam.User, error) {
+	m.getManyCalls.Add(1)
+	m.mu.Lock()
+	m.batches = append(m.batches, append([]string(nil), userIDs...))
+	_, ok := ctx.Deadline()
+	m.deadlines = append(m.deadlines, ok)
+	m.mu.Unlock()
+	if m.shouldFail {
+		return nil, errors.New("get many failed")
+	}
+	users := make(map[string]*iam.User, len(userIDs))
+	for _, id := range userIDs {
+		if u, ok := m.users[id]; ok {
+			users[id] = u
+		}
+	}
+	return users, nil
+}
+
 func TestGetCurrent_Success(t *testing.T) {
 	user := &iam.User{ID: "user123", Email: "alice@example.com"}
 	backend := &mockBackend{currentUser: user}
@@ -134,16 +181,16 @@ func TestList_Success(t *testing.T) {
 	backend := &mockBackend{users: users}
 	svc := New(backend)
 
-	result, total, err := svc.List(context.Background(), iam.ListOptions{})
+	result, err := svc.List(context.Background(), iam.ListOptions{})
 
 	if err != nil {
 		t.Fatalf("List returned error: %v", err)
 	}
-	if total != 2 {
-		t.Errorf("expected total 2, got %d", total)
+	if result.Total != 2 {
+		t.Errorf("expected total 2, got %d", result.Total)
 	}
-	if len(result) != 2 {
-		t.Errorf("expected 2 users, got %d", len(result))
+	if len(result.Users) != 2 {
+		t.Errorf("expected 2 users, got %d", len(result.Users))
 	}
 }
 
@@ -151,16 +198,16 @@ func TestList_Empty(t *testing.T) {
 	backend := &mockBackend{users: make(map[string]*iam.User)}
 	svc := New(backend)
 
-	result, total, err := svc.List(context.Background(), iam.ListOptions{})
+	result, err := svc.List(context.Background(), iam.ListOptions{})
 
 	if err != nil {
 		t.Fatalf("List returned error: %v", err)
 	}
-	if total != 0 {
-		t.Errorf("expected total 0, got %d", total)
+	if result.Total != 0 {
+		t.Errorf("expected total 0, got %d", result.Total)
 	}
-	if len(result) != 0 {
-		t.Errorf("expected 0 users, got %d", len(result))
+	if len(result.Users) != 0 {
+		t.Errorf("expected 0 users, got %d", len(result.Users))
 	}
 }
 
@@ -168,13 +215,24 @@ func TestList_Failed(t *testing.T) {
 	backend := &mockBackend{shouldFail: true}
 	svc := New(backend)
 
-	_, _, err := svc.List(context.Background(), iam.ListOptions{})
+	_, err := svc.List(context.Background(), iam.ListOptions{})
 
 	if err == nil {
 		t.Fatal("expected error")
 	}
 }
 
+func TestList_NegativePageSize(t *testing.T) {
+	backend := &mockBackend{}
+	svc := New(backend)
+
+	_, err := svc.List(context.Background(), iam.ListOptions{PageSize: -1})
+
+	if !errors.Is(err, iam.ErrInvalidArgument) {
+		t.Fatalf("expected iam.ErrInvalidArgument, got %v", err)
+	}
+}
+
 func TestGetRoles_Success(t *testing.T) {
 	roles := []iam.Role{
 		{ID: "role1", Name: "admin"},
@@ -250,3 +308,167 @@ func TestErrorWrapping(t *testing.T) {
 		t.Errorf("expected error wrapped with 'iam/user:', got: %s", errMsg)
 	}
 }
+
+func TestGetMany_Uncached(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{
+		"user1": {ID: "user1"},
+		"user2": {ID: "user2"},
+	}}
+	svc := New(backend)
+
+	result, err := svc.GetMany(context.Background(), []string{"user1", "user2", "user1", "unknown", ""})
+
+	if err != nil {
+		t.Fatalf("GetMany returned error: %v", err)
+	}
+	if len(result) != 2 || result["user1"] == nil || result["user2"] == nil {
+		t.Errorf("unexpected result: %v", result)
+	}
+	if got := backend.batches[0]; len(got) != 3 {
+		t.Errorf("expected deduplicated batch of 3, got %v", got)
+	}
+}
+
+func TestGetMany_Failed(t *testing.T) {
+	backend := &mockBackend{shouldFail: true}
+	svc := New(backend)
+
+	if _, err := svc.GetMany(context.Background(), []string{"user1"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCache_GetHitsCache(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{"user1": {ID: "user1"}}}
+	svc := New(backend, WithCache(time.Minute, 10))
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Get(context.Background(), "user1"); err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+	}
+	if n := backend.getCalls.Load(); n != 1 {
+		t.Errorf("expected 1 backend call, got %d", n)
+	}
+
+	svc.Invalidate("user1")
+	if _, err := svc.Get(context.Background(), "user1"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if n := backend.getCalls.Load(); n != 2 {
+		t.Errorf("expected 2 backend calls after Invalidate, got %d", n)
+	}
+}
+
+func TestCache_ReturnsCopies(t *testing.T) {
+	backend := &mockBackend{
+		users: map[string]*iam.User{"user1": {
+			ID:       "user1",
+			Name:     "Alice",
+			Roles:    []iam.Role{{ID: "r1"}},
+			Metadata: map[string]any{"team": "a"},
+		}},
+		userRoles: map[string][]iam.Role{"user1": {{ID: "r1"}}},
+	}
+	svc := New(backend, WithCache(time.Minute, 10))
+	ctx := context.Background()
+
+	u, _ := svc.Get(ctx, "user1")
+	u.Name = "changed"
+	u.Roles[0].ID = "changed"
+	u.Metadata["team"] = "changed"
+	many, _ := svc.GetMany(ctx, []string{"user1"})
+	many["user1"].Name = "changed"
+	roles, _ := svc.GetRoles(ctx, "user1")
+	roles[0].ID = "changed"
+
+	u, _ = svc.Get(ctx, "user1")
+	if u.Name != "Alice" || u.Roles[0].ID != "r1" || u.Metadata["team"] != "a" {
+		t.Errorf("cached user was modified through a returned copy: %+v", u)
+	}
+	if roles, _ := svc.GetRoles(ctx, "user1"); roles[0].ID != "r1" {
+		t.Errorf("cached roles were modified through a returned copy: %+v", roles)
+	}
+}
+
+func TestCache_ErrorsNotCached(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{}}
+	svc := New(backend, WithCache(time.Minute, 10))
+
+	_, _ = svc.Get(context.Background(), "user1")
+	_, _ = svc.Get(context.Background(), "user1")
+
+	if n := backend.getCalls.Load(); n != 2 {
+		t.Errorf("expected 2 backend calls, got %d", n)
+	}
+}
+
+func TestCache_GetManyFetchesOnlyMisses(t *testing.T) {
+	backend := &mockBackend{users: map[string]*iam.User{
+		"user1": {ID: "user1"},
+		"user2": {ID: "user2"},
+	}}
+	svc := New(backend, WithCache(time.Minute, 10))
+
+	if _, err := svc.Get(context.Background(), "user1"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	result, err := svc.GetMany(context.Background(), []string{"user1", "user2"})
+	if err != nil {
+		t.Fatalf("GetMany returned error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected 2 users, got %d", len(result))
+	}
+	if got := backend.batches[0]; len(got) != 1 || got[0] != "user2" {
+		t.Errorf("expected only user2 fetched, got %v", got)
+	}
+
+	// Both users are now cached.
+	if _, err := svc.GetMany(context.Background(), []string{"user1", "user2"}); err != nil {
+		t.Fatalf("GetMany returned error: %v", err)
+	}
+	if n := backend.getManyCalls.Load(); n != 1 {
+		t.Errorf("expected 1 GetMany call, got %d", n)
+	}
+}
+
+func TestCache_CoalescesConcurrentMisses(t *testing.T) {
+	backend := &mockBackend{
+		users:    map[string]*iam.User{"user1": {ID: "user1"}},
+		getDelay: 20 * time.Millisecond,
+	}
+	svc := New(backend, WithCache(time.Minute, 10))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.Get(context.Background(), "user1"); err != nil {
+				t.Errorf("Get returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := backend.getCalls.Load(); n != 1 {
+		t.Errorf("expected 1 backend call, got %d", n)
+	}
+}
+
+func TestCache_ClearCache(t *testing.T) {
+	backend := &mockBackend{userRoles: map[string][]iam.Role{"user1": {{ID: "role1"}}}}
+	svc := New(backend, WithCache(time.Minute, 10))
+
+	_, _ = svc.GetRoles(context.Background(), "user1")
+	backend.userRoles["user1"] = nil
+	if roles, _ := svc.GetRoles(context.Background(), "user1"); len(roles) != 1 {
+		t.Errorf("expected cached roles, got %v", roles)
+	}
+
+	svc.ClearCache()
+	if roles, _ := svc.GetRoles(context.Background(), "user1"); len(roles) != 0 {
+		t.Errorf("expected fresh roles after ClearCache, got %v", roles)
+	}
+}
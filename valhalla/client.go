@@ -14,6 +14,13 @@
  *       iam.WithAuthorizer(client.Authz()),
  *       iam.WithUserService(client.Users()),
  *   )
+ *
+ * 以 client credentials 認證每個 RPC：
+ *   ex := oauth2.New(clientID, secret, tokenURL, scopes)
+ *   client, err := valhalla.NewClient("iam.example.com:443",
+ *       grpc.WithTransportCredentials(credentials.NewTLS(nil)),
+ *       grpc.WithPerRPCCredentials(ex.PerRPCCredentials(iam.TokenRequest{})),
+ *   )
  */
 
 package valhalla
@@ -32,9 +39,14 @@ import (
 
 	iam "github.com/chimerakang/iam-go"
 	iamv1 "github.com/chimerakang/iam-go/proto/iam/v1"
+	"github.com/chimerakang/iam-go/session"
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Client 包裝 gRPC 連接到 Valhalla IAM 服務
@@ -44,15 +56,21 @@ type Client struct {
 	// gRPC 服務客戶端
 	authzClient   iamv1.AuthzServiceClient
 	userClient    iamv1.UserServiceClient
+	adminClient   iamv1.UserAdminServiceClient
 	tenantClient  iamv1.TenantServiceClient
 	sessionClient iamv1.SessionServiceClient
+	apiKeyClient  iamv1.APIKeyServiceClient
+	accountClient iamv1.ServiceAccountServiceClient
 
 	// iam-go 接口實現
-	verifier iam.TokenVerifier
-	authz    iam.Authorizer
-	users    iam.UserService
-	tenants  iam.TenantService
-	sessions iam.SessionService
+	verifier  iam.TokenVerifier
+	authz     iam.Authorizer
+	users     iam.UserService
+	userAdmin iam.UserAdminService
+	tenants   iam.TenantService
+	sessions  iam.SessionService
+	apiKeys   iam.APIKeyService
+	accounts  iam.ServiceAccountService
 
 	// 當前用戶上下文（從 token 中提取）
 	currentUserID   string
@@ -75,8 +93,11 @@ func NewClient(target string, opts ...grpc.DialOption) (*Client, error) {
 		conn:          conn,
 		authzClient:   iamv1.NewAuthzServiceClient(conn),
 		userClient:    iamv1.NewUserServiceClient(conn),
+		adminClient:   iamv1.NewUserAdminServiceClient(conn),
 		tenantClient:  iamv1.NewTenantServiceClient(conn),
 		sessionClient: iamv1.NewSessionServiceClient(conn),
+		apiKeyClient:  iamv1.NewAPIKeyServiceClient(conn),
+		accountClient: iamv1.NewServiceAccountServiceClient(conn),
 	}
 
 	// 初始化 iam-go 接口實現
@@ -90,8 +111,11 @@ func NewClient(target string, opts ...grpc.DialOption) (*Client, error) {
 	}
 	client.authz = &valhallaAuthorizer{authzClient: client.authzClient, client: client}
 	client.users = &valhallaUserService{userClient: client.userClient, client: client}
+	client.userAdmin = &valhallaUserAdminService{adminClient: client.adminClient}
 	client.tenants = &valhallaTenantService{tenantClient: client.tenantClient}
 	client.sessions = &valhallaSessionService{sessionClient: client.sessionClient, client: client}
+	client.apiKeys = &valhallaAPIKeyService{apiKeyClient: client.apiKeyClient}
+	client.accounts = &valhallaServiceAccountService{accountClient: client.accountClient}
 
 	return client, nil
 }
@@ -116,6 +140,11 @@ func (c *Client) Users() iam.UserService {
 	return c.users
 }
 
+// UserAdmin 返回 UserAdminService 實現
+func (c *Client) UserAdmin() iam.UserAdminService {
+	return c.userAdmin
+}
+
 // Tenants 返回 TenantService 實現
 func (c *Client) Tenants() iam.TenantService {
 	return c.tenants
@@ -126,12 +155,31 @@ func (c *Client) Sessions() iam.SessionService {
 	return c.sessions
 }
 
+// APIKeys 返回 APIKeyService 實現
+func (c *Client) APIKeys() iam.APIKeyService {
+	return c.apiKeys
+}
+
+// ServiceAccounts 返回 ServiceAccountService 實現
+func (c *Client) ServiceAccounts() iam.ServiceAccountService {
+	return c.accounts
+}
+
 // SetCurrentUser 設置當前用戶上下文（通常在驗證 token 後調用）
 func (c *Client) SetCurrentUser(userID, tenantID string) {
 	c.currentUserID = userID
 	c.currentTenantID = tenantID
 }
 
+// userID 返回請求的用戶：優先取 context 中的用戶，僅在缺少時退回 SetCurrentUser
+// 設置的共享值，避免並發請求互相使用對方的用戶 ID
+func (c *Client) userID(ctx context.Context) string {
+	if id := iam.UserIDFromContext(ctx); id != "" {
+		return id
+	}
+	return c.currentUserID
+}
+
 // --- TokenVerifier Implementation ---
 
 type valhallaTokenVerifier struct {
@@ -300,6 +348,9 @@ func (v *valhallaTokenVerifier) Verify(ctx context.Context, token string) (*iam.
 	if issuer, ok := claims["iss"].(string); ok {
 		result.Issuer = issuer
 	}
+	if sid, ok := claims["sid"].(string); ok {
+		result.SessionID = sid
+	}
 
 	// Extract roles array
 	if roles, ok := claims["roles"].([]interface{}); ok {
@@ -318,12 +369,32 @@ func (v *valhallaTokenVerifier) Verify(ctx context.Context, token string) (*iam.
 	if iat, ok := claims["iat"].(float64); ok {
 		result.IssuedAt = time.Unix(int64(iat), 0)
 	}
+	actor, err := iam.ParseActor(claims["act"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	result.Actor = actor
+	result.PrincipalType = iam.ParsePrincipalType(claims)
+	if acr, ok := claims["acr"].(string); ok {
+		result.ACR = acr
+	}
+	if amr, ok := claims["amr"].([]interface{}); ok {
+		for _, a := range amr {
+			if s, ok := a.(string); ok {
+				result.AMR = append(result.AMR, s)
+			}
+		}
+	}
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		result.AuthTime = time.Unix(int64(authTime), 0)
+	}
 
 	// Store extra claims
 	for key, value := range claims {
 		if key != "sub" && key != "tenant_id" && key != "email" &&
 			key != "iss" && key != "roles" && key != "exp" && key != "iat" &&
-			key != "aud" && key != "nbf" && key != "jti" {
+			key != "aud" && key != "nbf" && key != "jti" && key != "sid" &&
+			key != "act" && key != "acr" && key != "amr" && key != "auth_time" {
 			result.Extra[key] = value
 		}
 	}
@@ -390,7 +461,44 @@ func (u *valhallaUserService) Get(ctx context.Context, userID string) (*iam.User
 		UserId: userID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, wrapError("failed to get user", err)
+	}
+	return userFromProto(resp), nil
+}
+
+func (u *valhallaUserService) List(ctx context.Context, opts iam.ListOptions) (*iam.UserList, error) {
+	resp, err := u.userClient.ListUsers(ctx, &iamv1.ListUsersRequest{
+		Page:        int32(opts.Page),
+		PageSize:    int32(opts.PageSize),
+		PageToken:   opts.PageToken,
+		Role:        opts.Filter.Role,
+		TenantId:    opts.Filter.TenantID,
+		Status:      opts.Filter.Status,
+		EmailPrefix: opts.Filter.EmailPrefix,
+		OrderBy:     opts.OrderBy,
+	})
+	if err != nil {
+		return nil, wrapError("failed to list users", err)
+	}
+
+	users := make([]*iam.User, len(resp.Users))
+	for i, u := range resp.Users {
+		users[i] = userFromProto(u)
+	}
+
+	return &iam.UserList{
+		Users:         users,
+		Total:         int(resp.Total),
+		NextPageToken: resp.NextPageToken,
+	}, nil
+}
+
+func (u *valhallaUserService) GetRoles(ctx context.Context, userID string) ([]iam.Role, error) {
+	resp, err := u.userClient.GetUserRoles(ctx, &iamv1.GetUserRolesRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		return nil, wrapError("failed to get user roles", err)
 	}
 
 	roles := make([]iam.Role, len(resp.Roles))
@@ -401,75 +509,332 @@ func (u *valhallaUserService) Get(ctx context.Context, userID string) (*iam.User
 		}
 	}
 
-	metadata := make(map[string]any)
-	for k, v := range resp.Metadata {
-		metadata[k] = v
+	return roles, nil
+}
+
+// GetMany 批次查詢用戶；伺服器省略不存在的 ID
+func (u *valhallaUserService) GetMany(ctx context.Context, userIDs []string) (map[string]*iam.User, error) {
+	users := make(map[string]*iam.User, len(userIDs))
+	if len(userIDs) == 0 {
+		return users, nil
 	}
 
-	return &iam.User{
-		ID:       resp.Id,
-		Email:    resp.Email,
-		Name:     resp.Name,
-		TenantID: resp.TenantId,
-		Roles:    roles,
-		Metadata: metadata,
-	}, nil
+	resp, err := u.userClient.BatchGetUsers(ctx, &iamv1.BatchGetUsersRequest{
+		UserIds: userIDs,
+	})
+	if err != nil {
+		return nil, wrapError("failed to batch get users", err)
+	}
+
+	for _, pu := range resp.Users {
+		users[pu.Id] = userFromProto(pu)
+	}
+	return users, nil
 }
 
-func (u *valhallaUserService) List(ctx context.Context, opts iam.ListOptions) ([]*iam.User, int, error) {
-	resp, err := u.userClient.ListUsers(ctx, &iamv1.ListUsersRequest{
-		Page:     int32(opts.Page),
-		PageSize: int32(opts.PageSize),
+// --- UserAdminService Implementation ---
+
+type valhallaUserAdminService struct {
+	adminClient iamv1.UserAdminServiceClient
+}
+
+func (a *valhallaUserAdminService) Create(ctx context.Context, input iam.CreateUserInput) (*iam.User, error) {
+	resp, err := a.adminClient.CreateUser(ctx, &iamv1.CreateUserRequest{
+		Email:      input.Email,
+		Name:       input.Name,
+		TenantId:   input.TenantID,
+		RoleIds:    input.RoleIDs,
+		Metadata:   input.Metadata,
+		SendInvite: input.SendInvite,
 	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+		return nil, wrapError("failed to create user", err)
 	}
+	return userFromProto(resp), nil
+}
 
-	users := make([]*iam.User, len(resp.Users))
-	for i, u := range resp.Users {
-		roles := make([]iam.Role, len(u.Roles))
-		for j, r := range u.Roles {
-			roles[j] = iam.Role{
-				ID:   r.Id,
-				Name: r.Name,
-			}
-		}
+// Update 只送出 update 中非 nil 的欄位，並以 update_mask 標示
+func (a *valhallaUserAdminService) Update(ctx context.Context, userID string, update iam.UserUpdate) (*iam.User, error) {
+	req := &iamv1.UpdateUserRequest{
+		UserId:     userID,
+		UpdateMask: &fieldmaskpb.FieldMask{},
+	}
+	if update.Email != nil {
+		req.Email = *update.Email
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "email")
+	}
+	if update.Name != nil {
+		req.Name = *update.Name
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "name")
+	}
+	if update.Metadata != nil {
+		req.Metadata = update.Metadata
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "metadata")
+	}
 
-		metadata := make(map[string]any)
-		for k, v := range u.Metadata {
-			metadata[k] = v
-		}
+	resp, err := a.adminClient.UpdateUser(ctx, req)
+	if err != nil {
+		return nil, wrapError("failed to update user", err)
+	}
+	return userFromProto(resp), nil
+}
 
-		users[i] = &iam.User{
-			ID:       u.Id,
-			Email:    u.Email,
-			Name:     u.Name,
-			TenantID: u.TenantId,
-			Roles:    roles,
-			Metadata: metadata,
-		}
+func (a *valhallaUserAdminService) Disable(ctx context.Context, userID, reason string) error {
+	_, err := a.adminClient.DisableUser(ctx, &iamv1.DisableUserRequest{UserId: userID, Reason: reason})
+	if err != nil {
+		return wrapError("failed to disable user", err)
 	}
+	return nil
+}
 
-	return users, int(resp.Total), nil
+func (a *valhallaUserAdminService) Enable(ctx context.Context, userID string) error {
+	_, err := a.adminClient.EnableUser(ctx, &iamv1.EnableUserRequest{UserId: userID})
+	if err != nil {
+		return wrapError("failed to enable user", err)
+	}
+	return nil
 }
 
-func (u *valhallaUserService) GetRoles(ctx context.Context, userID string) ([]iam.Role, error) {
-	resp, err := u.userClient.GetUserRoles(ctx, &iamv1.GetUserRolesRequest{
-		UserId: userID,
+func (a *valhallaUserAdminService) Delete(ctx context.Context, userID string) error {
+	_, err := a.adminClient.DeleteUser(ctx, &iamv1.DeleteUserRequest{UserId: userID})
+	if err != nil {
+		return wrapError("failed to delete user", err)
+	}
+	return nil
+}
+
+func (a *valhallaUserAdminService) AssignRole(ctx context.Context, userID, roleID string) error {
+	_, err := a.adminClient.AssignRole(ctx, &iamv1.AssignRoleRequest{UserId: userID, RoleId: roleID})
+	if err != nil {
+		return wrapError("failed to assign role", err)
+	}
+	return nil
+}
+
+func (a *valhallaUserAdminService) RemoveRole(ctx context.Context, userID, roleID string) error {
+	_, err := a.adminClient.RemoveRole(ctx, &iamv1.RemoveRoleRequest{UserId: userID, RoleId: roleID})
+	if err != nil {
+		return wrapError("failed to remove role", err)
+	}
+	return nil
+}
+
+func (a *valhallaUserAdminService) ListRoles(ctx context.Context, opts iam.ListRolesOptions) (*iam.RoleList, error) {
+	resp, err := a.adminClient.ListRoles(ctx, &iamv1.ListRolesRequest{
+		TenantId: opts.TenantID,
+		Role:     opts.Role,
+		Offset:   int32(opts.Offset),
+		Limit:    int32(opts.Limit),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user roles: %w", err)
+		return nil, wrapError("failed to list roles", err)
 	}
 
 	roles := make([]iam.Role, len(resp.Roles))
 	for i, r := range resp.Roles {
+		roles[i] = iam.Role{ID: r.Id, Name: r.Name}
+	}
+	return &iam.RoleList{Roles: roles, Total: int(resp.Total)}, nil
+}
+
+// userFromProto 將 proto User 轉換為 iam.User
+func userFromProto(pu *iamv1.User) *iam.User {
+	roles := make([]iam.Role, len(pu.Roles))
+	for i, r := range pu.Roles {
 		roles[i] = iam.Role{
 			ID:   r.Id,
 			Name: r.Name,
 		}
 	}
 
-	return roles, nil
+	metadata := make(map[string]any)
+	for k, v := range pu.Metadata {
+		metadata[k] = v
+	}
+
+	return &iam.User{
+		ID:       pu.Id,
+		Email:    pu.Email,
+		Name:     pu.Name,
+		TenantID: pu.TenantId,
+		Roles:    roles,
+		Metadata: metadata,
+		Status:   pu.Status,
+	}
+}
+
+// --- APIKeyService Implementation ---
+
+type valhallaAPIKeyService struct {
+	apiKeyClient iamv1.APIKeyServiceClient
+}
+
+func (k *valhallaAPIKeyService) Create(ctx context.Context, input iam.CreateAPIKeyInput) (*iam.NewAPIKey, error) {
+	req := &iamv1.CreateAPIKeyRequest{
+		Name:     input.Name,
+		UserId:   input.UserID,
+		TenantId: input.TenantID,
+		Scopes:   input.Scopes,
+	}
+	if !input.ExpiresAt.IsZero() {
+		req.ExpiresAt = timestamppb.New(input.ExpiresAt)
+	}
+	resp, err := k.apiKeyClient.CreateAPIKey(ctx, req)
+	if err != nil {
+		return nil, wrapError("failed to create API key", err)
+	}
+	return &iam.NewAPIKey{APIKey: *apiKeyFromProto(resp.GetApiKey()), Key: resp.GetKey()}, nil
+}
+
+func (k *valhallaAPIKeyService) List(ctx context.Context, userID string) ([]iam.APIKey, error) {
+	resp, err := k.apiKeyClient.ListAPIKeys(ctx, &iamv1.ListAPIKeysRequest{UserId: userID})
+	if err != nil {
+		return nil, wrapError("failed to list API keys", err)
+	}
+	keys := make([]iam.APIKey, len(resp.GetApiKeys()))
+	for i, pk := range resp.GetApiKeys() {
+		keys[i] = *apiKeyFromProto(pk)
+		keys[i].SecretHash = ""
+	}
+	return keys, nil
+}
+
+func (k *valhallaAPIKeyService) Revoke(ctx context.Context, keyID string) error {
+	_, err := k.apiKeyClient.RevokeAPIKey(ctx, &iamv1.RevokeAPIKeyRequest{KeyId: keyID})
+	if err != nil {
+		return wrapError("failed to revoke API key", err)
+	}
+	return nil
+}
+
+func (k *valhallaAPIKeyService) Rotate(ctx context.Context, keyID string) (*iam.NewAPIKey, error) {
+	resp, err := k.apiKeyClient.RotateAPIKey(ctx, &iamv1.RotateAPIKeyRequest{KeyId: keyID})
+	if err != nil {
+		return nil, wrapError("failed to rotate API key", err)
+	}
+	return &iam.NewAPIKey{APIKey: *apiKeyFromProto(resp.GetApiKey()), Key: resp.GetKey()}, nil
+}
+
+// Lookup 依前綴查詢 API key（含 secret hash），供本地驗證使用
+func (k *valhallaAPIKeyService) Lookup(ctx context.Context, prefix string) (*iam.APIKey, error) {
+	resp, err := k.apiKeyClient.LookupAPIKey(ctx, &iamv1.LookupAPIKeyRequest{Prefix: prefix})
+	if err != nil {
+		return nil, wrapError("failed to look up API key", err)
+	}
+	return apiKeyFromProto(resp), nil
+}
+
+// apiKeyFromProto 將 proto APIKey 轉換為 iam.APIKey
+func apiKeyFromProto(pk *iamv1.APIKey) *iam.APIKey {
+	key := &iam.APIKey{
+		ID:         pk.GetId(),
+		Prefix:     pk.GetPrefix(),
+		Name:       pk.GetName(),
+		UserID:     pk.GetUserId(),
+		TenantID:   pk.GetTenantId(),
+		Scopes:     pk.GetScopes(),
+		SecretHash: pk.GetSecretHash(),
+	}
+	if pk.GetCreatedAt() != nil {
+		key.CreatedAt = pk.GetCreatedAt().AsTime()
+	}
+	if pk.GetExpiresAt() != nil {
+		key.ExpiresAt = pk.GetExpiresAt().AsTime()
+	}
+	if pk.GetRevokedAt() != nil {
+		key.RevokedAt = pk.GetRevokedAt().AsTime()
+	}
+	return key
+}
+
+// --- ServiceAccountService Implementation ---
+
+type valhallaServiceAccountService struct {
+	accountClient iamv1.ServiceAccountServiceClient
+}
+
+func (a *valhallaServiceAccountService) Create(ctx context.Context, input iam.CreateServiceAccountInput) (*iam.ServiceAccount, error) {
+	resp, err := a.accountClient.CreateServiceAccount(ctx, &iamv1.CreateServiceAccountRequest{
+		Name:        input.Name,
+		Description: input.Description,
+		ClientId:    input.ClientID,
+	})
+	if err != nil {
+		return nil, wrapError("failed to create service account", err)
+	}
+	return serviceAccountFromProto(resp), nil
+}
+
+func (a *valhallaServiceAccountService) Get(ctx context.Context, id string) (*iam.ServiceAccount, error) {
+	resp, err := a.accountClient.GetServiceAccount(ctx, &iamv1.GetServiceAccountRequest{Id: id})
+	if err != nil {
+		return nil, wrapError("failed to get service account", err)
+	}
+	return serviceAccountFromProto(resp), nil
+}
+
+func (a *valhallaServiceAccountService) List(ctx context.Context, tenantID string) ([]*iam.ServiceAccount, error) {
+	resp, err := a.accountClient.ListServiceAccounts(ctx, &iamv1.ListServiceAccountsRequest{TenantId: tenantID})
+	if err != nil {
+		return nil, wrapError("failed to list service accounts", err)
+	}
+	accounts := make([]*iam.ServiceAccount, len(resp.GetServiceAccounts()))
+	for i, sa := range resp.GetServiceAccounts() {
+		accounts[i] = serviceAccountFromProto(sa)
+	}
+	return accounts, nil
+}
+
+func (a *valhallaServiceAccountService) Delete(ctx context.Context, id string) error {
+	_, err := a.accountClient.DeleteServiceAccount(ctx, &iamv1.DeleteServiceAccountRequest{Id: id})
+	if err != nil {
+		return wrapError("failed to delete service account", err)
+	}
+	return nil
+}
+
+func (a *valhallaServiceAccountService) BindRole(ctx context.Context, id, tenantID, roleID string) error {
+	_, err := a.accountClient.BindServiceAccountRole(ctx, &iamv1.BindServiceAccountRoleRequest{
+		Id:       id,
+		TenantId: tenantID,
+		RoleId:   roleID,
+	})
+	if err != nil {
+		return wrapError("failed to bind service account role", err)
+	}
+	return nil
+}
+
+func (a *valhallaServiceAccountService) UnbindRole(ctx context.Context, id, tenantID, roleID string) error {
+	_, err := a.accountClient.UnbindServiceAccountRole(ctx, &iamv1.UnbindServiceAccountRoleRequest{
+		Id:       id,
+		TenantId: tenantID,
+		RoleId:   roleID,
+	})
+	if err != nil {
+		return wrapError("failed to unbind service account role", err)
+	}
+	return nil
+}
+
+// serviceAccountFromProto 將 proto ServiceAccount 轉換為 iam.ServiceAccount
+func serviceAccountFromProto(ps *iamv1.ServiceAccount) *iam.ServiceAccount {
+	sa := &iam.ServiceAccount{
+		ID:          ps.GetId(),
+		Name:        ps.GetName(),
+		Description: ps.GetDescription(),
+		ClientID:    ps.GetClientId(),
+	}
+	if ps.GetCreatedAt() != nil {
+		sa.CreatedAt = ps.GetCreatedAt().AsTime()
+	}
+	for _, b := range ps.GetBindings() {
+		sa.Bindings = append(sa.Bindings, iam.RoleBinding{
+			TenantID: b.GetTenantId(),
+			Role:     iam.Role{ID: b.GetRole().GetId(), Name: b.GetRole().GetName()},
+		})
+	}
+	return sa
 }
 
 // --- TenantService Implementation ---
@@ -483,7 +848,7 @@ func (t *valhallaTenantService) Resolve(ctx context.Context, identifier string)
 		Identifier: identifier,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve tenant: %w", err)
+		return nil, wrapError("failed to resolve tenant", err)
 	}
 
 	return &iam.Tenant{
@@ -506,6 +871,37 @@ func (t *valhallaTenantService) ValidateMembership(ctx context.Context, userID,
 	return resp.IsMember, nil
 }
 
+func (t *valhallaTenantService) ListMemberships(ctx context.Context, userID string) ([]iam.Membership, error) {
+	resp, err := t.tenantClient.ListMemberships(ctx, &iamv1.ListMembershipsRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	memberships := make([]iam.Membership, len(resp.Memberships))
+	for i, m := range resp.Memberships {
+		memberships[i] = iam.Membership{
+			Tenant: iam.Tenant{
+				ID:     m.GetTenant().GetId(),
+				Name:   m.GetTenant().GetName(),
+				Slug:   m.GetTenant().GetSlug(),
+				Status: m.GetTenant().GetStatus(),
+			},
+			Role: iam.Role{
+				ID:   m.GetRole().GetId(),
+				Name: m.GetRole().GetName(),
+			},
+			Status: m.Status,
+		}
+		if m.JoinedAt != nil {
+			memberships[i].JoinedAt = m.JoinedAt.AsTime()
+		}
+	}
+
+	return memberships, nil
+}
+
 // --- SessionService Implementation ---
 
 type valhallaSessionService struct {
@@ -513,24 +909,21 @@ type valhallaSessionService struct {
 	client        *Client
 }
 
+var _ iam.DeviceManager = (*valhallaSessionService)(nil)
+
 func (s *valhallaSessionService) List(ctx context.Context) ([]iam.Session, error) {
 	resp, err := s.sessionClient.ListSessions(ctx, &iamv1.ListSessionsRequest{
-		UserId: s.client.currentUserID,
+		UserId: s.client.userID(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sessions: %w", err)
 	}
 
+	current := iam.SessionIDFromContext(ctx)
 	sessions := make([]iam.Session, len(resp.Sessions))
 	for i, sess := range resp.Sessions {
-		sessions[i] = iam.Session{
-			ID:        sess.Id,
-			UserID:    sess.UserId,
-			CreatedAt: sess.CreatedAt.AsTime(),
-			ExpiresAt: sess.ExpiresAt.AsTime(),
-			UserAgent: sess.UserAgent,
-			IP:        sess.Ip,
-		}
+		sessions[i] = sessionFromProto(sess)
+		sessions[i].Current = current != "" && sess.Id == current
 	}
 
 	return sessions, nil
@@ -547,9 +940,15 @@ func (s *valhallaSessionService) Revoke(ctx context.Context, sessionID string) e
 }
 
 func (s *valhallaSessionService) RevokeAllOthers(ctx context.Context) error {
+	// 沒有當前會話 ID 時拒絕執行，避免連同呼叫者自身的會話一起撤銷
+	current := iam.SessionIDFromContext(ctx)
+	if current == "" {
+		return fmt.Errorf("failed to revoke other sessions: no current session in context")
+	}
+
 	_, err := s.sessionClient.RevokeAllOtherSessions(ctx, &iamv1.RevokeAllOtherSessionsRequest{
-		UserId:           s.client.currentUserID,
-		CurrentSessionId: "",
+		UserId:           s.client.userID(ctx),
+		CurrentSessionId: current,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to revoke other sessions: %w", err)
@@ -557,8 +956,144 @@ func (s *valhallaSessionService) RevokeAllOthers(ctx context.Context) error {
 	return nil
 }
 
+func (s *valhallaSessionService) Validate(ctx context.Context, sessionID string) (*iam.Session, error) {
+	resp, err := s.sessionClient.ValidateSession(ctx, &iamv1.ValidateSessionRequest{
+		SessionId: sessionID,
+	})
+	if err != nil {
+		// 未知會話視為無效，而非暫時性錯誤
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("failed to validate session: %w: %w", iam.ErrSessionInvalid, err)
+		}
+		return nil, fmt.Errorf("failed to validate session: %w", err)
+	}
+	if !resp.Valid {
+		return nil, fmt.Errorf("session %s: %s: %w", sessionID, resp.Reason, iam.ErrSessionInvalid)
+	}
+
+	result := sessionFromProto(resp.GetSession())
+	result.ID = sessionID
+	result.Current = sessionID == iam.SessionIDFromContext(ctx)
+	return &result, nil
+}
+
+func (s *valhallaSessionService) Touch(ctx context.Context, sessionID string) error {
+	_, err := s.sessionClient.TouchSession(ctx, &iamv1.TouchSessionRequest{
+		SessionId: sessionID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+func (s *valhallaSessionService) ListDevices(ctx context.Context) ([]iam.Device, error) {
+	resp, err := s.sessionClient.ListDevices(ctx, &iamv1.ListDevicesRequest{
+		UserId: s.client.userID(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	devices := make([]iam.Device, len(resp.Devices))
+	for i, d := range resp.Devices {
+		devices[i] = iam.Device{
+			ID:      d.Id,
+			UserID:  d.UserId,
+			Name:    d.Name,
+			Trusted: d.Trusted,
+			Device:  d.Device,
+			OS:      d.Os,
+			Browser: d.Browser,
+		}
+		if d.FirstSeenAt != nil {
+			devices[i].FirstSeenAt = d.FirstSeenAt.AsTime()
+		}
+		if d.LastSeenAt != nil {
+			devices[i].LastSeenAt = d.LastSeenAt.AsTime()
+		}
+	}
+	return devices, nil
+}
+
+func (s *valhallaSessionService) RenameDevice(ctx context.Context, deviceID, name string) error {
+	_, err := s.sessionClient.RenameDevice(ctx, &iamv1.RenameDeviceRequest{
+		DeviceId: deviceID,
+		Name:     name,
+	})
+	if err != nil {
+		return wrapError("failed to rename device", err)
+	}
+	return nil
+}
+
+func (s *valhallaSessionService) TrustDevice(ctx context.Context, deviceID string, trusted bool) error {
+	_, err := s.sessionClient.SetDeviceTrust(ctx, &iamv1.SetDeviceTrustRequest{
+		DeviceId: deviceID,
+		Trusted:  trusted,
+	})
+	if err != nil {
+		return wrapError("failed to set device trust", err)
+	}
+	return nil
+}
+
 // --- Helper Functions ---
 
+// sessionFromProto 將 proto Session 轉換為 iam.Session，
+// 伺服器未提供裝置資訊時從 User-Agent 解析
+func sessionFromProto(sess *iamv1.Session) iam.Session {
+	result := iam.Session{
+		ID:        sess.GetId(),
+		UserID:    sess.GetUserId(),
+		UserAgent: sess.GetUserAgent(),
+		IP:        sess.GetIp(),
+		DeviceID:  sess.GetDeviceId(),
+		Device:    sess.GetDevice(),
+		OS:        sess.GetOs(),
+		Browser:   sess.GetBrowser(),
+	}
+	if sess.GetCreatedAt() != nil {
+		result.CreatedAt = sess.GetCreatedAt().AsTime()
+	}
+	if sess.GetExpiresAt() != nil {
+		result.ExpiresAt = sess.GetExpiresAt().AsTime()
+	}
+	if sess.GetLastActiveAt() != nil {
+		result.LastActiveAt = sess.GetLastActiveAt().AsTime()
+	}
+	if loc := sess.GetLocation(); loc != nil {
+		result.Location = iam.Location{
+			Country:   loc.Country,
+			Region:    loc.Region,
+			City:      loc.City,
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+		}
+	}
+	if result.Device == "" && result.OS == "" && result.Browser == "" {
+		ua := session.ParseUserAgent(result.UserAgent)
+		result.Device, result.OS, result.Browser = ua.Device, ua.OS, ua.Browser
+	}
+	return result
+}
+
+// wrapError wraps a gRPC error, marking codes.NotFound with iam.ErrNotFound
+// so caches can tell a missing entity apart from a transient failure, and
+// codes.InvalidArgument and codes.AlreadyExists with iam.ErrInvalidArgument
+// and iam.ErrAlreadyExists.
+func wrapError(msg string, err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return fmt.Errorf("%s: %w: %w", msg, iam.ErrNotFound, err)
+	case codes.InvalidArgument:
+		return fmt.Errorf("%s: %w: %w", msg, iam.ErrInvalidArgument, err)
+	case codes.AlreadyExists:
+		return fmt.Errorf("%s: %w: %w", msg, iam.ErrAlreadyExists, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
 // decodeBase64URL decodes a base64url-encoded string
 func decodeBase64URL(encoded string) ([]byte, error) {
 	// Add padding if necessary
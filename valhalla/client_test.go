@@ -3,16 +3,61 @@ package valhalla
 import (
 	"context"
 	"crypto/rsa"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	iam "github.com/chimerakang/iam-go"
+	"github.com/chimerakang/iam-go/oauth2"
 	iamv1 "github.com/chimerakang/iam-go/proto/iam/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// newBufconnClient 建立連接到記憶體內 gRPC 伺服器的 Client，opts 為額外的撥號選項
+func newBufconnClient(t *testing.T, register func(*grpc.Server), opts ...grpc.DialOption) *Client {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	register(srv)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	client, err := NewClient("passthrough:///bufnet", append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+type stubTenantServer struct {
+	iamv1.UnimplementedTenantServiceServer
+	memberships []*iamv1.Membership
+}
+
+func (s *stubTenantServer) ListMemberships(_ context.Context, req *iamv1.ListMembershipsRequest) (*iamv1.ListMembershipsResponse, error) {
+	return &iamv1.ListMembershipsResponse{Memberships: s.memberships}, nil
+}
+
+func (s *stubTenantServer) ResolveTenant(_ context.Context, req *iamv1.ResolveTenantRequest) (*iamv1.Tenant, error) {
+	return nil, status.Errorf(codes.NotFound, "tenant %s not found", req.GetIdentifier())
+}
+
 // TestTokenVerifierJWKSCaching 驗證 JWKS 緩存機制
 func TestTokenVerifierJWKSCaching(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -234,3 +279,433 @@ func TestUserConversion(t *testing.T) {
 		t.Log("✅ 用戶轉換成功")
 	}
 }
+
+// TestTenantListMemberships 驗證租戶成員資格映射
+func TestTenantListMemberships(t *testing.T) {
+	joined := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	stub := &stubTenantServer{memberships: []*iamv1.Membership{
+		{
+			Tenant:   &iamv1.Tenant{Id: "tenant-1", Name: "Acme", Slug: "acme", Status: "active"},
+			Role:     &iamv1.Role{Id: "role-1", Name: "admin"},
+			Status:   "active",
+			JoinedAt: timestamppb.New(joined),
+		},
+		{Tenant: &iamv1.Tenant{Id: "tenant-2"}, Status: "invited"},
+	}}
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterTenantServiceServer(s, stub) })
+
+	memberships, err := client.Tenants().ListMemberships(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("ListMemberships: %v", err)
+	}
+	if len(memberships) != 2 {
+		t.Fatalf("expected 2 memberships, got %d", len(memberships))
+	}
+	if m := memberships[0]; m.Tenant.Slug != "acme" || m.Role.Name != "admin" || !m.JoinedAt.Equal(joined) {
+		t.Errorf("unexpected membership mapping: %+v", m)
+	}
+	if m := memberships[1]; m.Tenant.ID != "tenant-2" || m.Status != "invited" || !m.JoinedAt.IsZero() {
+		t.Errorf("unexpected membership mapping: %+v", m)
+	}
+}
+
+// TestTenantResolveNotFound 驗證 NotFound 映射為 iam.ErrNotFound
+func TestTenantResolveNotFound(t *testing.T) {
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterTenantServiceServer(s, &stubTenantServer{}) })
+
+	_, err := client.Tenants().Resolve(context.Background(), "missing")
+	if !errors.Is(err, iam.ErrNotFound) {
+		t.Fatalf("expected iam.ErrNotFound, got %v", err)
+	}
+}
+
+type stubUserServer struct {
+	iamv1.UnimplementedUserServiceServer
+	batchReq *iamv1.BatchGetUsersRequest
+	listReq  *iamv1.ListUsersRequest
+}
+
+func (s *stubUserServer) ListUsers(_ context.Context, req *iamv1.ListUsersRequest) (*iamv1.ListUsersResponse, error) {
+	s.listReq = req
+	if req.GetOrderBy() == "created_at" {
+		return nil, status.Error(codes.InvalidArgument, "unsupported order_by")
+	}
+	return &iamv1.ListUsersResponse{
+		Users:         []*iamv1.User{{Id: "user-1", Status: "disabled"}},
+		Total:         5,
+		NextPageToken: "next",
+	}, nil
+}
+
+func (s *stubUserServer) BatchGetUsers(_ context.Context, req *iamv1.BatchGetUsersRequest) (*iamv1.BatchGetUsersResponse, error) {
+	s.batchReq = req
+	return &iamv1.BatchGetUsersResponse{Users: []*iamv1.User{
+		{Id: "user-1", Email: "alice@example.com", Roles: []*iamv1.Role{{Id: "role-1", Name: "admin"}}},
+	}}, nil
+}
+
+// TestUserGetMany 驗證 BatchGetUsers 映射，未知 ID 不回傳
+func TestUserGetMany(t *testing.T) {
+	stub := &stubUserServer{}
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterUserServiceServer(s, stub) })
+
+	users, err := client.Users().GetMany(context.Background(), []string{"user-1", "user-missing"})
+	if err != nil {
+		t.Fatalf("GetMany: %v", err)
+	}
+	if len(stub.batchReq.GetUserIds()) != 2 {
+		t.Errorf("expected 2 requested IDs, got %v", stub.batchReq.GetUserIds())
+	}
+	if len(users) != 1 || users["user-1"].Email != "alice@example.com" || users["user-1"].Roles[0].Name != "admin" {
+		t.Errorf("unexpected users: %+v", users)
+	}
+}
+
+// TestUserListQuery 驗證列表篩選、排序與分頁 token 映射
+func TestUserListQuery(t *testing.T) {
+	stub := &stubUserServer{}
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterUserServiceServer(s, stub) })
+
+	list, err := client.Users().List(context.Background(), iam.ListOptions{
+		PageSize:  10,
+		PageToken: "cursor",
+		Filter:    iam.UserFilter{Role: "admin", TenantID: "t1", Status: "disabled", EmailPrefix: "al"},
+		OrderBy:   "email desc",
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	req := stub.listReq
+	if req.GetPageToken() != "cursor" || req.GetRole() != "admin" || req.GetTenantId() != "t1" ||
+		req.GetStatus() != "disabled" || req.GetEmailPrefix() != "al" || req.GetOrderBy() != "email desc" {
+		t.Errorf("unexpected request: %v", req)
+	}
+	if list.Total != 5 || list.NextPageToken != "next" || list.Users[0].Status != "disabled" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+
+	if _, err := client.Users().List(context.Background(), iam.ListOptions{OrderBy: "created_at"}); !errors.Is(err, iam.ErrInvalidArgument) {
+		t.Errorf("expected iam.ErrInvalidArgument, got %v", err)
+	}
+}
+
+type stubUserAdminServer struct {
+	iamv1.UnimplementedUserAdminServiceServer
+	updateReq  *iamv1.UpdateUserRequest
+	disableReq *iamv1.DisableUserRequest
+}
+
+func (s *stubUserAdminServer) CreateUser(_ context.Context, req *iamv1.CreateUserRequest) (*iamv1.User, error) {
+	if req.GetEmail() == "alice@example.com" {
+		return nil, status.Error(codes.AlreadyExists, "email taken")
+	}
+	return &iamv1.User{Id: "user-9", Email: req.GetEmail(), TenantId: req.GetTenantId(), Status: "active"}, nil
+}
+
+func (s *stubUserAdminServer) UpdateUser(_ context.Context, req *iamv1.UpdateUserRequest) (*iamv1.User, error) {
+	s.updateReq = req
+	return &iamv1.User{Id: req.GetUserId(), Name: req.GetName()}, nil
+}
+
+func (s *stubUserAdminServer) DisableUser(_ context.Context, req *iamv1.DisableUserRequest) (*iamv1.User, error) {
+	s.disableReq = req
+	return &iamv1.User{Id: req.GetUserId(), Status: "disabled"}, nil
+}
+
+func (s *stubUserAdminServer) AssignRole(_ context.Context, req *iamv1.AssignRoleRequest) (*iamv1.User, error) {
+	return nil, status.Errorf(codes.NotFound, "role %s not found", req.GetRoleId())
+}
+
+func (s *stubUserAdminServer) ListRoles(_ context.Context, req *iamv1.ListRolesRequest) (*iamv1.ListRolesResponse, error) {
+	if req.GetTenantId() != "t1" || req.GetOffset() != 1 || req.GetLimit() != 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "unexpected request %v", req)
+	}
+	return &iamv1.ListRolesResponse{Roles: []*iamv1.Role{{Id: "r2", Name: "editor"}}, Total: 3}, nil
+}
+
+// TestUserAdmin 驗證用戶管理 RPC 映射與錯誤轉換
+func TestUserAdmin(t *testing.T) {
+	stub := &stubUserAdminServer{}
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterUserAdminServiceServer(s, stub) })
+	ctx := context.Background()
+
+	user, err := client.UserAdmin().Create(ctx, iam.CreateUserInput{Email: "bob@example.com", TenantID: "t1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if user.ID != "user-9" || user.TenantID != "t1" || user.Status != "active" {
+		t.Errorf("unexpected user: %+v", user)
+	}
+	if _, err := client.UserAdmin().Create(ctx, iam.CreateUserInput{Email: "alice@example.com"}); !errors.Is(err, iam.ErrAlreadyExists) {
+		t.Errorf("expected iam.ErrAlreadyExists, got %v", err)
+	}
+
+	name := "Bob"
+	if _, err := client.UserAdmin().Update(ctx, "user-9", iam.UserUpdate{Name: &name}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if paths := stub.updateReq.GetUpdateMask().GetPaths(); len(paths) != 1 || paths[0] != "name" {
+		t.Errorf("update_mask = %v, want [name]", paths)
+	}
+
+	if err := client.UserAdmin().Disable(ctx, "user-9", "offboarded"); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if stub.disableReq.GetReason() != "offboarded" {
+		t.Errorf("reason = %q", stub.disableReq.GetReason())
+	}
+
+	if err := client.UserAdmin().AssignRole(ctx, "user-9", "ghost"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("expected iam.ErrNotFound, got %v", err)
+	}
+
+	roles, err := client.UserAdmin().ListRoles(ctx, iam.ListRolesOptions{TenantID: "t1", Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("ListRoles: %v", err)
+	}
+	if roles.Total != 3 || len(roles.Roles) != 1 || roles.Roles[0] != (iam.Role{ID: "r2", Name: "editor"}) {
+		t.Errorf("unexpected roles: %+v", roles)
+	}
+}
+
+type stubAPIKeyServer struct {
+	iamv1.UnimplementedAPIKeyServiceServer
+	createReq *iamv1.CreateAPIKeyRequest
+}
+
+func (s *stubAPIKeyServer) CreateAPIKey(_ context.Context, req *iamv1.CreateAPIKeyRequest) (*iamv1.CreateAPIKeyResponse, error) {
+	s.createReq = req
+	return &iamv1.CreateAPIKeyResponse{
+		ApiKey: &iamv1.APIKey{Id: "key-1", Prefix: "iam_0a1b2c3d", UserId: req.GetUserId(), Scopes: req.GetScopes(), ExpiresAt: req.GetExpiresAt()},
+		Key:    "iam_0a1b2c3d_secret",
+	}, nil
+}
+
+func (s *stubAPIKeyServer) LookupAPIKey(_ context.Context, req *iamv1.LookupAPIKeyRequest) (*iamv1.APIKey, error) {
+	if req.GetPrefix() != "iam_0a1b2c3d" {
+		return nil, status.Error(codes.NotFound, "no such key")
+	}
+	return &iamv1.APIKey{Id: "key-1", Prefix: req.GetPrefix(), SecretHash: "abc", RevokedAt: timestamppb.New(time.Unix(1700000000, 0))}, nil
+}
+
+// TestAPIKeys 驗證 API key RPC 映射與錯誤轉換
+func TestAPIKeys(t *testing.T) {
+	stub := &stubAPIKeyServer{}
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterAPIKeyServiceServer(s, stub) })
+	ctx := context.Background()
+	expires := time.Unix(1900000000, 0).UTC()
+
+	created, err := client.APIKeys().Create(ctx, iam.CreateAPIKeyInput{UserID: "u1", Scopes: []string{"orders:read"}, ExpiresAt: expires})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.Key != "iam_0a1b2c3d_secret" || created.ID != "key-1" || !created.ExpiresAt.Equal(expires) {
+		t.Errorf("unexpected key: %+v", created)
+	}
+	if len(stub.createReq.GetScopes()) != 1 || stub.createReq.GetExpiresAt() == nil {
+		t.Errorf("unexpected request: %+v", stub.createReq)
+	}
+
+	key, err := client.APIKeys().Lookup(ctx, "iam_0a1b2c3d")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if key.SecretHash != "abc" || key.RevokedAt.IsZero() {
+		t.Errorf("unexpected key: %+v", key)
+	}
+	if _, err := client.APIKeys().Lookup(ctx, "iam_ffffffff"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("expected iam.ErrNotFound, got %v", err)
+	}
+}
+
+type stubServiceAccountServer struct {
+	iamv1.UnimplementedServiceAccountServiceServer
+	bindReq *iamv1.BindServiceAccountRoleRequest
+}
+
+func (s *stubServiceAccountServer) GetServiceAccount(_ context.Context, req *iamv1.GetServiceAccountRequest) (*iamv1.ServiceAccount, error) {
+	if req.GetId() != "sa-1" {
+		return nil, status.Error(codes.NotFound, "no such service account")
+	}
+	return &iamv1.ServiceAccount{
+		Id:       "sa-1",
+		ClientId: "billing",
+		Bindings: []*iamv1.RoleBinding{
+			{TenantId: "t1", Role: &iamv1.Role{Id: "r1", Name: "reader"}},
+			{TenantId: "t2", Role: &iamv1.Role{Id: "r2", Name: "writer"}},
+		},
+	}, nil
+}
+
+func (s *stubServiceAccountServer) BindServiceAccountRole(_ context.Context, req *iamv1.BindServiceAccountRoleRequest) (*iamv1.ServiceAccount, error) {
+	s.bindReq = req
+	return &iamv1.ServiceAccount{Id: req.GetId()}, nil
+}
+
+// TestServiceAccounts 驗證服務帳號 RPC 映射與錯誤轉換
+func TestServiceAccounts(t *testing.T) {
+	stub := &stubServiceAccountServer{}
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterServiceAccountServiceServer(s, stub) })
+	ctx := context.Background()
+
+	sa, err := client.ServiceAccounts().Get(ctx, "sa-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if sa.ClientID != "billing" || len(sa.Bindings) != 2 || sa.RolesIn("t2")[0].Name != "writer" {
+		t.Errorf("unexpected account: %+v", sa)
+	}
+	if _, err := client.ServiceAccounts().Get(ctx, "sa-404"); !errors.Is(err, iam.ErrNotFound) {
+		t.Errorf("expected iam.ErrNotFound, got %v", err)
+	}
+
+	if err := client.ServiceAccounts().BindRole(ctx, "sa-1", "t3", "r1"); err != nil {
+		t.Fatalf("BindRole: %v", err)
+	}
+	if stub.bindReq.GetTenantId() != "t3" || stub.bindReq.GetRoleId() != "r1" {
+		t.Errorf("unexpected request: %+v", stub.bindReq)
+	}
+}
+
+type stubSessionServer struct {
+	iamv1.UnimplementedSessionServiceServer
+	revokeReq *iamv1.RevokeAllOtherSessionsRequest
+}
+
+func (s *stubSessionServer) ListSessions(_ context.Context, _ *iamv1.ListSessionsRequest) (*iamv1.ListSessionsResponse, error) {
+	return &iamv1.ListSessionsResponse{Sessions: []*iamv1.Session{
+		{
+			Id:        "sess-1",
+			DeviceId:  "dev-1",
+			UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			Location:  &iamv1.Location{Country: "TW", City: "Taipei", Latitude: 25.03, Longitude: 121.56},
+		},
+		{Id: "sess-2", DeviceId: "dev-2", Device: "mobile", Os: "Android", Browser: "Chrome"},
+	}}, nil
+}
+
+func (s *stubSessionServer) ValidateSession(_ context.Context, req *iamv1.ValidateSessionRequest) (*iamv1.ValidateSessionResponse, error) {
+	if req.GetSessionId() == "sess-revoked" {
+		return &iamv1.ValidateSessionResponse{Valid: false, Reason: "revoked"}, nil
+	}
+	return &iamv1.ValidateSessionResponse{Valid: true, Session: &iamv1.Session{
+		Id:           req.GetSessionId(),
+		UserId:       "user-1",
+		LastActiveAt: timestamppb.New(time.Unix(1700000000, 0)),
+	}}, nil
+}
+
+func (s *stubSessionServer) RevokeAllOtherSessions(_ context.Context, req *iamv1.RevokeAllOtherSessionsRequest) (*iamv1.RevokeAllOtherSessionsResponse, error) {
+	s.revokeReq = req
+	return &iamv1.RevokeAllOtherSessionsResponse{}, nil
+}
+
+// TestSessionCurrentSession 驗證當前會話 ID 從 context 傳遞
+func TestSessionCurrentSession(t *testing.T) {
+	stub := &stubSessionServer{}
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterSessionServiceServer(s, stub) })
+	ctx := iam.WithSessionID(context.Background(), "sess-2")
+
+	sessions, err := client.Sessions().List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if sessions[0].Current || !sessions[1].Current {
+		t.Errorf("expected only sess-2 current, got %+v", sessions)
+	}
+
+	if err := client.Sessions().RevokeAllOthers(ctx); err != nil {
+		t.Fatalf("RevokeAllOthers: %v", err)
+	}
+	if got := stub.revokeReq.GetCurrentSessionId(); got != "sess-2" {
+		t.Errorf("CurrentSessionId = %q, want sess-2", got)
+	}
+
+	// 請求的用戶優先於 SetCurrentUser 設置的共享用戶
+	client.SetCurrentUser("user-a", "t1")
+	if err := client.Sessions().RevokeAllOthers(iam.WithUserID(ctx, "user-b")); err != nil {
+		t.Fatalf("RevokeAllOthers: %v", err)
+	}
+	if got := stub.revokeReq.GetUserId(); got != "user-b" {
+		t.Errorf("UserId = %q, want user-b from context", got)
+	}
+	if err := client.Sessions().RevokeAllOthers(ctx); err != nil {
+		t.Fatalf("RevokeAllOthers: %v", err)
+	}
+	if got := stub.revokeReq.GetUserId(); got != "user-a" {
+		t.Errorf("UserId = %q, want fallback user-a", got)
+	}
+
+	if err := client.Sessions().RevokeAllOthers(context.Background()); err == nil {
+		t.Error("expected error without current session")
+	}
+}
+
+// TestSessionValidate 驗證 ValidateSession 映射
+func TestSessionValidate(t *testing.T) {
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterSessionServiceServer(s, &stubSessionServer{}) })
+
+	sess, err := client.Sessions().Validate(context.Background(), "sess-1")
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if sess.UserID != "user-1" || !sess.LastActiveAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected session: %+v", sess)
+	}
+
+	if _, err := client.Sessions().Validate(context.Background(), "sess-revoked"); !errors.Is(err, iam.ErrSessionInvalid) {
+		t.Errorf("expected iam.ErrSessionInvalid, got %v", err)
+	}
+}
+
+// TestSessionDeviceMapping 驗證裝置與位置欄位映射
+func TestSessionDeviceMapping(t *testing.T) {
+	client := newBufconnClient(t, func(s *grpc.Server) { iamv1.RegisterSessionServiceServer(s, &stubSessionServer{}) })
+
+	sessions, err := client.Sessions().List(context.Background())
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	first := sessions[0]
+	if first.DeviceID != "dev-1" || first.OS != "Linux" || first.Browser != "Firefox" || first.Device != "desktop" {
+		t.Errorf("device not parsed from User-Agent: %+v", first)
+	}
+	if first.Location.City != "Taipei" || first.Location.Latitude != 25.03 {
+		t.Errorf("unexpected location: %+v", first.Location)
+	}
+	if second := sessions[1]; second.Device != "mobile" || second.OS != "Android" {
+		t.Errorf("server-provided device fields overridden: %+v", second)
+	}
+}
+
+// authCheckingTenantServer 要求每個呼叫攜帶指定的 Bearer token
+type authCheckingTenantServer struct {
+	stubTenantServer
+	want string
+}
+
+func (s *authCheckingTenantServer) ListMemberships(ctx context.Context, req *iamv1.ListMembershipsRequest) (*iamv1.ListMembershipsResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if got := md.Get("authorization"); len(got) == 0 || got[0] != "Bearer "+s.want {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid token")
+	}
+	return s.stubTenantServer.ListMemberships(ctx, req)
+}
+
+// TestClientCredentials 驗證 Client 可透過 oauth2 PerRPCCredentials 以 client credentials 認證
+func TestClientCredentials(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"svc-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+	ex := oauth2.New("svc", "secret", tokenServer.URL, nil)
+
+	client := newBufconnClient(t, func(s *grpc.Server) {
+		iamv1.RegisterTenantServiceServer(s, &authCheckingTenantServer{want: "svc-token"})
+	}, grpc.WithPerRPCCredentials(ex.PerRPCCredentials(iam.TokenRequest{}, oauth2.AllowInsecure())))
+
+	if _, err := client.Tenants().ListMemberships(context.Background(), "user-1"); err != nil {
+		t.Fatalf("ListMemberships() error: %v", err)
+	}
+}